@@ -6,6 +6,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 type ContainerProbe struct {
@@ -37,6 +38,79 @@ type DynamicGroupConfig struct {
 	TokenDuration string `json:"tokenDuration,omitempty"`
 }
 
+type AutoscalingMetric string
+
+const (
+	AutoscalingMetricCPU            AutoscalingMetric = "CPU"
+	AutoscalingMetricRequestRate    AutoscalingMetric = "RequestRate"
+	AutoscalingMetricConnectionRate AutoscalingMetric = "ConnectionRate"
+)
+
+// GroupAutoscaling sizes a dynamic group between minReplicas and maxReplicas. CPU is served
+// by a HorizontalPodAutoscaler on the MarklogicGroup scale subresource; RequestRate is
+// evaluated by the operator from the App Server request rate the Management API reports for
+// the group. While it is set, spec replicas only seed the group at creation.
+// +kubebuilder:validation:XValidation:rule="self.minReplicas <= self.maxReplicas",message="minReplicas must not exceed maxReplicas"
+// +kubebuilder:validation:XValidation:rule="self.metric != 'RequestRate' || has(self.targetRequestsPerSecond)",message="targetRequestsPerSecond is required for the RequestRate metric"
+type GroupAutoscaling struct {
+	// +kubebuilder:default:=1
+	// +kubebuilder:validation:Minimum=1
+	MinReplicas int32 `json:"minReplicas,omitempty"`
+	// +kubebuilder:validation:Minimum=1
+	MaxReplicas int32 `json:"maxReplicas"`
+	// +kubebuilder:validation:Enum=CPU;RequestRate
+	// +kubebuilder:default:=CPU
+	Metric AutoscalingMetric `json:"metric,omitempty"`
+	// TargetCPUUtilizationPercentage is the average utilization of the MarkLogic container
+	// CPU requests.
+	// +kubebuilder:default:=70
+	// +kubebuilder:validation:Minimum=1
+	TargetCPUUtilizationPercentage int32 `json:"targetCPUUtilizationPercentage,omitempty"`
+	// TargetRequestsPerSecond is the request rate a single host should serve.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TargetRequestsPerSecond int32 `json:"targetRequestsPerSecond,omitempty"`
+	// ScaleDownStabilizationSeconds is how long a lower recommendation must hold before the
+	// group shrinks. Scale-ups are applied immediately.
+	// +kubebuilder:default:=300
+	// +kubebuilder:validation:Minimum=0
+	ScaleDownStabilizationSeconds int32 `json:"scaleDownStabilizationSeconds,omitempty"`
+}
+
+// HAProxyAutoscaling sizes the HAProxy Deployment between minReplicas and maxReplicas with
+// a HorizontalPodAutoscaler. ConnectionRate reads a per-pod metric from the custom metrics
+// API, so an adapter such as prometheus-adapter has to serve it from the HAProxy exporter.
+// While it is set, replicas only seed the Deployment at creation.
+// +kubebuilder:validation:XValidation:rule="self.minReplicas <= self.maxReplicas",message="minReplicas must not exceed maxReplicas"
+// +kubebuilder:validation:XValidation:rule="self.metric != 'ConnectionRate' || has(self.targetConnectionsPerSecond)",message="targetConnectionsPerSecond is required for the ConnectionRate metric"
+type HAProxyAutoscaling struct {
+	// +kubebuilder:default:=2
+	// +kubebuilder:validation:Minimum=1
+	MinReplicas int32 `json:"minReplicas,omitempty"`
+	// +kubebuilder:validation:Minimum=1
+	MaxReplicas int32 `json:"maxReplicas"`
+	// +kubebuilder:validation:Enum=CPU;ConnectionRate
+	// +kubebuilder:default:=CPU
+	Metric AutoscalingMetric `json:"metric,omitempty"`
+	// TargetCPUUtilizationPercentage is the average utilization of the HAProxy container CPU
+	// requests.
+	// +kubebuilder:default:=70
+	// +kubebuilder:validation:Minimum=1
+	TargetCPUUtilizationPercentage int32 `json:"targetCPUUtilizationPercentage,omitempty"`
+	// TargetConnectionsPerSecond is the connection rate a single HAProxy pod should serve.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TargetConnectionsPerSecond int32 `json:"targetConnectionsPerSecond,omitempty"`
+	// ConnectionRateMetric is the name of the per-pod connection rate metric.
+	// +kubebuilder:default:="haproxy_process_current_connection_rate"
+	ConnectionRateMetric string `json:"connectionRateMetric,omitempty"`
+	// ScaleDownStabilizationSeconds is how long a lower recommendation must hold before
+	// HAProxy shrinks.
+	// +kubebuilder:default:=300
+	// +kubebuilder:validation:Minimum=0
+	ScaleDownStabilizationSeconds int32 `json:"scaleDownStabilizationSeconds,omitempty"`
+}
+
 // Storage is the inteface to add pvc and pv support in marklogic
 type Persistence struct {
 	Enabled bool `json:"enabled,omitempty"`
@@ -69,11 +143,33 @@ type VolumeMountWrapper struct {
 	MountPath []corev1.VolumeMount `json:"mountPath,omitempty"`
 }
 
+// +kubebuilder:validation:XValidation:rule="!has(self.csi) || (!has(self.adminUsername) && !has(self.adminPassword) && !has(self.walletPassword))",message="adminUsername, adminPassword and walletPassword cannot be combined with csi"
+// +kubebuilder:validation:XValidation:rule="!has(self.csi) || !has(self.passwordRotation) || !self.passwordRotation.enabled",message="passwordRotation requires the admin credentials in a Kubernetes secret"
 type AdminAuth struct {
 	SecretName     *string `json:"secretName,omitempty"`
 	AdminUsername  *string `json:"adminUsername,omitempty"`
 	AdminPassword  *string `json:"adminPassword,omitempty"`
 	WalletPassword *string `json:"walletPassword,omitempty"`
+	// CSI mounts the admin credentials from the Secrets Store CSI driver instead of the admin
+	// secret. The SecretProviderClass must provide the files username, password and
+	// optionally wallet-password. The operator still reads secretName for Management API
+	// calls, so sync the objects to that secret when dynamic groups, App Servers or backups
+	// are used.
+	// +optional
+	CSI *corev1.CSIVolumeSource `json:"csi,omitempty"`
+	// PasswordRotation keeps the MarkLogic admin password in line with the admin secret.
+	// +optional
+	PasswordRotation *PasswordRotation `json:"passwordRotation,omitempty"`
+}
+
+// PasswordRotation changes the MarkLogic admin password through the Management API when the
+// password in the admin secret changes, and optionally generates a new one periodically.
+type PasswordRotation struct {
+	// +kubebuilder:default:=false
+	Enabled bool `json:"enabled,omitempty"`
+	// RotationPeriod generates a new admin password once the current one is this old.
+	// +optional
+	RotationPeriod *metav1.Duration `json:"rotationPeriod,omitempty"`
 }
 
 type LogCollection struct {
@@ -101,6 +197,21 @@ type LogFilesConfig struct {
 	AuditLogs   bool `json:"auditLogs,omitempty"`
 }
 
+// ServerMetrics has the operator read MarkLogic's own metrics, such as host CPU, request and
+// cache hit rates, and forest sizes and merges, from the status summaries of the Management
+// API with the admin credentials of spec.auth. They are published on the operator's metrics
+// endpoint as marklogic_<resource>_<property> gauges labelled with the namespace and group,
+// for example marklogic_servers_request_rate, and scraped through the controller-manager
+// metrics Service and its ServiceMonitor.
+type ServerMetrics struct {
+	// +kubebuilder:default:=false
+	Enabled bool `json:"enabled,omitempty"`
+	// IntervalSeconds is how often the Management API is read.
+	// +kubebuilder:default:=30
+	// +kubebuilder:validation:Minimum=10
+	IntervalSeconds int32 `json:"intervalSeconds,omitempty"`
+}
+
 type NetworkPolicy struct {
 	Enabled     bool                                    `json:"enabled,omitempty"`
 	PolicyTypes []networkingv1.PolicyType               `json:"policyTypes,omitempty"`
@@ -129,11 +240,78 @@ type HAProxy struct {
 	// +kubebuilder:default:={enabled: false, secretName: "", certFileName: ""}
 	Tls *TlsForHAProxy `json:"tls,omitempty"`
 	// +kubebuilder:default:={enabled: false, port: 1024, auth: {enabled: false, username: "", password: ""}}
-	Stats        Stats                       `json:"stats,omitempty"`
-	Resources    corev1.ResourceRequirements `json:"resources,omitempty"`
-	Affinity     *corev1.Affinity            `json:"affinity,omitempty"`
-	NodeSelector map[string]string           `json:"nodeSelector,omitempty"`
-	Ingress      Ingress                     `json:"ingress,omitempty"`
+	Stats     Stats                       `json:"stats,omitempty"`
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+	// Affinity defaults to spreading the HAProxy pods across nodes when possible.
+	Affinity     *corev1.Affinity  `json:"affinity,omitempty"`
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// TopologySpreadConstraints without a labelSelector select the HAProxy pods.
+	// +optional
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+	// +optional
+	Autoscaling *HAProxyAutoscaling `json:"autoscaling,omitempty"`
+	Ingress     Ingress             `json:"ingress,omitempty"`
+	// +optional
+	Gateway *HAProxyGateway `json:"gateway,omitempty"`
+	// +optional
+	HealthCheck *HAProxyHealthCheck `json:"healthCheck,omitempty"`
+	// +kubebuilder:default:={enabled: true, port: 9999}
+	RuntimeAPI *HAProxyRuntimeAPI `json:"runtimeAPI,omitempty"`
+	// GlobalConfig holds directives appended to the global section, one per line, such as
+	// maxconn or tune settings. The rendered configuration is checked with haproxy -c
+	// before it is rolled out.
+	// +kubebuilder:validation:XValidation:rule="!self.matches(r'(?m)^\\s*(global|defaults|frontend|backend|listen|resolvers|peers|userlist|program|cache|ring|mailers|http-errors|crt-store)(\\s|$)')",message="snippets cannot open a new section"
+	// +optional
+	GlobalConfig string `json:"globalConfig,omitempty"`
+	// DefaultsConfig holds directives appended to the defaults section, such as log formats.
+	// +kubebuilder:validation:XValidation:rule="!self.matches(r'(?m)^\\s*(global|defaults|frontend|backend|listen|resolvers|peers|userlist|program|cache|ring|mailers|http-errors|crt-store)(\\s|$)')",message="snippets cannot open a new section"
+	// +optional
+	DefaultsConfig string `json:"defaultsConfig,omitempty"`
+	// PodDisruptionBudget of the HAProxy Deployment, which allows one unavailable pod
+	// unless configured otherwise.
+	// +kubebuilder:default:={enabled: true}
+	PodDisruptionBudget *PodDisruptionBudget `json:"podDisruptionBudget,omitempty"`
+}
+
+// PodDisruptionBudget limits how many pods a voluntary disruption such as a node drain may
+// evict at once. Without minAvailable or maxUnavailable, data groups and HAProxy allow one
+// unavailable pod and dynamic groups allow half of their pods.
+// +kubebuilder:validation:XValidation:rule="!has(self.minAvailable) || !has(self.maxUnavailable)",message="minAvailable and maxUnavailable are mutually exclusive"
+type PodDisruptionBudget struct {
+	// +kubebuilder:default:=false
+	Enabled bool `json:"enabled,omitempty"`
+	// +optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// ForestFailover keeps local-disk failover replicas of the forests on the hosts of a group.
+// Each replica is placed on another host of the group, in a zone holding no other copy of
+// the forest whenever the group spans enough zones.
+type ForestFailover struct {
+	// +kubebuilder:default:=false
+	Enabled bool `json:"enabled,omitempty"`
+	// Replicas is the number of replica forests kept for every forest. Replicas configured
+	// by other means, such as a MarklogicDatabase, count towards it.
+	// +kubebuilder:default:=1
+	// +kubebuilder:validation:Minimum=1
+	Replicas int32 `json:"replicas,omitempty"`
+	// TopologyKey is the pod or node label naming the zone of a host. It defaults to the
+	// key of the first topologySpreadConstraint of the group, or topology.kubernetes.io/zone.
+	// An operator limited to one namespace cannot read nodes and uses pod labels only.
+	// +optional
+	TopologyKey string `json:"topologyKey,omitempty"`
+	// RequireZoneSpread leaves a forest short of replicas rather than placing two copies in
+	// the same zone.
+	// +optional
+	RequireZoneSpread bool `json:"requireZoneSpread,omitempty"`
+	// Databases limits failover to the forests of these databases; forests of every
+	// database are protected when empty.
+	// +optional
+	Databases []string `json:"databases,omitempty"`
 }
 
 // HAProxyGroup represents group-level HAProxy configuration that can override cluster settings
@@ -142,6 +320,49 @@ type HAProxyGroup struct {
 	AppServers       []AppServers `json:"appServers,omitempty"`
 	PathBasedRouting *bool        `json:"pathBasedRouting,omitempty"`
 	TcpPorts         *Tcpports    `json:"tcpPorts,omitempty"`
+	// HealthCheck overrides the health check of the cluster for the servers of this group.
+	// +optional
+	HealthCheck *HAProxyHealthCheck `json:"healthCheck,omitempty"`
+}
+
+// HAProxyRuntimeAPI binds the runtime API of HAProxy on a TCP port of the loopback
+// interface of each HAProxy pod. The operator uses it to add, drain and remove servers as
+// hosts join and leave the cluster, and only rolls the HAProxy Deployment when the rest of
+// the configuration changes. The API accepts admin commands without authentication, so it
+// is not reachable from other pods; the operator connects through a port-forward of the
+// Kubernetes API server, which requires the pods/portforward permission.
+type HAProxyRuntimeAPI struct {
+	Enabled bool `json:"enabled"`
+	// +kubebuilder:default:=9999
+	Port int32 `json:"port,omitempty"`
+}
+
+// HAProxyHealthCheck checks the servers of a group against the HealthCheck App Server of
+// each MarkLogic host instead of only opening a connection, which takes hosts that
+// MarkLogic reports offline out of rotation once the checks fail. Putting the servers of
+// offline hosts, and of hosts that a scale-down is decommissioning, into maintenance
+// requires runtimeAPI: the operator changes the server state at runtime so that host
+// status never rolls the HAProxy Deployment, and without the runtime API only the checks
+// apply. A backend shared with a group without health checks checks its servers against
+// the health endpoint with the default settings.
+type HAProxyHealthCheck struct {
+	// +kubebuilder:default:=false
+	Enabled bool `json:"enabled,omitempty"`
+	// Port of the HealthCheck App Server.
+	// +kubebuilder:default:=7997
+	Port int32 `json:"port,omitempty"`
+	// Interval is the number of seconds between two checks of a server.
+	// +kubebuilder:default:=5
+	// +kubebuilder:validation:Minimum=1
+	Interval int32 `json:"interval,omitempty"`
+	// Rise is the number of consecutive successful checks that bring a server back up.
+	// +kubebuilder:default:=2
+	// +kubebuilder:validation:Minimum=1
+	Rise int32 `json:"rise,omitempty"`
+	// Fall is the number of consecutive failed checks that take a server down.
+	// +kubebuilder:default:=3
+	// +kubebuilder:validation:Minimum=1
+	Fall int32 `json:"fall,omitempty"`
 }
 
 type AppServers struct {
@@ -150,6 +371,85 @@ type AppServers struct {
 	Port       int32  `json:"port,omitempty"`
 	TargetPort int32  `json:"targetPort,omitempty"`
 	Path       string `json:"path,omitempty"`
+	// Balance is the load-balancing algorithm of the backend, leastconn when empty.
+	// +kubebuilder:validation:Enum=leastconn;roundrobin;static-rr;first;source;uri
+	// +optional
+	Balance string `json:"balance,omitempty"`
+	// SessionAffinity pins the requests of a MarkLogic session or multi-statement
+	// transaction to the host that started it. Sessions stick on the HostId and SessionID
+	// cookies when it is not set.
+	// +optional
+	SessionAffinity *SessionAffinity `json:"sessionAffinity,omitempty"`
+	// Timeout overrides the connect and server timeouts of the HAProxy section for the
+	// backend. The client timeout only applies when the App Server has its own frontend,
+	// that is without path-based routing.
+	// +optional
+	Timeout *Timeout `json:"timeout,omitempty"`
+	// ExtraConfig holds directives appended to the frontend and backend of the App Server.
+	// +optional
+	ExtraConfig *HAProxyExtraConfig `json:"extraConfig,omitempty"`
+}
+
+// HAProxyExtraConfig holds configuration snippets for the sections of an App Server, such
+// as ACLs, rate limits or header rewrites, one directive per line.
+type HAProxyExtraConfig struct {
+	// Frontend is appended to the frontend of the App Server. With path-based routing the
+	// App Servers share a frontend and their snippets are added to it.
+	// +kubebuilder:validation:XValidation:rule="!self.matches(r'(?m)^\\s*(global|defaults|frontend|backend|listen|resolvers|peers|userlist|program|cache|ring|mailers|http-errors|crt-store)(\\s|$)')",message="snippets cannot open a new section"
+	// +optional
+	Frontend string `json:"frontend,omitempty"`
+	// Backend is appended to the backend of the App Server.
+	// +kubebuilder:validation:XValidation:rule="!self.matches(r'(?m)^\\s*(global|defaults|frontend|backend|listen|resolvers|peers|userlist|program|cache|ring|mailers|http-errors|crt-store)(\\s|$)')",message="snippets cannot open a new section"
+	// +optional
+	Backend string `json:"backend,omitempty"`
+}
+
+// SessionAffinity configures cookie-based stickiness of an HTTP backend. HAProxy inserts
+// its own cookie and remembers the value of the listed MarkLogic cookies.
+type SessionAffinity struct {
+	// Enabled set to false balances every request on its own.
+	// +kubebuilder:default:=true
+	Enabled bool `json:"enabled"`
+	// Cookies are the response cookies whose value pins the client to a host.
+	// +kubebuilder:default:={"HostId","SessionID"}
+	// +kubebuilder:validation:items:Pattern=`^[A-Za-z0-9!#$%&'*+.^_|~-]+$`
+	// +optional
+	Cookies []string `json:"cookies,omitempty"`
+	// Expire is how long an idle client stays pinned, as an HAProxy duration.
+	// +kubebuilder:default:="4h"
+	// +kubebuilder:validation:Pattern=`^[0-9]+(ms|s|m|h|d)$`
+	// +optional
+	Expire string `json:"expire,omitempty"`
+}
+
+// AppServer declares a MarkLogic App Server of a group. The operator creates and updates
+// it through the Management API, adds its port to the group Services and routes it
+// through HAProxy. Removing an entry stops routing but leaves the App Server in MarkLogic.
+// +kubebuilder:validation:XValidation:rule="self.port < 7997 || self.port > 8002",message="ports 7997-8002 are reserved for MarkLogic"
+type AppServer struct {
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// +kubebuilder:validation:Enum=http;xdbc;odbc
+	// +kubebuilder:default:=http
+	Type string `json:"type,omitempty"`
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port"`
+	// +kubebuilder:default:="Documents"
+	ContentDatabase string `json:"contentDatabase,omitempty"`
+	// ModulesDatabase holds the server's code. Modules are read from Root on the file
+	// system when empty.
+	// +optional
+	ModulesDatabase string `json:"modulesDatabase,omitempty"`
+	// +kubebuilder:default:="/"
+	Root string `json:"root,omitempty"`
+	// Authentication applies to http and xdbc servers.
+	// +kubebuilder:validation:Enum=digest;basic;digestbasic
+	// +kubebuilder:default:=digest
+	Authentication string `json:"authentication,omitempty"`
+	// Path routes http and xdbc servers when HAProxy uses path-based routing. Defaults to /<name>.
+	// +optional
+	Path string `json:"path,omitempty"`
 }
 
 type Stats struct {
@@ -174,6 +474,14 @@ type TcpPort struct {
 	TargetPort int32  `json:"targetPort,omitempty"`
 	Name       string `json:"name,omitempty"`
 	Type       string `json:"type,omitempty"`
+	// Balance is the load-balancing algorithm of the listener, leastconn when empty. Use
+	// source to keep the connections of a client on one host.
+	// +kubebuilder:validation:Enum=leastconn;roundrobin;static-rr;first;source
+	// +optional
+	Balance string `json:"balance,omitempty"`
+	// Timeout overrides the timeouts of the HAProxy section for the listener.
+	// +optional
+	Timeout *Timeout `json:"timeout,omitempty"`
 }
 
 type Timeout struct {
@@ -182,10 +490,76 @@ type Timeout struct {
 	Server  int32 `json:"server,omitempty"`
 }
 
+// +kubebuilder:validation:XValidation:rule="!has(self.issuerRef) || !has(self.secretName) || size(self.secretName) == 0",message="secretName and issuerRef are mutually exclusive"
 type TlsForHAProxy struct {
 	Enabled      bool   `json:"enabled,omitempty"`
 	SecretName   string `json:"secretName,omitempty"`
 	CertFileName string `json:"certFileName,omitempty"`
+	// IssuerRef asks cert-manager to issue the HAProxy certificate. The operator creates a
+	// Certificate for the HAProxy Service and the Ingress hosts and mounts the issued secret,
+	// so secretName and certFileName are not needed.
+	// +optional
+	IssuerRef *CertificateIssuerRef `json:"issuerRef,omitempty"`
+}
+
+// CertificateIssuerRef references a cert-manager Issuer or ClusterIssuer.
+type CertificateIssuerRef struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// +kubebuilder:default:=Issuer
+	// +kubebuilder:validation:Enum=Issuer;ClusterIssuer
+	Kind string `json:"kind,omitempty"`
+	// +kubebuilder:default:="cert-manager.io"
+	Group string `json:"group,omitempty"`
+}
+
+// HAProxyGateway attaches the HAProxy service to Gateway API Gateways. An HTTPRoute is
+// generated for every App Server HAProxy routes and a TCPRoute for every TCP port it
+// listens on, including those configured on the groups.
+// +kubebuilder:validation:XValidation:rule="!has(self.enabled) || !self.enabled || (has(self.parentRefs) && size(self.parentRefs) > 0)",message="parentRefs is required when the gateway is enabled"
+type HAProxyGateway struct {
+	// +kubebuilder:default:=false
+	Enabled bool `json:"enabled,omitempty"`
+	// ParentRefs are the Gateways the routes attach to. Without path-based routing every
+	// App Server is matched on / and needs a listener of its own, so a parentRef without a
+	// sectionName or port attaches each HTTPRoute to the listener on the port it serves.
+	// TCPRoutes always attach to the listener on their port.
+	// +optional
+	ParentRefs []GatewayParentRef `json:"parentRefs,omitempty"`
+	// +optional
+	Hostnames []string `json:"hostnames,omitempty"`
+	// +optional
+	TLS *GatewayTLS `json:"tls,omitempty"`
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+type GatewayParentRef struct {
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Namespace of the Gateway; the namespace of the MarklogicCluster when empty.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// +optional
+	SectionName string `json:"sectionName,omitempty"`
+	// +optional
+	Port int32 `json:"port,omitempty"`
+}
+
+// GatewayTLS attaches the HTTPRoutes to an HTTPS listener. The certificate is configured
+// on the listener of the Gateway.
+type GatewayTLS struct {
+	// SectionName is the HTTPS listener of the parent Gateways. It replaces the sectionName
+	// and port of the parentRefs for the HTTPRoutes of the App Servers.
+	// +kubebuilder:validation:MinLength=1
+	SectionName string `json:"sectionName"`
+	// RedirectHTTP attaches an HTTPRoute to the parentRefs as given that redirects plain
+	// HTTP requests to HTTPS.
+	// +optional
+	RedirectHTTP bool `json:"redirectHTTP,omitempty"`
 }
 
 type Ingress struct {
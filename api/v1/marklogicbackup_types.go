@@ -0,0 +1,163 @@
+/*
+Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MarklogicBackupSpec defines the desired state of MarklogicBackup
+type MarklogicBackupSpec struct {
+	// ClusterRef names the MarklogicCluster, in the same namespace, whose databases are backed up.
+	ClusterRef corev1.LocalObjectReference `json:"clusterRef"`
+	// +kubebuilder:validation:MinItems=1
+	Databases []string      `json:"databases"`
+	Storage   BackupStorage `json:"storage"`
+	// Schedule is a cron expression (minute hour day-of-month month day-of-week, UTC).
+	// When empty the backup runs once.
+	// +optional
+	Schedule string `json:"schedule,omitempty"`
+	// Suspend stops new scheduled runs. A run already in progress finishes.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+	// +kubebuilder:default:=true
+	IncludeReplicas *bool `json:"includeReplicas,omitempty"`
+	// Retention prunes older backups of each database after a successful run.
+	// +optional
+	Retention *BackupRetention `json:"retention,omitempty"`
+}
+
+type BackupStorage struct {
+	// Path is the directory, as seen by the MarkLogic hosts, that backups are written under.
+	// Each database is backed up to <path>/<backup name>/<database>, where MarkLogic
+	// creates one timestamped directory per run.
+	// +kubebuilder:validation:Pattern=`^/`
+	Path string `json:"path"`
+	// PersistentVolumeClaim backing Path. It must be mounted at Path on every group of
+	// the cluster (for example through additionalVolumes); the operator checks the
+	// claim is bound and mounted before each run.
+	// +optional
+	PersistentVolumeClaim *corev1.PersistentVolumeClaimVolumeSource `json:"persistentVolumeClaim,omitempty"`
+}
+
+type BackupRetention struct {
+	// KeepLast is the number of backups kept per database.
+	// +kubebuilder:validation:Minimum=1
+	KeepLast int32 `json:"keepLast"`
+}
+
+type BackupPhase string
+
+const (
+	BackupPhasePending   BackupPhase = "Pending"
+	BackupPhaseScheduled BackupPhase = "Scheduled"
+	BackupPhaseRunning   BackupPhase = "Running"
+	BackupPhaseCompleted BackupPhase = "Completed"
+	BackupPhaseFailed    BackupPhase = "Failed"
+)
+
+type DatabaseJobState string
+
+const (
+	DatabaseJobStatePending   DatabaseJobState = "Pending"
+	DatabaseJobStateRunning   DatabaseJobState = "Running"
+	DatabaseJobStateCompleted DatabaseJobState = "Completed"
+	DatabaseJobStateFailed    DatabaseJobState = "Failed"
+)
+
+// DatabaseJobStatus is the state of the backup or restore job of one database.
+type DatabaseJobStatus struct {
+	Name string `json:"name"`
+	// Directory is the backup directory the job writes to or reads from.
+	Directory string `json:"directory,omitempty"`
+	JobID     string `json:"jobID,omitempty"`
+	// HostName is the MarkLogic host running the job; status is only available there.
+	HostName string `json:"hostName,omitempty"`
+	// +kubebuilder:validation:Enum=Pending;Running;Completed;Failed
+	State DatabaseJobState `json:"state,omitempty"`
+	// SizeBytes is the on-disk size of the database when its backup completed.
+	SizeBytes int64  `json:"sizeBytes,omitempty"`
+	Message   string `json:"message,omitempty"`
+}
+
+// BackupRun is one execution of a MarklogicBackup.
+type BackupRun struct {
+	// ScheduledTime is the schedule slot the run belongs to, or its start for one-shot backups.
+	ScheduledTime  metav1.Time  `json:"scheduledTime"`
+	StartTime      *metav1.Time `json:"startTime,omitempty"`
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// +kubebuilder:validation:Enum=Running;Completed;Failed
+	Phase BackupPhase `json:"phase,omitempty"`
+	// SizeBytes sums SizeBytes over the databases of the run.
+	SizeBytes int64               `json:"sizeBytes,omitempty"`
+	Databases []DatabaseJobStatus `json:"databases,omitempty"`
+	Message   string              `json:"message,omitempty"`
+}
+
+// MarklogicBackupStatus defines the observed state of MarklogicBackup
+type MarklogicBackupStatus struct {
+	Conditions         []metav1.Condition `json:"conditions,omitempty"`
+	ObservedGeneration int64              `json:"observedGeneration,omitempty"`
+	// +kubebuilder:validation:Enum=Pending;Scheduled;Running;Completed;Failed
+	Phase BackupPhase `json:"phase,omitempty"`
+	// CurrentRun is the run in progress, if any.
+	CurrentRun         *BackupRun   `json:"currentRun,omitempty"`
+	LastScheduleTime   *metav1.Time `json:"lastScheduleTime,omitempty"`
+	NextScheduleTime   *metav1.Time `json:"nextScheduleTime,omitempty"`
+	LastSuccessfulTime *metav1.Time `json:"lastSuccessfulTime,omitempty"`
+	// History holds the most recent finished runs, newest first.
+	History []BackupRun `json:"history,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:metadata:annotations="helm.sh/resource-policy=keep"
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:shortName=mlbackup
+//+kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.clusterRef.name"
+//+kubebuilder:printcolumn:name="Schedule",type="string",JSONPath=".spec.schedule"
+//+kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+//+kubebuilder:printcolumn:name="Last Success",type="date",JSONPath=".status.lastSuccessfulTime"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// MarklogicBackup is the Schema for the marklogicbackups API
+type MarklogicBackup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MarklogicBackupSpec   `json:"spec,omitempty"`
+	Status MarklogicBackupStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// MarklogicBackupList contains a list of MarklogicBackup
+type MarklogicBackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MarklogicBackup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MarklogicBackup{}, &MarklogicBackupList{})
+}
+
+// Observed State for MarkLogic Backup and Restore
+const (
+	// BackupReady is False while the cluster, schedule or storage prevents runs.
+	BackupReady MarkLogicConditionType = "Ready"
+)
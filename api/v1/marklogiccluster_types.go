@@ -70,6 +70,7 @@ type MarklogicClusterSpec struct {
 	HugePages *HugePages `json:"hugePages,omitempty"`
 	// +kubebuilder:default:={enabled: false, image: "fluent/fluent-bit:4.1.1", resources: {requests: {cpu: "100m", memory: "200Mi"}, limits: {cpu: "200m", memory: "500Mi"}}, files: {errorLogs: true, accessLogs: true, requestLogs: true}, outputs: "stdout"}
 	LogCollection                  *LogCollection                  `json:"logCollection,omitempty"`
+	Metrics                        *ServerMetrics                  `json:"metrics,omitempty"`
 	HAProxy                        *HAProxy                        `json:"haproxy,omitempty"`
 	Tls                            *Tls                            `json:"tls,omitempty"`
 	AdditionalVolumes              *[]corev1.Volume                `json:"additionalVolumes,omitempty"`
@@ -93,7 +94,9 @@ type MarklogicClusterSpec struct {
 
 // +kubebuilder:validation:XValidation:rule="!has(self.dynamic) || self.isDynamic == true", message="dynamic can only be set when isDynamic is true"
 // +kubebuilder:validation:XValidation:rule="!(self.isDynamic == true && self.isBootstrap == true)", message="isDynamic cannot be set when isBootstrap is true"
+// +kubebuilder:validation:XValidation:rule="!has(self.autoscaling) || self.isDynamic == true", message="autoscaling can only be set when isDynamic is true"
 // +kubebuilder:validation:XValidation:rule="!self.isDynamic || !has(self.image) || size(self.image) == 0 || self.image.matches('^.+:(latest.*|((1[2-9]|[2-9][0-9])[.][0-9]+[.][0-9]+.*))$')", message="dynamic host group image override must use tag latest or MarkLogic major version 12+"
+// +kubebuilder:validation:XValidation:rule="!has(self.joinMode) || self.joinMode != 'operator' || !self.isDynamic", message="joinMode operator cannot be set when isDynamic is true"
 type MarklogicGroups struct {
 	// +kubebuilder:default:=1
 	Replicas *int32 `json:"replicas,omitempty"`
@@ -120,7 +123,15 @@ type MarklogicGroups struct {
 	// +kubebuilder:default:={enabled: true, initialDelaySeconds: 10, timeoutSeconds: 5, periodSeconds: 30, successThreshold: 1, failureThreshold: 3}
 	ReadinessProbe ContainerProbe `json:"readinessProbe,omitempty"`
 	LogCollection  *LogCollection `json:"logCollection,omitempty"`
+	Metrics        *ServerMetrics `json:"metrics,omitempty"`
 	HAProxy        *HAProxyGroup  `json:"haproxy,omitempty"`
+	// AppServers are created in the MarkLogic group of this entry once it exists.
+	// +kubebuilder:validation:MaxItems=32
+	// +kubebuilder:validation:XValidation:rule="self.all(a, self.exists_one(b, b.port == a.port))",message="appServers ports must be unique within a group"
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	AppServers []AppServer `json:"appServers,omitempty"`
 	// +kubebuilder:default:=false
 	IsBootstrap bool `json:"isBootstrap,omitempty"`
 	// +kubebuilder:default:=false
@@ -128,18 +139,51 @@ type MarklogicGroups struct {
 	// A field-level CEL rule using oldSelf is invalid here because markLogicGroups items are uncorrelatable.
 	IsDynamic bool `json:"isDynamic,omitempty"`
 	// +optional
-	Dynamic                        *DynamicGroupConfig             `json:"dynamic,omitempty"`
+	Dynamic *DynamicGroupConfig `json:"dynamic,omitempty"`
+	// Autoscaling hands the replicas of a dynamic group to an autoscaler. Without it the
+	// group still exposes the scale subresource, and replicas set there by an HPA or KEDA
+	// are kept until spec replicas change.
+	// +optional
+	Autoscaling *GroupAutoscaling `json:"autoscaling,omitempty"`
+	// JoinMode operator has the operator initialize and join the hosts of this group
+	// instead of cluster-config.sh.
+	// +kubebuilder:default:="script"
+	// +optional
+	JoinMode JoinMode `json:"joinMode,omitempty"`
+	// Maintenance pauses the reconcile of this group and drains its hosts from HAProxy
+	// through haproxy.runtimeAPI, which applies the drain without restarting HAProxy.
+	// The group is paused even while the cluster is paused; HAProxy is drained once the
+	// cluster is reconciled again.
+	// +optional
+	Maintenance bool `json:"maintenance,omitempty"`
+	// Failover keeps failover replicas of the forests on the hosts of this group.
+	// +optional
+	Failover *ForestFailover `json:"failover,omitempty"`
+	// +optional
+	PodDisruptionBudget            *PodDisruptionBudget            `json:"podDisruptionBudget,omitempty"`
 	Tls                            *Tls                            `json:"tls,omitempty"`
 	AdditionalVolumes              *[]corev1.Volume                `json:"additionalVolumes,omitempty"`
 	AdditionalVolumeMounts         *[]corev1.VolumeMount           `json:"additionalVolumeMounts,omitempty"`
 	AdditionalVolumeClaimTemplates *[]corev1.PersistentVolumeClaim `json:"additionalVolumeClaimTemplates,omitempty"`
 }
 
+// +kubebuilder:validation:XValidation:rule="!has(self.issuerRef) || (!has(self.certSecretNames) && !has(self.caSecretName))",message="issuerRef cannot be combined with certSecretNames or caSecretName"
 type Tls struct {
 	// +kubebuilder:default:=false
 	EnableOnDefaultAppServers bool     `json:"enableOnDefaultAppServers,omitempty"`
 	CertSecretNames           []string `json:"certSecretNames,omitempty"`
 	CaSecretName              string   `json:"caSecretName,omitempty"`
+	// IssuerRef asks cert-manager to issue the host certificates instead of reading them from
+	// certSecretNames and caSecretName. The operator creates one Certificate per group covering
+	// every pod FQDN and the group Services. The issuer must publish ca.crt in the issued secret.
+	// +optional
+	IssuerRef *CertificateIssuerRef `json:"issuerRef,omitempty"`
+	// ExpiryWarningDays is how long before the host certificates expire the operator starts
+	// warning about it.
+	// +kubebuilder:default:=30
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ExpiryWarningDays int32 `json:"expiryWarningDays,omitempty"`
 }
 
 // MarklogicClusterStatus defines the observed state of MarklogicCluster
@@ -147,11 +191,106 @@ type MarklogicClusterStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// ObservedGeneration is the cluster generation the rollup below was computed for.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Replicas is the desired number of MarkLogic hosts across all groups.
+	Replicas int32 `json:"replicas,omitempty"`
+	// ReadyReplicas is the number of ready MarkLogic hosts across all groups.
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+	// Hosts is ReadyReplicas/Replicas, kept as a string for the printer column.
+	Hosts string `json:"hosts,omitempty"`
+	// MarkLogicVersion is the version shared by all groups, or "mixed" while they differ.
+	MarkLogicVersion string `json:"markLogicVersion,omitempty"`
+	// Groups is a per-group rollup of the owned MarklogicGroup status.
+	Groups []MarklogicGroupRollup `json:"groups,omitempty"`
+	// Upgrade tracks an orchestrated MarkLogic image upgrade so an interrupted
+	// upgrade resumes with the group it stopped at.
+	// +optional
+	Upgrade *UpgradeStatus `json:"upgrade,omitempty"`
+	// PasswordRotation records the last change of the MarkLogic admin password.
+	// +optional
+	PasswordRotation *PasswordRotationStatus `json:"passwordRotation,omitempty"`
+}
+
+// PasswordRotationStatus describes the admin password rotation of the cluster.
+type PasswordRotationStatus struct {
+	// LastRotationTime is when the admin password was last changed in MarkLogic, or when
+	// rotation was enabled.
+	LastRotationTime *metav1.Time `json:"lastRotationTime,omitempty"`
+	// NextRotationTime is when rotationPeriod generates the next password.
+	// +optional
+	NextRotationTime *metav1.Time `json:"nextRotationTime,omitempty"`
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+type UpgradePhase string
+
+const (
+	UpgradePhaseInProgress UpgradePhase = "InProgress"
+	UpgradePhaseCompleted  UpgradePhase = "Completed"
+)
+
+type UpgradeGroupState string
+
+const (
+	UpgradeGroupStatePending   UpgradeGroupState = "Pending"
+	UpgradeGroupStateRolling   UpgradeGroupState = "Rolling"
+	UpgradeGroupStateVerifying UpgradeGroupState = "Verifying"
+	UpgradeGroupStateCompleted UpgradeGroupState = "Completed"
+)
+
+// UpgradeStatus records the group-by-group progress of an image upgrade.
+type UpgradeStatus struct {
+	// +kubebuilder:validation:Enum=InProgress;Completed
+	Phase              UpgradePhase         `json:"phase,omitempty"`
+	CurrentGroup       string               `json:"currentGroup,omitempty"`
+	Message            string               `json:"message,omitempty"`
+	Groups             []UpgradeGroupStatus `json:"groups,omitempty"`
+	StartedTime        *metav1.Time         `json:"startedTime,omitempty"`
+	CompletionTime     *metav1.Time         `json:"completionTime,omitempty"`
+	LastTransitionTime *metav1.Time         `json:"lastTransitionTime,omitempty"`
+}
+
+// UpgradeGroupStatus is the upgrade state of a single group, in upgrade order.
+type UpgradeGroupStatus struct {
+	Name      string `json:"name"`
+	FromImage string `json:"fromImage,omitempty"`
+	ToImage   string `json:"toImage,omitempty"`
+	// TargetVersion is the MarkLogic version every host of the group must report before
+	// the next group starts. Empty when the image tag does not carry a version.
+	TargetVersion string `json:"targetVersion,omitempty"`
+	// +kubebuilder:validation:Enum=Pending;Rolling;Verifying;Completed
+	State   UpgradeGroupState `json:"state,omitempty"`
+	Message string            `json:"message,omitempty"`
+}
+
+// MarklogicGroupRollup summarizes the status of one owned MarklogicGroup.
+type MarklogicGroupRollup struct {
+	Name             string `json:"name"`
+	IsBootstrap      bool   `json:"isBootstrap,omitempty"`
+	IsDynamic        bool   `json:"isDynamic,omitempty"`
+	Replicas         int32  `json:"replicas,omitempty"`
+	ReadyReplicas    int32  `json:"readyReplicas,omitempty"`
+	Stage            string `json:"stage,omitempty"`
+	DynamicPhase     string `json:"dynamicPhase,omitempty"`
+	MarkLogicVersion string `json:"markLogicVersion,omitempty"`
+	// VolumeResizePhase is only set while a volume resize is in flight or has failed.
+	VolumeResizePhase VolumeResizePhase `json:"volumeResizePhase,omitempty"`
+	// DecommissionPhase is set while a scale-down is removing a host.
+	DecommissionPhase DecommissionPhase `json:"decommissionPhase,omitempty"`
+	// Paused is set while the operator leaves the group alone.
+	Paused bool `json:"paused,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:metadata:annotations="helm.sh/resource-policy=keep"
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+//+kubebuilder:printcolumn:name="Hosts",type="string",JSONPath=".status.hosts"
+//+kubebuilder:printcolumn:name="Version",type="string",JSONPath=".status.markLogicVersion"
+//+kubebuilder:printcolumn:name="Progressing",type="string",JSONPath=".status.conditions[?(@.type=='Progressing')].reason",priority=1
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // MarklogicCluster is the Schema for the marklogicclusters API
 type MarklogicCluster struct {
@@ -183,4 +322,18 @@ const (
 	ClusterScalingDown  MarkLogicConditionType = "Resuming"
 	ClusterDecommission MarkLogicConditionType = "Decommission"
 	ClusterUpdating     MarkLogicConditionType = "Updating"
+	ClusterProgressing  MarkLogicConditionType = "Progressing"
+	ClusterDegraded     MarkLogicConditionType = "Degraded"
+	// ClusterAppServersReady is set when groups declare appServers; False while any of
+	// them could not be created or updated.
+	ClusterAppServersReady MarkLogicConditionType = "AppServersReady"
+	// ClusterPaused is True while the marklogic.progress.com/paused annotation stops the
+	// cluster reconcile.
+	ClusterPaused MarkLogicConditionType = "Paused"
+	// ClusterHAProxyConfigValid is set when HAProxy configuration snippets are used; False
+	// while haproxy -c rejects the rendered configuration, which is then not rolled out.
+	ClusterHAProxyConfigValid MarkLogicConditionType = "HAProxyConfigValid"
+	// ClusterGatewayRoutesReady is set while haproxy.gateway is enabled; False while a route
+	// could not be applied. The operator only looks for stale routes while it is present.
+	ClusterGatewayRoutesReady MarkLogicConditionType = "GatewayRoutesReady"
 )
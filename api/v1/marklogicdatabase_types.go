@@ -0,0 +1,183 @@
+/*
+Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MarklogicDatabaseSpec defines the desired state of MarklogicDatabase
+type MarklogicDatabaseSpec struct {
+	// ClusterRef names the MarklogicCluster, in the same namespace, that hosts the database.
+	ClusterRef corev1.LocalObjectReference `json:"clusterRef"`
+	// DatabaseName is the name of the database in MarkLogic.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="databaseName is immutable"
+	DatabaseName string `json:"databaseName"`
+	// +kubebuilder:default:={perHost: 1}
+	Forests DatabaseForests `json:"forests,omitempty"`
+	// Indexes are applied to the database properties. Settings left unset keep the
+	// server's value.
+	// +optional
+	Indexes *DatabaseIndexes `json:"indexes,omitempty"`
+	// Replicas is the number of replica forests kept for each forest, each on a
+	// different host of the selected groups.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Replicas int32 `json:"replicas,omitempty"`
+	// DeletionPolicy decides whether deleting the resource also deletes the database and
+	// the data of its forests.
+	// +kubebuilder:validation:Enum=Retain;Delete
+	// +kubebuilder:default:=Retain
+	DeletionPolicy DatabaseDeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+type DatabaseDeletionPolicy string
+
+const (
+	DatabaseDeletionPolicyRetain DatabaseDeletionPolicy = "Retain"
+	DatabaseDeletionPolicyDelete DatabaseDeletionPolicy = "Delete"
+)
+
+// DatabaseForests places forests on every host of the selected groups. Forests are
+// named <databaseName>-<pod name>-<n>, so hosts added later get their forests too.
+type DatabaseForests struct {
+	// PerHost is the number of forests created on each host.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default:=1
+	PerHost int32 `json:"perHost,omitempty"`
+	// Groups lists the spec.markLogicGroups entries of the cluster whose hosts hold
+	// forests. Every group except the dynamic ones is used when empty.
+	// +optional
+	Groups []string `json:"groups,omitempty"`
+	// DataDirectory is the forest data directory on the hosts. MarkLogic's default is used when empty.
+	// +optional
+	DataDirectory string `json:"dataDirectory,omitempty"`
+}
+
+type DatabaseIndexes struct {
+	// +kubebuilder:validation:Enum=off;basic;advanced;decompounding
+	// +optional
+	StemmedSearches string `json:"stemmedSearches,omitempty"`
+	// +optional
+	WordSearches *bool `json:"wordSearches,omitempty"`
+	// +optional
+	FastPhraseSearches *bool `json:"fastPhraseSearches,omitempty"`
+	// +optional
+	TripleIndex *bool `json:"tripleIndex,omitempty"`
+	// +optional
+	CollectionLexicon *bool `json:"collectionLexicon,omitempty"`
+	// +optional
+	URILexicon *bool `json:"uriLexicon,omitempty"`
+	// RangeElementIndexes, when set, is the complete list of element range indexes of
+	// the database.
+	// +optional
+	RangeElementIndexes []RangeElementIndex `json:"rangeElementIndexes,omitempty"`
+	// RangePathIndexes, when set, is the complete list of path range indexes of the database.
+	// +optional
+	RangePathIndexes []RangePathIndex `json:"rangePathIndexes,omitempty"`
+}
+
+type RangeElementIndex struct {
+	// +kubebuilder:validation:Enum=int;unsignedInt;long;unsignedLong;float;double;decimal;dateTime;time;date;gYearMonth;gYear;gMonth;gDay;yearMonthDuration;dayTimeDuration;string;anyURI
+	ScalarType string `json:"scalarType"`
+	// +optional
+	NamespaceURI string `json:"namespaceURI,omitempty"`
+	// +kubebuilder:validation:MinLength=1
+	LocalName string `json:"localName"`
+	// Collation applies to string and anyURI indexes; the root collation is used when empty.
+	// +optional
+	Collation string `json:"collation,omitempty"`
+	// +optional
+	RangeValuePositions bool `json:"rangeValuePositions,omitempty"`
+}
+
+type RangePathIndex struct {
+	// +kubebuilder:validation:Enum=int;unsignedInt;long;unsignedLong;float;double;decimal;dateTime;time;date;gYearMonth;gYear;gMonth;gDay;yearMonthDuration;dayTimeDuration;string;anyURI
+	ScalarType string `json:"scalarType"`
+	// +kubebuilder:validation:MinLength=1
+	PathExpression string `json:"pathExpression"`
+	// Collation applies to string and anyURI indexes; the root collation is used when empty.
+	// +optional
+	Collation string `json:"collation,omitempty"`
+	// +optional
+	RangeValuePositions bool `json:"rangeValuePositions,omitempty"`
+}
+
+// DatabaseForestStatus is a forest of the database and its replicas.
+type DatabaseForestStatus struct {
+	Name string `json:"name"`
+	Host string `json:"host"`
+	// +optional
+	Replicas []DatabaseForestReplica `json:"replicas,omitempty"`
+}
+
+type DatabaseForestReplica struct {
+	Name string `json:"name"`
+	Host string `json:"host"`
+}
+
+// MarklogicDatabaseStatus defines the observed state of MarklogicDatabase
+type MarklogicDatabaseStatus struct {
+	Conditions         []metav1.Condition `json:"conditions,omitempty"`
+	ObservedGeneration int64              `json:"observedGeneration,omitempty"`
+	// Forests are the forests the operator manages for the database.
+	Forests []DatabaseForestStatus `json:"forests,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:metadata:annotations="helm.sh/resource-policy=keep"
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:shortName=mldb
+//+kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.clusterRef.name"
+//+kubebuilder:printcolumn:name="Database",type="string",JSONPath=".spec.databaseName"
+//+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+//+kubebuilder:printcolumn:name="Drifted",type="string",JSONPath=".status.conditions[?(@.type==\"Drifted\")].status"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// MarklogicDatabase is the Schema for the marklogicdatabases API
+type MarklogicDatabase struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MarklogicDatabaseSpec   `json:"spec,omitempty"`
+	Status MarklogicDatabaseStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// MarklogicDatabaseList contains a list of MarklogicDatabase
+type MarklogicDatabaseList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MarklogicDatabase `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MarklogicDatabase{}, &MarklogicDatabaseList{})
+}
+
+// Observed State for MarkLogic Database
+const (
+	// DatabaseReady is True once the database exists with all of its planned forests.
+	DatabaseReady MarkLogicConditionType = "Ready"
+	// DatabaseDrifted is True when the server configuration differed from the spec on the
+	// last check. Index settings and replica configuration are corrected; forests the
+	// operator does not manage, or that live on another host, are only reported.
+	DatabaseDrifted MarkLogicConditionType = "Drifted"
+)
@@ -26,7 +26,11 @@ import (
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
 // +kubebuilder:validation:XValidation:rule="!has(self.dynamic) || self.isDynamic == true", message="dynamic can only be set when isDynamic is true"
+// +kubebuilder:validation:XValidation:rule="!has(self.autoscaling) || self.isDynamic == true", message="autoscaling can only be set when isDynamic is true"
 // +kubebuilder:validation:XValidation:rule="!self.isDynamic || self.image.matches('^.+:(latest.*|((1[2-9]|[2-9][0-9])[.][0-9]+[.][0-9]+.*))$')", message="dynamic hosts require image tag latest or MarkLogic major version 12+"
+// +kubebuilder:validation:XValidation:rule="!has(self.joinMode) || self.joinMode != 'operator' || !self.isDynamic", message="joinMode operator cannot be set when isDynamic is true"
+// +kubebuilder:validation:XValidation:rule="!has(self.joinMode) || self.joinMode != 'operator' || !has(self.tls) || !self.tls.enableOnDefaultAppServers", message="joinMode operator does not support tls.enableOnDefaultAppServers"
+// +kubebuilder:validation:XValidation:rule="!has(self.joinMode) || self.joinMode != 'operator' || ((!has(self.license) || !has(self.license.csi)) && !has(self.adminCredentialsCSI))", message="joinMode operator needs the admin credentials and license in secrets, not csi"
 // MarklogicGroupSpec defines the desired state of MarklogicGroup
 type MarklogicGroupSpec struct {
 	// +kubebuilder:default:=1
@@ -69,13 +73,30 @@ type MarklogicGroupSpec struct {
 	ReadinessProbe ContainerProbe `json:"readinessProbe,omitempty"`
 	// +kubebuilder:default:={enabled: false, image: "fluent/fluent-bit:4.1.1", resources: {requests: {cpu: "100m", memory: "200Mi"}, limits: {cpu: "200m", memory: "500Mi"}}, files: {errorLogs: true, accessLogs: true, requestLogs: true}, outputs: "stdout"}
 	LogCollection *LogCollection `json:"logCollection,omitempty"`
+	Metrics       *ServerMetrics `json:"metrics,omitempty"`
 	// +kubebuilder:default:={name: "Default", enableXdqpSsl: true}
 	GroupConfig *GroupConfig `json:"groupConfig,omitempty"`
 	// +kubebuilder:default:=false
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="isDynamic is immutable after creation"
 	IsDynamic bool `json:"isDynamic,omitempty"`
 	// +optional
-	Dynamic                        *DynamicGroupConfig             `json:"dynamic,omitempty"`
+	Dynamic *DynamicGroupConfig `json:"dynamic,omitempty"`
+	// +optional
+	Autoscaling *GroupAutoscaling `json:"autoscaling,omitempty"`
+	// +kubebuilder:default:="script"
+	// +optional
+	JoinMode JoinMode `json:"joinMode,omitempty"`
+	// Maintenance stops the operator from changing the group while MarkLogic is maintained
+	// by hand. Set through the MarklogicCluster, it also drains the hosts of the group from
+	// HAProxy through spec.haproxy.runtimeAPI; without the runtime API HAProxy keeps
+	// sending them traffic. The cluster passes the setting on to the group even while the
+	// cluster is paused, but only drains HAProxy while it is not.
+	// +optional
+	Maintenance bool `json:"maintenance,omitempty"`
+	// +optional
+	Failover *ForestFailover `json:"failover,omitempty"`
+	// +optional
+	PodDisruptionBudget            *PodDisruptionBudget            `json:"podDisruptionBudget,omitempty"`
 	License                        *License                        `json:"license,omitempty"`
 	EnableConverters               bool                            `json:"enableConverters,omitempty"`
 	BootstrapHost                  string                          `json:"bootstrapHost,omitempty"`
@@ -86,7 +107,15 @@ type MarklogicGroupSpec struct {
 	AdditionalVolumeMounts         *[]corev1.VolumeMount           `json:"additionalVolumeMounts,omitempty"`
 	AdditionalVolumeClaimTemplates *[]corev1.PersistentVolumeClaim `json:"additionalVolumeClaimTemplates,omitempty"`
 	SecretName                     string                          `json:"secretName,omitempty"`
-	Tls                            *Tls                            `json:"tls,omitempty"`
+	// AdminCredentialsCSI mounts the admin credentials from the Secrets Store CSI driver
+	// instead of the secret named by secretName.
+	// +optional
+	AdminCredentialsCSI *corev1.CSIVolumeSource `json:"adminCredentialsCSI,omitempty"`
+	Tls                 *Tls                    `json:"tls,omitempty"`
+	// AppServers declared for the group on the MarklogicCluster; their ports are added to
+	// the group Services.
+	// +optional
+	AppServers []AppServer `json:"appServers,omitempty"`
 }
 
 // InternalState defines the observed state of MarklogicGroup
@@ -207,11 +236,167 @@ type MarklogicGroupStatus struct {
 	Stage              string                   `json:"stage,omitempty"`
 	MarkLogicPods      []corev1.ObjectReference `json:"active,omitempty"`
 	VolumeResizeStatus *VolumeResizeStatus      `json:"volumeResizeStatus,omitempty"`
+	// Replicas and ReadyReplicas mirror the StatefulSet status.
+	Replicas      int32 `json:"replicas,omitempty"`
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+	// Selector is the pod label selector the scale subresource reports to autoscalers.
+	// +optional
+	Selector string `json:"selector,omitempty"`
+	// +optional
+	Autoscaling *AutoscalingStatus `json:"autoscaling,omitempty"`
+	// Certificates are the host certificates installed from the TLS secrets of the group.
+	// +optional
+	Certificates []CertificateStatus `json:"certificates,omitempty"`
 
 	// +optional
 	MarklogicGroupStatus InternalState `json:"markLogicGroupStatus,omitempty"`
 	// +optional
 	Dynamic *DynamicGroupStatus `json:"dynamic,omitempty"`
+	// Decommission tracks the host a scale-down is removing from the cluster. It is
+	// cleared once the StatefulSet has shrunk to the desired replicas.
+	// +optional
+	Decommission *DecommissionStatus `json:"decommission,omitempty"`
+	// Join reports how far the operator got initializing and joining the hosts of a group
+	// with joinMode operator.
+	// +optional
+	Join *JoinStatus `json:"join,omitempty"`
+	// Failover reports the failover replicas of the forests on the hosts of the group.
+	// +optional
+	Failover *FailoverStatus `json:"failover,omitempty"`
+}
+
+type JoinPhase string
+
+const (
+	JoinPhasePending               JoinPhase = "Pending"
+	JoinPhaseInitializingBootstrap JoinPhase = "InitializingBootstrap"
+	JoinPhaseConfiguringGroup      JoinPhase = "ConfiguringGroup"
+	JoinPhaseJoiningHosts          JoinPhase = "JoiningHosts"
+	JoinPhaseJoined                JoinPhase = "Joined"
+)
+
+// JoinStatus describes the cluster join driven by the operator.
+type JoinStatus struct {
+	Phase   JoinPhase `json:"phase,omitempty"`
+	Message string    `json:"message,omitempty"`
+	// ConfiguredGeneration is the generation of the MarklogicGroup whose group properties
+	// were last applied to MarkLogic.
+	// +optional
+	ConfiguredGeneration int64            `json:"configuredGeneration,omitempty"`
+	Hosts                []HostJoinStatus `json:"hosts,omitempty"`
+}
+
+// HostJoinStatus is the progress of one pod of the group.
+type HostJoinStatus struct {
+	PodName     string       `json:"podName,omitempty"`
+	Hostname    string       `json:"hostname,omitempty"`
+	State       string       `json:"state,omitempty"`
+	Message     string       `json:"message,omitempty"`
+	Attempts    int32        `json:"attempts,omitempty"`
+	LastUpdated *metav1.Time `json:"lastUpdated,omitempty"`
+}
+
+// CertificateStatus describes the host certificate currently held by a TLS secret. A new
+// certificate in the secret is pushed to the MarkLogic certificate template without
+// restarting the pods.
+type CertificateStatus struct {
+	SecretName string `json:"secretName"`
+	// Subject is the common name, or the first DNS name of certificates without one.
+	Subject      string       `json:"subject,omitempty"`
+	SerialNumber string       `json:"serialNumber,omitempty"`
+	NotAfter     *metav1.Time `json:"notAfter,omitempty"`
+	// Fingerprint is the SHA-256 fingerprint of the certificate.
+	Fingerprint string `json:"fingerprint,omitempty"`
+	// LastRotationTime is when the operator last pushed a certificate from this secret.
+	// +optional
+	LastRotationTime *metav1.Time `json:"lastRotationTime,omitempty"`
+}
+
+type DecommissionPhase string
+
+const (
+	DecommissionPhaseEvacuating   DecommissionPhase = "EvacuatingForests"
+	DecommissionPhaseRemovingHost DecommissionPhase = "RemovingHost"
+	DecommissionPhaseHostRemoved  DecommissionPhase = "HostRemoved"
+	DecommissionPhaseBlocked      DecommissionPhase = "Blocked"
+)
+
+// DecommissionStatus describes the removal of the highest-ordinal host of a non-dynamic
+// group. The StatefulSet keeps the pod until the host has left the cluster.
+type DecommissionStatus struct {
+	PodName string            `json:"podName"`
+	Host    string            `json:"host,omitempty"`
+	Phase   DecommissionPhase `json:"phase,omitempty"`
+	Message string            `json:"message,omitempty"`
+	// Forests are the forests migrated off the host.
+	Forests   []DecommissionForest `json:"forests,omitempty"`
+	StartTime *metav1.Time         `json:"startTime,omitempty"`
+}
+
+type ForestFailoverPhase string
+
+const (
+	// ForestFailoverProtected forests are open with every replica synchronized.
+	ForestFailoverProtected ForestFailoverPhase = "Protected"
+	// ForestFailoverSynchronizing forests have replicas still catching up.
+	ForestFailoverSynchronizing ForestFailoverPhase = "Synchronizing"
+	// ForestFailoverFailedOver forests are served by a replica.
+	ForestFailoverFailedOver ForestFailoverPhase = "FailedOver"
+	// ForestFailoverUnprotected forests have fewer replicas than the failover policy asks for.
+	ForestFailoverUnprotected ForestFailoverPhase = "Unprotected"
+)
+
+// FailoverStatus describes the forests whose master forest is on a host of the group.
+type FailoverStatus struct {
+	// TopologyKey is the label the zones were read from.
+	TopologyKey      string                 `json:"topologyKey,omitempty"`
+	ProtectedForests int32                  `json:"protectedForests"`
+	Forests          []ForestFailoverStatus `json:"forests,omitempty"`
+}
+
+type ForestFailoverStatus struct {
+	Name     string `json:"name"`
+	Database string `json:"database,omitempty"`
+	Host     string `json:"host,omitempty"`
+	Zone     string `json:"zone,omitempty"`
+	// State is the forest state MarkLogic reports, such as open or sync replicating.
+	State    string                  `json:"state,omitempty"`
+	Phase    ForestFailoverPhase     `json:"phase,omitempty"`
+	Replicas []FailoverReplicaStatus `json:"replicas,omitempty"`
+	Message  string                  `json:"message,omitempty"`
+}
+
+type FailoverReplicaStatus struct {
+	Name  string `json:"name"`
+	Host  string `json:"host,omitempty"`
+	Zone  string `json:"zone,omitempty"`
+	State string `json:"state,omitempty"`
+}
+
+// AutoscalingStatus reports the last evaluation of the group's autoscaling policy.
+type AutoscalingStatus struct {
+	Metric AutoscalingMetric `json:"metric,omitempty"`
+	// CurrentRequestsPerSecond is the request rate last read for the group.
+	// +optional
+	CurrentRequestsPerSecond string `json:"currentRequestsPerSecond,omitempty"`
+	DesiredReplicas          int32  `json:"desiredReplicas,omitempty"`
+	// ScaleDownSince is when recommendations dropped below the current replicas, and
+	// ScaleDownReplicas the highest recommendation since then.
+	// +optional
+	ScaleDownSince    *metav1.Time `json:"scaleDownSince,omitempty"`
+	ScaleDownReplicas int32        `json:"scaleDownReplicas,omitempty"`
+	LastScaleTime     *metav1.Time `json:"lastScaleTime,omitempty"`
+	Message           string       `json:"message,omitempty"`
+}
+
+type DecommissionForest struct {
+	Name       string `json:"name"`
+	TargetHost string `json:"targetHost"`
+	// Attempts counts the migrations issued for the forest, and MigrationTime is when
+	// the last one was issued.
+	// +optional
+	Attempts      int32        `json:"attempts,omitempty"`
+	MigrationTime *metav1.Time `json:"migrationTime,omitempty"`
 }
 
 type DynamicGroupStatus struct {
@@ -268,6 +453,18 @@ func (status *MarklogicGroupStatus) GetConditionStatus(conditionType string) met
 	return metav1.ConditionUnknown
 }
 
+// JoinMode selects who initializes the hosts of a group and joins them to the cluster.
+// With script, each pod runs cluster-config.sh on startup. With operator, the MarklogicGroup
+// controller does it through the Admin and Management APIs and reports each host in
+// status.join. TLS on the default App Servers is only configured by the script.
+// +kubebuilder:validation:Enum=script;operator
+type JoinMode string
+
+const (
+	JoinModeScript   JoinMode = "script"
+	JoinModeOperator JoinMode = "operator"
+)
+
 type GroupConfig struct {
 	// +kubebuilder:default:="Default"
 	Name string `json:"name,omitempty"`
@@ -275,14 +472,35 @@ type GroupConfig struct {
 	EnableXdqpSsl bool `json:"enableXdqpSsl,omitempty"`
 }
 
+// +kubebuilder:validation:XValidation:rule="!has(self.secretRef) || !has(self.csi)",message="secretRef and csi are mutually exclusive"
+// +kubebuilder:validation:XValidation:rule="(!has(self.secretRef) && !has(self.csi)) || ((!has(self.key) || size(self.key) == 0) && (!has(self.licensee) || size(self.licensee) == 0))",message="key and licensee cannot be combined with secretRef or csi"
 type License struct {
+	// Key is passed to the pods in the LICENSE_KEY environment variable. Prefer secretRef or csi.
 	Key      string `json:"key,omitempty"`
 	Licensee string `json:"licensee,omitempty"`
+	// SecretRef mounts the license key and licensee from a secret as files.
+	// +optional
+	SecretRef *LicenseSecretRef `json:"secretRef,omitempty"`
+	// CSI mounts the license from the Secrets Store CSI driver. The SecretProviderClass must
+	// provide the files license-key and licensee.
+	// +optional
+	CSI *corev1.CSIVolumeSource `json:"csi,omitempty"`
+}
+
+// LicenseSecretRef selects the keys of a secret holding the MarkLogic license.
+type LicenseSecretRef struct {
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// +kubebuilder:default:="license-key"
+	LicenseKeyKey string `json:"licenseKeyKey,omitempty"`
+	// +kubebuilder:default:="licensee"
+	LicenseeKey string `json:"licenseeKey,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:metadata:annotations="helm.sh/resource-policy=keep"
 //+kubebuilder:subresource:status
+//+kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.replicas,selectorpath=.status.selector
 
 // MarklogicGroup is the Schema for the marklogicgroup API
 type MarklogicGroup struct {
@@ -316,6 +534,18 @@ const (
 	ServerResuming     MarkLogicConditionType = "Resuming"
 	ServerDecommission MarkLogicConditionType = "Decommission"
 	ServerUpdating     MarkLogicConditionType = "Updating"
+	// GroupHostsJoined is True once the operator has joined every host of a group with
+	// joinMode operator.
+	GroupHostsJoined MarkLogicConditionType = "HostsJoined"
+	// GroupCertificateExpiring is True while a host certificate of the group is within
+	// tls.expiryWarningDays of its expiry.
+	GroupCertificateExpiring MarkLogicConditionType = "CertificateExpiring"
+	// GroupPaused is True while the group, or the cluster that owns it, is paused by the
+	// marklogic.progress.com/paused annotation or the group is in maintenance.
+	GroupPaused MarkLogicConditionType = "Paused"
+	// GroupFailoverReady is True while every forest on the hosts of a group with a
+	// failover policy is protected by synchronized replicas.
+	GroupFailoverReady MarkLogicConditionType = "FailoverReady"
 )
 
 // Internal State for MarkLogic Server
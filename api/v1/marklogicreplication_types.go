@@ -0,0 +1,149 @@
+/*
+Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MarklogicReplicationSpec defines the desired state of MarklogicReplication
+type MarklogicReplicationSpec struct {
+	// Source is the master cluster whose databases are replicated.
+	Source ReplicationEndpoint `json:"source"`
+	// Target is the replica cluster that receives the journal frames.
+	Target ReplicationEndpoint `json:"target"`
+	// Databases lists the databases replicated from the source to the target. Both
+	// databases must exist and have forests with the same names.
+	// +kubebuilder:validation:MinItems=1
+	Databases []ReplicatedDatabase `json:"databases"`
+	// LagLimit is the number of seconds the replicas may fall behind before the source
+	// stops committing transactions.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default:=15
+	LagLimit int32 `json:"lagLimit,omitempty"`
+	// Suspended keeps the configuration in place but stops shipping journal frames.
+	// +optional
+	Suspended bool `json:"suspended,omitempty"`
+}
+
+// ReplicationEndpoint is a MarklogicCluster managed by an operator or a MarkLogic cluster
+// reached through its Management API.
+// +kubebuilder:validation:XValidation:rule="has(self.clusterRef) != has(self.external)",message="exactly one of clusterRef or external must be set"
+type ReplicationEndpoint struct {
+	// +optional
+	ClusterRef *ReplicationClusterRef `json:"clusterRef,omitempty"`
+	// +optional
+	External *ExternalCluster `json:"external,omitempty"`
+}
+
+type ReplicationClusterRef struct {
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Namespace of the MarklogicCluster; the namespace of the MarklogicReplication when empty.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// ExternalCluster is a MarkLogic cluster outside this Kubernetes cluster.
+type ExternalCluster struct {
+	// Host is the host[:port] of the Management API; port 8002 is used when omitted.
+	// +kubebuilder:validation:MinLength=1
+	Host string `json:"host"`
+	// SecretName is a secret with username and password keys, in the namespace of the
+	// MarklogicReplication.
+	// +kubebuilder:validation:MinLength=1
+	SecretName string `json:"secretName"`
+	// UseTLS connects over HTTPS. The certificate must be trusted by the system roots of
+	// the operator.
+	// +optional
+	UseTLS bool `json:"useTLS,omitempty"`
+}
+
+type ReplicatedDatabase struct {
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// TargetName is the database on the target; the source name is used when empty.
+	// +optional
+	TargetName string `json:"targetName,omitempty"`
+}
+
+// DatabaseReplicationState is the replication of one database as seen from the source.
+type DatabaseReplicationState struct {
+	Name       string `json:"name"`
+	TargetName string `json:"targetName"`
+	// State is the replication state MarkLogic reports for the database, or
+	// DatabaseNotFound while the database is missing on either cluster.
+	State string `json:"state,omitempty"`
+	// LagSeconds is the largest lag of the forests of the database.
+	// +optional
+	LagSeconds int64 `json:"lagSeconds,omitempty"`
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// MarklogicReplicationStatus defines the observed state of MarklogicReplication
+type MarklogicReplicationStatus struct {
+	Conditions         []metav1.Condition `json:"conditions,omitempty"`
+	ObservedGeneration int64              `json:"observedGeneration,omitempty"`
+	// SourceClusterName and TargetClusterName are the MarkLogic names of the coupled clusters.
+	// +optional
+	SourceClusterName string `json:"sourceClusterName,omitempty"`
+	// +optional
+	TargetClusterName string `json:"targetClusterName,omitempty"`
+	// +optional
+	Databases []DatabaseReplicationState `json:"databases,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:metadata:annotations="helm.sh/resource-policy=keep"
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:shortName=mlrepl
+//+kubebuilder:printcolumn:name="Source",type="string",JSONPath=".status.sourceClusterName"
+//+kubebuilder:printcolumn:name="Target",type="string",JSONPath=".status.targetClusterName"
+//+kubebuilder:printcolumn:name="Coupled",type="string",JSONPath=".status.conditions[?(@.type==\"Coupled\")].status"
+//+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// MarklogicReplication is the Schema for the marklogicreplications API
+type MarklogicReplication struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MarklogicReplicationSpec   `json:"spec,omitempty"`
+	Status MarklogicReplicationStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// MarklogicReplicationList contains a list of MarklogicReplication
+type MarklogicReplicationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MarklogicReplication `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MarklogicReplication{}, &MarklogicReplicationList{})
+}
+
+// Observed State for MarkLogic Replication
+const (
+	// ReplicationCoupled is True once each cluster knows the other as a foreign cluster.
+	ReplicationCoupled MarkLogicConditionType = "Coupled"
+	// ReplicationReady is True while every database replicates within the lag limit.
+	ReplicationReady MarkLogicConditionType = "Ready"
+)
@@ -0,0 +1,100 @@
+/*
+Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MarklogicRestoreSpec defines the desired state of MarklogicRestore
+// +kubebuilder:validation:XValidation:rule="has(self.backupRef) != (has(self.path) && size(self.path) > 0)", message="exactly one of backupRef or path must be set"
+// +kubebuilder:validation:XValidation:rule="has(self.backupRef) || (has(self.databases) && size(self.databases) > 0)", message="databases must be set when restoring from a path"
+type MarklogicRestoreSpec struct {
+	// ClusterRef names the MarklogicCluster, in the same namespace, the databases are restored into.
+	ClusterRef corev1.LocalObjectReference `json:"clusterRef"`
+	// BackupRef names a MarklogicBackup to restore from, using its storage layout.
+	// +optional
+	BackupRef *corev1.LocalObjectReference `json:"backupRef,omitempty"`
+	// Path is a directory holding one backup directory per database (<path>/<database>),
+	// used when the backup was not taken by a MarklogicBackup.
+	// +kubebuilder:validation:Pattern=`^/`
+	// +optional
+	Path string `json:"path,omitempty"`
+	// Databases to restore. Defaults to the databases of the referenced MarklogicBackup.
+	// +optional
+	Databases []string `json:"databases,omitempty"`
+	// RestoreToTime selects the newest backup taken at or before this time. The newest
+	// backup is restored when unset.
+	// +optional
+	RestoreToTime *metav1.Time `json:"restoreToTime,omitempty"`
+	// +kubebuilder:default:=true
+	IncludeReplicas *bool `json:"includeReplicas,omitempty"`
+}
+
+type RestorePhase string
+
+const (
+	RestorePhasePending   RestorePhase = "Pending"
+	RestorePhaseRunning   RestorePhase = "Running"
+	RestorePhaseCompleted RestorePhase = "Completed"
+	RestorePhaseFailed    RestorePhase = "Failed"
+)
+
+// MarklogicRestoreStatus defines the observed state of MarklogicRestore
+type MarklogicRestoreStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// +kubebuilder:validation:Enum=Pending;Running;Completed;Failed
+	Phase          RestorePhase        `json:"phase,omitempty"`
+	StartTime      *metav1.Time        `json:"startTime,omitempty"`
+	CompletionTime *metav1.Time        `json:"completionTime,omitempty"`
+	Databases      []DatabaseJobStatus `json:"databases,omitempty"`
+	Message        string              `json:"message,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:metadata:annotations="helm.sh/resource-policy=keep"
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:shortName=mlrestore
+//+kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.clusterRef.name"
+//+kubebuilder:printcolumn:name="Backup",type="string",JSONPath=".spec.backupRef.name"
+//+kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// MarklogicRestore is the Schema for the marklogicrestores API. A restore runs once;
+// create a new MarklogicRestore to restore again.
+type MarklogicRestore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf", message="MarklogicRestore spec is immutable"
+	Spec   MarklogicRestoreSpec   `json:"spec,omitempty"`
+	Status MarklogicRestoreStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// MarklogicRestoreList contains a list of MarklogicRestore
+type MarklogicRestoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MarklogicRestore `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MarklogicRestore{}, &MarklogicRestoreList{})
+}
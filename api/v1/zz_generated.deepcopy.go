@@ -25,6 +25,7 @@ import (
 	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -50,6 +51,16 @@ func (in *AdminAuth) DeepCopyInto(out *AdminAuth) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.CSI != nil {
+		in, out := &in.CSI, &out.CSI
+		*out = new(corev1.CSIVolumeSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PasswordRotation != nil {
+		in, out := &in.PasswordRotation, &out.PasswordRotation
+		*out = new(PasswordRotation)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdminAuth.
@@ -62,9 +73,39 @@ func (in *AdminAuth) DeepCopy() *AdminAuth {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppServer) DeepCopyInto(out *AppServer) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppServer.
+func (in *AppServer) DeepCopy() *AppServer {
+	if in == nil {
+		return nil
+	}
+	out := new(AppServer)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AppServers) DeepCopyInto(out *AppServers) {
 	*out = *in
+	if in.SessionAffinity != nil {
+		in, out := &in.SessionAffinity, &out.SessionAffinity
+		*out = new(SessionAffinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(Timeout)
+		**out = **in
+	}
+	if in.ExtraConfig != nil {
+		in, out := &in.ExtraConfig, &out.ExtraConfig
+		*out = new(HAProxyExtraConfig)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppServers.
@@ -77,6 +118,131 @@ func (in *AppServers) DeepCopy() *AppServers {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoscalingStatus) DeepCopyInto(out *AutoscalingStatus) {
+	*out = *in
+	if in.ScaleDownSince != nil {
+		in, out := &in.ScaleDownSince, &out.ScaleDownSince
+		*out = (*in).DeepCopy()
+	}
+	if in.LastScaleTime != nil {
+		in, out := &in.LastScaleTime, &out.LastScaleTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscalingStatus.
+func (in *AutoscalingStatus) DeepCopy() *AutoscalingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoscalingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupRetention) DeepCopyInto(out *BackupRetention) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupRetention.
+func (in *BackupRetention) DeepCopy() *BackupRetention {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupRetention)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupRun) DeepCopyInto(out *BackupRun) {
+	*out = *in
+	in.ScheduledTime.DeepCopyInto(&out.ScheduledTime)
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]DatabaseJobStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupRun.
+func (in *BackupRun) DeepCopy() *BackupRun {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupRun)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupStorage) DeepCopyInto(out *BackupStorage) {
+	*out = *in
+	if in.PersistentVolumeClaim != nil {
+		in, out := &in.PersistentVolumeClaim, &out.PersistentVolumeClaim
+		*out = new(corev1.PersistentVolumeClaimVolumeSource)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupStorage.
+func (in *BackupStorage) DeepCopy() *BackupStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateIssuerRef) DeepCopyInto(out *CertificateIssuerRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateIssuerRef.
+func (in *CertificateIssuerRef) DeepCopy() *CertificateIssuerRef {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateIssuerRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateStatus) DeepCopyInto(out *CertificateStatus) {
+	*out = *in
+	if in.NotAfter != nil {
+		in, out := &in.NotAfter, &out.NotAfter
+		*out = (*in).DeepCopy()
+	}
+	if in.LastRotationTime != nil {
+		in, out := &in.LastRotationTime, &out.LastRotationTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateStatus.
+func (in *CertificateStatus) DeepCopy() *CertificateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ContainerProbe) DeepCopyInto(out *ContainerProbe) {
 	*out = *in
@@ -92,6 +258,186 @@ func (in *ContainerProbe) DeepCopy() *ContainerProbe {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseForestReplica) DeepCopyInto(out *DatabaseForestReplica) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseForestReplica.
+func (in *DatabaseForestReplica) DeepCopy() *DatabaseForestReplica {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseForestReplica)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseForestStatus) DeepCopyInto(out *DatabaseForestStatus) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = make([]DatabaseForestReplica, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseForestStatus.
+func (in *DatabaseForestStatus) DeepCopy() *DatabaseForestStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseForestStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseForests) DeepCopyInto(out *DatabaseForests) {
+	*out = *in
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseForests.
+func (in *DatabaseForests) DeepCopy() *DatabaseForests {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseForests)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseIndexes) DeepCopyInto(out *DatabaseIndexes) {
+	*out = *in
+	if in.WordSearches != nil {
+		in, out := &in.WordSearches, &out.WordSearches
+		*out = new(bool)
+		**out = **in
+	}
+	if in.FastPhraseSearches != nil {
+		in, out := &in.FastPhraseSearches, &out.FastPhraseSearches
+		*out = new(bool)
+		**out = **in
+	}
+	if in.TripleIndex != nil {
+		in, out := &in.TripleIndex, &out.TripleIndex
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CollectionLexicon != nil {
+		in, out := &in.CollectionLexicon, &out.CollectionLexicon
+		*out = new(bool)
+		**out = **in
+	}
+	if in.URILexicon != nil {
+		in, out := &in.URILexicon, &out.URILexicon
+		*out = new(bool)
+		**out = **in
+	}
+	if in.RangeElementIndexes != nil {
+		in, out := &in.RangeElementIndexes, &out.RangeElementIndexes
+		*out = make([]RangeElementIndex, len(*in))
+		copy(*out, *in)
+	}
+	if in.RangePathIndexes != nil {
+		in, out := &in.RangePathIndexes, &out.RangePathIndexes
+		*out = make([]RangePathIndex, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseIndexes.
+func (in *DatabaseIndexes) DeepCopy() *DatabaseIndexes {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseIndexes)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseJobStatus) DeepCopyInto(out *DatabaseJobStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseJobStatus.
+func (in *DatabaseJobStatus) DeepCopy() *DatabaseJobStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseJobStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseReplicationState) DeepCopyInto(out *DatabaseReplicationState) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseReplicationState.
+func (in *DatabaseReplicationState) DeepCopy() *DatabaseReplicationState {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseReplicationState)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DecommissionForest) DeepCopyInto(out *DecommissionForest) {
+	*out = *in
+	if in.MigrationTime != nil {
+		in, out := &in.MigrationTime, &out.MigrationTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DecommissionForest.
+func (in *DecommissionForest) DeepCopy() *DecommissionForest {
+	if in == nil {
+		return nil
+	}
+	out := new(DecommissionForest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DecommissionStatus) DeepCopyInto(out *DecommissionStatus) {
+	*out = *in
+	if in.Forests != nil {
+		in, out := &in.Forests, &out.Forests
+		*out = make([]DecommissionForest, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DecommissionStatus.
+func (in *DecommissionStatus) DeepCopy() *DecommissionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DecommissionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DynamicGroupConfig) DeepCopyInto(out *DynamicGroupConfig) {
 	*out = *in
@@ -152,6 +498,21 @@ func (in *DynamicHostStatus) DeepCopy() *DynamicHostStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalCluster) DeepCopyInto(out *ExternalCluster) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalCluster.
+func (in *ExternalCluster) DeepCopy() *ExternalCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FailedPVCStatus) DeepCopyInto(out *FailedPVCStatus) {
 	*out = *in
@@ -167,6 +528,128 @@ func (in *FailedPVCStatus) DeepCopy() *FailedPVCStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailoverReplicaStatus) DeepCopyInto(out *FailoverReplicaStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailoverReplicaStatus.
+func (in *FailoverReplicaStatus) DeepCopy() *FailoverReplicaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FailoverReplicaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailoverStatus) DeepCopyInto(out *FailoverStatus) {
+	*out = *in
+	if in.Forests != nil {
+		in, out := &in.Forests, &out.Forests
+		*out = make([]ForestFailoverStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailoverStatus.
+func (in *FailoverStatus) DeepCopy() *FailoverStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FailoverStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ForestFailover) DeepCopyInto(out *ForestFailover) {
+	*out = *in
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ForestFailover.
+func (in *ForestFailover) DeepCopy() *ForestFailover {
+	if in == nil {
+		return nil
+	}
+	out := new(ForestFailover)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ForestFailoverStatus) DeepCopyInto(out *ForestFailoverStatus) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = make([]FailoverReplicaStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ForestFailoverStatus.
+func (in *ForestFailoverStatus) DeepCopy() *ForestFailoverStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ForestFailoverStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayParentRef) DeepCopyInto(out *GatewayParentRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayParentRef.
+func (in *GatewayParentRef) DeepCopy() *GatewayParentRef {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayParentRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayTLS) DeepCopyInto(out *GatewayTLS) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayTLS.
+func (in *GatewayTLS) DeepCopy() *GatewayTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GroupAutoscaling) DeepCopyInto(out *GroupAutoscaling) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupAutoscaling.
+func (in *GroupAutoscaling) DeepCopy() *GroupAutoscaling {
+	if in == nil {
+		return nil
+	}
+	out := new(GroupAutoscaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GroupConfig) DeepCopyInto(out *GroupConfig) {
 	*out = *in
@@ -203,7 +686,9 @@ func (in *HAProxy) DeepCopyInto(out *HAProxy) {
 	if in.AppServers != nil {
 		in, out := &in.AppServers, &out.AppServers
 		*out = make([]AppServers, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.PathBasedRouting != nil {
 		in, out := &in.PathBasedRouting, &out.PathBasedRouting
@@ -224,7 +709,7 @@ func (in *HAProxy) DeepCopyInto(out *HAProxy) {
 	if in.Tls != nil {
 		in, out := &in.Tls, &out.Tls
 		*out = new(TlsForHAProxy)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	out.Stats = in.Stats
 	in.Resources.DeepCopyInto(&out.Resources)
@@ -240,45 +725,207 @@ func (in *HAProxy) DeepCopyInto(out *HAProxy) {
 			(*out)[key] = val
 		}
 	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]corev1.TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(HAProxyAutoscaling)
+		**out = **in
+	}
 	in.Ingress.DeepCopyInto(&out.Ingress)
+	if in.Gateway != nil {
+		in, out := &in.Gateway, &out.Gateway
+		*out = new(HAProxyGateway)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(HAProxyHealthCheck)
+		**out = **in
+	}
+	if in.RuntimeAPI != nil {
+		in, out := &in.RuntimeAPI, &out.RuntimeAPI
+		*out = new(HAProxyRuntimeAPI)
+		**out = **in
+	}
+	if in.PodDisruptionBudget != nil {
+		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
+		*out = new(PodDisruptionBudget)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HAProxy.
+func (in *HAProxy) DeepCopy() *HAProxy {
+	if in == nil {
+		return nil
+	}
+	out := new(HAProxy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HAProxyAutoscaling) DeepCopyInto(out *HAProxyAutoscaling) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HAProxyAutoscaling.
+func (in *HAProxyAutoscaling) DeepCopy() *HAProxyAutoscaling {
+	if in == nil {
+		return nil
+	}
+	out := new(HAProxyAutoscaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HAProxyExtraConfig) DeepCopyInto(out *HAProxyExtraConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HAProxyExtraConfig.
+func (in *HAProxyExtraConfig) DeepCopy() *HAProxyExtraConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(HAProxyExtraConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HAProxyGateway) DeepCopyInto(out *HAProxyGateway) {
+	*out = *in
+	if in.ParentRefs != nil {
+		in, out := &in.ParentRefs, &out.ParentRefs
+		*out = make([]GatewayParentRef, len(*in))
+		copy(*out, *in)
+	}
+	if in.Hostnames != nil {
+		in, out := &in.Hostnames, &out.Hostnames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(GatewayTLS)
+		**out = **in
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HAProxyGateway.
+func (in *HAProxyGateway) DeepCopy() *HAProxyGateway {
+	if in == nil {
+		return nil
+	}
+	out := new(HAProxyGateway)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HAProxyGroup) DeepCopyInto(out *HAProxyGroup) {
+	*out = *in
+	if in.AppServers != nil {
+		in, out := &in.AppServers, &out.AppServers
+		*out = make([]AppServers, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PathBasedRouting != nil {
+		in, out := &in.PathBasedRouting, &out.PathBasedRouting
+		*out = new(bool)
+		**out = **in
+	}
+	if in.TcpPorts != nil {
+		in, out := &in.TcpPorts, &out.TcpPorts
+		*out = new(Tcpports)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(HAProxyHealthCheck)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HAProxyGroup.
+func (in *HAProxyGroup) DeepCopy() *HAProxyGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(HAProxyGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HAProxyHealthCheck) DeepCopyInto(out *HAProxyHealthCheck) {
+	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HAProxy.
-func (in *HAProxy) DeepCopy() *HAProxy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HAProxyHealthCheck.
+func (in *HAProxyHealthCheck) DeepCopy() *HAProxyHealthCheck {
 	if in == nil {
 		return nil
 	}
-	out := new(HAProxy)
+	out := new(HAProxyHealthCheck)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HAProxyGroup) DeepCopyInto(out *HAProxyGroup) {
+func (in *HAProxyRuntimeAPI) DeepCopyInto(out *HAProxyRuntimeAPI) {
 	*out = *in
-	if in.AppServers != nil {
-		in, out := &in.AppServers, &out.AppServers
-		*out = make([]AppServers, len(*in))
-		copy(*out, *in)
-	}
-	if in.PathBasedRouting != nil {
-		in, out := &in.PathBasedRouting, &out.PathBasedRouting
-		*out = new(bool)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HAProxyRuntimeAPI.
+func (in *HAProxyRuntimeAPI) DeepCopy() *HAProxyRuntimeAPI {
+	if in == nil {
+		return nil
 	}
-	if in.TcpPorts != nil {
-		in, out := &in.TcpPorts, &out.TcpPorts
-		*out = new(Tcpports)
-		(*in).DeepCopyInto(*out)
+	out := new(HAProxyRuntimeAPI)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostJoinStatus) DeepCopyInto(out *HostJoinStatus) {
+	*out = *in
+	if in.LastUpdated != nil {
+		in, out := &in.LastUpdated, &out.LastUpdated
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HAProxyGroup.
-func (in *HAProxyGroup) DeepCopy() *HAProxyGroup {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostJoinStatus.
+func (in *HostJoinStatus) DeepCopy() *HostJoinStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(HAProxyGroup)
+	out := new(HostJoinStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -341,9 +988,41 @@ func (in *Ingress) DeepCopy() *Ingress {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JoinStatus) DeepCopyInto(out *JoinStatus) {
+	*out = *in
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]HostJoinStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JoinStatus.
+func (in *JoinStatus) DeepCopy() *JoinStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(JoinStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *License) DeepCopyInto(out *License) {
 	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(LicenseSecretRef)
+		**out = **in
+	}
+	if in.CSI != nil {
+		in, out := &in.CSI, &out.CSI
+		*out = new(corev1.CSIVolumeSource)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new License.
@@ -356,6 +1035,21 @@ func (in *License) DeepCopy() *License {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LicenseSecretRef) DeepCopyInto(out *LicenseSecretRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LicenseSecretRef.
+func (in *LicenseSecretRef) DeepCopy() *LicenseSecretRef {
+	if in == nil {
+		return nil
+	}
+	out := new(LicenseSecretRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LogCollection) DeepCopyInto(out *LogCollection) {
 	*out = *in
@@ -402,6 +1096,143 @@ func (in *LogFilesConfig) DeepCopy() *LogFilesConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MarklogicBackup) DeepCopyInto(out *MarklogicBackup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MarklogicBackup.
+func (in *MarklogicBackup) DeepCopy() *MarklogicBackup {
+	if in == nil {
+		return nil
+	}
+	out := new(MarklogicBackup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MarklogicBackup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MarklogicBackupList) DeepCopyInto(out *MarklogicBackupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MarklogicBackup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MarklogicBackupList.
+func (in *MarklogicBackupList) DeepCopy() *MarklogicBackupList {
+	if in == nil {
+		return nil
+	}
+	out := new(MarklogicBackupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MarklogicBackupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MarklogicBackupSpec) DeepCopyInto(out *MarklogicBackupSpec) {
+	*out = *in
+	out.ClusterRef = in.ClusterRef
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Storage.DeepCopyInto(&out.Storage)
+	if in.IncludeReplicas != nil {
+		in, out := &in.IncludeReplicas, &out.IncludeReplicas
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(BackupRetention)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MarklogicBackupSpec.
+func (in *MarklogicBackupSpec) DeepCopy() *MarklogicBackupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MarklogicBackupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MarklogicBackupStatus) DeepCopyInto(out *MarklogicBackupStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CurrentRun != nil {
+		in, out := &in.CurrentRun, &out.CurrentRun
+		*out = new(BackupRun)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextScheduleTime != nil {
+		in, out := &in.NextScheduleTime, &out.NextScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastSuccessfulTime != nil {
+		in, out := &in.LastSuccessfulTime, &out.LastSuccessfulTime
+		*out = (*in).DeepCopy()
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]BackupRun, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MarklogicBackupStatus.
+func (in *MarklogicBackupStatus) DeepCopy() *MarklogicBackupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MarklogicBackupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MarklogicCluster) DeepCopyInto(out *MarklogicCluster) {
 	*out = *in
@@ -527,7 +1358,7 @@ func (in *MarklogicClusterSpec) DeepCopyInto(out *MarklogicClusterSpec) {
 	if in.License != nil {
 		in, out := &in.License, &out.License
 		*out = new(License)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.HugePages != nil {
 		in, out := &in.HugePages, &out.HugePages
@@ -539,6 +1370,11 @@ func (in *MarklogicClusterSpec) DeepCopyInto(out *MarklogicClusterSpec) {
 		*out = new(LogCollection)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = new(ServerMetrics)
+		**out = **in
+	}
 	if in.HAProxy != nil {
 		in, out := &in.HAProxy, &out.HAProxy
 		*out = new(HAProxy)
@@ -600,13 +1436,131 @@ func (in *MarklogicClusterSpec) DeepCopy() *MarklogicClusterSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(MarklogicClusterSpec)
+	out := new(MarklogicClusterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MarklogicClusterStatus) DeepCopyInto(out *MarklogicClusterStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]MarklogicGroupRollup, len(*in))
+		copy(*out, *in)
+	}
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(UpgradeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PasswordRotation != nil {
+		in, out := &in.PasswordRotation, &out.PasswordRotation
+		*out = new(PasswordRotationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MarklogicClusterStatus.
+func (in *MarklogicClusterStatus) DeepCopy() *MarklogicClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MarklogicClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MarklogicDatabase) DeepCopyInto(out *MarklogicDatabase) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MarklogicDatabase.
+func (in *MarklogicDatabase) DeepCopy() *MarklogicDatabase {
+	if in == nil {
+		return nil
+	}
+	out := new(MarklogicDatabase)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MarklogicDatabase) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MarklogicDatabaseList) DeepCopyInto(out *MarklogicDatabaseList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MarklogicDatabase, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MarklogicDatabaseList.
+func (in *MarklogicDatabaseList) DeepCopy() *MarklogicDatabaseList {
+	if in == nil {
+		return nil
+	}
+	out := new(MarklogicDatabaseList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MarklogicDatabaseList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MarklogicDatabaseSpec) DeepCopyInto(out *MarklogicDatabaseSpec) {
+	*out = *in
+	out.ClusterRef = in.ClusterRef
+	in.Forests.DeepCopyInto(&out.Forests)
+	if in.Indexes != nil {
+		in, out := &in.Indexes, &out.Indexes
+		*out = new(DatabaseIndexes)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MarklogicDatabaseSpec.
+func (in *MarklogicDatabaseSpec) DeepCopy() *MarklogicDatabaseSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MarklogicDatabaseSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MarklogicClusterStatus) DeepCopyInto(out *MarklogicClusterStatus) {
+func (in *MarklogicDatabaseStatus) DeepCopyInto(out *MarklogicDatabaseStatus) {
 	*out = *in
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
@@ -615,14 +1569,21 @@ func (in *MarklogicClusterStatus) DeepCopyInto(out *MarklogicClusterStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Forests != nil {
+		in, out := &in.Forests, &out.Forests
+		*out = make([]DatabaseForestStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MarklogicClusterStatus.
-func (in *MarklogicClusterStatus) DeepCopy() *MarklogicClusterStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MarklogicDatabaseStatus.
+func (in *MarklogicDatabaseStatus) DeepCopy() *MarklogicDatabaseStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(MarklogicClusterStatus)
+	out := new(MarklogicDatabaseStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -686,6 +1647,21 @@ func (in *MarklogicGroupList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MarklogicGroupRollup) DeepCopyInto(out *MarklogicGroupRollup) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MarklogicGroupRollup.
+func (in *MarklogicGroupRollup) DeepCopy() *MarklogicGroupRollup {
+	if in == nil {
+		return nil
+	}
+	out := new(MarklogicGroupRollup)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MarklogicGroupSpec) DeepCopyInto(out *MarklogicGroupSpec) {
 	*out = *in
@@ -780,6 +1756,11 @@ func (in *MarklogicGroupSpec) DeepCopyInto(out *MarklogicGroupSpec) {
 		*out = new(LogCollection)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = new(ServerMetrics)
+		**out = **in
+	}
 	if in.GroupConfig != nil {
 		in, out := &in.GroupConfig, &out.GroupConfig
 		*out = new(GroupConfig)
@@ -790,10 +1771,25 @@ func (in *MarklogicGroupSpec) DeepCopyInto(out *MarklogicGroupSpec) {
 		*out = new(DynamicGroupConfig)
 		**out = **in
 	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(GroupAutoscaling)
+		**out = **in
+	}
+	if in.Failover != nil {
+		in, out := &in.Failover, &out.Failover
+		*out = new(ForestFailover)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodDisruptionBudget != nil {
+		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
+		*out = new(PodDisruptionBudget)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.License != nil {
 		in, out := &in.License, &out.License
 		*out = new(License)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.DoNotDelete != nil {
 		in, out := &in.DoNotDelete, &out.DoNotDelete
@@ -834,11 +1830,21 @@ func (in *MarklogicGroupSpec) DeepCopyInto(out *MarklogicGroupSpec) {
 			}
 		}
 	}
+	if in.AdminCredentialsCSI != nil {
+		in, out := &in.AdminCredentialsCSI, &out.AdminCredentialsCSI
+		*out = new(corev1.CSIVolumeSource)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Tls != nil {
 		in, out := &in.Tls, &out.Tls
 		*out = new(Tls)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.AppServers != nil {
+		in, out := &in.AppServers, &out.AppServers
+		*out = make([]AppServer, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MarklogicGroupSpec.
@@ -871,11 +1877,38 @@ func (in *MarklogicGroupStatus) DeepCopyInto(out *MarklogicGroupStatus) {
 		*out = new(VolumeResizeStatus)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(AutoscalingStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Certificates != nil {
+		in, out := &in.Certificates, &out.Certificates
+		*out = make([]CertificateStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Dynamic != nil {
 		in, out := &in.Dynamic, &out.Dynamic
 		*out = new(DynamicGroupStatus)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Decommission != nil {
+		in, out := &in.Decommission, &out.Decommission
+		*out = new(DecommissionStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Join != nil {
+		in, out := &in.Join, &out.Join
+		*out = new(JoinStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Failover != nil {
+		in, out := &in.Failover, &out.Failover
+		*out = new(FailoverStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MarklogicGroupStatus.
@@ -950,74 +1983,336 @@ func (in *MarklogicGroups) DeepCopyInto(out *MarklogicGroups) {
 			(*out)[key] = val
 		}
 	}
-	if in.HugePages != nil {
-		in, out := &in.HugePages, &out.HugePages
-		*out = new(HugePages)
+	if in.HugePages != nil {
+		in, out := &in.HugePages, &out.HugePages
+		*out = new(HugePages)
+		**out = **in
+	}
+	out.LivenessProbe = in.LivenessProbe
+	out.ReadinessProbe = in.ReadinessProbe
+	if in.LogCollection != nil {
+		in, out := &in.LogCollection, &out.LogCollection
+		*out = new(LogCollection)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = new(ServerMetrics)
+		**out = **in
+	}
+	if in.HAProxy != nil {
+		in, out := &in.HAProxy, &out.HAProxy
+		*out = new(HAProxyGroup)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AppServers != nil {
+		in, out := &in.AppServers, &out.AppServers
+		*out = make([]AppServer, len(*in))
+		copy(*out, *in)
+	}
+	if in.Dynamic != nil {
+		in, out := &in.Dynamic, &out.Dynamic
+		*out = new(DynamicGroupConfig)
+		**out = **in
+	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(GroupAutoscaling)
+		**out = **in
+	}
+	if in.Failover != nil {
+		in, out := &in.Failover, &out.Failover
+		*out = new(ForestFailover)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodDisruptionBudget != nil {
+		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
+		*out = new(PodDisruptionBudget)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tls != nil {
+		in, out := &in.Tls, &out.Tls
+		*out = new(Tls)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdditionalVolumes != nil {
+		in, out := &in.AdditionalVolumes, &out.AdditionalVolumes
+		*out = new([]corev1.Volume)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make([]corev1.Volume, len(*in))
+			for i := range *in {
+				(*in)[i].DeepCopyInto(&(*out)[i])
+			}
+		}
+	}
+	if in.AdditionalVolumeMounts != nil {
+		in, out := &in.AdditionalVolumeMounts, &out.AdditionalVolumeMounts
+		*out = new([]corev1.VolumeMount)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make([]corev1.VolumeMount, len(*in))
+			for i := range *in {
+				(*in)[i].DeepCopyInto(&(*out)[i])
+			}
+		}
+	}
+	if in.AdditionalVolumeClaimTemplates != nil {
+		in, out := &in.AdditionalVolumeClaimTemplates, &out.AdditionalVolumeClaimTemplates
+		*out = new([]corev1.PersistentVolumeClaim)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make([]corev1.PersistentVolumeClaim, len(*in))
+			for i := range *in {
+				(*in)[i].DeepCopyInto(&(*out)[i])
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MarklogicGroups.
+func (in *MarklogicGroups) DeepCopy() *MarklogicGroups {
+	if in == nil {
+		return nil
+	}
+	out := new(MarklogicGroups)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MarklogicReplication) DeepCopyInto(out *MarklogicReplication) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MarklogicReplication.
+func (in *MarklogicReplication) DeepCopy() *MarklogicReplication {
+	if in == nil {
+		return nil
+	}
+	out := new(MarklogicReplication)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MarklogicReplication) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MarklogicReplicationList) DeepCopyInto(out *MarklogicReplicationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MarklogicReplication, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MarklogicReplicationList.
+func (in *MarklogicReplicationList) DeepCopy() *MarklogicReplicationList {
+	if in == nil {
+		return nil
+	}
+	out := new(MarklogicReplicationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MarklogicReplicationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MarklogicReplicationSpec) DeepCopyInto(out *MarklogicReplicationSpec) {
+	*out = *in
+	in.Source.DeepCopyInto(&out.Source)
+	in.Target.DeepCopyInto(&out.Target)
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]ReplicatedDatabase, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MarklogicReplicationSpec.
+func (in *MarklogicReplicationSpec) DeepCopy() *MarklogicReplicationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MarklogicReplicationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MarklogicReplicationStatus) DeepCopyInto(out *MarklogicReplicationStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]DatabaseReplicationState, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MarklogicReplicationStatus.
+func (in *MarklogicReplicationStatus) DeepCopy() *MarklogicReplicationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MarklogicReplicationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MarklogicRestore) DeepCopyInto(out *MarklogicRestore) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MarklogicRestore.
+func (in *MarklogicRestore) DeepCopy() *MarklogicRestore {
+	if in == nil {
+		return nil
+	}
+	out := new(MarklogicRestore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MarklogicRestore) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MarklogicRestoreList) DeepCopyInto(out *MarklogicRestoreList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MarklogicRestore, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MarklogicRestoreList.
+func (in *MarklogicRestoreList) DeepCopy() *MarklogicRestoreList {
+	if in == nil {
+		return nil
+	}
+	out := new(MarklogicRestoreList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MarklogicRestoreList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MarklogicRestoreSpec) DeepCopyInto(out *MarklogicRestoreSpec) {
+	*out = *in
+	out.ClusterRef = in.ClusterRef
+	if in.BackupRef != nil {
+		in, out := &in.BackupRef, &out.BackupRef
+		*out = new(corev1.LocalObjectReference)
 		**out = **in
 	}
-	out.LivenessProbe = in.LivenessProbe
-	out.ReadinessProbe = in.ReadinessProbe
-	if in.LogCollection != nil {
-		in, out := &in.LogCollection, &out.LogCollection
-		*out = new(LogCollection)
-		(*in).DeepCopyInto(*out)
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.HAProxy != nil {
-		in, out := &in.HAProxy, &out.HAProxy
-		*out = new(HAProxyGroup)
-		(*in).DeepCopyInto(*out)
+	if in.RestoreToTime != nil {
+		in, out := &in.RestoreToTime, &out.RestoreToTime
+		*out = (*in).DeepCopy()
 	}
-	if in.Dynamic != nil {
-		in, out := &in.Dynamic, &out.Dynamic
-		*out = new(DynamicGroupConfig)
+	if in.IncludeReplicas != nil {
+		in, out := &in.IncludeReplicas, &out.IncludeReplicas
+		*out = new(bool)
 		**out = **in
 	}
-	if in.Tls != nil {
-		in, out := &in.Tls, &out.Tls
-		*out = new(Tls)
-		(*in).DeepCopyInto(*out)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MarklogicRestoreSpec.
+func (in *MarklogicRestoreSpec) DeepCopy() *MarklogicRestoreSpec {
+	if in == nil {
+		return nil
 	}
-	if in.AdditionalVolumes != nil {
-		in, out := &in.AdditionalVolumes, &out.AdditionalVolumes
-		*out = new([]corev1.Volume)
-		if **in != nil {
-			in, out := *in, *out
-			*out = make([]corev1.Volume, len(*in))
-			for i := range *in {
-				(*in)[i].DeepCopyInto(&(*out)[i])
-			}
+	out := new(MarklogicRestoreSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MarklogicRestoreStatus) DeepCopyInto(out *MarklogicRestoreStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.AdditionalVolumeMounts != nil {
-		in, out := &in.AdditionalVolumeMounts, &out.AdditionalVolumeMounts
-		*out = new([]corev1.VolumeMount)
-		if **in != nil {
-			in, out := *in, *out
-			*out = make([]corev1.VolumeMount, len(*in))
-			for i := range *in {
-				(*in)[i].DeepCopyInto(&(*out)[i])
-			}
-		}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
 	}
-	if in.AdditionalVolumeClaimTemplates != nil {
-		in, out := &in.AdditionalVolumeClaimTemplates, &out.AdditionalVolumeClaimTemplates
-		*out = new([]corev1.PersistentVolumeClaim)
-		if **in != nil {
-			in, out := *in, *out
-			*out = make([]corev1.PersistentVolumeClaim, len(*in))
-			for i := range *in {
-				(*in)[i].DeepCopyInto(&(*out)[i])
-			}
-		}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]DatabaseJobStatus, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MarklogicGroups.
-func (in *MarklogicGroups) DeepCopy() *MarklogicGroups {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MarklogicRestoreStatus.
+func (in *MarklogicRestoreStatus) DeepCopy() *MarklogicRestoreStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(MarklogicGroups)
+	out := new(MarklogicRestoreStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -1076,6 +2371,49 @@ func (in *PVCResizeStatus) DeepCopy() *PVCResizeStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PasswordRotation) DeepCopyInto(out *PasswordRotation) {
+	*out = *in
+	if in.RotationPeriod != nil {
+		in, out := &in.RotationPeriod, &out.RotationPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PasswordRotation.
+func (in *PasswordRotation) DeepCopy() *PasswordRotation {
+	if in == nil {
+		return nil
+	}
+	out := new(PasswordRotation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PasswordRotationStatus) DeepCopyInto(out *PasswordRotationStatus) {
+	*out = *in
+	if in.LastRotationTime != nil {
+		in, out := &in.LastRotationTime, &out.LastRotationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextRotationTime != nil {
+		in, out := &in.NextRotationTime, &out.NextRotationTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PasswordRotationStatus.
+func (in *PasswordRotationStatus) DeepCopy() *PasswordRotationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PasswordRotationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Persistence) DeepCopyInto(out *Persistence) {
 	*out = *in
@@ -1103,6 +2441,131 @@ func (in *Persistence) DeepCopy() *Persistence {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodDisruptionBudget) DeepCopyInto(out *PodDisruptionBudget) {
+	*out = *in
+	if in.MinAvailable != nil {
+		in, out := &in.MinAvailable, &out.MinAvailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodDisruptionBudget.
+func (in *PodDisruptionBudget) DeepCopy() *PodDisruptionBudget {
+	if in == nil {
+		return nil
+	}
+	out := new(PodDisruptionBudget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RangeElementIndex) DeepCopyInto(out *RangeElementIndex) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RangeElementIndex.
+func (in *RangeElementIndex) DeepCopy() *RangeElementIndex {
+	if in == nil {
+		return nil
+	}
+	out := new(RangeElementIndex)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RangePathIndex) DeepCopyInto(out *RangePathIndex) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RangePathIndex.
+func (in *RangePathIndex) DeepCopy() *RangePathIndex {
+	if in == nil {
+		return nil
+	}
+	out := new(RangePathIndex)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicatedDatabase) DeepCopyInto(out *ReplicatedDatabase) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicatedDatabase.
+func (in *ReplicatedDatabase) DeepCopy() *ReplicatedDatabase {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicatedDatabase)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicationClusterRef) DeepCopyInto(out *ReplicationClusterRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationClusterRef.
+func (in *ReplicationClusterRef) DeepCopy() *ReplicationClusterRef {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicationClusterRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicationEndpoint) DeepCopyInto(out *ReplicationEndpoint) {
+	*out = *in
+	if in.ClusterRef != nil {
+		in, out := &in.ClusterRef, &out.ClusterRef
+		*out = new(ReplicationClusterRef)
+		**out = **in
+	}
+	if in.External != nil {
+		in, out := &in.External, &out.External
+		*out = new(ExternalCluster)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationEndpoint.
+func (in *ReplicationEndpoint) DeepCopy() *ReplicationEndpoint {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicationEndpoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServerMetrics) DeepCopyInto(out *ServerMetrics) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerMetrics.
+func (in *ServerMetrics) DeepCopy() *ServerMetrics {
+	if in == nil {
+		return nil
+	}
+	out := new(ServerMetrics)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Service) DeepCopyInto(out *Service) {
 	*out = *in
@@ -1132,6 +2595,26 @@ func (in *Service) DeepCopy() *Service {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SessionAffinity) DeepCopyInto(out *SessionAffinity) {
+	*out = *in
+	if in.Cookies != nil {
+		in, out := &in.Cookies, &out.Cookies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SessionAffinity.
+func (in *SessionAffinity) DeepCopy() *SessionAffinity {
+	if in == nil {
+		return nil
+	}
+	out := new(SessionAffinity)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Stats) DeepCopyInto(out *Stats) {
 	*out = *in
@@ -1166,6 +2649,11 @@ func (in *StatsAuth) DeepCopy() *StatsAuth {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TcpPort) DeepCopyInto(out *TcpPort) {
 	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(Timeout)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TcpPort.
@@ -1184,7 +2672,9 @@ func (in *Tcpports) DeepCopyInto(out *Tcpports) {
 	if in.Ports != nil {
 		in, out := &in.Ports, &out.Ports
 		*out = make([]TcpPort, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
@@ -1221,6 +2711,11 @@ func (in *Tls) DeepCopyInto(out *Tls) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.IssuerRef != nil {
+		in, out := &in.IssuerRef, &out.IssuerRef
+		*out = new(CertificateIssuerRef)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Tls.
@@ -1236,6 +2731,11 @@ func (in *Tls) DeepCopy() *Tls {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TlsForHAProxy) DeepCopyInto(out *TlsForHAProxy) {
 	*out = *in
+	if in.IssuerRef != nil {
+		in, out := &in.IssuerRef, &out.IssuerRef
+		*out = new(CertificateIssuerRef)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TlsForHAProxy.
@@ -1248,6 +2748,53 @@ func (in *TlsForHAProxy) DeepCopy() *TlsForHAProxy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeGroupStatus) DeepCopyInto(out *UpgradeGroupStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeGroupStatus.
+func (in *UpgradeGroupStatus) DeepCopy() *UpgradeGroupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeGroupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeStatus) DeepCopyInto(out *UpgradeStatus) {
+	*out = *in
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]UpgradeGroupStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.StartedTime != nil {
+		in, out := &in.StartedTime, &out.StartedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeStatus.
+func (in *UpgradeStatus) DeepCopy() *UpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VolumeMountWrapper) DeepCopyInto(out *VolumeMountWrapper) {
 	*out = *in
@@ -40,6 +40,7 @@ import (
 
 	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
 	"github.com/marklogic/marklogic-operator-kubernetes/internal/controller"
+	webhookv1 "github.com/marklogic/marklogic-operator-kubernetes/internal/webhook/v1"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -62,6 +63,7 @@ func main() {
 	var secureMetrics bool
 	var enableHTTP2 bool
 	var watchNamespace string
+	var enableWebhooks bool
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080",
 		"The address the metrics endpoint binds to. Use :8443 when --metrics-secure is true.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
@@ -77,6 +79,10 @@ func main() {
 		"Namespace(s) to watch for resources. If empty, watches all namespaces (cluster-scoped). "+
 			"Can be a single namespace or comma-separated list of namespaces. "+
 			"Can be set via WATCH_NAMESPACE environment variable.")
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", os.Getenv("ENABLE_WEBHOOKS") == "true",
+		"Register the MarklogicCluster and MarklogicGroup admission webhooks. "+
+			"Requires a serving certificate in the webhook server cert directory. "+
+			"Can be set via ENABLE_WEBHOOKS environment variable.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -231,6 +237,52 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "MarklogicCluster")
 		os.Exit(1)
 	}
+	if err = (&controller.MarklogicBackupReconciler{
+		Client:   mgr.GetClient(),
+		Log:      ctrl.Log.WithName("controllers").WithName("MarklogicBackup"),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("marklogicbackup-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "MarklogicBackup")
+		os.Exit(1)
+	}
+	if err = (&controller.MarklogicRestoreReconciler{
+		Client:   mgr.GetClient(),
+		Log:      ctrl.Log.WithName("controllers").WithName("MarklogicRestore"),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("marklogicrestore-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "MarklogicRestore")
+		os.Exit(1)
+	}
+	if err = (&controller.MarklogicDatabaseReconciler{
+		Client:   mgr.GetClient(),
+		Log:      ctrl.Log.WithName("controllers").WithName("MarklogicDatabase"),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("marklogicdatabase-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "MarklogicDatabase")
+		os.Exit(1)
+	}
+	if err = (&controller.MarklogicReplicationReconciler{
+		Client:   mgr.GetClient(),
+		Log:      ctrl.Log.WithName("controllers").WithName("MarklogicReplication"),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("marklogicreplication-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "MarklogicReplication")
+		os.Exit(1)
+	}
+	if enableWebhooks {
+		if err = webhookv1.SetupMarklogicClusterWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "MarklogicCluster")
+			os.Exit(1)
+		}
+		if err = webhookv1.SetupMarklogicGroupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "MarklogicGroup")
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
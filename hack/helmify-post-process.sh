@@ -2,8 +2,9 @@
 # Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
 #
 # Post-processing script for Helmify output.
-# Restores scope-awareness (cluster-scoped vs namespace-scoped) and
-# metrics-security (secure HTTPS vs plain HTTP) that helmify cannot generate,
+# Restores scope-awareness (cluster-scoped vs namespace-scoped),
+# metrics-security (secure HTTPS vs plain HTTP) and the optional admission
+# webhook server that helmify cannot generate,
 # because kustomize only exposes the cluster-scoped/secure configuration to helmify.
 #
 # Run automatically by: make helm
@@ -75,12 +76,38 @@ metrics:
   # secure: false            — HTTP on :8080, no authentication. Safe for namespace scope
   #                            or development environments that have no cluster-level RBAC.
   secure: true
+  # serviceMonitor creates a Prometheus Operator ServiceMonitor for the metrics Service,
+  # which serves the operator metrics and the MarkLogic metrics of groups with
+  # spec.metrics.enabled. Requires the monitoring.coreos.com/v1 CRDs. With secure metrics,
+  # bind the marklogic-operator-metrics-reader ClusterRole to the Prometheus service account.
+  serviceMonitor:
+    enabled: false
+    interval: 30s
+    labels: {}
 YAML_EOF
     echo "  [values.yaml] Done (metrics)."
 else
     echo "  [values.yaml] metrics already present – skipping."
 fi
 
+if ! grep -q "^webhook:" "${VALUES_FILE}"; then
+    echo "  [values.yaml] Adding webhook configuration..."
+    cat >> "${VALUES_FILE}" << 'YAML_EOF'
+
+# Admission webhooks
+webhook:
+  # enabled deploys the MarklogicCluster and MarklogicGroup validating and defaulting
+  # webhooks (templates/webhook.yaml) and starts the operator with ENABLE_WEBHOOKS=true.
+  # Requires cert-manager, which issues the serving certificate into certSecretName
+  # and injects its CA bundle into the webhook configurations.
+  enabled: false
+  certSecretName: webhook-server-cert
+YAML_EOF
+    echo "  [values.yaml] Done (webhook)."
+else
+    echo "  [values.yaml] webhook already present – skipping."
+fi
+
 # Strip manager.args block if helmify re-added it (hardcoded in deployment.yaml template)
 python3 - "${VALUES_FILE}" << 'PYEOF'
 import sys, re
@@ -217,12 +244,70 @@ else
     echo "  [deployment.yaml] WATCH_NAMESPACE already present – skipping."
 fi
 
+# 2d. Inject webhook.enabled-conditional ENABLE_WEBHOOKS, webhook port and serving cert
+if ! grep -q "webhook.enabled" "${DEPLOYMENT_FILE}"; then
+    echo "  [deployment.yaml] Injecting webhook.enabled-conditional webhook server..."
+    python3 - "${DEPLOYMENT_FILE}" << 'PYEOF'
+import sys
+
+filename = sys.argv[1]
+with open(filename, 'r') as f:
+    content = f.read()
+
+INJECTIONS = [
+    ('        image: {{ .Values.controllerManager.manager.image.repository }}', """        {{- if .Values.webhook.enabled }}
+        - name: ENABLE_WEBHOOKS
+          value: "true"
+        {{- end }}
+"""),
+    ('        readinessProbe:\n', """        {{- if .Values.webhook.enabled }}
+        - containerPort: 9443
+          name: webhook-server
+          protocol: TCP
+        {{- end }}
+"""),
+    ('      imagePullSecrets:', """        {{- if .Values.webhook.enabled }}
+        volumeMounts:
+        - mountPath: /tmp/k8s-webhook-server/serving-certs
+          name: cert
+          readOnly: true
+        {{- end }}
+"""),
+]
+
+for anchor, injection in INJECTIONS:
+    if anchor not in content:
+        print("  WARNING: anchor %r not found in deployment.yaml." % anchor.strip())
+        sys.exit(1)
+    content = content.replace(anchor, injection + anchor, 1)
+
+content = content.rstrip('\n') + """
+      {{- if .Values.webhook.enabled }}
+      volumes:
+      - name: cert
+        secret:
+          defaultMode: 420
+          secretName: {{ .Values.webhook.certSecretName }}
+      {{- end }}
+"""
+
+with open(filename, 'w') as f:
+    f.write(content)
+print("  [deployment.yaml] Done (webhook server).")
+PYEOF
+else
+    echo "  [deployment.yaml] webhook.enabled already present – skipping."
+fi
+
 # ──────────────────────────────────────────────────────────────────────────────
 # 3. manager-rbac.yaml – scope-conditional ClusterRole vs Role/RoleBinding
+#    The rules are taken from config/rbac/role.yaml (generated from the
+#    +kubebuilder:rbac markers), so the chart follows the markers. Rules for
+#    cluster-scoped resources cannot go into a namespaced Role; in namespace mode
+#    they are granted by a separate ClusterRole.
 # ──────────────────────────────────────────────────────────────────────────────
-if ! grep -q "scope.type" "${MANAGER_RBAC_FILE}"; then
-    echo "  [manager-rbac.yaml] Rewriting with scope-conditional RBAC..."
-    cat > "${MANAGER_RBAC_FILE}" << 'TMPL_EOF'
+echo "  [manager-rbac.yaml] Rewriting with scope-conditional RBAC..."
+cat > "${MANAGER_RBAC_FILE}" << 'TMPL_EOF'
 {{- if eq .Values.scope.type "cluster" }}
 apiVersion: rbac.authorization.k8s.io/v1
 kind: ClusterRole
@@ -231,111 +316,7 @@ metadata:
   labels:
   {{- include "marklogic-operator-kubernetes.labels" . | nindent 4 }}
 rules:
-- apiGroups:
-  - ""
-  resources:
-  - configmaps
-  - pods
-  - secrets
-  - serviceaccounts
-  - services
-  verbs:
-  - create
-  - delete
-  - get
-  - list
-  - patch
-  - update
-  - watch
-- apiGroups:
-  - ""
-  resources:
-  - persistentvolumeclaims
-  verbs:
-  - get
-  - list
-  - patch
-  - update
-  - watch
-- apiGroups:
-  - ""
-  resources:
-  - persistentvolumeclaims/status
-  verbs:
-  - get
-- apiGroups:
-  - ""
-  - events.k8s.io
-  resources:
-  - events
-  verbs:
-  - create
-  - patch
-  - update
-- apiGroups:
-  - apps
-  resources:
-  - daemonsets
-  - deployments
-  - replicasets
-  - statefulsets
-  verbs:
-  - create
-  - delete
-  - get
-  - list
-  - patch
-  - update
-  - watch
-- apiGroups:
-  - marklogic.progress.com
-  resources:
-  - marklogicclusters
-  - marklogicgroups
-  verbs:
-  - create
-  - delete
-  - get
-  - list
-  - patch
-  - update
-  - watch
-- apiGroups:
-  - marklogic.progress.com
-  resources:
-  - marklogicclusters/finalizers
-  - marklogicgroups/finalizers
-  verbs:
-  - update
-- apiGroups:
-  - marklogic.progress.com
-  resources:
-  - marklogicclusters/status
-  - marklogicgroups/status
-  verbs:
-  - get
-  - patch
-  - update
-- apiGroups:
-  - networking.k8s.io
-  resources:
-  - ingresses
-  verbs:
-  - create
-  - delete
-  - get
-  - list
-  - patch
-  - update
-  - watch
-- apiGroups:
-  - storage.k8s.io
-  resources:
-  - storageclasses
-  verbs:
-  - get
-  - list
-  - watch
+__MANAGER_RULES__
 ---
 apiVersion: rbac.authorization.k8s.io/v1
 kind: ClusterRoleBinding
@@ -388,103 +369,7 @@ metadata:
   labels:
   {{- include "marklogic-operator-kubernetes.labels" $ | nindent 4 }}
 rules:
-- apiGroups:
-  - ""
-  resources:
-  - configmaps
-  - pods
-  - secrets
-  - serviceaccounts
-  - services
-  verbs:
-  - create
-  - delete
-  - get
-  - list
-  - patch
-  - update
-  - watch
-- apiGroups:
-  - ""
-  resources:
-  - persistentvolumeclaims
-  verbs:
-  - get
-  - list
-  - patch
-  - update
-  - watch
-- apiGroups:
-  - ""
-  resources:
-  - persistentvolumeclaims/status
-  verbs:
-  - get
-- apiGroups:
-  - ""
-  - events.k8s.io
-  resources:
-  - events
-  verbs:
-  - create
-  - patch
-  - update
-- apiGroups:
-  - apps
-  resources:
-  - daemonsets
-  - deployments
-  - replicasets
-  - statefulsets
-  verbs:
-  - create
-  - delete
-  - get
-  - list
-  - patch
-  - update
-  - watch
-- apiGroups:
-  - marklogic.progress.com
-  resources:
-  - marklogicclusters
-  - marklogicgroups
-  verbs:
-  - create
-  - delete
-  - get
-  - list
-  - patch
-  - update
-  - watch
-- apiGroups:
-  - marklogic.progress.com
-  resources:
-  - marklogicclusters/finalizers
-  - marklogicgroups/finalizers
-  verbs:
-  - update
-- apiGroups:
-  - marklogic.progress.com
-  resources:
-  - marklogicclusters/status
-  - marklogicgroups/status
-  verbs:
-  - get
-  - patch
-  - update
-- apiGroups:
-  - networking.k8s.io
-  resources:
-  - ingresses
-  verbs:
-  - create
-  - delete
-  - get
-  - list
-  - patch
-  - update
-  - watch
+__NAMESPACED_RULES__
 ---
 apiVersion: rbac.authorization.k8s.io/v1
 kind: RoleBinding
@@ -506,9 +391,10 @@ subjects:
   namespace: '{{ $.Release.Namespace }}'
 {{- end }}
 {{- /*
-storageclass is a cluster-scoped resource; a namespaced Role cannot grant access
-to it. A dedicated ClusterRole + ClusterRoleBinding is required in namespace mode
-so the operator can read allowVolumeExpansion and perform PVC resize operations.
+storageclasses and nodes are cluster-scoped resources; a namespaced Role cannot
+grant access to them. A dedicated ClusterRole + ClusterRoleBinding is required in
+namespace mode so the operator can read allowVolumeExpansion and perform PVC resize
+operations, and read the zone labels of nodes for zone-aware forest failover.
 */}}
 ---
 apiVersion: rbac.authorization.k8s.io/v1
@@ -518,14 +404,7 @@ metadata:
   labels:
   {{- include "marklogic-operator-kubernetes.labels" . | nindent 4 }}
 rules:
-- apiGroups:
-  - storage.k8s.io
-  resources:
-  - storageclasses
-  verbs:
-  - get
-  - list
-  - watch
+__CLUSTER_SCOPED_RULES__
 ---
 apiVersion: rbac.authorization.k8s.io/v1
 kind: ClusterRoleBinding
@@ -543,10 +422,57 @@ subjects:
   namespace: '{{ .Release.Namespace }}'
 {{- end }}
 TMPL_EOF
-    echo "  [manager-rbac.yaml] Done."
-else
-    echo "  [manager-rbac.yaml] scope logic already present – skipping."
-fi
+python3 - "${MANAGER_RBAC_FILE}" "config/rbac/role.yaml" << 'PYEOF'
+import sys
+
+CLUSTER_SCOPED = {"nodes", "storageclasses"}
+
+def parse_rules(filename):
+    rules, rule, key = [], None, None
+    with open(filename, 'r') as f:
+        lines = f.read().split('rules:\n', 1)[1].splitlines()
+    for line in lines:
+        if line.startswith('- '):
+            rule = {}
+            rules.append(rule)
+            line = '  ' + line[2:]
+        if line.startswith('  - '):
+            rule[key].append(line[4:])
+        elif line.startswith('  ') and line.endswith(':'):
+            key = line.strip()[:-1]
+            rule[key] = []
+        else:
+            break
+    return rules
+
+def render(rules):
+    out = []
+    for rule in rules:
+        for i, key in enumerate(rule):
+            out.append(('- ' if i == 0 else '  ') + key + ':')
+            out.extend('  - ' + value for value in rule[key])
+    return '\n'.join(out)
+
+cluster_rules = parse_rules(sys.argv[2])
+namespaced_rules, cluster_scoped_rules = [], []
+for rule in cluster_rules:
+    resources = rule.get('resources', [])
+    namespaced = [r for r in resources if r.split('/')[0] not in CLUSTER_SCOPED]
+    scoped = [r for r in resources if r.split('/')[0] in CLUSTER_SCOPED]
+    if namespaced:
+        namespaced_rules.append(dict(rule, resources=namespaced))
+    if scoped:
+        cluster_scoped_rules.append(dict(rule, resources=scoped))
+
+with open(sys.argv[1], 'r') as f:
+    content = f.read()
+content = content.replace('__MANAGER_RULES__', render(cluster_rules))
+content = content.replace('__NAMESPACED_RULES__', render(namespaced_rules))
+content = content.replace('__CLUSTER_SCOPED_RULES__', render(cluster_scoped_rules))
+with open(sys.argv[1], 'w') as f:
+    f.write(content)
+PYEOF
+echo "  [manager-rbac.yaml] Done."
 
 # ──────────────────────────────────────────────────────────────────────────────
 # 4. metrics-auth-rbac.yaml – guard with scope=cluster AND metrics.secure=true.
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 
 	"k8s.io/apimachinery/pkg/runtime"
@@ -34,7 +35,9 @@ import (
 	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
 	"github.com/marklogic/marklogic-operator-kubernetes/pkg/k8sutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 // MarklogicClusterReconciler reconciles a MarklogicCluster object
@@ -49,6 +52,7 @@ type MarklogicClusterReconciler struct {
 //+kubebuilder:rbac:groups=marklogic.progress.com,resources=marklogicclusters/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=marklogic.progress.com,resources=marklogicclusters/finalizers,verbs=update
 //+kubebuilder:rbac:groups=core,resources=serviceaccounts,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=core,resources=pods/portforward,verbs=create
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -114,6 +118,15 @@ func markLogicClusterCreateUpdateDeletePredicate() predicate.Predicate {
 				if !reflect.DeepEqual(oldObj.Spec, newObj.Spec) {
 					return true // Reconcile if spec has changed
 				}
+			case *marklogicv1.MarklogicGroup:
+				// Owned groups only matter when a field rolled into the cluster status changed.
+				oldGroup := e.ObjectOld.(*marklogicv1.MarklogicGroup)
+				newGroup := e.ObjectNew.(*marklogicv1.MarklogicGroup)
+				return !reflect.DeepEqual(k8sutil.SummarizeMarklogicGroup(oldGroup), k8sutil.SummarizeMarklogicGroup(newGroup))
+			case *corev1.Secret:
+				oldObj := e.ObjectOld.(*corev1.Secret)
+				newObj := e.ObjectNew.(*corev1.Secret)
+				return !reflect.DeepEqual(oldObj.Data, newObj.Data) // Reconcile when the admin password is changed
 			default:
 				return false // Ignore updates for other types
 			}
@@ -135,5 +148,19 @@ func (r *MarklogicClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		For(&marklogicv1.MarklogicCluster{}).
 		WithEventFilter(markLogicClusterCreateUpdateDeletePredicate()).
 		Owns(&marklogicv1.MarklogicGroup{}).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.secretToMarklogicClusters)).
 		Complete(r)
 }
+
+// secretToMarklogicClusters maps an admin secret to the clusters rotating their password from it.
+func (r *MarklogicClusterReconciler) secretToMarklogicClusters(ctx context.Context, obj client.Object) []reconcile.Request {
+	clusters := &marklogicv1.MarklogicClusterList{}
+	if err := r.List(ctx, clusters, client.InNamespace(obj.GetNamespace())); err != nil {
+		return nil
+	}
+	requests := []reconcile.Request{}
+	for _, name := range k8sutil.MarklogicClustersForAdminSecret(clusters.Items, obj) {
+		requests = append(requests, reconcile.Request{NamespacedName: name})
+	}
+	return requests
+}
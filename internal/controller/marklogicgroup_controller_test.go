@@ -283,7 +283,7 @@ var _ = Describe("MarkLogicGroup controller", func() {
 
 			factoryCallCount := 0
 			originalFactory := k8sutil.NewDynamicManagementClient
-			k8sutil.NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+			k8sutil.NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.DynamicHostClient {
 				if strings.Contains(opts.Host, staticName) {
 					factoryCallCount++
 				}
@@ -322,7 +322,7 @@ var _ = Describe("MarkLogicGroup controller", func() {
 
 			behavior := &fakeDynamicManagementBehavior{listHostsErr: errors.New("connection refused")}
 			originalFactory := k8sutil.NewDynamicManagementClient
-			k8sutil.NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+			k8sutil.NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.DynamicHostClient {
 				return &fakeDynamicManagementClient{behavior: behavior}
 			}
 			defer func() { k8sutil.NewDynamicManagementClient = originalFactory }()
@@ -370,7 +370,7 @@ var _ = Describe("MarkLogicGroup controller", func() {
 
 			behavior := &fakeDynamicManagementBehavior{hosts: []mlmanage.HostStatus{{Name: "bootstrap-0", Online: true, Version: "11.0-1"}}}
 			originalFactory := k8sutil.NewDynamicManagementClient
-			k8sutil.NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+			k8sutil.NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.DynamicHostClient {
 				return &fakeDynamicManagementClient{behavior: behavior}
 			}
 			defer func() { k8sutil.NewDynamicManagementClient = originalFactory }()
@@ -420,7 +420,7 @@ var _ = Describe("MarkLogicGroup controller", func() {
 			callsMu := &sync.Mutex{}
 			behavior := &fakeDynamicManagementBehavior{hosts: []mlmanage.HostStatus{{Name: "bootstrap-0", Online: true, Version: "12.0-1"}}, groupInfo: mlmanage.GroupInfo{Exists: false}, autoRegisterOnJoin: true, hostIDsByHost: map[string]string{host0: "host-id-0"}}
 			originalFactory := k8sutil.NewDynamicManagementClient
-			k8sutil.NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+			k8sutil.NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.DynamicHostClient {
 				return &fakeDynamicManagementClient{behavior: behavior, callsMu: callsMu, tokenHostCalls: &tokenHostCalls}
 			}
 			defer func() { k8sutil.NewDynamicManagementClient = originalFactory }()
@@ -476,7 +476,7 @@ var _ = Describe("MarkLogicGroup controller", func() {
 				groupInfo: mlmanage.GroupInfo{Exists: false},
 			}
 			originalFactory := k8sutil.NewDynamicManagementClient
-			k8sutil.NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+			k8sutil.NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.DynamicHostClient {
 				return &fakeDynamicManagementClient{behavior: behavior, calls: &calls, callsMu: callsMu}
 			}
 			defer func() { k8sutil.NewDynamicManagementClient = originalFactory }()
@@ -542,7 +542,7 @@ var _ = Describe("MarkLogicGroup controller", func() {
 				groupInfo: mlmanage.GroupInfo{Exists: false},
 			}
 			originalFactory := k8sutil.NewDynamicManagementClient
-			k8sutil.NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+			k8sutil.NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.DynamicHostClient {
 				return &fakeDynamicManagementClient{behavior: behavior, callsMu: &sync.Mutex{}}
 			}
 			defer func() { k8sutil.NewDynamicManagementClient = originalFactory }()
@@ -643,7 +643,7 @@ var _ = Describe("MarkLogicGroup controller", func() {
 			}
 
 			originalFactory := k8sutil.NewDynamicManagementClient
-			k8sutil.NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+			k8sutil.NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.DynamicHostClient {
 				return &fakeDynamicManagementClient{behavior: behavior, calls: &calls, callsMu: callsMu, tokenHostCalls: &tokenHostCalls}
 			}
 			defer func() { k8sutil.NewDynamicManagementClient = originalFactory }()
@@ -729,7 +729,7 @@ var _ = Describe("MarkLogicGroup controller", func() {
 			}
 
 			originalFactory := k8sutil.NewDynamicManagementClient
-			k8sutil.NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+			k8sutil.NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.DynamicHostClient {
 				return &fakeDynamicManagementClient{behavior: behavior, callsMu: callsMu, tokenHostCalls: &tokenHostCalls}
 			}
 			defer func() { k8sutil.NewDynamicManagementClient = originalFactory }()
@@ -791,7 +791,7 @@ var _ = Describe("MarkLogicGroup controller", func() {
 			}
 
 			originalFactory := k8sutil.NewDynamicManagementClient
-			k8sutil.NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+			k8sutil.NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.DynamicHostClient {
 				return &fakeDynamicManagementClient{behavior: behavior, callsMu: &sync.Mutex{}}
 			}
 			defer func() { k8sutil.NewDynamicManagementClient = originalFactory }()
@@ -857,7 +857,7 @@ var _ = Describe("MarkLogicGroup controller", func() {
 			}
 
 			originalFactory := k8sutil.NewDynamicManagementClient
-			k8sutil.NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+			k8sutil.NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.DynamicHostClient {
 				return &fakeDynamicManagementClient{behavior: behavior, callsMu: &sync.Mutex{}}
 			}
 			defer func() { k8sutil.NewDynamicManagementClient = originalFactory }()
@@ -920,7 +920,7 @@ var _ = Describe("MarkLogicGroup controller", func() {
 			}
 
 			originalFactory := k8sutil.NewDynamicManagementClient
-			k8sutil.NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+			k8sutil.NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.DynamicHostClient {
 				return &fakeDynamicManagementClient{behavior: behavior, callsMu: &sync.Mutex{}}
 			}
 			defer func() { k8sutil.NewDynamicManagementClient = originalFactory }()
@@ -1013,7 +1013,7 @@ var _ = Describe("MarkLogicGroup controller", func() {
 			behavior := &fakeDynamicManagementBehavior{hosts: []mlmanage.HostStatus{{Name: "bootstrap-0", Online: true, Version: "12.0-1"}}, groupInfo: mlmanage.GroupInfo{Exists: false}, autoRegisterOnJoin: true, hostIDsByHost: map[string]string{host0: "host-id-0"}}
 
 			originalFactory := k8sutil.NewDynamicManagementClient
-			k8sutil.NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+			k8sutil.NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.DynamicHostClient {
 				return &fakeDynamicManagementClient{behavior: behavior, callsMu: &callsMu, tokenHostCalls: &tokenHostCalls}
 			}
 			defer func() { k8sutil.NewDynamicManagementClient = originalFactory }()
@@ -1107,7 +1107,7 @@ var _ = Describe("MarkLogicGroup controller", func() {
 			}
 
 			originalFactory := k8sutil.NewDynamicManagementClient
-			k8sutil.NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+			k8sutil.NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.DynamicHostClient {
 				return &fakeDynamicManagementClient{behavior: behavior, callsMu: callsMu, removeHostCalls: &removeHostCalls}
 			}
 			defer func() { k8sutil.NewDynamicManagementClient = originalFactory }()
@@ -1187,7 +1187,7 @@ var _ = Describe("MarkLogicGroup controller", func() {
 			behavior := &fakeDynamicManagementBehavior{hosts: []mlmanage.HostStatus{{Name: "bootstrap-0", Online: true, Version: "12.0-1"}}, groupInfo: mlmanage.GroupInfo{Exists: false}, autoRegisterOnJoin: true, hostIDsByHost: map[string]string{host0: "host-id-0", host1: "host-id-1"}}
 
 			originalFactory := k8sutil.NewDynamicManagementClient
-			k8sutil.NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+			k8sutil.NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.DynamicHostClient {
 				return &fakeDynamicManagementClient{behavior: behavior, callsMu: callsMu, removeHostCalls: &removeHostCalls}
 			}
 			defer func() { k8sutil.NewDynamicManagementClient = originalFactory }()
@@ -1262,7 +1262,7 @@ var _ = Describe("MarkLogicGroup controller", func() {
 			behavior := &fakeDynamicManagementBehavior{hosts: []mlmanage.HostStatus{{Name: "bootstrap-0", Online: true, Version: "12.0-1"}}, groupInfo: mlmanage.GroupInfo{Exists: false}, autoRegisterOnJoin: true, hostIDsByHost: map[string]string{emptyDirHost: "host-id-emptydir", pvcHost: "host-id-pvc"}}
 
 			originalFactory := k8sutil.NewDynamicManagementClient
-			k8sutil.NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+			k8sutil.NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.DynamicHostClient {
 				return &fakeDynamicManagementClient{behavior: behavior, callsMu: callsMu, removeHostCalls: &removeHostCalls}
 			}
 			defer func() { k8sutil.NewDynamicManagementClient = originalFactory }()
@@ -1353,7 +1353,7 @@ var _ = Describe("MarkLogicGroup controller", func() {
 			behavior := &fakeDynamicManagementBehavior{hosts: []mlmanage.HostStatus{{Name: "bootstrap-0", Online: true, Version: "12.0-1"}}, groupInfo: mlmanage.GroupInfo{Exists: false}, autoRegisterOnJoin: true, hostIDsByHost: map[string]string{host0: "host-id-0"}}
 
 			originalFactory := k8sutil.NewDynamicManagementClient
-			k8sutil.NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+			k8sutil.NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.DynamicHostClient {
 				return &fakeDynamicManagementClient{behavior: behavior, callsMu: callsMu, removeHostCalls: &removeHostCalls}
 			}
 			defer func() { k8sutil.NewDynamicManagementClient = originalFactory }()
@@ -1431,7 +1431,7 @@ var _ = Describe("MarkLogicGroup controller", func() {
 			behavior := &fakeDynamicManagementBehavior{hosts: []mlmanage.HostStatus{{Name: "bootstrap-0", Online: true, Version: "12.0-1"}}, groupInfo: mlmanage.GroupInfo{Exists: false}, autoRegisterOnJoin: true, hostIDsByHost: map[string]string{host0: "host-id-0"}}
 
 			originalFactory := k8sutil.NewDynamicManagementClient
-			k8sutil.NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+			k8sutil.NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.DynamicHostClient {
 				return &fakeDynamicManagementClient{behavior: behavior, callsMu: &sync.Mutex{}}
 			}
 			defer func() { k8sutil.NewDynamicManagementClient = originalFactory }()
@@ -1493,7 +1493,7 @@ var _ = Describe("MarkLogicGroup controller", func() {
 			behavior := &fakeDynamicManagementBehavior{hosts: []mlmanage.HostStatus{{Name: "bootstrap-0", Online: true, Version: "12.0-1"}}, groupInfo: mlmanage.GroupInfo{Exists: false}, autoRegisterOnJoin: true, hostIDsByHost: map[string]string{host0: "host-id-0"}, removeErrByHostID: map[string]error{"host-id-0": errors.New("connection refused")}}
 
 			originalFactory := k8sutil.NewDynamicManagementClient
-			k8sutil.NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+			k8sutil.NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.DynamicHostClient {
 				return &fakeDynamicManagementClient{behavior: behavior, callsMu: &sync.Mutex{}}
 			}
 			defer func() { k8sutil.NewDynamicManagementClient = originalFactory }()
@@ -1562,7 +1562,7 @@ var _ = Describe("MarkLogicGroup controller", func() {
 			behavior := &fakeDynamicManagementBehavior{hosts: []mlmanage.HostStatus{{Name: "bootstrap-0", Online: true, Version: "12.0-1"}}, groupInfo: mlmanage.GroupInfo{Exists: false}, autoRegisterOnJoin: true, hostIDsByHost: map[string]string{host0: "host-id-old"}}
 
 			originalFactory := k8sutil.NewDynamicManagementClient
-			k8sutil.NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+			k8sutil.NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.DynamicHostClient {
 				return &fakeDynamicManagementClient{behavior: behavior, callsMu: &sync.Mutex{}}
 			}
 			defer func() { k8sutil.NewDynamicManagementClient = originalFactory }()
@@ -1646,7 +1646,7 @@ var _ = Describe("MarkLogicGroup controller", func() {
 			behavior := &fakeDynamicManagementBehavior{hosts: []mlmanage.HostStatus{{Name: "bootstrap-0", Online: true, Version: "12.0-1"}}, groupInfo: mlmanage.GroupInfo{Exists: false}, autoRegisterOnJoin: true, hostIDsByHost: map[string]string{host0: "host-id-initial"}}
 
 			originalFactory := k8sutil.NewDynamicManagementClient
-			k8sutil.NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+			k8sutil.NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.DynamicHostClient {
 				return &fakeDynamicManagementClient{behavior: behavior, callsMu: callsMu, tokenHostCalls: &tokenHostCalls}
 			}
 			defer func() { k8sutil.NewDynamicManagementClient = originalFactory }()
@@ -1739,7 +1739,7 @@ var _ = Describe("MarkLogicGroup controller", func() {
 			behavior := &fakeDynamicManagementBehavior{hosts: []mlmanage.HostStatus{{Name: "bootstrap-0", Online: true, Version: "12.0-1"}}, groupInfo: mlmanage.GroupInfo{Exists: false}, autoRegisterOnJoin: true, hostIDsByHost: map[string]string{host0: oldHostID}}
 
 			originalFactory := k8sutil.NewDynamicManagementClient
-			k8sutil.NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+			k8sutil.NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.DynamicHostClient {
 				return &fakeDynamicManagementClient{behavior: behavior, callsMu: callsMu, calls: &calls, tokenHostCalls: &tokenHostCalls, removeHostCalls: &removeHostCalls}
 			}
 			defer func() { k8sutil.NewDynamicManagementClient = originalFactory }()
@@ -1906,7 +1906,7 @@ var _ = Describe("MarkLogicGroup controller", func() {
 			defer func() { k8sutil.DynamicPVCRestartCleanup = originalCleanup }()
 
 			originalFactory := k8sutil.NewDynamicManagementClient
-			k8sutil.NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+			k8sutil.NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.DynamicHostClient {
 				return &fakeDynamicManagementClient{behavior: behavior, callsMu: callsMu, tokenHostCalls: &tokenHostCalls}
 			}
 			defer func() { k8sutil.NewDynamicManagementClient = originalFactory }()
@@ -2032,7 +2032,7 @@ var _ = Describe("MarkLogicGroup controller", func() {
 			behavior := &fakeDynamicManagementBehavior{hosts: []mlmanage.HostStatus{{Name: "bootstrap-0", Online: true, Version: "12.0-1"}}, groupInfo: mlmanage.GroupInfo{Exists: false}, autoRegisterOnJoin: true, hostIDsByHost: map[string]string{host0: "host-id-0", host1: "host-id-1"}}
 
 			originalFactory := k8sutil.NewDynamicManagementClient
-			k8sutil.NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+			k8sutil.NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.DynamicHostClient {
 				return &fakeDynamicManagementClient{behavior: behavior, callsMu: &callsMu, tokenHostCalls: &tokenHostCalls}
 			}
 			defer func() { k8sutil.NewDynamicManagementClient = originalFactory }()
@@ -2115,7 +2115,7 @@ var _ = Describe("MarkLogicGroup controller", func() {
 			behavior := &fakeDynamicManagementBehavior{hosts: []mlmanage.HostStatus{{Name: "bootstrap-0", Online: true, Version: "12.0-1"}}, groupInfo: mlmanage.GroupInfo{Exists: false}, autoRegisterOnJoin: true, hostIDsByHost: map[string]string{host0: "host-id-0"}}
 
 			originalFactory := k8sutil.NewDynamicManagementClient
-			k8sutil.NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+			k8sutil.NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.DynamicHostClient {
 				return &fakeDynamicManagementClient{behavior: behavior, callsMu: &sync.Mutex{}}
 			}
 			defer func() { k8sutil.NewDynamicManagementClient = originalFactory }()
@@ -2827,3 +2827,13 @@ func hasOrderedSubsequence(calls []string, expected []string) bool {
 	}
 	return false
 }
+
+func (f *fakeDynamicManagementClient) LeaveCluster(ctx context.Context, hostFQDN string) error {
+	f.record("LeaveCluster")
+	return nil
+}
+
+func (f *fakeDynamicManagementClient) UpdateGroupProperties(ctx context.Context, groupName string, properties map[string]any) error {
+	f.record("UpdateGroupProperties")
+	return nil
+}
@@ -0,0 +1,68 @@
+/*
+Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/k8sutil"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// MarklogicReplicationReconciler reconciles a MarklogicReplication object
+type MarklogicReplicationReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Log      logr.Logger
+	Recorder record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups=marklogic.progress.com,resources=marklogicreplications,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=marklogic.progress.com,resources=marklogicreplications/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=marklogic.progress.com,resources=marklogicreplications/finalizers,verbs=update
+
+func (r *MarklogicReplicationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	rc, err := k8sutil.CreateReplicationContext(ctx, &req, r.Client, r.Scheme, r.Recorder)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			logger.Info("MarklogicReplication resource not found. Exiting reconcile loop since there is nothing to do")
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "Failed to get MarklogicReplication resource")
+		return ctrl.Result{}, err
+	}
+
+	return rc.ReconcileMarklogicReplicationHandler()
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *MarklogicReplicationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&marklogicv1.MarklogicReplication{}).
+		WithEventFilter(predicate.GenerationChangedPredicate{}).
+		Complete(r)
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package v1
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+)
+
+// Defaults mirror the +kubebuilder:default markers on the API types so objects created
+// through paths that skip schema defaulting end up with the same spec.
+var (
+	defaultLiveness = marklogicv1.ContainerProbe{
+		Enabled:             true,
+		InitialDelaySeconds: 30,
+		TimeoutSeconds:      5,
+		PeriodSeconds:       30,
+		SuccessThreshold:    1,
+		FailureThreshold:    3,
+	}
+	defaultReadiness = marklogicv1.ContainerProbe{
+		Enabled:             true,
+		InitialDelaySeconds: 10,
+		TimeoutSeconds:      5,
+		PeriodSeconds:       30,
+		SuccessThreshold:    1,
+		FailureThreshold:    3,
+	}
+)
+
+const (
+	defaultHAProxyFrontendPort int32 = 80
+	defaultHAProxyStatsPort    int32 = 1024
+)
+
+func defaultLivenessProbe(probe *marklogicv1.ContainerProbe) {
+	defaultProbe(probe, defaultLiveness)
+}
+
+func defaultReadinessProbe(probe *marklogicv1.ContainerProbe) {
+	defaultProbe(probe, defaultReadiness)
+}
+
+// defaultProbe replaces an empty probe with the defaults and fills zero timings on an
+// enabled probe. A probe that is explicitly disabled with other fields set is left alone.
+func defaultProbe(probe *marklogicv1.ContainerProbe, defaults marklogicv1.ContainerProbe) {
+	if *probe == (marklogicv1.ContainerProbe{}) {
+		*probe = defaults
+		return
+	}
+	if !probe.Enabled {
+		return
+	}
+	if probe.TimeoutSeconds == 0 {
+		probe.TimeoutSeconds = defaults.TimeoutSeconds
+	}
+	if probe.PeriodSeconds == 0 {
+		probe.PeriodSeconds = defaults.PeriodSeconds
+	}
+	if probe.SuccessThreshold == 0 {
+		probe.SuccessThreshold = defaults.SuccessThreshold
+	}
+	if probe.FailureThreshold == 0 {
+		probe.FailureThreshold = defaults.FailureThreshold
+	}
+}
+
+func defaultPersistence(persistence *marklogicv1.Persistence) {
+	if persistence == nil {
+		return
+	}
+	if persistence.ResizeStrategy == "" {
+		persistence.ResizeStrategy = marklogicv1.VolumeResizeStrategyParallel
+	}
+}
+
+func defaultHAProxy(haproxy *marklogicv1.HAProxy) {
+	if haproxy == nil {
+		return
+	}
+	if haproxy.FrontendPort == 0 {
+		haproxy.FrontendPort = defaultHAProxyFrontendPort
+	}
+	if haproxy.Stats.Port == 0 {
+		haproxy.Stats.Port = defaultHAProxyStatsPort
+	}
+	defaultAppServerPorts(haproxy.AppServers)
+	defaultTcpPorts(haproxy.TcpPorts)
+}
+
+// defaultAppServerPorts makes the implicit targetPort == port mapping used by the
+// HAProxy config generator explicit in the spec.
+func defaultAppServerPorts(appServers []marklogicv1.AppServers) {
+	for i := range appServers {
+		if appServers[i].TargetPort == 0 {
+			appServers[i].TargetPort = appServers[i].Port
+		}
+	}
+}
+
+func defaultTcpPorts(tcpPorts *marklogicv1.Tcpports) {
+	if tcpPorts == nil {
+		return
+	}
+	for i := range tcpPorts.Ports {
+		if tcpPorts.Ports[i].TargetPort == 0 {
+			tcpPorts.Ports[i].TargetPort = tcpPorts.Ports[i].Port
+		}
+	}
+}
+
+// validatePersistence rejects sizes that would make resource.MustParse panic when the
+// StatefulSet volume claim template is generated.
+func validatePersistence(persistence *marklogicv1.Persistence, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if persistence == nil || persistence.Size == "" {
+		return allErrs
+	}
+	quantity, err := resource.ParseQuantity(persistence.Size)
+	if err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("size"), persistence.Size, err.Error()))
+		return allErrs
+	}
+	if quantity.Sign() <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("size"), persistence.Size, "must be greater than zero"))
+	}
+	return allErrs
+}
+
+// validateAutoscaling rejects autoscaling on groups without dynamic hosts, which only shrink
+// through decommissioning, and a minReplicas above maxReplicas.
+func validateAutoscaling(autoscaling *marklogicv1.GroupAutoscaling, isDynamic bool, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if autoscaling == nil {
+		return allErrs
+	}
+	if !isDynamic {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "autoscaling can only be set when isDynamic is true"))
+	}
+	if autoscaling.MinReplicas > autoscaling.MaxReplicas {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("minReplicas"), autoscaling.MinReplicas,
+			fmt.Sprintf("must not exceed maxReplicas (%d)", autoscaling.MaxReplicas)))
+	}
+	return allErrs
+}
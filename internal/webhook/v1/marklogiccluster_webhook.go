@@ -0,0 +1,191 @@
+/*
+Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+)
+
+var marklogicclusterlog = logf.Log.WithName("marklogiccluster-resource")
+
+// SetupMarklogicClusterWebhookWithManager registers the defaulting and validating webhooks for MarklogicCluster.
+func SetupMarklogicClusterWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&marklogicv1.MarklogicCluster{}).
+		WithDefaulter(&MarklogicClusterCustomDefaulter{}).
+		WithValidator(&MarklogicClusterCustomValidator{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-marklogic-progress-com-v1-marklogiccluster,mutating=true,failurePolicy=fail,sideEffects=None,groups=marklogic.progress.com,resources=marklogicclusters,verbs=create;update,versions=v1,name=mmarklogiccluster-v1.kb.io,admissionReviewVersions=v1
+
+// MarklogicClusterCustomDefaulter fills in the defaults the controllers rely on when the
+// CRD schema defaults were not applied (for example a partially populated probe or a
+// persistence block without resizeStrategy).
+type MarklogicClusterCustomDefaulter struct{}
+
+var _ admission.CustomDefaulter = &MarklogicClusterCustomDefaulter{}
+
+// Default implements admission.CustomDefaulter.
+func (d *MarklogicClusterCustomDefaulter) Default(_ context.Context, obj runtime.Object) error {
+	cluster, ok := obj.(*marklogicv1.MarklogicCluster)
+	if !ok {
+		return fmt.Errorf("expected a MarklogicCluster object but got %T", obj)
+	}
+	marklogicclusterlog.V(1).Info("Defaulting for MarklogicCluster", "name", cluster.GetName())
+
+	defaultPersistence(cluster.Spec.Persistence)
+	defaultHAProxy(cluster.Spec.HAProxy)
+	for _, group := range cluster.Spec.MarkLogicGroups {
+		if group == nil {
+			continue
+		}
+		defaultLivenessProbe(&group.LivenessProbe)
+		defaultReadinessProbe(&group.ReadinessProbe)
+		defaultPersistence(group.Persistence)
+		defaultTcpPorts(groupTcpPorts(group.HAProxy))
+		if group.HAProxy != nil {
+			defaultAppServerPorts(group.HAProxy.AppServers)
+		}
+	}
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate-marklogic-progress-com-v1-marklogiccluster,mutating=false,failurePolicy=fail,sideEffects=None,groups=marklogic.progress.com,resources=marklogicclusters,verbs=create;update,versions=v1,name=vmarklogiccluster-v1.kb.io,admissionReviewVersions=v1
+
+// MarklogicClusterCustomValidator rejects MarklogicCluster specs the controllers cannot reconcile.
+type MarklogicClusterCustomValidator struct{}
+
+var _ admission.CustomValidator = &MarklogicClusterCustomValidator{}
+
+// ValidateCreate implements admission.CustomValidator.
+func (v *MarklogicClusterCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	cluster, ok := obj.(*marklogicv1.MarklogicCluster)
+	if !ok {
+		return nil, fmt.Errorf("expected a MarklogicCluster object but got %T", obj)
+	}
+	marklogicclusterlog.V(1).Info("Validation for MarklogicCluster upon creation", "name", cluster.GetName())
+
+	return nil, clusterInvalidError(cluster, validateMarklogicCluster(cluster))
+}
+
+// ValidateUpdate implements admission.CustomValidator.
+func (v *MarklogicClusterCustomValidator) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldCluster, ok := oldObj.(*marklogicv1.MarklogicCluster)
+	if !ok {
+		return nil, fmt.Errorf("expected a MarklogicCluster object for the oldObj but got %T", oldObj)
+	}
+	cluster, ok := newObj.(*marklogicv1.MarklogicCluster)
+	if !ok {
+		return nil, fmt.Errorf("expected a MarklogicCluster object for the newObj but got %T", newObj)
+	}
+	marklogicclusterlog.V(1).Info("Validation for MarklogicCluster upon update", "name", cluster.GetName())
+
+	allErrs := validateMarklogicCluster(cluster)
+	allErrs = append(allErrs, validateMarklogicClusterUpdate(oldCluster, cluster)...)
+	return nil, clusterInvalidError(cluster, allErrs)
+}
+
+// ValidateDelete implements admission.CustomValidator.
+func (v *MarklogicClusterCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateMarklogicCluster(cluster *marklogicv1.MarklogicCluster) field.ErrorList {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+	groupsPath := specPath.Child("markLogicGroups")
+
+	allErrs = append(allErrs, validatePersistence(cluster.Spec.Persistence, specPath.Child("persistence"))...)
+
+	seenNames := map[string]int{}
+	bootstrapIndex := -1
+	for i, group := range cluster.Spec.MarkLogicGroups {
+		if group == nil {
+			continue
+		}
+		groupPath := groupsPath.Index(i)
+		if group.Name != "" {
+			if first, exists := seenNames[group.Name]; exists {
+				allErrs = append(allErrs, field.Duplicate(groupPath.Child("name"),
+					fmt.Sprintf("%s (already used by %s)", group.Name, groupsPath.Index(first).Child("name"))))
+			} else {
+				seenNames[group.Name] = i
+			}
+		}
+		if group.IsBootstrap {
+			if bootstrapIndex >= 0 {
+				allErrs = append(allErrs, field.Invalid(groupPath.Child("isBootstrap"), true,
+					fmt.Sprintf("only one MarkLogic group may be the bootstrap group; %s is already set",
+						groupsPath.Index(bootstrapIndex).Child("isBootstrap"))))
+			} else {
+				bootstrapIndex = i
+			}
+		}
+		allErrs = append(allErrs, validatePersistence(group.Persistence, groupPath.Child("persistence"))...)
+		allErrs = append(allErrs, validateAutoscaling(group.Autoscaling, group.IsDynamic, groupPath.Child("autoscaling"))...)
+	}
+	return allErrs
+}
+
+// validateMarklogicClusterUpdate matches groups by name because markLogicGroups entries
+// can be reordered or appended; CEL cannot correlate them across updates.
+func validateMarklogicClusterUpdate(oldCluster, cluster *marklogicv1.MarklogicCluster) field.ErrorList {
+	var allErrs field.ErrorList
+	groupsPath := field.NewPath("spec").Child("markLogicGroups")
+
+	oldDynamic := map[string]bool{}
+	for _, group := range oldCluster.Spec.MarkLogicGroups {
+		if group != nil && group.Name != "" {
+			oldDynamic[group.Name] = group.IsDynamic
+		}
+	}
+	for i, group := range cluster.Spec.MarkLogicGroups {
+		if group == nil {
+			continue
+		}
+		wasDynamic, exists := oldDynamic[group.Name]
+		if exists && wasDynamic != group.IsDynamic {
+			allErrs = append(allErrs, field.Forbidden(groupsPath.Index(i).Child("isDynamic"),
+				fmt.Sprintf("isDynamic is immutable for existing group %q (was %t)", group.Name, wasDynamic)))
+		}
+	}
+	return allErrs
+}
+
+func clusterInvalidError(cluster *marklogicv1.MarklogicCluster, allErrs field.ErrorList) error {
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(marklogicv1.GroupVersion.WithKind("MarklogicCluster").GroupKind(), cluster.Name, allErrs)
+}
+
+func groupTcpPorts(group *marklogicv1.HAProxyGroup) *marklogicv1.Tcpports {
+	if group == nil {
+		return nil
+	}
+	return group.TcpPorts
+}
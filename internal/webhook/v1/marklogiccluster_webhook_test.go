@@ -0,0 +1,158 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package v1
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newWebhookTestCluster(groups ...*marklogicv1.MarklogicGroups) *marklogicv1.MarklogicCluster {
+	return &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			Image:           "progressofficial/marklogic-db:12.0.3",
+			MarkLogicGroups: groups,
+		},
+	}
+}
+
+func expectFieldError(t *testing.T, err error, fieldPath string) {
+	t.Helper()
+	if err == nil {
+		t.Fatalf("expected validation error for %s, got nil", fieldPath)
+	}
+	if !apierrors.IsInvalid(err) {
+		t.Fatalf("expected Invalid error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), fieldPath) {
+		t.Fatalf("expected error to reference %s, got %v", fieldPath, err)
+	}
+}
+
+func TestMarklogicClusterValidateCreate(t *testing.T) {
+	validator := &MarklogicClusterCustomValidator{}
+
+	t.Run("accepts a valid cluster", func(t *testing.T) {
+		cluster := newWebhookTestCluster(
+			&marklogicv1.MarklogicGroups{Name: "dnode", IsBootstrap: true, Persistence: &marklogicv1.Persistence{Enabled: true, Size: "10Gi"}},
+			&marklogicv1.MarklogicGroups{Name: "enode"},
+		)
+		if _, err := validator.ValidateCreate(context.Background(), cluster); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("rejects duplicate group names", func(t *testing.T) {
+		cluster := newWebhookTestCluster(
+			&marklogicv1.MarklogicGroups{Name: "dnode", IsBootstrap: true},
+			&marklogicv1.MarklogicGroups{Name: "dnode"},
+		)
+		_, err := validator.ValidateCreate(context.Background(), cluster)
+		expectFieldError(t, err, "spec.markLogicGroups[1].name")
+	})
+
+	t.Run("rejects more than one bootstrap group", func(t *testing.T) {
+		cluster := newWebhookTestCluster(
+			&marklogicv1.MarklogicGroups{Name: "dnode", IsBootstrap: true},
+			&marklogicv1.MarklogicGroups{Name: "enode", IsBootstrap: true},
+		)
+		_, err := validator.ValidateCreate(context.Background(), cluster)
+		expectFieldError(t, err, "spec.markLogicGroups[1].isBootstrap")
+	})
+
+	t.Run("rejects unparsable persistence size", func(t *testing.T) {
+		cluster := newWebhookTestCluster(
+			&marklogicv1.MarklogicGroups{Name: "dnode", IsBootstrap: true, Persistence: &marklogicv1.Persistence{Enabled: true, Size: "ten gigs"}},
+		)
+		cluster.Spec.Persistence = &marklogicv1.Persistence{Enabled: true, Size: "0"}
+		_, err := validator.ValidateCreate(context.Background(), cluster)
+		expectFieldError(t, err, "spec.markLogicGroups[0].persistence.size")
+		expectFieldError(t, err, "spec.persistence.size")
+	})
+
+	t.Run("rejects autoscaling outside dynamic groups", func(t *testing.T) {
+		cluster := newWebhookTestCluster(
+			&marklogicv1.MarklogicGroups{Name: "dnode", IsBootstrap: true, Autoscaling: &marklogicv1.GroupAutoscaling{MinReplicas: 1, MaxReplicas: 3}},
+			&marklogicv1.MarklogicGroups{Name: "enode", IsDynamic: true, Autoscaling: &marklogicv1.GroupAutoscaling{MinReplicas: 4, MaxReplicas: 3}},
+		)
+		_, err := validator.ValidateCreate(context.Background(), cluster)
+		expectFieldError(t, err, "spec.markLogicGroups[0].autoscaling")
+		expectFieldError(t, err, "spec.markLogicGroups[1].autoscaling.minReplicas")
+	})
+}
+
+func TestMarklogicClusterValidateUpdate(t *testing.T) {
+	validator := &MarklogicClusterCustomValidator{}
+	oldCluster := newWebhookTestCluster(
+		&marklogicv1.MarklogicGroups{Name: "dnode", IsBootstrap: true},
+		&marklogicv1.MarklogicGroups{Name: "dynamic", IsDynamic: true},
+	)
+
+	t.Run("rejects isDynamic flip on an existing group", func(t *testing.T) {
+		cluster := newWebhookTestCluster(
+			&marklogicv1.MarklogicGroups{Name: "dnode", IsBootstrap: true},
+			&marklogicv1.MarklogicGroups{Name: "dynamic", IsDynamic: false},
+		)
+		_, err := validator.ValidateUpdate(context.Background(), oldCluster, cluster)
+		expectFieldError(t, err, "spec.markLogicGroups[1].isDynamic")
+	})
+
+	t.Run("allows adding a new dynamic group", func(t *testing.T) {
+		cluster := newWebhookTestCluster(
+			&marklogicv1.MarklogicGroups{Name: "dnode", IsBootstrap: true},
+			&marklogicv1.MarklogicGroups{Name: "dynamic", IsDynamic: true},
+			&marklogicv1.MarklogicGroups{Name: "dynamic-2", IsDynamic: true},
+		)
+		if _, err := validator.ValidateUpdate(context.Background(), oldCluster, cluster); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+}
+
+func TestMarklogicClusterDefault(t *testing.T) {
+	cluster := newWebhookTestCluster(
+		&marklogicv1.MarklogicGroups{
+			Name:           "dnode",
+			IsBootstrap:    true,
+			Persistence:    &marklogicv1.Persistence{Enabled: true, Size: "10Gi"},
+			ReadinessProbe: marklogicv1.ContainerProbe{Enabled: true, InitialDelaySeconds: 60},
+			HAProxy: &marklogicv1.HAProxyGroup{
+				AppServers: []marklogicv1.AppServers{{Name: "app", Port: 8010}},
+			},
+		},
+	)
+	cluster.Spec.HAProxy = &marklogicv1.HAProxy{
+		Enabled:  true,
+		TcpPorts: &marklogicv1.Tcpports{Enabled: true, Ports: []marklogicv1.TcpPort{{Name: "odbc", Port: 5432}}},
+	}
+
+	if err := (&MarklogicClusterCustomDefaulter{}).Default(context.Background(), cluster); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	group := cluster.Spec.MarkLogicGroups[0]
+	if group.LivenessProbe != defaultLiveness {
+		t.Fatalf("expected default liveness probe, got %+v", group.LivenessProbe)
+	}
+	if group.ReadinessProbe.InitialDelaySeconds != 60 || group.ReadinessProbe.PeriodSeconds != 30 || group.ReadinessProbe.FailureThreshold != 3 {
+		t.Fatalf("expected readiness probe to keep user delay and gain default timings, got %+v", group.ReadinessProbe)
+	}
+	if group.Persistence.ResizeStrategy != marklogicv1.VolumeResizeStrategyParallel {
+		t.Fatalf("expected parallel resize strategy, got %q", group.Persistence.ResizeStrategy)
+	}
+	if group.HAProxy.AppServers[0].TargetPort != 8010 {
+		t.Fatalf("expected group app server targetPort to default to port, got %d", group.HAProxy.AppServers[0].TargetPort)
+	}
+	if cluster.Spec.HAProxy.FrontendPort != 80 || cluster.Spec.HAProxy.Stats.Port != 1024 {
+		t.Fatalf("expected default HAProxy ports, got frontend=%d stats=%d", cluster.Spec.HAProxy.FrontendPort, cluster.Spec.HAProxy.Stats.Port)
+	}
+	if cluster.Spec.HAProxy.TcpPorts.Ports[0].TargetPort != 5432 {
+		t.Fatalf("expected tcp targetPort to default to port, got %d", cluster.Spec.HAProxy.TcpPorts.Ports[0].TargetPort)
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+)
+
+var marklogicgrouplog = logf.Log.WithName("marklogicgroup-resource")
+
+// SetupMarklogicGroupWebhookWithManager registers the defaulting and validating webhooks for MarklogicGroup.
+func SetupMarklogicGroupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&marklogicv1.MarklogicGroup{}).
+		WithDefaulter(&MarklogicGroupCustomDefaulter{}).
+		WithValidator(&MarklogicGroupCustomValidator{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-marklogic-progress-com-v1-marklogicgroup,mutating=true,failurePolicy=fail,sideEffects=None,groups=marklogic.progress.com,resources=marklogicgroups,verbs=create;update,versions=v1,name=mmarklogicgroup-v1.kb.io,admissionReviewVersions=v1
+
+// MarklogicGroupCustomDefaulter applies the same probe and persistence defaults as the cluster webhook.
+type MarklogicGroupCustomDefaulter struct{}
+
+var _ admission.CustomDefaulter = &MarklogicGroupCustomDefaulter{}
+
+// Default implements admission.CustomDefaulter.
+func (d *MarklogicGroupCustomDefaulter) Default(_ context.Context, obj runtime.Object) error {
+	group, ok := obj.(*marklogicv1.MarklogicGroup)
+	if !ok {
+		return fmt.Errorf("expected a MarklogicGroup object but got %T", obj)
+	}
+	marklogicgrouplog.V(1).Info("Defaulting for MarklogicGroup", "name", group.GetName())
+
+	defaultLivenessProbe(&group.Spec.LivenessProbe)
+	defaultReadinessProbe(&group.Spec.ReadinessProbe)
+	defaultPersistence(group.Spec.Persistence)
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate-marklogic-progress-com-v1-marklogicgroup,mutating=false,failurePolicy=fail,sideEffects=None,groups=marklogic.progress.com,resources=marklogicgroups,verbs=create;update,versions=v1,name=vmarklogicgroup-v1.kb.io,admissionReviewVersions=v1
+
+// MarklogicGroupCustomValidator rejects MarklogicGroup specs the group controller cannot reconcile.
+type MarklogicGroupCustomValidator struct{}
+
+var _ admission.CustomValidator = &MarklogicGroupCustomValidator{}
+
+// ValidateCreate implements admission.CustomValidator.
+func (v *MarklogicGroupCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	group, ok := obj.(*marklogicv1.MarklogicGroup)
+	if !ok {
+		return nil, fmt.Errorf("expected a MarklogicGroup object but got %T", obj)
+	}
+	marklogicgrouplog.V(1).Info("Validation for MarklogicGroup upon creation", "name", group.GetName())
+
+	return nil, groupInvalidError(group, validateMarklogicGroup(group))
+}
+
+// ValidateUpdate implements admission.CustomValidator.
+func (v *MarklogicGroupCustomValidator) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldGroup, ok := oldObj.(*marklogicv1.MarklogicGroup)
+	if !ok {
+		return nil, fmt.Errorf("expected a MarklogicGroup object for the oldObj but got %T", oldObj)
+	}
+	group, ok := newObj.(*marklogicv1.MarklogicGroup)
+	if !ok {
+		return nil, fmt.Errorf("expected a MarklogicGroup object for the newObj but got %T", newObj)
+	}
+	marklogicgrouplog.V(1).Info("Validation for MarklogicGroup upon update", "name", group.GetName())
+
+	allErrs := validateMarklogicGroup(group)
+	if oldGroup.Spec.IsDynamic != group.Spec.IsDynamic {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec").Child("isDynamic"),
+			fmt.Sprintf("isDynamic is immutable after creation (was %t)", oldGroup.Spec.IsDynamic)))
+	}
+	return nil, groupInvalidError(group, allErrs)
+}
+
+// ValidateDelete implements admission.CustomValidator.
+func (v *MarklogicGroupCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateMarklogicGroup(group *marklogicv1.MarklogicGroup) field.ErrorList {
+	specPath := field.NewPath("spec")
+	allErrs := validatePersistence(group.Spec.Persistence, specPath.Child("persistence"))
+	return append(allErrs, validateAutoscaling(group.Spec.Autoscaling, group.Spec.IsDynamic, specPath.Child("autoscaling"))...)
+}
+
+func groupInvalidError(group *marklogicv1.MarklogicGroup, allErrs field.ErrorList) error {
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(marklogicv1.GroupVersion.WithKind("MarklogicGroup").GroupKind(), group.Name, allErrs)
+}
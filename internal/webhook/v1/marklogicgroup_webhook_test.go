@@ -0,0 +1,68 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package v1
+
+import (
+	"context"
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMarklogicGroupValidate(t *testing.T) {
+	validator := &MarklogicGroupCustomValidator{}
+	newGroup := func(dynamic bool, size string) *marklogicv1.MarklogicGroup {
+		return &marklogicv1.MarklogicGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: "dnode", Namespace: "default"},
+			Spec: marklogicv1.MarklogicGroupSpec{
+				IsDynamic:   dynamic,
+				Persistence: &marklogicv1.Persistence{Enabled: true, Size: size},
+			},
+		}
+	}
+
+	t.Run("rejects unparsable persistence size", func(t *testing.T) {
+		_, err := validator.ValidateCreate(context.Background(), newGroup(false, "lots"))
+		expectFieldError(t, err, "spec.persistence.size")
+	})
+
+	t.Run("rejects isDynamic flip", func(t *testing.T) {
+		_, err := validator.ValidateUpdate(context.Background(), newGroup(false, "10Gi"), newGroup(true, "10Gi"))
+		expectFieldError(t, err, "spec.isDynamic")
+	})
+
+	t.Run("rejects autoscaling outside dynamic groups", func(t *testing.T) {
+		group := newGroup(false, "10Gi")
+		group.Spec.Autoscaling = &marklogicv1.GroupAutoscaling{MinReplicas: 1, MaxReplicas: 3}
+		_, err := validator.ValidateCreate(context.Background(), group)
+		expectFieldError(t, err, "spec.autoscaling")
+	})
+
+	t.Run("accepts size growth", func(t *testing.T) {
+		if _, err := validator.ValidateUpdate(context.Background(), newGroup(false, "10Gi"), newGroup(false, "20Gi")); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+}
+
+func TestMarklogicGroupDefault(t *testing.T) {
+	group := &marklogicv1.MarklogicGroup{
+		Spec: marklogicv1.MarklogicGroupSpec{
+			LivenessProbe: marklogicv1.ContainerProbe{Enabled: false, InitialDelaySeconds: 5},
+			Persistence:   &marklogicv1.Persistence{Enabled: true, Size: "10Gi"},
+		},
+	}
+	if err := (&MarklogicGroupCustomDefaulter{}).Default(context.Background(), group); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if group.Spec.LivenessProbe.Enabled || group.Spec.LivenessProbe.PeriodSeconds != 0 {
+		t.Fatalf("expected explicitly disabled liveness probe to be left alone, got %+v", group.Spec.LivenessProbe)
+	}
+	if group.Spec.ReadinessProbe != defaultReadiness {
+		t.Fatalf("expected default readiness probe, got %+v", group.Spec.ReadinessProbe)
+	}
+	if group.Spec.Persistence.ResizeStrategy != marklogicv1.VolumeResizeStrategyParallel {
+		t.Fatalf("expected parallel resize strategy, got %q", group.Spec.Persistence.ResizeStrategy)
+	}
+}
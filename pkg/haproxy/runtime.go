@@ -0,0 +1,187 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+// Package haproxy talks to the runtime API of a running HAProxy process.
+package haproxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Admin state flags of a server, see "show servers state" in the HAProxy management guide.
+const (
+	adminForcedMaintenance = 0x01
+	adminConfigMaintenance = 0x04
+)
+
+type RuntimeClient interface {
+	ShowServersState(ctx context.Context) ([]ServerState, error)
+	AddServer(ctx context.Context, backend, server, address string, options []string) error
+	DeleteServer(ctx context.Context, backend, server string) error
+	SetServerState(ctx context.Context, backend, server, state string) error
+	SetServerWeight(ctx context.Context, backend, server string, weight int) error
+	SetServerAddress(ctx context.Context, backend, server, ip string, port int) error
+	EnableHealth(ctx context.Context, backend, server string) error
+}
+
+// ServerState is a server as reported by "show servers state".
+type ServerState struct {
+	Backend string
+	Name    string
+	Address string
+	Port    int
+	Weight  int
+	// FQDN is set for servers resolved through a resolvers section.
+	FQDN        string
+	Maintenance bool
+}
+
+type runtimeClient struct {
+	address string
+	timeout time.Duration
+}
+
+// NewRuntimeClient connects to the runtime API socket bound on address (host:port).
+func NewRuntimeClient(address string) RuntimeClient {
+	return &runtimeClient{address: address, timeout: 5 * time.Second}
+}
+
+// execute sends a single command in non-interactive mode; HAProxy closes the connection
+// after the response.
+func (c *runtimeClient) execute(ctx context.Context, command string) (out string, err error) {
+	dialer := &net.Dialer{Timeout: c.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", c.address)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		err = errors.Join(err, conn.Close())
+	}()
+	deadline := time.Now().Add(c.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		return "", err
+	}
+	if _, err := io.WriteString(conn, command+"\n"); err != nil {
+		return "", err
+	}
+	data, err := io.ReadAll(conn)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// expect runs a command whose response is empty on success, or one of the given messages.
+func (c *runtimeClient) expect(ctx context.Context, command string, success ...string) error {
+	out, err := c.execute(ctx, command)
+	if err != nil {
+		return err
+	}
+	if out == "" {
+		return nil
+	}
+	for _, prefix := range success {
+		if strings.HasPrefix(out, prefix) {
+			return nil
+		}
+	}
+	return fmt.Errorf("haproxy runtime API %q: %s", command, out)
+}
+
+func (c *runtimeClient) ShowServersState(ctx context.Context) ([]ServerState, error) {
+	out, err := c.execute(ctx, "show servers state")
+	if err != nil {
+		return nil, err
+	}
+	return parseServersState(out)
+}
+
+func (c *runtimeClient) AddServer(ctx context.Context, backend, server, address string, options []string) error {
+	command := fmt.Sprintf("add server %s/%s %s", backend, server, address)
+	if len(options) > 0 {
+		command += " " + strings.Join(options, " ")
+	}
+	return c.expect(ctx, command, "New server registered.")
+}
+
+func (c *runtimeClient) DeleteServer(ctx context.Context, backend, server string) error {
+	return c.expect(ctx, fmt.Sprintf("del server %s/%s", backend, server), "Server deleted.")
+}
+
+// SetServerState sets the admin state of a server to ready, drain or maint.
+func (c *runtimeClient) SetServerState(ctx context.Context, backend, server, state string) error {
+	return c.expect(ctx, fmt.Sprintf("set server %s/%s state %s", backend, server, state))
+}
+
+func (c *runtimeClient) SetServerWeight(ctx context.Context, backend, server string, weight int) error {
+	return c.expect(ctx, fmt.Sprintf("set server %s/%s weight %d", backend, server, weight))
+}
+
+func (c *runtimeClient) SetServerAddress(ctx context.Context, backend, server, ip string, port int) error {
+	return c.expect(ctx, fmt.Sprintf("set server %s/%s addr %s port %d", backend, server, ip, port),
+		"IP changed from", "no need to change", "port changed from")
+}
+
+func (c *runtimeClient) EnableHealth(ctx context.Context, backend, server string) error {
+	return c.expect(ctx, fmt.Sprintf("enable health %s/%s", backend, server))
+}
+
+// parseServersState reads the output of "show servers state": a version line, a header
+// line starting with '#' naming the columns, and a line per server.
+func parseServersState(out string) ([]ServerState, error) {
+	var columns map[string]int
+	var servers []ServerState
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			columns = map[string]int{}
+			for i, name := range strings.Fields(strings.TrimPrefix(line, "#")) {
+				columns[name] = i
+			}
+			continue
+		}
+		if columns == nil {
+			// The format version.
+			continue
+		}
+		fields := strings.Fields(line)
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(fields) {
+				return fields[i]
+			}
+			return ""
+		}
+		admin, err := strconv.Atoi(field("srv_admin_state"))
+		if err != nil {
+			return nil, fmt.Errorf("unexpected servers state line %q", line)
+		}
+		weight, _ := strconv.Atoi(field("srv_uweight"))
+		port, _ := strconv.Atoi(field("srv_port"))
+		fqdn := field("srv_fqdn")
+		if fqdn == "-" {
+			fqdn = ""
+		}
+		servers = append(servers, ServerState{
+			Backend:     field("be_name"),
+			Name:        field("srv_name"),
+			Address:     field("srv_addr"),
+			Port:        port,
+			Weight:      weight,
+			FQDN:        fqdn,
+			Maintenance: admin&(adminForcedMaintenance|adminConfigMaintenance) != 0,
+		})
+	}
+	return servers, nil
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package haproxy
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+)
+
+// serveRuntime answers each command with the response returned by respond, then closes the
+// connection the way the non-interactive runtime API does.
+func serveRuntime(t *testing.T, respond func(command string) string) (RuntimeClient, *[]string) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+	var commands []string
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			command, _ := bufio.NewReader(conn).ReadString('\n')
+			command = strings.TrimSpace(command)
+			commands = append(commands, command)
+			_, _ = conn.Write([]byte(respond(command)))
+			_ = conn.Close()
+		}
+	}()
+	return NewRuntimeClient(listener.Addr().String()), &commands
+}
+
+func TestShowServersStateParsesColumns(t *testing.T) {
+	client, _ := serveRuntime(t, func(string) string {
+		return "1\n" +
+			"# be_id be_name srv_id srv_name srv_addr srv_op_state srv_admin_state srv_uweight srv_iweight srv_time_since_last_change srv_check_status srv_check_result srv_check_health srv_check_state srv_agent_state bk_f_forced_id srv_f_forced_id srv_fqdn srv_port\n" +
+			"3 marklogic-8000-backend 1 dnode-8000-0 10.0.0.4 2 0 1 1 30 6 3 4 6 0 0 0 dnode-0.dnode.ml.svc.cluster.local 8000\n" +
+			"3 marklogic-8000-backend 2 dnode-8000-1 10.0.0.5 0 5 0 1 30 1 0 0 14 0 0 0 - 8000\n\n"
+	})
+
+	servers, err := client.ShowServersState(context.Background())
+	if err != nil {
+		t.Fatalf("ShowServersState returned error: %v", err)
+	}
+	if len(servers) != 2 {
+		t.Fatalf("expected two servers, got %+v", servers)
+	}
+	if servers[0] != (ServerState{Backend: "marklogic-8000-backend", Name: "dnode-8000-0", Address: "10.0.0.4", Port: 8000, Weight: 1, FQDN: "dnode-0.dnode.ml.svc.cluster.local"}) {
+		t.Fatalf("unexpected first server %+v", servers[0])
+	}
+	if !servers[1].Maintenance || servers[1].FQDN != "" || servers[1].Weight != 0 {
+		t.Fatalf("expected the second server in maintenance without FQDN, got %+v", servers[1])
+	}
+}
+
+func TestRuntimeCommandsReportErrors(t *testing.T) {
+	client, commands := serveRuntime(t, func(command string) string {
+		switch {
+		case strings.HasPrefix(command, "add server"):
+			return "New server registered.\n"
+		case strings.HasPrefix(command, "del server"):
+			return "Server still has connections attached to it, cannot remove it.\n"
+		}
+		return "\n"
+	})
+	ctx := context.Background()
+
+	if err := client.AddServer(ctx, "be", "srv", "10.0.0.4:8000", []string{"check", "weight", "1"}); err != nil {
+		t.Fatalf("AddServer returned error: %v", err)
+	}
+	if err := client.SetServerState(ctx, "be", "srv", "maint"); err != nil {
+		t.Fatalf("SetServerState returned error: %v", err)
+	}
+	err := client.DeleteServer(ctx, "be", "srv")
+	if err == nil || !strings.Contains(err.Error(), "still has connections") {
+		t.Fatalf("expected the deletion to fail, got %v", err)
+	}
+	expected := []string{"add server be/srv 10.0.0.4:8000 check weight 1", "set server be/srv state maint", "del server be/srv"}
+	if strings.Join(*commands, "|") != strings.Join(expected, "|") {
+		t.Fatalf("unexpected commands %q", *commands)
+	}
+}
@@ -0,0 +1,258 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	appServerRetrySeconds = 30
+
+	appServerTypeODBC = "odbc"
+
+	appServerReasonReady          = "AppServersReady"
+	appServerReasonGroupPending   = "GroupPending"
+	appServerReasonSyncFailed     = "SyncFailed"
+	appServerReasonTypeMismatched = "TypeMismatched"
+)
+
+// ReconcileAppServers creates the App Servers declared on the cluster groups and keeps
+// their properties in line with the spec. Groups that have not joined MarkLogic yet are
+// retried later.
+func (cc *ClusterContext) ReconcileAppServers() result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	logger := cc.ReqLogger
+	if !clusterDeclaresAppServers(cr) {
+		return result.Continue()
+	}
+
+	reason, message := appServerReasonReady, "all declared App Servers are configured"
+	var problems []string
+	mc, err := cc.newClusterManagementClient()
+	if err != nil {
+		reason = appServerReasonSyncFailed
+		problems = append(problems, err.Error())
+	} else {
+		for _, group := range cr.Spec.MarkLogicGroups {
+			if group == nil || len(group.AppServers) == 0 {
+				continue
+			}
+			groupName := markLogicGroupNameForSpec(group)
+			info, err := mc.GetGroup(cc.Ctx, groupName)
+			if err != nil {
+				reason = appServerReasonSyncFailed
+				problems = append(problems, err.Error())
+				continue
+			}
+			if !info.Exists {
+				reason = appServerReasonGroupPending
+				problems = append(problems, fmt.Sprintf("MarkLogic group %s does not exist yet", groupName))
+				continue
+			}
+			for _, server := range group.AppServers {
+				created, err := cc.syncAppServer(mc, groupName, server)
+				if err != nil {
+					reason = appServerReasonSyncFailed
+					if errors.As(err, &appServerTypeMismatch{}) {
+						reason = appServerReasonTypeMismatched
+					}
+					problems = append(problems, err.Error())
+					continue
+				}
+				if created {
+					cc.Recorder.Eventf(cr, corev1.EventTypeNormal, "AppServerCreated", "Created %s App Server %s on port %d in group %s",
+						appServerType(server), server.Name, server.Port, groupName)
+				}
+			}
+		}
+	}
+
+	conditionStatus := metav1.ConditionTrue
+	if len(problems) > 0 {
+		conditionStatus = metav1.ConditionFalse
+		message = strings.Join(problems, "; ")
+		logger.Info("App Servers are not fully configured", "reason", reason, "message", message)
+	}
+	patchClient := client.MergeFrom(cr.DeepCopy())
+	status := cr.Status.DeepCopy()
+	setClusterCondition(status, cr.Generation, marklogicv1.ClusterAppServersReady, conditionStatus, reason, message)
+	if !reflect.DeepEqual(*status, cr.Status) {
+		cr.Status = *status
+		if err := cc.Client.Status().Patch(cc.Ctx, cr, patchClient); err != nil {
+			logger.Error(err, "Failed to update MarkLogicCluster status")
+			return result.Error(err)
+		}
+	}
+	if len(problems) > 0 {
+		return result.RequeueSoon(appServerRetrySeconds)
+	}
+	return result.Continue()
+}
+
+type appServerTypeMismatch struct {
+	name, actual, desired string
+}
+
+func (e appServerTypeMismatch) Error() string {
+	return fmt.Sprintf("App Server %s is a %s server and cannot become %s; delete it in MarkLogic or rename the entry", e.name, e.actual, e.desired)
+}
+
+// syncAppServer creates the App Server or updates the properties that differ from the
+// spec, reporting whether it was created.
+func (cc *ClusterContext) syncAppServer(mc mlmanage.AppServerClient, groupName string, server marklogicv1.AppServer) (bool, error) {
+	desired := appServerProperties(server)
+	info, err := mc.GetAppServer(cc.Ctx, groupName, server.Name)
+	if err != nil {
+		return false, err
+	}
+	if !info.Exists {
+		create := map[string]any{"server-name": server.Name, "server-type": appServerType(server), "group-name": groupName}
+		for key, value := range desired {
+			create[key] = value
+		}
+		return true, mc.CreateAppServer(cc.Ctx, groupName, create)
+	}
+	if actual := fmt.Sprint(info.Properties["server-type"]); !strings.EqualFold(actual, appServerType(server)) {
+		return false, appServerTypeMismatch{name: server.Name, actual: actual, desired: appServerType(server)}
+	}
+	changed := map[string]any{}
+	for key, value := range desired {
+		if fmt.Sprint(value) != fmt.Sprint(info.Properties[key]) {
+			changed[key] = value
+		}
+	}
+	if len(changed) > 0 {
+		keys := make([]string, 0, len(changed))
+		for key := range changed {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		cc.ReqLogger.Info("Updating App Server properties", "group", groupName, "server", server.Name, "properties", keys)
+	}
+	return false, mc.UpdateAppServerProperties(cc.Ctx, groupName, server.Name, changed)
+}
+
+// appServerProperties are the Management API properties the operator manages for an
+// App Server. An empty modules database is left to MarkLogic's file-system default.
+func appServerProperties(server marklogicv1.AppServer) map[string]any {
+	properties := map[string]any{
+		"port":             server.Port,
+		"content-database": defaultString(server.ContentDatabase, "Documents"),
+		"root":             defaultString(server.Root, "/"),
+	}
+	if server.ModulesDatabase != "" {
+		properties["modules-database"] = server.ModulesDatabase
+	}
+	if appServerType(server) != appServerTypeODBC {
+		properties["authentication"] = defaultString(server.Authentication, "digest")
+	}
+	return properties
+}
+
+func appServerType(server marklogicv1.AppServer) string {
+	return defaultString(strings.ToLower(server.Type), "http")
+}
+
+func defaultString(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+func clusterDeclaresAppServers(cr *marklogicv1.MarklogicCluster) bool {
+	for _, group := range cr.Spec.MarkLogicGroups {
+		if group != nil && len(group.AppServers) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// markLogicGroupNameForSpec is the MarkLogic group a cluster group entry joins, matching
+// resolvedMarkLogicGroupName for the MarklogicGroup created from it.
+func markLogicGroupNameForSpec(group *marklogicv1.MarklogicGroups) string {
+	if group.GroupConfig != nil && strings.TrimSpace(group.GroupConfig.Name) != "" {
+		return group.GroupConfig.Name
+	}
+	return group.Name
+}
+
+// appServerRoutes splits the declared App Servers of a group into the HTTP routes and TCP
+// ports HAProxy serves: http and xdbc speak HTTP, odbc is proxied as plain TCP.
+func appServerRoutes(servers []marklogicv1.AppServer) ([]marklogicv1.AppServers, []marklogicv1.TcpPort) {
+	var routes []marklogicv1.AppServers
+	var tcpPorts []marklogicv1.TcpPort
+	for _, server := range servers {
+		if appServerType(server) == appServerTypeODBC {
+			tcpPorts = append(tcpPorts, marklogicv1.TcpPort{Name: server.Name, Type: appServerTypeODBC, Port: server.Port, TargetPort: server.Port})
+			continue
+		}
+		routes = append(routes, marklogicv1.AppServers{
+			Name:       server.Name,
+			Type:       appServerType(server),
+			Port:       server.Port,
+			TargetPort: server.Port,
+			Path:       defaultString(server.Path, "/"+server.Name),
+		})
+	}
+	return routes, tcpPorts
+}
+
+// appServerServicePorts returns a Service port for each declared App Server whose port is
+// not already exposed.
+func appServerServicePorts(servers []marklogicv1.AppServer, existing []corev1.ServicePort) []corev1.ServicePort {
+	taken := map[int32]bool{}
+	for _, port := range existing {
+		taken[port.Port] = true
+	}
+	var ports []corev1.ServicePort
+	for _, server := range servers {
+		if taken[server.Port] {
+			continue
+		}
+		taken[server.Port] = true
+		ports = append(ports, corev1.ServicePort{
+			Name:       fmt.Sprintf("app-%d", server.Port),
+			Port:       server.Port,
+			TargetPort: intstr.FromInt(int(server.Port)),
+			Protocol:   corev1.ProtocolTCP,
+		})
+	}
+	return ports
+}
+
+// haproxyAppServerPorts returns the HAProxy Service ports of the App Servers declared on
+// the groups HAProxy routes. Under path-based routing HTTP servers share the frontend port.
+func haproxyAppServerPorts(cr *marklogicv1.MarklogicCluster, existing []corev1.ServicePort) []corev1.ServicePort {
+	var servers []marklogicv1.AppServer
+	for _, group := range cr.Spec.MarkLogicGroups {
+		if group == nil || (group.HAProxy != nil && !group.HAProxy.Enabled) {
+			continue
+		}
+		pathBased := cr.Spec.HAProxy.PathBasedRouting != nil && *cr.Spec.HAProxy.PathBasedRouting
+		if group.HAProxy != nil && group.HAProxy.PathBasedRouting != nil {
+			pathBased = *group.HAProxy.PathBasedRouting
+		}
+		for _, server := range group.AppServers {
+			if pathBased && appServerType(server) != appServerTypeODBC {
+				continue
+			}
+			servers = append(servers, server)
+		}
+	}
+	return appServerServicePorts(servers, existing)
+}
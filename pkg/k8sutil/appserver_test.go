@@ -0,0 +1,192 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newAppServerTestContext(t *testing.T, groups ...*marklogicv1.MarklogicGroups) *ClusterContext {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := marklogicv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add marklogic scheme: %v", err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add core scheme: %v", err)
+	}
+	pathBased := false
+	cluster := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			ClusterDomain:   "cluster.local",
+			MarkLogicGroups: groups,
+			HAProxy: &marklogicv1.HAProxy{
+				Enabled:          true,
+				PathBasedRouting: &pathBased,
+				AppServers:       []marklogicv1.AppServers{{Name: "app-service", Type: "http", Port: 8000}},
+			},
+		},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml-admin", Namespace: "default"},
+		Data:       map[string][]byte{"username": []byte("admin"), "password": []byte("admin")},
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithStatusSubresource(&marklogicv1.MarklogicCluster{}).
+		WithObjects(cluster, secret).
+		Build()
+	return &ClusterContext{
+		Ctx:              context.Background(),
+		Client:           fakeClient,
+		Scheme:           scheme,
+		MarklogicCluster: cluster,
+		Recorder:         record.NewFakeRecorder(10),
+	}
+}
+
+// stubAppServerClient holds App Server properties keyed by "<group>/<server>".
+type stubAppServerClient struct {
+	appServers map[string]map[string]any
+}
+
+func (s *stubAppServerClient) GetAppServer(ctx context.Context, groupName, serverName string) (mlmanage.AppServerInfo, error) {
+	properties, ok := s.appServers[groupName+"/"+serverName]
+	return mlmanage.AppServerInfo{Exists: ok, Properties: properties}, nil
+}
+
+func (s *stubAppServerClient) CreateAppServer(ctx context.Context, groupName string, properties map[string]any) error {
+	if s.appServers == nil {
+		s.appServers = map[string]map[string]any{}
+	}
+	s.appServers[groupName+"/"+fmt.Sprint(properties["server-name"])] = properties
+	return nil
+}
+
+func (s *stubAppServerClient) UpdateAppServerProperties(ctx context.Context, groupName, serverName string, properties map[string]any) error {
+	current, ok := s.appServers[groupName+"/"+serverName]
+	if !ok {
+		return fmt.Errorf("app server %s does not exist in group %s", serverName, groupName)
+	}
+	for key, value := range properties {
+		current[key] = value
+	}
+	return nil
+}
+
+func TestReconcileAppServersCreatesAndUpdatesServers(t *testing.T) {
+	cc := newAppServerTestContext(t, &marklogicv1.MarklogicGroups{
+		Name:        "dnode",
+		IsBootstrap: true,
+		Replicas:    int32Ptr(1),
+		GroupConfig: &marklogicv1.GroupConfig{Name: "Default"},
+		AppServers: []marklogicv1.AppServer{
+			{Name: "orders", Type: "http", Port: 8010, ContentDatabase: "orders", ModulesDatabase: "Modules"},
+			{Name: "reporting", Type: "odbc", Port: 5432},
+		},
+	})
+	stub := &stubAppServerClient{
+		appServers: map[string]map[string]any{
+			"Default/orders": {"server-type": "http", "port": float64(8010), "content-database": "Documents", "root": "/", "authentication": "digest"},
+		},
+	}
+	useStubClusterManagementClient(t, managementStub{
+		GroupClient:     &stubDynamicManagementClient{groups: map[string]bool{"Default": true}},
+		AppServerClient: stub,
+	})
+
+	if res := cc.ReconcileAppServers(); res.Completed() {
+		t.Fatal("expected the handler to continue once every App Server is configured")
+	}
+	orders := stub.appServers["Default/orders"]
+	if orders["content-database"] != "orders" || orders["modules-database"] != "Modules" {
+		t.Fatalf("expected the drifted properties to be updated, got %v", orders)
+	}
+	reporting, ok := stub.appServers["Default/reporting"]
+	if !ok || reporting["server-type"] != "odbc" || reporting["group-name"] != "Default" {
+		t.Fatalf("expected the odbc server to be created, got %v", reporting)
+	}
+	if _, ok := reporting["authentication"]; ok {
+		t.Fatalf("expected no authentication property on an odbc server, got %v", reporting)
+	}
+	if !apimeta.IsStatusConditionTrue(cc.MarklogicCluster.Status.Conditions, string(marklogicv1.ClusterAppServersReady)) {
+		t.Fatalf("expected AppServersReady, got %+v", cc.MarklogicCluster.Status.Conditions)
+	}
+}
+
+func TestReconcileAppServersWaitsForGroup(t *testing.T) {
+	cc := newAppServerTestContext(t,
+		&marklogicv1.MarklogicGroups{Name: "dnode", IsBootstrap: true, Replicas: int32Ptr(1)},
+		&marklogicv1.MarklogicGroups{
+			Name:        "enode",
+			Replicas:    int32Ptr(1),
+			GroupConfig: &marklogicv1.GroupConfig{Name: "enode"},
+			AppServers:  []marklogicv1.AppServer{{Name: "orders", Port: 8010}},
+		},
+	)
+	stub := &stubAppServerClient{}
+	useStubClusterManagementClient(t, managementStub{GroupClient: &stubDynamicManagementClient{}, AppServerClient: stub})
+
+	if res := cc.ReconcileAppServers(); !res.Completed() {
+		t.Fatal("expected a requeue while the group has not joined")
+	}
+	if len(stub.appServers) != 0 {
+		t.Fatalf("expected no App Server before the group exists, got %v", stub.appServers)
+	}
+	cond := apimeta.FindStatusCondition(cc.MarklogicCluster.Status.Conditions, string(marklogicv1.ClusterAppServersReady))
+	if cond == nil || cond.Status != metav1.ConditionFalse || cond.Reason != appServerReasonGroupPending {
+		t.Fatalf("expected GroupPending, got %+v", cond)
+	}
+}
+
+func TestDeclaredAppServersAreRoutedAndExposed(t *testing.T) {
+	t.Parallel()
+
+	servers := []marklogicv1.AppServer{
+		{Name: "orders", Type: "http", Port: 8010},
+		{Name: "reporting", Type: "odbc", Port: 5432},
+	}
+	cc := newAppServerTestContext(t, &marklogicv1.MarklogicGroups{Name: "dnode", IsBootstrap: true, Replicas: int32Ptr(2), AppServers: servers})
+	cr := cc.MarklogicCluster
+
+	config := generateHAProxyConfig(context.Background(), cr)
+	if _, ok := config.FrontEndConfigMap["8000"]; !ok {
+		t.Fatalf("expected the cluster App Server to stay routed, got %v", config.FrontEndConfigMap)
+	}
+	if backends := config.BackendConfigMap["8010"]; len(backends) != 1 || backends[0].Replicas != 2 {
+		t.Fatalf("expected a backend for the declared http server, got %v", config.BackendConfigMap)
+	}
+	if tcp := config.TCPConfigMap["5432"]; len(tcp) != 1 || tcp[0].PortName != "reporting" {
+		t.Fatalf("expected a tcp listener for the declared odbc server, got %v", config.TCPConfigMap)
+	}
+	if len(cr.Spec.HAProxy.AppServers) != 1 {
+		t.Fatalf("expected the cluster spec to be left alone, got %v", cr.Spec.HAProxy.AppServers)
+	}
+
+	ports := haproxyAppServerPorts(cr, []corev1.ServicePort{{Name: "qconsole", Port: 8000}})
+	if len(ports) != 2 || ports[0].Port != 8010 || ports[1].Port != 5432 {
+		t.Fatalf("unexpected HAProxy service ports %v", ports)
+	}
+
+	group := &marklogicv1.MarklogicGroup{Spec: marklogicv1.MarklogicGroupSpec{
+		Name:       "dnode",
+		AppServers: servers,
+		Service:    marklogicv1.Service{AdditionalPorts: []corev1.ServicePort{{Name: "custom", Port: 5432}}},
+	}}
+	params := generateServiceParams(group)
+	if len(params.Ports) != 2 || params.Ports[1].Name != "app-8010" {
+		t.Fatalf("expected one added port for the http server, got %v", params.Ports)
+	}
+}
@@ -0,0 +1,410 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/cisco-open/k8s-objectmatcher/patch"
+	"github.com/go-logr/logr"
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// autoscalingPollInterval is how often request rates are read for RequestRate policies.
+const autoscalingPollInterval = 30 * time.Second
+
+// autoscalingPolicy returns the autoscaling of a dynamic group. Admission rejects it on other
+// groups; objects stored before that check are treated as not autoscaled.
+func autoscalingPolicy(cr *marklogicv1.MarklogicGroup) *marklogicv1.GroupAutoscaling {
+	if !cr.Spec.IsDynamic {
+		return nil
+	}
+	return cr.Spec.Autoscaling
+}
+
+func requestRateAutoscaled(cr *marklogicv1.MarklogicGroup) bool {
+	policy := autoscalingPolicy(cr)
+	return policy != nil && policy.Metric == marklogicv1.AutoscalingMetricRequestRate
+}
+
+// requeueForAutoscaling polls groups with a RequestRate policy; nothing else triggers a
+// reconcile when the request rate changes.
+func requeueForAutoscaling(cr *marklogicv1.MarklogicGroup, res reconcile.Result, err error) (reconcile.Result, error) {
+	if err == nil && requestRateAutoscaled(cr) && res.RequeueAfter == 0 {
+		res.RequeueAfter = autoscalingPollInterval
+	}
+	return res, err
+}
+
+func clampReplicas(replicas int32, policy *marklogicv1.GroupAutoscaling) int32 {
+	if replicas < policy.MinReplicas {
+		return policy.MinReplicas
+	}
+	if replicas > policy.MaxReplicas {
+		return policy.MaxReplicas
+	}
+	return replicas
+}
+
+// autoscaledReplicas returns the replicas to keep on an existing MarklogicGroup because an
+// autoscaler owns them, or nil when the cluster spec decides. Without a policy, replicas an
+// HPA or KEDA set through the scale subresource are kept until spec replicas change.
+func autoscaledReplicas(current, desired *marklogicv1.MarklogicGroup) *int32 {
+	if current.Spec.Replicas == nil {
+		return nil
+	}
+	replicas := *current.Spec.Replicas
+	if policy := autoscalingPolicy(desired); policy != nil {
+		replicas = clampReplicas(replicas, policy)
+		return &replicas
+	}
+	lastApplied, ok := lastAppliedReplicas(current)
+	if !ok || desired.Spec.Replicas == nil || *desired.Spec.Replicas != lastApplied || replicas == lastApplied {
+		return nil
+	}
+	return &replicas
+}
+
+// lastAppliedReplicas reads the replicas the cluster controller last applied to a group.
+func lastAppliedReplicas(group *marklogicv1.MarklogicGroup) (int32, bool) {
+	original, err := patch.DefaultAnnotator.GetOriginalConfiguration(group)
+	if err != nil || len(original) == 0 {
+		return 0, false
+	}
+	var applied struct {
+		Spec struct {
+			Replicas *int32 `json:"replicas"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal(original, &applied); err != nil || applied.Spec.Replicas == nil {
+		return 0, false
+	}
+	return *applied.Spec.Replicas, true
+}
+
+// ReconcileAutoscaling applies the autoscaling policy of a dynamic group. CPU policies are
+// handed to a HorizontalPodAutoscaler; RequestRate policies are evaluated here and scale
+// the group by patching its replicas, as an autoscaler would through the scale subresource.
+func (oc *OperatorContext) ReconcileAutoscaling() result.ReconcileResult {
+	cr := oc.MarklogicGroup
+	policy := autoscalingPolicy(cr)
+	if policy == nil || policy.Metric != marklogicv1.AutoscalingMetricCPU {
+		if err := oc.deleteAutoscalingHPA(); err != nil {
+			return result.Error(err)
+		}
+	}
+	if policy == nil {
+		if cr.Status.Autoscaling == nil {
+			return result.Continue()
+		}
+		patchClient := client.MergeFrom(cr.DeepCopy())
+		cr.Status.Autoscaling = nil
+		if err := oc.Client.Status().Patch(oc.Ctx, cr, patchClient); err != nil {
+			oc.ReqLogger.Error(err, "Failed to clear MarkLogicGroup autoscaling status")
+			return result.Error(err)
+		}
+		return result.Continue()
+	}
+
+	status := &marklogicv1.AutoscalingStatus{Metric: policy.Metric}
+	if cr.Status.Autoscaling != nil && cr.Status.Autoscaling.Metric == policy.Metric {
+		status = cr.Status.Autoscaling.DeepCopy()
+	}
+	if policy.Metric == marklogicv1.AutoscalingMetricCPU {
+		hpa, err := oc.applyAutoscalingHPA(policy)
+		if err != nil {
+			return result.Error(err)
+		}
+		status.DesiredReplicas = hpa.Status.DesiredReplicas
+		status.LastScaleTime = hpa.Status.LastScaleTime
+		status.Message = ""
+		return oc.patchAutoscalingStatus(status)
+	}
+	if res := oc.evaluateRequestRate(policy, status); res.Completed() {
+		return res
+	}
+	return oc.patchAutoscalingStatus(status)
+}
+
+func (oc *OperatorContext) evaluateRequestRate(policy *marklogicv1.GroupAutoscaling, status *marklogicv1.AutoscalingStatus) result.ReconcileResult {
+	cr := oc.MarklogicGroup
+	current := int32(1)
+	if cr.Spec.Replicas != nil {
+		current = *cr.Spec.Replicas
+	}
+	mc, err := oc.groupManagementClient()
+	if err != nil {
+		status.Message = err.Error()
+		return result.Continue()
+	}
+	rate, err := mc.GetGroupRequestRate(oc.Ctx, resolvedMarkLogicGroupName(cr))
+	if err != nil {
+		oc.ReqLogger.Info("Cannot read the group request rate, keeping replicas", "error", err.Error())
+		status.Message = err.Error()
+		return result.Continue()
+	}
+	status.Message = ""
+	status.CurrentRequestsPerSecond = strconv.FormatFloat(rate, 'f', 1, 64)
+	recommended := clampReplicas(int32(math.Ceil(rate/float64(policy.TargetRequestsPerSecond))), policy)
+	status.DesiredReplicas = recommended
+	target := stabilizeReplicas(policy, current, recommended, status, metav1.Now())
+	if target == current {
+		return result.Continue()
+	}
+
+	patchClient := client.MergeFrom(cr.DeepCopy())
+	cr.Spec.Replicas = &target
+	if err := oc.Client.Patch(oc.Ctx, cr, patchClient); err != nil {
+		oc.ReqLogger.Error(err, "Failed to scale MarkLogicGroup")
+		return result.Error(err)
+	}
+	now := metav1.Now()
+	status.LastScaleTime = &now
+	oc.Recorder.Eventf(cr, corev1.EventTypeNormal, "AutoscalerScaled", "Scaled from %d to %d replicas at %s requests/sec (target %d per host)",
+		current, target, status.CurrentRequestsPerSecond, policy.TargetRequestsPerSecond)
+	return result.Continue()
+}
+
+// stabilizeReplicas returns the replicas to scale to. Scale-ups apply at once; a scale-down
+// waits until recommendations have stayed below current for the stabilization window and
+// then goes to the highest of them.
+func stabilizeReplicas(policy *marklogicv1.GroupAutoscaling, current, recommended int32, status *marklogicv1.AutoscalingStatus, now metav1.Time) int32 {
+	if clamped := clampReplicas(current, policy); clamped != current {
+		status.ScaleDownSince = nil
+		status.ScaleDownReplicas = 0
+		return clamped
+	}
+	if recommended >= current {
+		status.ScaleDownSince = nil
+		status.ScaleDownReplicas = 0
+		return recommended
+	}
+	if status.ScaleDownSince == nil {
+		status.ScaleDownSince = &now
+		status.ScaleDownReplicas = recommended
+	} else if recommended > status.ScaleDownReplicas {
+		status.ScaleDownReplicas = recommended
+	}
+	window := time.Duration(policy.ScaleDownStabilizationSeconds) * time.Second
+	if now.Sub(status.ScaleDownSince.Time) < window {
+		return current
+	}
+	target := status.ScaleDownReplicas
+	status.ScaleDownSince = nil
+	status.ScaleDownReplicas = 0
+	return target
+}
+
+func (oc *OperatorContext) patchAutoscalingStatus(status *marklogicv1.AutoscalingStatus) result.ReconcileResult {
+	cr := oc.MarklogicGroup
+	if reflect.DeepEqual(cr.Status.Autoscaling, status) {
+		return result.Continue()
+	}
+	patchClient := client.MergeFrom(cr.DeepCopy())
+	cr.Status.Autoscaling = status
+	if err := oc.Client.Status().Patch(oc.Ctx, cr, patchClient); err != nil {
+		oc.ReqLogger.Error(err, "Failed to update MarkLogicGroup autoscaling status")
+		return result.Error(err)
+	}
+	return result.Continue()
+}
+
+func (oc *OperatorContext) generateAutoscalingHPA(policy *marklogicv1.GroupAutoscaling) *autoscalingv2.HorizontalPodAutoscaler {
+	cr := oc.MarklogicGroup
+	minReplicas := policy.MinReplicas
+	utilization := policy.TargetCPUUtilizationPercentage
+	window := policy.ScaleDownStabilizationSeconds
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{
+		TypeMeta:   generateTypeMeta("HorizontalPodAutoscaler", "autoscaling/v2"),
+		ObjectMeta: generateObjectMeta(cr.Spec.Name, cr.Namespace, oc.GetOperatorLabels(cr.Spec.Name), nil),
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+				APIVersion: "marklogic.progress.com/v1",
+				Kind:       "MarklogicGroup",
+				Name:       cr.Name,
+			},
+			MinReplicas: &minReplicas,
+			MaxReplicas: policy.MaxReplicas,
+			Metrics: []autoscalingv2.MetricSpec{
+				{
+					// Sidecars are left out so that only MarkLogic drives the group size.
+					Type: autoscalingv2.ContainerResourceMetricSourceType,
+					ContainerResource: &autoscalingv2.ContainerResourceMetricSource{
+						Name:      corev1.ResourceCPU,
+						Container: "marklogic-server",
+						Target: autoscalingv2.MetricTarget{
+							Type:               autoscalingv2.UtilizationMetricType,
+							AverageUtilization: &utilization,
+						},
+					},
+				},
+			},
+			Behavior: &autoscalingv2.HorizontalPodAutoscalerBehavior{
+				ScaleDown: &autoscalingv2.HPAScalingRules{StabilizationWindowSeconds: &window},
+			},
+		},
+	}
+	AddOwnerRefToObject(hpa, marklogicServerAsOwner(cr))
+	return hpa
+}
+
+func (oc *OperatorContext) applyAutoscalingHPA(policy *marklogicv1.GroupAutoscaling) (*autoscalingv2.HorizontalPodAutoscaler, error) {
+	return applyHorizontalPodAutoscaler(oc.Ctx, oc.Client, oc.ReqLogger, oc.generateAutoscalingHPA(policy), oc.MarklogicGroup)
+}
+
+// deleteAutoscalingHPA removes the HorizontalPodAutoscaler the operator created for a CPU
+// policy. HPAs created by users for the group are left alone.
+func (oc *OperatorContext) deleteAutoscalingHPA() error {
+	cr := oc.MarklogicGroup
+	return deleteHorizontalPodAutoscaler(oc.Ctx, oc.Client, oc.ReqLogger, types.NamespacedName{Name: cr.Spec.Name, Namespace: cr.Namespace}, cr)
+}
+
+func applyHorizontalPodAutoscaler(ctx context.Context, c client.Client, logger logr.Logger, desired *autoscalingv2.HorizontalPodAutoscaler, owner client.Object) (*autoscalingv2.HorizontalPodAutoscaler, error) {
+	current := &autoscalingv2.HorizontalPodAutoscaler{}
+	err := c.Get(ctx, types.NamespacedName{Name: desired.Name, Namespace: desired.Namespace}, current)
+	if apierrors.IsNotFound(err) {
+		logger.Info("Creating HorizontalPodAutoscaler", "name", desired.Name)
+		if err := c.Create(ctx, desired); err != nil {
+			logger.Error(err, "HorizontalPodAutoscaler creation has failed")
+			return nil, err
+		}
+		return desired, nil
+	}
+	if err != nil {
+		logger.Error(err, "Failed to get HorizontalPodAutoscaler")
+		return nil, err
+	}
+	if !metav1.IsControlledBy(current, owner) {
+		return nil, fmt.Errorf("HorizontalPodAutoscaler %s/%s exists and is not managed by %s", current.Namespace, current.Name, owner.GetName())
+	}
+	if equality.Semantic.DeepEqual(current.Spec, desired.Spec) && equality.Semantic.DeepEqual(current.Labels, desired.Labels) {
+		return current, nil
+	}
+	logger.Info("HorizontalPodAutoscaler is different from the spec, updating it", "name", desired.Name)
+	current.Spec = desired.Spec
+	current.Labels = desired.Labels
+	if err := c.Update(ctx, current); err != nil {
+		logger.Error(err, "Error updating HorizontalPodAutoscaler")
+		return nil, err
+	}
+	return current, nil
+}
+
+// deleteHorizontalPodAutoscaler removes an HPA created by the owner; HPAs created by users
+// under the same name are left alone.
+func deleteHorizontalPodAutoscaler(ctx context.Context, c client.Client, logger logr.Logger, name types.NamespacedName, owner client.Object) error {
+	current := &autoscalingv2.HorizontalPodAutoscaler{}
+	err := c.Get(ctx, name, current)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		logger.Error(err, "Failed to get HorizontalPodAutoscaler")
+		return err
+	}
+	if !metav1.IsControlledBy(current, owner) {
+		return nil
+	}
+	logger.Info("Removing HorizontalPodAutoscaler", "name", current.Name)
+	if err := c.Delete(ctx, current); err != nil && !apierrors.IsNotFound(err) {
+		logger.Error(err, "Failed to delete HorizontalPodAutoscaler")
+		return err
+	}
+	return nil
+}
+
+const haproxyAutoscalerName = "marklogic-haproxy"
+
+// haproxySeedReplicas is the replica count the HAProxy Deployment is created with.
+func haproxySeedReplicas(cr *marklogicv1.MarklogicCluster) int32 {
+	replicas := cr.Spec.HAProxy.ReplicaCount
+	if policy := cr.Spec.HAProxy.Autoscaling; policy != nil {
+		replicas = min(max(replicas, policy.MinReplicas), policy.MaxReplicas)
+	}
+	return replicas
+}
+
+func (cc *ClusterContext) generateHAProxyHPA(policy *marklogicv1.HAProxyAutoscaling) *autoscalingv2.HorizontalPodAutoscaler {
+	cr := cc.MarklogicCluster
+	minReplicas := policy.MinReplicas
+	window := policy.ScaleDownStabilizationSeconds
+	metric := autoscalingv2.MetricSpec{
+		Type: autoscalingv2.ContainerResourceMetricSourceType,
+		ContainerResource: &autoscalingv2.ContainerResourceMetricSource{
+			Name:      corev1.ResourceCPU,
+			Container: "haproxy",
+			Target: autoscalingv2.MetricTarget{
+				Type:               autoscalingv2.UtilizationMetricType,
+				AverageUtilization: &policy.TargetCPUUtilizationPercentage,
+			},
+		},
+	}
+	if policy.Metric == marklogicv1.AutoscalingMetricConnectionRate {
+		metricName := policy.ConnectionRateMetric
+		if metricName == "" {
+			metricName = "haproxy_process_current_connection_rate"
+		}
+		metric = autoscalingv2.MetricSpec{
+			Type: autoscalingv2.PodsMetricSourceType,
+			Pods: &autoscalingv2.PodsMetricSource{
+				Metric: autoscalingv2.MetricIdentifier{Name: metricName},
+				Target: autoscalingv2.MetricTarget{
+					Type:         autoscalingv2.AverageValueMetricType,
+					AverageValue: resource.NewQuantity(int64(policy.TargetConnectionsPerSecond), resource.DecimalSI),
+				},
+			},
+		}
+	}
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{
+		TypeMeta:   generateTypeMeta("HorizontalPodAutoscaler", "autoscaling/v2"),
+		ObjectMeta: generateObjectMeta(haproxyAutoscalerName, cr.Namespace, cc.GetHAProxyLabels(cr.Name), nil),
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       "marklogic-haproxy",
+			},
+			MinReplicas: &minReplicas,
+			MaxReplicas: policy.MaxReplicas,
+			Metrics:     []autoscalingv2.MetricSpec{metric},
+			Behavior: &autoscalingv2.HorizontalPodAutoscalerBehavior{
+				ScaleDown: &autoscalingv2.HPAScalingRules{StabilizationWindowSeconds: &window},
+			},
+		},
+	}
+	AddOwnerRefToObject(hpa, marklogicClusterAsOwner(cr))
+	return hpa
+}
+
+// ReconcileHAProxyAutoscaling keeps the HorizontalPodAutoscaler of the HAProxy Deployment
+// in line with spec.haproxy.autoscaling, and removes it when autoscaling is turned off.
+func (cc *ClusterContext) ReconcileHAProxyAutoscaling() result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	if cr.Spec.HAProxy == nil || !cr.Spec.HAProxy.Enabled || cr.Spec.HAProxy.Autoscaling == nil {
+		name := types.NamespacedName{Name: haproxyAutoscalerName, Namespace: cr.Namespace}
+		if err := deleteHorizontalPodAutoscaler(cc.Ctx, cc.Client, cc.ReqLogger, name, cr); err != nil {
+			return result.Error(err)
+		}
+		return result.Continue()
+	}
+	if _, err := applyHorizontalPodAutoscaler(cc.Ctx, cc.Client, cc.ReqLogger, cc.generateHAProxyHPA(cr.Spec.HAProxy.Autoscaling), cr); err != nil {
+		return result.Error(err)
+	}
+	return result.Continue()
+}
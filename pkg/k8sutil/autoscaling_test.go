@@ -0,0 +1,308 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cisco-open/k8s-objectmatcher/patch"
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func newAutoscalingTestContext(t *testing.T, replicas int32, policy *marklogicv1.GroupAutoscaling) *OperatorContext {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := marklogicv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add marklogic scheme: %v", err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add core scheme: %v", err)
+	}
+	if err := autoscalingv2.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add autoscaling scheme: %v", err)
+	}
+	group := &marklogicv1.MarklogicGroup{
+		TypeMeta: metav1.TypeMeta{APIVersion: "marklogic.progress.com/v1", Kind: "MarklogicGroup"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "enode",
+			Namespace:       "default",
+			UID:             "enode-uid",
+			OwnerReferences: []metav1.OwnerReference{{APIVersion: "marklogic.progress.com/v1", Kind: "MarklogicCluster", Name: "ml", UID: "ml-uid"}},
+		},
+		Spec: marklogicv1.MarklogicGroupSpec{
+			Name:          "enode",
+			Replicas:      int32Ptr(replicas),
+			IsDynamic:     true,
+			ClusterDomain: "cluster.local",
+			GroupConfig:   &marklogicv1.GroupConfig{Name: "E-Nodes"},
+			Autoscaling:   policy,
+		},
+	}
+	objects := []client.Object{
+		group,
+		&marklogicv1.MarklogicCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+			Spec: marklogicv1.MarklogicClusterSpec{
+				ClusterDomain:   "cluster.local",
+				MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "dnode", IsBootstrap: true}},
+			},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "ml-admin", Namespace: "default"},
+			Data:       map[string][]byte{"username": []byte("admin"), "password": []byte("admin")},
+		},
+	}
+	return &OperatorContext{
+		Ctx: context.Background(),
+		Client: fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithStatusSubresource(&marklogicv1.MarklogicGroup{}).
+			WithObjects(objects...).
+			Build(),
+		Scheme:         scheme,
+		MarklogicGroup: group,
+		Recorder:       record.NewFakeRecorder(10),
+	}
+}
+
+// stubMetricsClient holds the request rate and status summary values reported per group.
+type stubMetricsClient struct {
+	requestRates  map[string]float64
+	serverMetrics map[string][]mlmanage.ServerMetric
+}
+
+func (s *stubMetricsClient) GetGroupRequestRate(ctx context.Context, groupName string) (float64, error) {
+	rate, ok := s.requestRates[groupName]
+	if !ok {
+		return 0, fmt.Errorf("no request rate for group %s", groupName)
+	}
+	return rate, nil
+}
+
+func (s *stubMetricsClient) GetServerMetrics(ctx context.Context, groupName string) ([]mlmanage.ServerMetric, error) {
+	values, ok := s.serverMetrics[groupName]
+	if !ok {
+		return nil, fmt.Errorf("no metrics for group %s", groupName)
+	}
+	return values, nil
+}
+
+func TestStabilizeReplicasDelaysScaleDown(t *testing.T) {
+	policy := &marklogicv1.GroupAutoscaling{MinReplicas: 1, MaxReplicas: 10, ScaleDownStabilizationSeconds: 300}
+	status := &marklogicv1.AutoscalingStatus{}
+	start := metav1.NewTime(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+
+	if got := stabilizeReplicas(policy, 2, 6, status, start); got != 6 {
+		t.Fatalf("expected an immediate scale-up to 6, got %d", got)
+	}
+	if got := stabilizeReplicas(policy, 6, 2, status, start); got != 6 || status.ScaleDownSince == nil {
+		t.Fatalf("expected the scale-down to wait, got %d with status %+v", got, status)
+	}
+	if got := stabilizeReplicas(policy, 6, 4, status, metav1.NewTime(start.Add(time.Minute))); got != 6 {
+		t.Fatalf("expected the scale-down to keep waiting, got %d", got)
+	}
+	if got := stabilizeReplicas(policy, 6, 2, status, metav1.NewTime(start.Add(5*time.Minute))); got != 4 {
+		t.Fatalf("expected to shrink to the highest recommendation in the window, got %d", got)
+	}
+	if status.ScaleDownSince != nil || status.ScaleDownReplicas != 0 {
+		t.Fatalf("expected the stabilization window to reset, got %+v", status)
+	}
+	if got := stabilizeReplicas(policy, 12, 12, status, start); got != 10 {
+		t.Fatalf("expected replicas above maxReplicas to be clamped, got %d", got)
+	}
+}
+
+func TestReconcileAutoscalingRequestRateScalesGroup(t *testing.T) {
+	stub := &stubMetricsClient{requestRates: map[string]float64{"E-Nodes": 250}}
+	useStubClusterManagementClient(t, managementStub{MetricsClient: stub})
+	oc := newAutoscalingTestContext(t, 1, &marklogicv1.GroupAutoscaling{
+		MinReplicas:             1,
+		MaxReplicas:             4,
+		Metric:                  marklogicv1.AutoscalingMetricRequestRate,
+		TargetRequestsPerSecond: 100,
+	})
+
+	if res := oc.ReconcileAutoscaling(); res.Completed() {
+		t.Fatalf("expected reconcile to continue, got %+v", res)
+	}
+	stored := &marklogicv1.MarklogicGroup{}
+	if err := oc.Client.Get(oc.Ctx, types.NamespacedName{Name: "enode", Namespace: "default"}, stored); err != nil {
+		t.Fatalf("failed to get group: %v", err)
+	}
+	if *stored.Spec.Replicas != 3 {
+		t.Fatalf("expected 250 requests/sec at 100 per host to need 3 replicas, got %d", *stored.Spec.Replicas)
+	}
+	status := stored.Status.Autoscaling
+	if status == nil || status.CurrentRequestsPerSecond != "250.0" || status.DesiredReplicas != 3 || status.LastScaleTime == nil {
+		t.Fatalf("unexpected autoscaling status: %+v", status)
+	}
+
+	res, err := requeueForAutoscaling(oc.MarklogicGroup, reconcile.Result{}, nil)
+	if err != nil || res.RequeueAfter != autoscalingPollInterval {
+		t.Fatalf("expected RequestRate groups to be polled, got %+v %v", res, err)
+	}
+}
+
+func TestReconcileAutoscalingCPUManagesHPA(t *testing.T) {
+	oc := newAutoscalingTestContext(t, 2, &marklogicv1.GroupAutoscaling{
+		MinReplicas:                    2,
+		MaxReplicas:                    8,
+		Metric:                         marklogicv1.AutoscalingMetricCPU,
+		TargetCPUUtilizationPercentage: 70,
+		ScaleDownStabilizationSeconds:  300,
+	})
+	if res := oc.ReconcileAutoscaling(); res.Completed() {
+		t.Fatalf("expected reconcile to continue, got %+v", res)
+	}
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{}
+	if err := oc.Client.Get(oc.Ctx, types.NamespacedName{Name: "enode", Namespace: "default"}, hpa); err != nil {
+		t.Fatalf("expected HorizontalPodAutoscaler: %v", err)
+	}
+	if hpa.Spec.ScaleTargetRef.Kind != "MarklogicGroup" || *hpa.Spec.MinReplicas != 2 || hpa.Spec.MaxReplicas != 8 {
+		t.Fatalf("unexpected HPA spec: %+v", hpa.Spec)
+	}
+	if *hpa.Spec.Behavior.ScaleDown.StabilizationWindowSeconds != 300 {
+		t.Fatalf("expected the scale-down stabilization window on the HPA, got %+v", hpa.Spec.Behavior)
+	}
+	if metric := hpa.Spec.Metrics[0].ContainerResource; metric == nil || metric.Container != "marklogic-server" || *metric.Target.AverageUtilization != 70 {
+		t.Fatalf("expected CPU utilization of the MarkLogic container, got %+v", hpa.Spec.Metrics)
+	}
+
+	oc.MarklogicGroup.Spec.Autoscaling = nil
+	if res := oc.ReconcileAutoscaling(); res.Completed() {
+		t.Fatalf("expected reconcile to continue, got %+v", res)
+	}
+	if err := oc.Client.Get(oc.Ctx, types.NamespacedName{Name: "enode", Namespace: "default"}, hpa); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected the HPA to be removed with the policy, got %v", err)
+	}
+}
+
+func TestAutoscaledReplicasKeepsReplicasSetByAutoscaler(t *testing.T) {
+	applied := &marklogicv1.MarklogicGroup{Spec: marklogicv1.MarklogicGroupSpec{Name: "enode", Replicas: int32Ptr(2)}}
+	if err := patch.DefaultAnnotator.SetLastAppliedAnnotation(applied); err != nil {
+		t.Fatalf("failed to set last applied annotation: %v", err)
+	}
+	current := applied.DeepCopy()
+	current.Spec.Replicas = int32Ptr(5)
+
+	desired := &marklogicv1.MarklogicGroup{Spec: marklogicv1.MarklogicGroupSpec{Name: "enode", Replicas: int32Ptr(2), IsDynamic: true}}
+	if got := autoscaledReplicas(current, desired); got == nil || *got != 5 {
+		t.Fatalf("expected replicas set through the scale subresource to be kept, got %v", got)
+	}
+	desired.Spec.Replicas = int32Ptr(3)
+	if got := autoscaledReplicas(current, desired); got != nil {
+		t.Fatalf("expected a spec replicas change to win, got %d", *got)
+	}
+	desired.Spec.Autoscaling = &marklogicv1.GroupAutoscaling{MinReplicas: 1, MaxReplicas: 4}
+	if got := autoscaledReplicas(current, desired); got == nil || *got != 4 {
+		t.Fatalf("expected autoscaled replicas clamped to maxReplicas, got %v", got)
+	}
+	desired.Spec.IsDynamic = false
+	if got := autoscaledReplicas(current, desired); got != nil {
+		t.Fatalf("expected autoscaling to be ignored outside dynamic groups, got %d", *got)
+	}
+}
+
+func TestReconcileHAProxyAutoscalingManagesHPA(t *testing.T) {
+	cc := newAppServerTestContext(t, &marklogicv1.MarklogicGroups{Name: "dnode", IsBootstrap: true, Replicas: int32Ptr(1)})
+	if err := autoscalingv2.AddToScheme(cc.Scheme); err != nil {
+		t.Fatalf("failed to add autoscaling scheme: %v", err)
+	}
+	cr := cc.MarklogicCluster
+	cr.UID = "ml-uid"
+	cr.Spec.HAProxy.Autoscaling = &marklogicv1.HAProxyAutoscaling{
+		MinReplicas:                    2,
+		MaxReplicas:                    6,
+		Metric:                         marklogicv1.AutoscalingMetricCPU,
+		TargetCPUUtilizationPercentage: 70,
+		ScaleDownStabilizationSeconds:  300,
+	}
+	name := types.NamespacedName{Name: "marklogic-haproxy", Namespace: "default"}
+
+	if res := cc.ReconcileHAProxyAutoscaling(); res.Completed() {
+		t.Fatalf("expected reconcile to continue, got %+v", res)
+	}
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{}
+	if err := cc.Client.Get(cc.Ctx, name, hpa); err != nil {
+		t.Fatalf("expected HorizontalPodAutoscaler: %v", err)
+	}
+	if ref := hpa.Spec.ScaleTargetRef; ref.Kind != "Deployment" || ref.Name != "marklogic-haproxy" || *hpa.Spec.MinReplicas != 2 || hpa.Spec.MaxReplicas != 6 {
+		t.Fatalf("unexpected HPA spec: %+v", hpa.Spec)
+	}
+	if metric := hpa.Spec.Metrics[0].ContainerResource; metric == nil || metric.Container != "haproxy" || *metric.Target.AverageUtilization != 70 {
+		t.Fatalf("expected CPU utilization of the HAProxy container, got %+v", hpa.Spec.Metrics)
+	}
+
+	cr.Spec.HAProxy.Autoscaling.Metric = marklogicv1.AutoscalingMetricConnectionRate
+	cr.Spec.HAProxy.Autoscaling.TargetConnectionsPerSecond = 500
+	if res := cc.ReconcileHAProxyAutoscaling(); res.Completed() {
+		t.Fatalf("expected reconcile to continue, got %+v", res)
+	}
+	if err := cc.Client.Get(cc.Ctx, name, hpa); err != nil {
+		t.Fatalf("expected HorizontalPodAutoscaler: %v", err)
+	}
+	metric := hpa.Spec.Metrics[0].Pods
+	if len(hpa.Spec.Metrics) != 1 || metric == nil || metric.Metric.Name != "haproxy_process_current_connection_rate" || metric.Target.AverageValue.Value() != 500 {
+		t.Fatalf("expected the connection rate per pod, got %+v", hpa.Spec.Metrics)
+	}
+
+	cr.Spec.HAProxy.Autoscaling = nil
+	if res := cc.ReconcileHAProxyAutoscaling(); res.Completed() {
+		t.Fatalf("expected reconcile to continue, got %+v", res)
+	}
+	if err := cc.Client.Get(cc.Ctx, name, hpa); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected the HPA to be removed with the policy, got %v", err)
+	}
+}
+
+func TestHAProxyDeploymentSpreadsPodsAndSeedsReplicas(t *testing.T) {
+	cc := newAppServerTestContext(t, &marklogicv1.MarklogicGroups{Name: "dnode", IsBootstrap: true, Replicas: int32Ptr(1)})
+	cc.Request = &reconcile.Request{NamespacedName: types.NamespacedName{Name: "ml", Namespace: "default"}}
+	cr := cc.MarklogicCluster
+	cr.Spec.HAProxy.ReplicaCount = 1
+	cr.Spec.HAProxy.PriorityClassName = "system-cluster-critical"
+	cr.Spec.HAProxy.TopologySpreadConstraints = []corev1.TopologySpreadConstraint{{
+		MaxSkew:           1,
+		TopologyKey:       corev1.LabelTopologyZone,
+		WhenUnsatisfiable: corev1.ScheduleAnyway,
+	}}
+	cr.Spec.HAProxy.Autoscaling = &marklogicv1.HAProxyAutoscaling{MinReplicas: 2, MaxReplicas: 6}
+
+	deployment := cc.createHAProxyDeploymentDef(metav1.ObjectMeta{Name: "marklogic-haproxy", Namespace: "default"})
+	spec := deployment.Spec.Template.Spec
+	if *deployment.Spec.Replicas != 2 {
+		t.Fatalf("expected the replicas to start at minReplicas, got %d", *deployment.Spec.Replicas)
+	}
+	terms := spec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution
+	if len(terms) != 1 || terms[0].PodAffinityTerm.TopologyKey != corev1.LabelHostname || terms[0].PodAffinityTerm.LabelSelector.MatchLabels["app.kubernetes.io/component"] != "haproxy" {
+		t.Fatalf("expected HAProxy pods to prefer different nodes, got %+v", spec.Affinity)
+	}
+	if constraint := spec.TopologySpreadConstraints[0]; constraint.LabelSelector == nil || constraint.LabelSelector.MatchLabels["app.kubernetes.io/component"] != "haproxy" {
+		t.Fatalf("expected the HAProxy pods as the spread selector, got %+v", spec.TopologySpreadConstraints)
+	}
+	if cr.Spec.HAProxy.TopologySpreadConstraints[0].LabelSelector != nil {
+		t.Fatal("expected the spec to be left alone")
+	}
+	if spec.PriorityClassName != "system-cluster-critical" {
+		t.Fatalf("expected the priority class, got %q", spec.PriorityClassName)
+	}
+
+	cr.Spec.HAProxy.Affinity = &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{}}
+	if affinity := cc.createHAProxyDeploymentDef(metav1.ObjectMeta{Name: "marklogic-haproxy"}).Spec.Template.Spec.Affinity; affinity.PodAntiAffinity != nil {
+		t.Fatalf("expected the affinity of the spec to replace the default, got %+v", affinity)
+	}
+}
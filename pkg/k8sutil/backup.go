@@ -0,0 +1,369 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	backupPollSeconds  = 15
+	backupRetrySeconds = 30
+	backupHistoryLimit = 5
+
+	backupReasonReady              = "Ready"
+	backupReasonClusterNotFound    = "ClusterNotFound"
+	backupReasonClusterUnavailable = "ClusterUnavailable"
+	backupReasonInvalidSchedule    = "InvalidSchedule"
+	backupReasonStorageNotReady    = "StorageNotReady"
+	backupReasonSuspended          = "Suspended"
+)
+
+// ReconcileBackup starts due backup runs, follows the Management API jobs of the run in
+// progress and prunes old backups once a run succeeds.
+func (bc *BackupContext) ReconcileBackup() result.ReconcileResult {
+	return bc.reconcileBackup(time.Now())
+}
+
+func (bc *BackupContext) reconcileBackup(now time.Time) result.ReconcileResult {
+	cr := bc.MarklogicBackup
+	patchClient := client.MergeFrom(cr.DeepCopy())
+	status := cr.Status.DeepCopy()
+	status.ObservedGeneration = cr.Generation
+
+	requeueSecs, err := bc.advanceBackup(status, now)
+	if err != nil {
+		return result.Error(err)
+	}
+	if !reflect.DeepEqual(*status, cr.Status) {
+		cr.Status = *status
+		if err := bc.Client.Status().Patch(bc.Ctx, cr, patchClient); err != nil {
+			bc.ReqLogger.Error(err, "Failed to update MarklogicBackup status")
+			return result.Error(err)
+		}
+	}
+	if requeueSecs > 0 {
+		return result.RequeueSoon(requeueSecs)
+	}
+	return result.Done()
+}
+
+// advanceBackup moves the backup status forward and returns how long to wait before the
+// next reconcile, or 0 when only a spec change can make progress.
+func (bc *BackupContext) advanceBackup(status *marklogicv1.MarklogicBackupStatus, now time.Time) (int, error) {
+	cr := bc.MarklogicBackup
+
+	var schedule *cronSchedule
+	var scheduleErr error
+	if strings.TrimSpace(cr.Spec.Schedule) != "" {
+		schedule, scheduleErr = parseCronSchedule(cr.Spec.Schedule)
+	}
+
+	cluster := &marklogicv1.MarklogicCluster{}
+	if err := bc.Client.Get(bc.Ctx, types.NamespacedName{Name: cr.Spec.ClusterRef.Name, Namespace: cr.Namespace}, cluster); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return 0, err
+		}
+		setBackupCondition(&status.Conditions, cr.Generation, metav1.ConditionFalse, backupReasonClusterNotFound,
+			fmt.Sprintf("MarklogicCluster %s not found", cr.Spec.ClusterRef.Name))
+		return backupRetrySeconds, nil
+	}
+
+	if status.CurrentRun != nil {
+		done, err := bc.pollBackupRun(cluster, status.CurrentRun)
+		if err != nil {
+			setBackupCondition(&status.Conditions, cr.Generation, metav1.ConditionFalse, backupReasonClusterUnavailable, err.Error())
+			return backupRetrySeconds, nil
+		}
+		if !done {
+			status.Phase = marklogicv1.BackupPhaseRunning
+			setBackupCondition(&status.Conditions, cr.Generation, metav1.ConditionTrue, backupReasonReady, "backup run in progress")
+			return backupPollSeconds, nil
+		}
+		bc.finishBackupRun(cluster, status, now)
+	}
+
+	// A run already in flight finishes even if the schedule was edited into an invalid one.
+	if scheduleErr != nil {
+		status.Phase = marklogicv1.BackupPhaseFailed
+		setBackupCondition(&status.Conditions, cr.Generation, metav1.ConditionFalse, backupReasonInvalidSchedule, scheduleErr.Error())
+		return 0, nil
+	}
+
+	if schedule == nil {
+		if len(status.History) > 0 {
+			status.Phase = status.History[0].Phase
+			return 0, nil
+		}
+		return bc.startBackupRun(cluster, status, now, now)
+	}
+
+	last := cr.CreationTimestamp.Time
+	if status.LastScheduleTime != nil {
+		last = status.LastScheduleTime.Time
+	}
+	slot := schedule.next(last)
+	if slot.IsZero() {
+		setBackupCondition(&status.Conditions, cr.Generation, metav1.ConditionFalse, backupReasonInvalidSchedule,
+			fmt.Sprintf("schedule %q never fires", cr.Spec.Schedule))
+		return 0, nil
+	}
+	if cr.Spec.Suspend {
+		status.Phase = marklogicv1.BackupPhaseScheduled
+		status.NextScheduleTime = nil
+		setBackupCondition(&status.Conditions, cr.Generation, metav1.ConditionTrue, backupReasonSuspended, "scheduled backups are suspended")
+		return 0, nil
+	}
+	if !slot.After(now) {
+		// Only the latest missed slot runs; older ones are skipped like a CronJob without backfill.
+		for next := schedule.next(slot); !next.After(now); next = schedule.next(slot) {
+			slot = next
+		}
+		return bc.startBackupRun(cluster, status, slot, now)
+	}
+
+	status.Phase = marklogicv1.BackupPhaseScheduled
+	next := metav1.NewTime(slot)
+	status.NextScheduleTime = &next
+	setBackupCondition(&status.Conditions, cr.Generation, metav1.ConditionTrue, backupReasonReady,
+		fmt.Sprintf("next backup at %s", slot.Format(time.RFC3339)))
+	return int(slot.Sub(now).Seconds()) + 1, nil
+}
+
+func (bc *BackupContext) startBackupRun(cluster *marklogicv1.MarklogicCluster, status *marklogicv1.MarklogicBackupStatus, slot, now time.Time) (int, error) {
+	cr := bc.MarklogicBackup
+	problem, err := backupStorageProblem(bc, cluster, cr.Spec.Storage)
+	if err != nil {
+		return 0, err
+	}
+	if problem != "" {
+		status.Phase = marklogicv1.BackupPhasePending
+		setBackupCondition(&status.Conditions, cr.Generation, metav1.ConditionFalse, backupReasonStorageNotReady, problem)
+		return backupRetrySeconds, nil
+	}
+
+	scheduled := metav1.NewTime(slot)
+	started := metav1.NewTime(now)
+	run := &marklogicv1.BackupRun{
+		ScheduledTime: scheduled,
+		StartTime:     &started,
+		Phase:         marklogicv1.BackupPhaseRunning,
+	}
+	for _, database := range cr.Spec.Databases {
+		run.Databases = append(run.Databases, marklogicv1.DatabaseJobStatus{
+			Name:      database,
+			Directory: backupDirectory(cr.Spec.Storage.Path, cr.Name, database),
+			State:     marklogicv1.DatabaseJobStatePending,
+		})
+	}
+	status.CurrentRun = run
+	status.LastScheduleTime = &scheduled
+	status.NextScheduleTime = nil
+	status.Phase = marklogicv1.BackupPhaseRunning
+	setBackupCondition(&status.Conditions, cr.Generation, metav1.ConditionTrue, backupReasonReady, "backup run in progress")
+	bc.Recorder.Eventf(cr, corev1.EventTypeNormal, "BackupStarted", "Backing up %s", strings.Join(cr.Spec.Databases, ", "))
+
+	if _, err := bc.pollBackupRun(cluster, run); err != nil {
+		setBackupCondition(&status.Conditions, cr.Generation, metav1.ConditionFalse, backupReasonClusterUnavailable, err.Error())
+		return backupRetrySeconds, nil
+	}
+	return backupPollSeconds, nil
+}
+
+// pollBackupRun starts the pending database jobs of run and refreshes the running ones.
+// It reports whether every job has finished. A database whose job fails is not retried
+// within the same run.
+func (bc *BackupContext) pollBackupRun(cluster *marklogicv1.MarklogicCluster, run *marklogicv1.BackupRun) (bool, error) {
+	mc, err := managementClientForCluster(bc.Ctx, bc.Client, cluster)
+	if err != nil {
+		return false, err
+	}
+	includeReplicas := bc.MarklogicBackup.Spec.IncludeReplicas == nil || *bc.MarklogicBackup.Spec.IncludeReplicas
+	done := true
+	for i := range run.Databases {
+		db := &run.Databases[i]
+		switch db.State {
+		case marklogicv1.DatabaseJobStatePending:
+			job, err := mc.BackupDatabase(bc.Ctx, db.Name, mlmanage.BackupOptions{BackupDir: db.Directory, IncludeReplicas: includeReplicas})
+			if err != nil {
+				db.State = marklogicv1.DatabaseJobStateFailed
+				db.Message = err.Error()
+				continue
+			}
+			db.JobID = job.ID
+			db.HostName = job.HostName
+			db.State = marklogicv1.DatabaseJobStateRunning
+			db.Message = ""
+			done = false
+		case marklogicv1.DatabaseJobStateRunning:
+			jobStatus, err := mc.GetBackupStatus(bc.Ctx, db.Name, mlmanage.DatabaseJob{ID: db.JobID, HostName: db.HostName})
+			if err != nil {
+				db.Message = err.Error()
+				done = false
+				continue
+			}
+			switch jobStatus.State {
+			case mlmanage.JobStateCompleted:
+				db.State = marklogicv1.DatabaseJobStateCompleted
+				db.Message = ""
+				if size, err := mc.GetDatabaseSize(bc.Ctx, db.Name); err == nil {
+					db.SizeBytes = size
+				} else {
+					bc.ReqLogger.Info("Could not read database size after backup", "database", db.Name, "error", err.Error())
+				}
+			case mlmanage.JobStateFailed:
+				db.State = marklogicv1.DatabaseJobStateFailed
+				db.Message = jobStatus.Message
+				if db.Message == "" {
+					db.Message = "backup job failed"
+				}
+			default:
+				db.Message = jobStatus.Message
+				done = false
+			}
+		}
+	}
+	return done, nil
+}
+
+func (bc *BackupContext) finishBackupRun(cluster *marklogicv1.MarklogicCluster, status *marklogicv1.MarklogicBackupStatus, now time.Time) {
+	cr := bc.MarklogicBackup
+	run := status.CurrentRun
+	completed := metav1.NewTime(now)
+	run.CompletionTime = &completed
+	run.SizeBytes = 0
+	var failed []string
+	for _, db := range run.Databases {
+		run.SizeBytes += db.SizeBytes
+		if db.State != marklogicv1.DatabaseJobStateCompleted {
+			failed = append(failed, fmt.Sprintf("%s: %s", db.Name, db.Message))
+		}
+	}
+	if len(failed) > 0 {
+		run.Phase = marklogicv1.BackupPhaseFailed
+		run.Message = strings.Join(failed, "; ")
+		bc.Recorder.Eventf(cr, corev1.EventTypeWarning, "BackupFailed", "Backup failed for %s", run.Message)
+	} else {
+		run.Phase = marklogicv1.BackupPhaseCompleted
+		run.Message = fmt.Sprintf("backed up %d database(s)", len(run.Databases))
+		status.LastSuccessfulTime = &completed
+		bc.Recorder.Eventf(cr, corev1.EventTypeNormal, "BackupCompleted", "Backed up %s (%d bytes)", strings.Join(cr.Spec.Databases, ", "), run.SizeBytes)
+		bc.pruneBackups(cluster, run)
+	}
+	status.Phase = run.Phase
+	status.History = append([]marklogicv1.BackupRun{*run}, status.History...)
+	if len(status.History) > backupHistoryLimit {
+		status.History = status.History[:backupHistoryLimit]
+	}
+	status.CurrentRun = nil
+}
+
+// pruneBackups applies the retention policy after a successful run. Pruning failures
+// are reported as events and retried after the next run.
+func (bc *BackupContext) pruneBackups(cluster *marklogicv1.MarklogicCluster, run *marklogicv1.BackupRun) {
+	cr := bc.MarklogicBackup
+	if cr.Spec.Retention == nil || cr.Spec.Retention.KeepLast < 1 {
+		return
+	}
+	mc, err := managementClientForCluster(bc.Ctx, bc.Client, cluster)
+	if err != nil {
+		bc.Recorder.Eventf(cr, corev1.EventTypeWarning, "BackupPruneFailed", "Could not prune old backups: %v", err)
+		return
+	}
+	for _, db := range run.Databases {
+		if err := mc.PurgeBackups(bc.Ctx, db.Name, db.Directory, int(cr.Spec.Retention.KeepLast)); err != nil {
+			bc.Recorder.Eventf(cr, corev1.EventTypeWarning, "BackupPruneFailed", "Could not prune old backups of %s: %v", db.Name, err)
+		}
+	}
+}
+
+// backupStorageProblem returns why the backup storage cannot be used yet, or "" when it can.
+// Plain paths are trusted to be mounted on every host.
+func backupStorageProblem(bc *BackupContext, cluster *marklogicv1.MarklogicCluster, storage marklogicv1.BackupStorage) (string, error) {
+	claim := storage.PersistentVolumeClaim
+	if claim == nil {
+		return "", nil
+	}
+	if claim.ReadOnly {
+		return fmt.Sprintf("persistentVolumeClaim %s is mounted read-only", claim.ClaimName), nil
+	}
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := bc.Client.Get(bc.Ctx, types.NamespacedName{Name: claim.ClaimName, Namespace: cluster.Namespace}, pvc); err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Sprintf("persistentVolumeClaim %s not found", claim.ClaimName), nil
+		}
+		return "", err
+	}
+	if pvc.Status.Phase != corev1.ClaimBound {
+		return fmt.Sprintf("persistentVolumeClaim %s is %s, not Bound", claim.ClaimName, pvc.Status.Phase), nil
+	}
+	for _, groupSpec := range cluster.Spec.MarkLogicGroups {
+		if groupSpec == nil {
+			continue
+		}
+		group := &marklogicv1.MarklogicGroup{}
+		if err := bc.Client.Get(bc.Ctx, types.NamespacedName{Name: groupSpec.Name, Namespace: cluster.Namespace}, group); err != nil {
+			if apierrors.IsNotFound(err) {
+				return fmt.Sprintf("MarklogicGroup %s not created yet", groupSpec.Name), nil
+			}
+			return "", err
+		}
+		if !groupMountsClaim(group, claim.ClaimName, storage.Path) {
+			return fmt.Sprintf("group %s does not mount persistentVolumeClaim %s at %s", group.Name, claim.ClaimName, storage.Path), nil
+		}
+	}
+	return "", nil
+}
+
+// groupMountsClaim reports whether the group's additional volumes mount claimName at, or
+// above, dir.
+func groupMountsClaim(group *marklogicv1.MarklogicGroup, claimName, dir string) bool {
+	if group.Spec.AdditionalVolumes == nil || group.Spec.AdditionalVolumeMounts == nil {
+		return false
+	}
+	volumeNames := map[string]bool{}
+	for _, volume := range *group.Spec.AdditionalVolumes {
+		if volume.PersistentVolumeClaim != nil && volume.PersistentVolumeClaim.ClaimName == claimName {
+			volumeNames[volume.Name] = true
+		}
+	}
+	dir = path.Clean(dir)
+	for _, mount := range *group.Spec.AdditionalVolumeMounts {
+		if !volumeNames[mount.Name] || mount.ReadOnly {
+			continue
+		}
+		mountPath := path.Clean(mount.MountPath)
+		if dir == mountPath || strings.HasPrefix(dir, strings.TrimSuffix(mountPath, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func backupDirectory(root, backupName, database string) string {
+	return path.Join(root, backupName, database)
+}
+
+func setBackupCondition(conditions *[]metav1.Condition, generation int64, conditionStatus metav1.ConditionStatus, reason, message string) {
+	apimeta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               string(marklogicv1.BackupReady),
+		Status:             conditionStatus,
+		ObservedGeneration: generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
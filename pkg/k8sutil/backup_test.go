@@ -0,0 +1,254 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newBackupTestClient(t *testing.T, objects ...client.Object) client.Client {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := marklogicv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add marklogic scheme: %v", err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add core scheme: %v", err)
+	}
+	objects = append(objects,
+		&marklogicv1.MarklogicCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+			Spec: marklogicv1.MarklogicClusterSpec{
+				ClusterDomain:   "cluster.local",
+				MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "dnode", IsBootstrap: true}},
+			},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "ml-admin", Namespace: "default"},
+			Data:       map[string][]byte{"username": []byte("admin"), "password": []byte("admin")},
+		},
+	)
+	return fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithStatusSubresource(&marklogicv1.MarklogicBackup{}, &marklogicv1.MarklogicRestore{}, &marklogicv1.MarklogicDatabase{}, &marklogicv1.MarklogicReplication{}).
+		WithObjects(objects...).
+		Build()
+}
+
+// stubBackupClient observes the backup and restore calls.
+type stubBackupClient struct {
+	backupFn        func(database string, opts mlmanage.BackupOptions) (mlmanage.DatabaseJob, error)
+	backupStatusFn  func(database string, job mlmanage.DatabaseJob) (mlmanage.JobStatus, error)
+	purgeFn         func(database, backupDir string, keep int) error
+	restoreFn       func(database string, opts mlmanage.RestoreOptions) (mlmanage.DatabaseJob, error)
+	restoreStatusFn func(database string, job mlmanage.DatabaseJob) (mlmanage.JobStatus, error)
+}
+
+func (s *stubBackupClient) BackupDatabase(ctx context.Context, database string, opts mlmanage.BackupOptions) (mlmanage.DatabaseJob, error) {
+	if s.backupFn == nil {
+		return mlmanage.DatabaseJob{}, errors.New("backupFn is not configured")
+	}
+	return s.backupFn(database, opts)
+}
+
+func (s *stubBackupClient) GetBackupStatus(ctx context.Context, database string, job mlmanage.DatabaseJob) (mlmanage.JobStatus, error) {
+	if s.backupStatusFn == nil {
+		return mlmanage.JobStatus{}, errors.New("backupStatusFn is not configured")
+	}
+	return s.backupStatusFn(database, job)
+}
+
+func (s *stubBackupClient) PurgeBackups(ctx context.Context, database, backupDir string, keep int) error {
+	if s.purgeFn != nil {
+		return s.purgeFn(database, backupDir, keep)
+	}
+	return nil
+}
+
+func (s *stubBackupClient) RestoreDatabase(ctx context.Context, database string, opts mlmanage.RestoreOptions) (mlmanage.DatabaseJob, error) {
+	if s.restoreFn == nil {
+		return mlmanage.DatabaseJob{}, errors.New("restoreFn is not configured")
+	}
+	return s.restoreFn(database, opts)
+}
+
+func (s *stubBackupClient) GetRestoreStatus(ctx context.Context, database string, job mlmanage.DatabaseJob) (mlmanage.JobStatus, error) {
+	if s.restoreStatusFn == nil {
+		return mlmanage.JobStatus{}, errors.New("restoreStatusFn is not configured")
+	}
+	return s.restoreStatusFn(database, job)
+}
+
+func (s *stubBackupClient) GetDatabaseSize(ctx context.Context, database string) (int64, error) {
+	return 1 << 20, nil
+}
+
+func TestReconcileBackupRunsOneShotBackup(t *testing.T) {
+	backup := &marklogicv1.MarklogicBackup{
+		ObjectMeta: metav1.ObjectMeta{Name: "adhoc", Namespace: "default"},
+		Spec: marklogicv1.MarklogicBackupSpec{
+			ClusterRef: corev1.LocalObjectReference{Name: "ml"},
+			Databases:  []string{"Documents", "Security"},
+			Storage:    marklogicv1.BackupStorage{Path: "/backups"},
+		},
+	}
+	fakeClient := newBackupTestClient(t, backup)
+
+	jobStates := map[string]string{}
+	var startedDirs []string
+	useStubClusterManagementClient(t, managementStub{BackupClient: &stubBackupClient{
+		backupFn: func(database string, opts mlmanage.BackupOptions) (mlmanage.DatabaseJob, error) {
+			startedDirs = append(startedDirs, opts.BackupDir)
+			jobStates[database] = mlmanage.JobStateInProgress
+			return mlmanage.DatabaseJob{ID: "job-" + database, HostName: "dnode-0"}, nil
+		},
+		backupStatusFn: func(database string, job mlmanage.DatabaseJob) (mlmanage.JobStatus, error) {
+			return mlmanage.JobStatus{State: jobStates[database]}, nil
+		},
+	}})
+
+	bc := &BackupContext{Ctx: context.Background(), Client: fakeClient, MarklogicBackup: backup, Recorder: record.NewFakeRecorder(10)}
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	if res := bc.reconcileBackup(now); !res.Completed() {
+		t.Fatal("expected the backup to requeue while jobs run")
+	}
+	if len(startedDirs) != 2 || startedDirs[0] != "/backups/adhoc/Documents" || startedDirs[1] != "/backups/adhoc/Security" {
+		t.Fatalf("unexpected backup directories %v", startedDirs)
+	}
+	if backup.Status.Phase != marklogicv1.BackupPhaseRunning || backup.Status.CurrentRun == nil {
+		t.Fatalf("expected a running backup, got %+v", backup.Status)
+	}
+
+	jobStates["Documents"] = mlmanage.JobStateCompleted
+	jobStates["Security"] = mlmanage.JobStateCompleted
+	if res := bc.reconcileBackup(now.Add(time.Minute)); !res.Completed() {
+		t.Fatal("expected the backup reconcile to finish")
+	}
+
+	current := &marklogicv1.MarklogicBackup{}
+	if err := fakeClient.Get(context.Background(), types.NamespacedName{Name: "adhoc", Namespace: "default"}, current); err != nil {
+		t.Fatalf("failed to fetch MarklogicBackup: %v", err)
+	}
+	status := current.Status
+	if status.Phase != marklogicv1.BackupPhaseCompleted || status.CurrentRun != nil || status.LastSuccessfulTime == nil {
+		t.Fatalf("expected a completed backup, got %+v", status)
+	}
+	if len(status.History) != 1 || status.History[0].SizeBytes != 2<<20 {
+		t.Fatalf("expected one run with the summed database sizes, got %+v", status.History)
+	}
+
+	// A one-shot backup never runs again.
+	startedDirs = nil
+	bc.MarklogicBackup = current
+	if res := bc.reconcileBackup(now.Add(time.Hour)); !res.Completed() {
+		t.Fatal("expected the reconcile to be done")
+	}
+	if len(startedDirs) != 0 {
+		t.Fatalf("expected no new backup jobs, got %v", startedDirs)
+	}
+}
+
+func TestReconcileBackupRunsLatestDueSlotAndPrunes(t *testing.T) {
+	lastSchedule := metav1.NewTime(time.Date(2026, 1, 8, 2, 0, 0, 0, time.UTC))
+	backup := &marklogicv1.MarklogicBackup{
+		ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "default"},
+		Spec: marklogicv1.MarklogicBackupSpec{
+			ClusterRef: corev1.LocalObjectReference{Name: "ml"},
+			Databases:  []string{"Documents"},
+			Storage:    marklogicv1.BackupStorage{Path: "/backups"},
+			Schedule:   "0 2 * * *",
+			Retention:  &marklogicv1.BackupRetention{KeepLast: 3},
+		},
+		Status: marklogicv1.MarklogicBackupStatus{LastScheduleTime: &lastSchedule},
+	}
+	fakeClient := newBackupTestClient(t, backup)
+
+	var purged []string
+	useStubClusterManagementClient(t, managementStub{BackupClient: &stubBackupClient{
+		backupFn: func(database string, opts mlmanage.BackupOptions) (mlmanage.DatabaseJob, error) {
+			return mlmanage.DatabaseJob{ID: "1"}, nil
+		},
+		backupStatusFn: func(database string, job mlmanage.DatabaseJob) (mlmanage.JobStatus, error) {
+			return mlmanage.JobStatus{State: mlmanage.JobStateCompleted}, nil
+		},
+		purgeFn: func(database, backupDir string, keep int) error {
+			if keep != 3 {
+				t.Errorf("expected keep=3, got %d", keep)
+			}
+			purged = append(purged, backupDir)
+			return nil
+		},
+	}})
+
+	bc := &BackupContext{Ctx: context.Background(), Client: fakeClient, MarklogicBackup: backup, Recorder: record.NewFakeRecorder(10)}
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	bc.reconcileBackup(now)
+	if got := backup.Status.LastScheduleTime.Time; !got.Equal(time.Date(2026, 1, 10, 2, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected only the latest missed slot to run, got %s", got)
+	}
+
+	bc.reconcileBackup(now.Add(time.Minute))
+	if len(purged) != 1 || purged[0] != "/backups/nightly/Documents" {
+		t.Fatalf("expected retention to prune the database directory, got %v", purged)
+	}
+	if backup.Status.Phase != marklogicv1.BackupPhaseScheduled {
+		t.Fatalf("expected the backup to wait for the next slot, got %s", backup.Status.Phase)
+	}
+	if next := backup.Status.NextScheduleTime; next == nil || !next.Time.Equal(time.Date(2026, 1, 11, 2, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected next schedule time %v", next)
+	}
+}
+
+func TestReconcileBackupWaitsForMountedClaim(t *testing.T) {
+	claim := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "backups", Namespace: "default"},
+		Status:     corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound},
+	}
+	group := &marklogicv1.MarklogicGroup{ObjectMeta: metav1.ObjectMeta{Name: "dnode", Namespace: "default"}}
+	backup := &marklogicv1.MarklogicBackup{
+		ObjectMeta: metav1.ObjectMeta{Name: "adhoc", Namespace: "default"},
+		Spec: marklogicv1.MarklogicBackupSpec{
+			ClusterRef: corev1.LocalObjectReference{Name: "ml"},
+			Databases:  []string{"Documents"},
+			Storage: marklogicv1.BackupStorage{
+				Path:                  "/backups/ml",
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "backups"},
+			},
+		},
+	}
+	fakeClient := newBackupTestClient(t, claim, group, backup)
+	bc := &BackupContext{Ctx: context.Background(), Client: fakeClient, MarklogicBackup: backup, Recorder: record.NewFakeRecorder(10)}
+
+	bc.reconcileBackup(time.Now())
+	if backup.Status.Phase != marklogicv1.BackupPhasePending || backup.Status.CurrentRun != nil {
+		t.Fatalf("expected the backup to wait for storage, got %+v", backup.Status)
+	}
+	if cond := backup.Status.Conditions; len(cond) != 1 || cond[0].Reason != backupReasonStorageNotReady {
+		t.Fatalf("expected StorageNotReady, got %+v", cond)
+	}
+
+	group.Spec.AdditionalVolumes = &[]corev1.Volume{{
+		Name:         "backups",
+		VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "backups"}},
+	}}
+	group.Spec.AdditionalVolumeMounts = &[]corev1.VolumeMount{{Name: "backups", MountPath: "/backups"}}
+	if !groupMountsClaim(group, "backups", "/backups/ml") {
+		t.Fatal("expected a mount above the backup path to satisfy the check")
+	}
+	if groupMountsClaim(group, "backups", "/backupsfoo") {
+		t.Fatal("expected a sibling path not to match the mount")
+	}
+}
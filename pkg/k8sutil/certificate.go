@@ -0,0 +1,226 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	controllerClient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+)
+
+const (
+	haproxyCertificateName = "marklogic-haproxy-tls"
+	// haproxyIssuedCertFile is where the issued certificate is mounted in the HAProxy pod.
+	// The key is mounted next to it as tls.crt.key, which HAProxy loads automatically.
+	haproxyIssuedCertFile = "tls.crt"
+)
+
+var certificateGVK = schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"}
+
+// certManagerIssued reports whether the host certificates of a group come from cert-manager.
+func certManagerIssued(tls *marklogicv1.Tls) bool {
+	return tls != nil && tls.EnableOnDefaultAppServers && tls.IssuerRef != nil
+}
+
+func haproxyCertManagerIssued(tls *marklogicv1.TlsForHAProxy) bool {
+	return tls != nil && tls.Enabled && tls.IssuerRef != nil
+}
+
+func groupCertificateName(groupName string) string {
+	return groupName + "-tls"
+}
+
+// serviceDNSNames returns the short and qualified names a Service is reachable under.
+func serviceDNSNames(service, namespace, clusterDomain string) []string {
+	return []string{
+		service,
+		service + "." + namespace,
+		service + "." + namespace + ".svc",
+		service + "." + namespace + ".svc." + clusterDomain,
+	}
+}
+
+// groupCertificateDNSNames lists the FQDN of every pod the group can run, followed by the
+// headless and -cluster Services. Autoscaled groups cover maxReplicas so that scaling does
+// not wait for a new certificate.
+func groupCertificateDNSNames(cr *marklogicv1.MarklogicGroup) []string {
+	replicas := int32(1)
+	if cr.Spec.Replicas != nil {
+		replicas = *cr.Spec.Replicas
+	}
+	if policy := autoscalingPolicy(cr); policy != nil && policy.MaxReplicas > replicas {
+		replicas = policy.MaxReplicas
+	}
+	suffix := fmt.Sprintf("%s.%s.svc.%s", cr.Spec.Name, cr.Namespace, cr.Spec.ClusterDomain)
+	dnsNames := []string{}
+	for i := int32(0); i < replicas; i++ {
+		dnsNames = append(dnsNames, fmt.Sprintf("%s-%d.%s", cr.Spec.Name, i, suffix))
+	}
+	dnsNames = append(dnsNames, suffix)
+	return append(dnsNames, serviceDNSNames(cr.Spec.Name+"-cluster", cr.Namespace, cr.Spec.ClusterDomain)...)
+}
+
+// haproxyCertificateDNSNames covers the HAProxy Service and every host published on the Ingress.
+func haproxyCertificateDNSNames(cr *marklogicv1.MarklogicCluster) []string {
+	dnsNames := serviceDNSNames("marklogic-haproxy", cr.Namespace, cr.Spec.ClusterDomain)
+	ingress := cr.Spec.HAProxy.Ingress
+	if !ingress.Enabled {
+		return dnsNames
+	}
+	seen := map[string]bool{}
+	for _, name := range dnsNames {
+		seen[name] = true
+	}
+	hosts := []string{ingress.Host}
+	for _, rule := range ingress.AdditionalHosts {
+		hosts = append(hosts, rule.Host)
+	}
+	for _, host := range hosts {
+		if host != "" && !seen[host] {
+			seen[host] = true
+			dnsNames = append(dnsNames, host)
+		}
+	}
+	return dnsNames
+}
+
+func generateCertificate(name, namespace string, labels map[string]string, owner metav1.OwnerReference, issuer *marklogicv1.CertificateIssuerRef, dnsNames []string) *unstructured.Unstructured {
+	names := make([]any, 0, len(dnsNames))
+	for _, dnsName := range dnsNames {
+		names = append(names, dnsName)
+	}
+	issuerRef := map[string]any{"name": issuer.Name}
+	if issuer.Kind != "" {
+		issuerRef["kind"] = issuer.Kind
+	}
+	if issuer.Group != "" {
+		issuerRef["group"] = issuer.Group
+	}
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(certificateGVK)
+	obj.SetName(name)
+	obj.SetNamespace(namespace)
+	obj.SetLabels(labels)
+	obj.SetOwnerReferences([]metav1.OwnerReference{owner})
+	obj.Object["spec"] = map[string]any{
+		"secretName": name,
+		"dnsNames":   names,
+		"issuerRef":  issuerRef,
+		"usages":     []any{"server auth", "client auth"},
+		// copy-certs.sh validates the key pair with openssl rsa.
+		"privateKey": map[string]any{"algorithm": "RSA", "encoding": "PKCS1", "size": int64(2048)},
+	}
+	return obj
+}
+
+// applyCertificate creates or updates a cert-manager Certificate. Unlike the optional
+// ServiceMonitor, a missing cert-manager CRD is an error since the pods cannot start
+// without the issued secret.
+func applyCertificate(ctx context.Context, c controllerClient.Client, logger logr.Logger, desired *unstructured.Unstructured) error {
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(certificateGVK)
+	err := c.Get(ctx, types.NamespacedName{Name: desired.GetName(), Namespace: desired.GetNamespace()}, current)
+	switch {
+	case apimeta.IsNoMatchError(err):
+		err = fmt.Errorf("cert-manager Certificate CRD is not installed: %w", err)
+		logger.Error(err, "Cannot request certificate", "name", desired.GetName())
+		return err
+	case apierrors.IsNotFound(err):
+		logger.Info("Creating cert-manager Certificate", "name", desired.GetName())
+		if err := c.Create(ctx, desired); err != nil {
+			logger.Error(err, "Certificate creation has failed")
+			return err
+		}
+		return nil
+	case err != nil:
+		logger.Error(err, "Failed to get Certificate")
+		return err
+	}
+	if equality.Semantic.DeepEqual(current.Object["spec"], desired.Object["spec"]) &&
+		equality.Semantic.DeepEqual(current.GetLabels(), desired.GetLabels()) {
+		return nil
+	}
+	logger.Info("Certificate is different from the spec, updating it", "name", desired.GetName())
+	current.Object["spec"] = desired.Object["spec"]
+	current.SetLabels(desired.GetLabels())
+	if err := c.Update(ctx, current); err != nil {
+		logger.Error(err, "Error updating Certificate")
+		return err
+	}
+	return nil
+}
+
+// deleteCertificate removes a Certificate the owner created earlier. The issued secret is
+// left in place; cert-manager does not own it by default.
+func deleteCertificate(ctx context.Context, c controllerClient.Client, logger logr.Logger, name, namespace string, ownerUID types.UID) error {
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(certificateGVK)
+	err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, current)
+	if apierrors.IsNotFound(err) || apimeta.IsNoMatchError(err) {
+		return nil
+	}
+	if err != nil {
+		logger.Error(err, "Failed to get Certificate")
+		return err
+	}
+	if controller := metav1.GetControllerOf(current); controller == nil || controller.UID != ownerUID {
+		return nil
+	}
+	logger.Info("Certificate is no longer requested, deleting it", "name", name)
+	if err := c.Delete(ctx, current); err != nil && !apierrors.IsNotFound(err) {
+		logger.Error(err, "Failed to delete Certificate")
+		return err
+	}
+	return nil
+}
+
+// ReconcileCertificates requests the host certificates of the group from cert-manager when
+// tls.issuerRef is set. The StatefulSet mounts the issued secret, see generateVolumes.
+func (oc *OperatorContext) ReconcileCertificates() result.ReconcileResult {
+	cr := oc.MarklogicGroup
+	name := groupCertificateName(cr.Spec.Name)
+	if !certManagerIssued(cr.Spec.Tls) {
+		if err := deleteCertificate(oc.Ctx, oc.Client, oc.ReqLogger, name, cr.Namespace, cr.UID); err != nil {
+			return result.Error(err)
+		}
+		return result.Continue()
+	}
+	certificate := generateCertificate(name, cr.Namespace, oc.GetOperatorLabels(cr.Spec.Name),
+		marklogicServerAsOwner(cr), cr.Spec.Tls.IssuerRef, groupCertificateDNSNames(cr))
+	if err := applyCertificate(oc.Ctx, oc.Client, oc.ReqLogger, certificate); err != nil {
+		oc.Recorder.Event(cr, "Warning", "CertificateFailed", err.Error())
+		return result.Error(err)
+	}
+	return result.Continue()
+}
+
+// ReconcileHAProxyCertificate requests the HAProxy certificate from cert-manager when
+// haproxy.tls.issuerRef is set.
+func (cc *ClusterContext) ReconcileHAProxyCertificate() result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	if !haproxyCertManagerIssued(cr.Spec.HAProxy.Tls) {
+		if err := deleteCertificate(cc.Ctx, cc.Client, cc.ReqLogger, haproxyCertificateName, cr.Namespace, cr.UID); err != nil {
+			return result.Error(err)
+		}
+		return result.Continue()
+	}
+	certificate := generateCertificate(haproxyCertificateName, cr.Namespace, cc.GetHAProxyLabels(cr.GetObjectMeta().GetName()),
+		marklogicClusterAsOwner(cr), cr.Spec.HAProxy.Tls.IssuerRef, haproxyCertificateDNSNames(cr))
+	if err := applyCertificate(cc.Ctx, cc.Client, cc.ReqLogger, certificate); err != nil {
+		cc.Recorder.Event(cr, "Warning", "CertificateFailed", err.Error())
+		return result.Error(err)
+	}
+	return result.Continue()
+}
@@ -0,0 +1,231 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const defaultCertificateExpiryWarningDays = 30
+
+// certificateSecretNames returns the TLS secrets holding host certificates of the group.
+func certificateSecretNames(cr *marklogicv1.MarklogicGroup) []string {
+	tls := cr.Spec.Tls
+	if tls == nil || !tls.EnableOnDefaultAppServers {
+		return nil
+	}
+	if certManagerIssued(tls) {
+		return []string{groupCertificateName(cr.Spec.Name)}
+	}
+	return tls.CertSecretNames
+}
+
+// MarklogicGroupsForSecret lists the groups whose host certificates come from the secret.
+// The group controller uses it to reconcile a group when its certificates are replaced.
+func MarklogicGroupsForSecret(groups []marklogicv1.MarklogicGroup, secret client.Object) []types.NamespacedName {
+	requests := []types.NamespacedName{}
+	for i := range groups {
+		group := &groups[i]
+		if group.Namespace != secret.GetNamespace() {
+			continue
+		}
+		for _, name := range certificateSecretNames(group) {
+			if name == secret.GetName() {
+				requests = append(requests, types.NamespacedName{Name: group.Name, Namespace: group.Namespace})
+				break
+			}
+		}
+	}
+	return requests
+}
+
+// parseCertificateSecret reads the leaf certificate of a kubernetes.io/tls secret.
+func parseCertificateSecret(secret *corev1.Secret) (*x509.Certificate, string, error) {
+	block, _ := pem.Decode(secret.Data[corev1.TLSCertKey])
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, "", fmt.Errorf("secret %s has no PEM certificate in %s", secret.Name, corev1.TLSCertKey)
+	}
+	if len(secret.Data[corev1.TLSPrivateKeyKey]) == 0 {
+		return nil, "", fmt.Errorf("secret %s has no private key in %s", secret.Name, corev1.TLSPrivateKeyKey)
+	}
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, "", fmt.Errorf("secret %s: %w", secret.Name, err)
+	}
+	sum := sha256.Sum256(block.Bytes)
+	return certificate, hex.EncodeToString(sum[:]), nil
+}
+
+func certificateSubject(certificate *x509.Certificate) string {
+	if certificate.Subject.CommonName != "" || len(certificate.DNSNames) == 0 {
+		return certificate.Subject.CommonName
+	}
+	return certificate.DNSNames[0]
+}
+
+func findCertificateStatus(certificates []marklogicv1.CertificateStatus, secretName string) *marklogicv1.CertificateStatus {
+	for i := range certificates {
+		if certificates[i].SecretName == secretName {
+			return &certificates[i]
+		}
+	}
+	return nil
+}
+
+// ReconcileCertificateRotation keeps the host certificates in MarkLogic in line with the
+// TLS secrets of the group. cluster-config.sh installs the certificates when a host is
+// initialized; a certificate replaced in a secret afterwards is pushed to the certificate
+// template through the Management API, so the App Servers switch to it without a restart.
+// The serial number and expiry of each certificate are recorded in status.
+func (oc *OperatorContext) ReconcileCertificateRotation() result.ReconcileResult {
+	cr := oc.MarklogicGroup
+	logger := oc.ReqLogger
+	secretNames := certificateSecretNames(cr)
+	original := cr.Status.DeepCopy()
+	patchClient := client.MergeFrom(cr.DeepCopy())
+	now := metav1.Now()
+
+	if len(secretNames) == 0 {
+		cr.Status.Certificates = nil
+		apimeta.RemoveStatusCondition(&cr.Status.Conditions, string(marklogicv1.GroupCertificateExpiring))
+		return oc.patchCertificateStatus(patchClient, original, result.Continue())
+	}
+
+	certificates := []marklogicv1.CertificateStatus{}
+	rotated := []mlmanage.HostCertificate{}
+	rotatedSecrets := []string{}
+	for _, name := range secretNames {
+		previous := findCertificateStatus(original.Certificates, name)
+		secret := &corev1.Secret{}
+		if err := oc.Client.Get(oc.Ctx, types.NamespacedName{Name: name, Namespace: cr.Namespace}, secret); err != nil {
+			if !apierrors.IsNotFound(err) {
+				logger.Error(err, "Failed to get TLS secret", "secret", name)
+				return result.Error(err)
+			}
+			// cert-manager may not have issued the secret yet; keep what was installed last.
+			if previous != nil {
+				certificates = append(certificates, *previous)
+			}
+			continue
+		}
+		certificate, fingerprint, err := parseCertificateSecret(secret)
+		if err != nil {
+			logger.Error(err, "Skipping invalid TLS secret", "secret", name)
+			oc.Recorder.Event(cr, "Warning", "InvalidCertificate", err.Error())
+			if previous != nil {
+				certificates = append(certificates, *previous)
+			}
+			continue
+		}
+		notAfter := metav1.NewTime(certificate.NotAfter)
+		current := marklogicv1.CertificateStatus{
+			SecretName:   name,
+			Subject:      certificateSubject(certificate),
+			SerialNumber: certificate.SerialNumber.Text(16),
+			NotAfter:     &notAfter,
+			Fingerprint:  fingerprint,
+		}
+		// The first certificate seen was installed by cluster-config.sh at host initialization.
+		if previous != nil {
+			current.LastRotationTime = previous.LastRotationTime
+			if previous.Fingerprint != fingerprint {
+				current.LastRotationTime = &now
+				rotated = append(rotated, mlmanage.HostCertificate{
+					Cert:       string(secret.Data[corev1.TLSCertKey]),
+					PrivateKey: string(secret.Data[corev1.TLSPrivateKeyKey]),
+				})
+				rotatedSecrets = append(rotatedSecrets, name)
+			}
+		}
+		certificates = append(certificates, current)
+	}
+
+	if len(rotated) > 0 {
+		if cr.Status.ReadyReplicas == 0 {
+			// The StatefulSet becoming ready reconciles the group again.
+			logger.Info("Waiting for a ready host before installing the new certificates", "secrets", rotatedSecrets)
+			return result.Continue()
+		}
+		mgmt, err := oc.groupManagementClient()
+		if err != nil {
+			logger.Error(err, "Failed to create Management API client for certificate rotation")
+			return result.Error(err)
+		}
+		logger.Info("TLS secrets changed, installing the new host certificates", "secrets", rotatedSecrets)
+		if err := mgmt.InsertHostCertificates(oc.Ctx, mlmanage.DefaultCertificateTemplate, rotated); err != nil {
+			logger.Error(err, "Failed to install the new host certificates")
+			oc.Recorder.Eventf(cr, "Warning", "CertificateRotationFailed", "Failed to install certificates from %s: %v", strings.Join(rotatedSecrets, ", "), err)
+			return result.Error(err)
+		}
+		oc.Recorder.Eventf(cr, "Normal", "CertificateRotated", "Installed new host certificates from %s", strings.Join(rotatedSecrets, ", "))
+	}
+	cr.Status.Certificates = certificates
+	oc.setCertificateExpiryCondition(now)
+	return oc.patchCertificateStatus(patchClient, original, result.Continue())
+}
+
+// setCertificateExpiryCondition warns once when the first certificate of the group enters
+// the expiry warning window.
+func (oc *OperatorContext) setCertificateExpiryCondition(now metav1.Time) {
+	cr := oc.MarklogicGroup
+	warningDays := cr.Spec.Tls.ExpiryWarningDays
+	if warningDays <= 0 {
+		warningDays = defaultCertificateExpiryWarningDays
+	}
+	var expiring *marklogicv1.CertificateStatus
+	for i := range cr.Status.Certificates {
+		certificate := &cr.Status.Certificates[i]
+		if certificate.NotAfter == nil || certificate.NotAfter.After(now.Add(time.Duration(warningDays)*24*time.Hour)) {
+			continue
+		}
+		if expiring == nil || certificate.NotAfter.Before(expiring.NotAfter) {
+			expiring = certificate
+		}
+	}
+	condition := metav1.Condition{
+		Type:               string(marklogicv1.GroupCertificateExpiring),
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: cr.Generation,
+		Reason:             "CertificatesValid",
+		Message:            fmt.Sprintf("No host certificate expires within %d days", warningDays),
+		LastTransitionTime: now,
+	}
+	if expiring != nil {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "CertificateExpiring"
+		condition.Message = fmt.Sprintf("Certificate %s (serial %s) from secret %s expires at %s",
+			expiring.Subject, expiring.SerialNumber, expiring.SecretName, expiring.NotAfter.UTC().Format(time.RFC3339))
+		if !apimeta.IsStatusConditionTrue(cr.Status.Conditions, condition.Type) {
+			oc.Recorder.Event(cr, "Warning", "CertificateExpiring", condition.Message)
+		}
+	}
+	apimeta.SetStatusCondition(&cr.Status.Conditions, condition)
+}
+
+func (oc *OperatorContext) patchCertificateStatus(patchClient client.Patch, original *marklogicv1.MarklogicGroupStatus, res result.ReconcileResult) result.ReconcileResult {
+	if reflect.DeepEqual(*original, oc.MarklogicGroup.Status) {
+		return res
+	}
+	if err := oc.Client.Status().Patch(oc.Ctx, oc.MarklogicGroup, patchClient); err != nil {
+		oc.ReqLogger.Error(err, "Failed to update MarkLogicGroup certificate status")
+		return result.Error(err)
+	}
+	return res
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func testCertificateSecretData(t *testing.T, serial int64, validFor time.Duration) map[string][]byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "enode-0.enode.default.svc.cluster.local"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(validFor),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+	return map[string][]byte{
+		corev1.TLSCertKey:       pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		corev1.TLSPrivateKeyKey: pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+func TestReconcileCertificateRotationPushesReplacedCertificate(t *testing.T) {
+	stub := &stubSecurityClient{}
+	useStubClusterManagementClient(t, managementStub{SecurityClient: stub})
+	oc := newAutoscalingTestContext(t, 1, nil)
+	recorder := record.NewFakeRecorder(10)
+	oc.Recorder = recorder
+	oc.MarklogicGroup.Spec.Tls = &marklogicv1.Tls{EnableOnDefaultAppServers: true, CertSecretNames: []string{"enode-0-cert"}, ExpiryWarningDays: 30}
+	if err := oc.Client.Update(oc.Ctx, oc.MarklogicGroup); err != nil {
+		t.Fatalf("failed to update group: %v", err)
+	}
+	oc.MarklogicGroup.Status.ReadyReplicas = 1
+	if err := oc.Client.Status().Update(oc.Ctx, oc.MarklogicGroup); err != nil {
+		t.Fatalf("failed to update group status: %v", err)
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "enode-0-cert", Namespace: "default"},
+		Type:       corev1.SecretTypeTLS,
+		Data:       testCertificateSecretData(t, 1, 365*24*time.Hour),
+	}
+	if err := oc.Client.Create(oc.Ctx, secret); err != nil {
+		t.Fatalf("failed to create secret: %v", err)
+	}
+
+	if res := oc.ReconcileCertificateRotation(); res.Completed() {
+		t.Fatalf("expected reconcile to continue, got %+v", res)
+	}
+	if len(stub.insertedCertificates) != 0 {
+		t.Fatalf("expected the certificate installed at initialization not to be pushed again, got %v", stub.insertedCertificates)
+	}
+	status := oc.MarklogicGroup.Status.Certificates
+	if len(status) != 1 || status[0].SerialNumber != "1" || status[0].NotAfter == nil || status[0].LastRotationTime != nil {
+		t.Fatalf("unexpected certificate status: %+v", status)
+	}
+
+	secret.Data = testCertificateSecretData(t, 2, 10*24*time.Hour)
+	if err := oc.Client.Update(oc.Ctx, secret); err != nil {
+		t.Fatalf("failed to update secret: %v", err)
+	}
+	if res := oc.ReconcileCertificateRotation(); res.Completed() {
+		t.Fatalf("expected reconcile to continue, got %+v", res)
+	}
+	pushed := stub.insertedCertificates[mlmanage.DefaultCertificateTemplate]
+	if len(pushed) != 1 || pushed[0].Cert != string(secret.Data[corev1.TLSCertKey]) {
+		t.Fatalf("expected the new certificate to be pushed to the default template, got %v", stub.insertedCertificates)
+	}
+	status = oc.MarklogicGroup.Status.Certificates
+	if status[0].SerialNumber != "2" || status[0].LastRotationTime == nil {
+		t.Fatalf("expected the rotated certificate in status, got %+v", status)
+	}
+	if !apimeta.IsStatusConditionTrue(oc.MarklogicGroup.Status.Conditions, string(marklogicv1.GroupCertificateExpiring)) {
+		t.Fatalf("expected the CertificateExpiring condition, got %+v", oc.MarklogicGroup.Status.Conditions)
+	}
+	events := []string{}
+	for len(recorder.Events) > 0 {
+		events = append(events, <-recorder.Events)
+	}
+	joined := strings.Join(events, "\n")
+	if !strings.Contains(joined, "CertificateRotated") || !strings.Contains(joined, "Warning CertificateExpiring") {
+		t.Fatalf("expected rotation and expiry events, got %v", events)
+	}
+
+	if res := oc.ReconcileCertificateRotation(); res.Completed() {
+		t.Fatalf("expected reconcile to continue, got %+v", res)
+	}
+	if len(stub.insertedCertificates[mlmanage.DefaultCertificateTemplate]) != 1 || len(recorder.Events) != 0 {
+		t.Fatalf("expected an unchanged secret to neither push nor warn again")
+	}
+}
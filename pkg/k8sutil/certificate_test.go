@@ -0,0 +1,122 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"context"
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileCertificatesRequestsHostCertificate(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := marklogicv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add marklogic scheme: %v", err)
+	}
+	group := &marklogicv1.MarklogicGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "dnode", Namespace: "ml", UID: "dnode-uid"},
+		Spec: marklogicv1.MarklogicGroupSpec{
+			Name:          "dnode",
+			Replicas:      int32Ptr(2),
+			ClusterDomain: "cluster.local",
+			Tls: &marklogicv1.Tls{
+				EnableOnDefaultAppServers: true,
+				IssuerRef:                 &marklogicv1.CertificateIssuerRef{Name: "ml-ca", Kind: "ClusterIssuer", Group: "cert-manager.io"},
+			},
+		},
+	}
+	oc := &OperatorContext{
+		Ctx:            context.Background(),
+		Client:         fake.NewClientBuilder().WithScheme(scheme).WithObjects(group).Build(),
+		Scheme:         scheme,
+		MarklogicGroup: group,
+		Recorder:       record.NewFakeRecorder(10),
+	}
+	if res := oc.ReconcileCertificates(); res.Completed() {
+		t.Fatalf("expected reconcile to continue, got %+v", res)
+	}
+
+	certificate := &unstructured.Unstructured{}
+	certificate.SetGroupVersionKind(certificateGVK)
+	if err := oc.Client.Get(oc.Ctx, types.NamespacedName{Name: "dnode-tls", Namespace: "ml"}, certificate); err != nil {
+		t.Fatalf("expected Certificate: %v", err)
+	}
+	dnsNames, _, _ := unstructured.NestedStringSlice(certificate.Object, "spec", "dnsNames")
+	expected := []string{
+		"dnode-0.dnode.ml.svc.cluster.local",
+		"dnode-1.dnode.ml.svc.cluster.local",
+		"dnode.ml.svc.cluster.local",
+		"dnode-cluster",
+		"dnode-cluster.ml",
+		"dnode-cluster.ml.svc",
+		"dnode-cluster.ml.svc.cluster.local",
+	}
+	if len(dnsNames) != len(expected) {
+		t.Fatalf("expected SANs %v, got %v", expected, dnsNames)
+	}
+	for i := range expected {
+		if dnsNames[i] != expected[i] {
+			t.Fatalf("expected SANs %v, got %v", expected, dnsNames)
+		}
+	}
+	if kind, _, _ := unstructured.NestedString(certificate.Object, "spec", "issuerRef", "kind"); kind != "ClusterIssuer" {
+		t.Fatalf("expected the issuer kind from the spec, got %q", kind)
+	}
+
+	oc.MarklogicGroup.Spec.Tls.IssuerRef = nil
+	if res := oc.ReconcileCertificates(); res.Completed() {
+		t.Fatalf("expected reconcile to continue, got %+v", res)
+	}
+	if err := oc.Client.Get(oc.Ctx, types.NamespacedName{Name: "dnode-tls", Namespace: "ml"}, certificate); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected the Certificate to be removed with issuerRef, got %v", err)
+	}
+}
+
+func TestIssuedCertificatesAreMounted(t *testing.T) {
+	params := containerParameters{
+		Name: "dnode",
+		Tls: &marklogicv1.Tls{
+			EnableOnDefaultAppServers: true,
+			IssuerRef:                 &marklogicv1.CertificateIssuerRef{Name: "ml-ca"},
+		},
+	}
+	volumes := map[string]corev1.Volume{}
+	for _, v := range generateVolumes("dnode", params) {
+		volumes[v.Name] = v
+	}
+	ca, server := volumes["ca-cert-secret"], volumes["server-cert-secrets"]
+	if ca.Secret == nil || ca.Secret.SecretName != "dnode-tls" || ca.Secret.Items[0].Path != "cacert.pem" {
+		t.Fatalf("expected ca.crt of the issued secret as cacert.pem, got %+v", ca)
+	}
+	if server.Secret == nil || server.Secret.SecretName != "dnode-tls" || server.Secret.Items[0].Path != "tls_0.crt" {
+		t.Fatalf("expected the issued certificate in the copy-certs layout, got %+v", server)
+	}
+
+	cluster := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "ml"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			ClusterDomain: "cluster.local",
+			HAProxy: &marklogicv1.HAProxy{
+				Enabled: true,
+				Tls:     &marklogicv1.TlsForHAProxy{Enabled: true, IssuerRef: &marklogicv1.CertificateIssuerRef{Name: "ml-ca"}},
+				Ingress: marklogicv1.Ingress{Enabled: true, Host: "ml.example.com"},
+			},
+		},
+	}
+	dnsNames := haproxyCertificateDNSNames(cluster)
+	if dnsNames[3] != "marklogic-haproxy.ml.svc.cluster.local" || dnsNames[len(dnsNames)-1] != "ml.example.com" {
+		t.Fatalf("expected the HAProxy Service and Ingress host as SANs, got %v", dnsNames)
+	}
+	if got := getSSLConfig(cluster.Spec.HAProxy.Tls); got != "ssl crt /usr/local/etc/ssl/tls.crt" {
+		t.Fatalf("expected HAProxy to load the issued certificate, got %q", got)
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NewClusterManagementClient builds the Management API client used by cluster-level
+// reconcilers. Tests replace it with a stub.
+var NewClusterManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+	return mlmanage.NewClient(opts)
+}
+
+// clusterAdminSecretName mirrors the secret name handed to each MarklogicGroup.
+func clusterAdminSecretName(cr *marklogicv1.MarklogicCluster) string {
+	if cr.Spec.Auth != nil && cr.Spec.Auth.SecretName != nil && *cr.Spec.Auth.SecretName != "" {
+		return *cr.Spec.Auth.SecretName
+	}
+	return fmt.Sprintf("%s-admin", cr.ObjectMeta.Name)
+}
+
+func bootstrapGroupSpec(cr *marklogicv1.MarklogicCluster) *marklogicv1.MarklogicGroups {
+	for _, group := range cr.Spec.MarkLogicGroups {
+		if group != nil && group.IsBootstrap {
+			return group
+		}
+	}
+	return nil
+}
+
+// bootstrapHostFQDN is the first pod of the bootstrap group, the host every other group joins.
+func bootstrapHostFQDN(cr *marklogicv1.MarklogicCluster) string {
+	bootstrap := bootstrapGroupSpec(cr)
+	if bootstrap == nil {
+		return ""
+	}
+	clusterDomain := strings.TrimSpace(cr.Spec.ClusterDomain)
+	if clusterDomain == "" {
+		clusterDomain = "cluster.local"
+	}
+	return fmt.Sprintf("%s-0.%s.%s.svc.%s", bootstrap.Name, bootstrap.Name, cr.Namespace, clusterDomain)
+}
+
+func clusterManagementUsesTLS(cr *marklogicv1.MarklogicCluster) bool {
+	if bootstrap := bootstrapGroupSpec(cr); bootstrap != nil && bootstrap.Tls != nil {
+		return bootstrap.Tls.EnableOnDefaultAppServers
+	}
+	return cr.Spec.Tls != nil && cr.Spec.Tls.EnableOnDefaultAppServers
+}
+
+func (cc *ClusterContext) readClusterCredentialSecret(secretName string) (string, string, error) {
+	return readCredentialSecret(cc.Ctx, cc.Client, cc.MarklogicCluster.Namespace, secretName)
+}
+
+func readCredentialSecret(ctx context.Context, c client.Client, namespace, secretName string) (string, string, error) {
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Name: secretName, Namespace: namespace}, secret); err != nil {
+		return "", "", err
+	}
+	username, hasUser := secret.Data["username"]
+	password, hasPass := secret.Data["password"]
+	if !hasUser || !hasPass {
+		return "", "", fmt.Errorf("secret %s missing username/password", secretName)
+	}
+	return string(username), string(password), nil
+}
+
+// newClusterManagementClient connects to the bootstrap host with the cluster admin credentials.
+func (cc *ClusterContext) newClusterManagementClient() (mlmanage.Client, error) {
+	return managementClientForCluster(cc.Ctx, cc.Client, cc.MarklogicCluster)
+}
+
+// managementClientForCluster is shared by the reconcilers of resources that reference a
+// MarklogicCluster, such as MarklogicBackup and MarklogicRestore.
+func managementClientForCluster(ctx context.Context, c client.Client, cr *marklogicv1.MarklogicCluster) (mlmanage.Client, error) {
+	host := bootstrapHostFQDN(cr)
+	if host == "" {
+		return nil, fmt.Errorf("marklogiccluster %s/%s has no bootstrap group", cr.Namespace, cr.Name)
+	}
+	username, password, err := readAdminCredentials(ctx, c, cr.Namespace, clusterAdminSecretName(cr))
+	if err != nil {
+		return nil, err
+	}
+	useTLS := clusterManagementUsesTLS(cr)
+	return NewClusterManagementClient(mlmanage.ClientOptions{
+		Host:     host,
+		Username: username,
+		Password: password,
+		UseTLS:   useTLS,
+		// Same trust model as the dynamic-host reconciler: operator-managed or
+		// self-signed certificates are not verified until a CA bundle is wired in.
+		InsecureSkipVerify: useTLS,
+	}), nil
+}
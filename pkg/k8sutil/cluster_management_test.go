@@ -0,0 +1,31 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"testing"
+
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+)
+
+// managementStub assembles a Management API client from the stubs of the features a test
+// exercises. Calls into a feature without a stub panic.
+type managementStub struct {
+	mlmanage.HostClient
+	mlmanage.GroupClient
+	mlmanage.AdminClient
+	mlmanage.SecurityClient
+	mlmanage.MetricsClient
+	mlmanage.DatabaseClient
+	mlmanage.ForestClient
+	mlmanage.BackupClient
+	mlmanage.AppServerClient
+	mlmanage.ReplicationClient
+}
+
+func useStubClusterManagementClient(t *testing.T, stub managementStub) {
+	t.Helper()
+	originalFactory := NewClusterManagementClient
+	NewClusterManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client { return stub }
+	t.Cleanup(func() { NewClusterManagementClient = originalFactory })
+}
@@ -0,0 +1,247 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	clusterReasonAllGroupsReady    = "AllGroupsReady"
+	clusterReasonGroupsNotReady    = "GroupsNotReady"
+	clusterReasonGroupsProgressing = "GroupsProgressing"
+	clusterReasonReconciled        = "Reconciled"
+	clusterReasonGroupsDegraded    = "GroupsDegraded"
+	clusterReasonNoDegradation     = "AsExpected"
+	clusterReasonDecommissioning   = "HostsDecommissioning"
+	clusterReasonNoDecommission    = "NoDecommission"
+
+	mixedMarkLogicVersion = "mixed"
+)
+
+var markLogicVersionPattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+)*`)
+
+// ReconcileClusterStatus rolls the status of the owned MarklogicGroups up into the
+// MarklogicCluster status and derives the Ready/Progressing/Degraded conditions.
+func (cc *ClusterContext) ReconcileClusterStatus() result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	logger := cc.ReqLogger
+
+	groups := make([]*marklogicv1.MarklogicGroup, 0, len(cr.Spec.MarkLogicGroups))
+	rollups := make([]marklogicv1.MarklogicGroupRollup, 0, len(cr.Spec.MarkLogicGroups))
+	for _, spec := range cr.Spec.MarkLogicGroups {
+		if spec == nil {
+			continue
+		}
+		group := &marklogicv1.MarklogicGroup{}
+		err := cc.Client.Get(cc.Ctx, types.NamespacedName{Name: spec.Name, Namespace: cr.Namespace}, group)
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				logger.Error(err, "Failed to get MarkLogicGroup for cluster status", "group", spec.Name)
+				return result.Error(err)
+			}
+			group = nil
+		}
+		groups = append(groups, group)
+		rollup := SummarizeMarklogicGroup(group)
+		rollup.Name = spec.Name
+		rollup.IsBootstrap = spec.IsBootstrap
+		rollup.IsDynamic = spec.IsDynamic
+		if group == nil && spec.Replicas != nil {
+			rollup.Replicas = *spec.Replicas
+		}
+		rollups = append(rollups, rollup)
+	}
+
+	patchClient := client.MergeFrom(cr.DeepCopy())
+	status := cr.Status.DeepCopy()
+	applyClusterRollup(status, rollups, groups, cr.Generation)
+	if reflect.DeepEqual(*status, cr.Status) {
+		return result.Continue()
+	}
+	cr.Status = *status
+	if err := cc.Client.Status().Patch(cc.Ctx, cr, patchClient); err != nil {
+		logger.Error(err, "Failed to update MarkLogicCluster status")
+		return result.Error(err)
+	}
+	return result.Continue()
+}
+
+// SummarizeMarklogicGroup returns the rollup fields the cluster status tracks for a group.
+// The cluster controller also uses it to decide whether a group update is worth a reconcile.
+func SummarizeMarklogicGroup(group *marklogicv1.MarklogicGroup) marklogicv1.MarklogicGroupRollup {
+	if group == nil {
+		return marklogicv1.MarklogicGroupRollup{}
+	}
+	rollup := marklogicv1.MarklogicGroupRollup{
+		Name:             group.Name,
+		IsDynamic:        group.Spec.IsDynamic,
+		ReadyReplicas:    group.Status.ReadyReplicas,
+		Stage:            group.Status.Stage,
+		MarkLogicVersion: markLogicVersionFromImage(group.Spec.Image),
+	}
+	if group.Spec.Replicas != nil {
+		rollup.Replicas = *group.Spec.Replicas
+	}
+	if group.Status.Dynamic != nil {
+		rollup.DynamicPhase = group.Status.Dynamic.Phase
+	}
+	if resize := group.Status.VolumeResizeStatus; resize != nil && resize.Phase != "" && resize.Phase != marklogicv1.VolumeResizePhaseCompleted {
+		rollup.VolumeResizePhase = resize.Phase
+	}
+	if group.Status.Decommission != nil {
+		rollup.DecommissionPhase = group.Status.Decommission.Phase
+	}
+	rollup.Paused = apimeta.IsStatusConditionTrue(group.Status.Conditions, string(marklogicv1.GroupPaused))
+	return rollup
+}
+
+func applyClusterRollup(status *marklogicv1.MarklogicClusterStatus, rollups []marklogicv1.MarklogicGroupRollup, groups []*marklogicv1.MarklogicGroup, generation int64) {
+	var replicas, readyReplicas int32
+	versions := map[string]struct{}{}
+	var missing, notReady, progressing, degraded, decommissioning []string
+	for i, rollup := range rollups {
+		replicas += rollup.Replicas
+		readyReplicas += rollup.ReadyReplicas
+		if rollup.MarkLogicVersion != "" {
+			versions[rollup.MarkLogicVersion] = struct{}{}
+		}
+		if groups[i] == nil {
+			missing = append(missing, rollup.Name)
+			continue
+		}
+		if rollup.DecommissionPhase != "" {
+			decommissioning = append(decommissioning, fmt.Sprintf("%s: %s", rollup.Name, rollup.DecommissionPhase))
+		}
+		if rollup.ReadyReplicas < rollup.Replicas {
+			notReady = append(notReady, fmt.Sprintf("%s (%d/%d)", rollup.Name, rollup.ReadyReplicas, rollup.Replicas))
+		}
+		if reason := groupDegradedReason(rollup); reason != "" {
+			degraded = append(degraded, fmt.Sprintf("%s: %s", rollup.Name, reason))
+		} else if reason := groupProgressingReason(rollup); reason != "" {
+			progressing = append(progressing, fmt.Sprintf("%s: %s", rollup.Name, reason))
+		}
+	}
+
+	status.ObservedGeneration = generation
+	status.Replicas = replicas
+	status.ReadyReplicas = readyReplicas
+	status.Hosts = fmt.Sprintf("%d/%d", readyReplicas, replicas)
+	status.Groups = rollups
+	status.MarkLogicVersion = ""
+	if len(versions) == 1 {
+		for version := range versions {
+			status.MarkLogicVersion = version
+		}
+	} else if len(versions) > 1 {
+		status.MarkLogicVersion = mixedMarkLogicVersion
+	}
+
+	for _, name := range missing {
+		progressing = append(progressing, fmt.Sprintf("%s: not created yet", name))
+	}
+	for _, entry := range notReady {
+		progressing = append(progressing, fmt.Sprintf("%s ready", entry))
+	}
+	if status.Upgrade != nil && status.Upgrade.Phase == marklogicv1.UpgradePhaseInProgress {
+		progressing = append(progressing, fmt.Sprintf("upgrade %s", status.Upgrade.Message))
+	}
+	sort.Strings(progressing)
+
+	if len(degraded) > 0 {
+		setClusterCondition(status, generation, marklogicv1.ClusterDegraded, metav1.ConditionTrue, clusterReasonGroupsDegraded, strings.Join(degraded, "; "))
+	} else {
+		setClusterCondition(status, generation, marklogicv1.ClusterDegraded, metav1.ConditionFalse, clusterReasonNoDegradation, "no MarkLogic group reports a failure")
+	}
+	if len(progressing) > 0 {
+		setClusterCondition(status, generation, marklogicv1.ClusterProgressing, metav1.ConditionTrue, clusterReasonGroupsProgressing, strings.Join(progressing, "; "))
+	} else {
+		setClusterCondition(status, generation, marklogicv1.ClusterProgressing, metav1.ConditionFalse, clusterReasonReconciled, "all MarkLogic groups are reconciled")
+	}
+	if len(decommissioning) > 0 {
+		setClusterCondition(status, generation, marklogicv1.ClusterDecommission, metav1.ConditionTrue, clusterReasonDecommissioning, strings.Join(decommissioning, "; "))
+	} else {
+		setClusterCondition(status, generation, marklogicv1.ClusterDecommission, metav1.ConditionFalse, clusterReasonNoDecommission, "no MarkLogic host is being decommissioned")
+	}
+	switch {
+	case len(degraded) > 0:
+		setClusterCondition(status, generation, marklogicv1.ClusterReady, metav1.ConditionFalse, clusterReasonGroupsDegraded, strings.Join(degraded, "; "))
+	case len(missing) > 0 || len(notReady) > 0:
+		setClusterCondition(status, generation, marklogicv1.ClusterReady, metav1.ConditionFalse, clusterReasonGroupsNotReady, fmt.Sprintf("%d/%d MarkLogic hosts ready", readyReplicas, replicas))
+	default:
+		setClusterCondition(status, generation, marklogicv1.ClusterReady, metav1.ConditionTrue, clusterReasonAllGroupsReady, fmt.Sprintf("%d/%d MarkLogic hosts ready", readyReplicas, replicas))
+	}
+}
+
+func groupDegradedReason(rollup marklogicv1.MarklogicGroupRollup) string {
+	switch rollup.VolumeResizePhase {
+	case marklogicv1.VolumeResizePhaseFailed, marklogicv1.VolumeResizePhaseStalled:
+		return fmt.Sprintf("volume resize %s", rollup.VolumeResizePhase)
+	}
+	if rollup.DecommissionPhase == marklogicv1.DecommissionPhaseBlocked {
+		return "scale-down blocked"
+	}
+	switch rollup.DynamicPhase {
+	case dynamicPhaseFailed, dynamicPhaseDegraded:
+		return fmt.Sprintf("dynamic hosts %s", rollup.DynamicPhase)
+	}
+	return ""
+}
+
+func groupProgressingReason(rollup marklogicv1.MarklogicGroupRollup) string {
+	if rollup.VolumeResizePhase != "" {
+		return fmt.Sprintf("volume resize %s", rollup.VolumeResizePhase)
+	}
+	if rollup.DecommissionPhase != "" {
+		return fmt.Sprintf("decommission %s", rollup.DecommissionPhase)
+	}
+	switch rollup.DynamicPhase {
+	case dynamicPhasePending, dynamicPhaseReconciling, dynamicPhaseDeleting:
+		return fmt.Sprintf("dynamic hosts %s", rollup.DynamicPhase)
+	}
+	return ""
+}
+
+func setClusterCondition(status *marklogicv1.MarklogicClusterStatus, generation int64, conditionType marklogicv1.MarkLogicConditionType, conditionStatus metav1.ConditionStatus, reason, message string) {
+	apimeta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               string(conditionType),
+		Status:             conditionStatus,
+		ObservedGeneration: generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// markLogicVersionFromImage extracts the MarkLogic version from an image tag such as
+// "progressofficial/marklogic-db:12.0.3-ubi9-rootless-2.2.6". Tags that do not start with
+// a version (for example "latest") are returned as-is; digests yield an empty string.
+func markLogicVersionFromImage(image string) string {
+	if idx := strings.Index(image, "@"); idx >= 0 {
+		image = image[:idx]
+	}
+	name := image
+	if idx := strings.LastIndex(name, "/"); idx >= 0 {
+		name = name[idx+1:]
+	}
+	idx := strings.LastIndex(name, ":")
+	if idx < 0 {
+		return ""
+	}
+	tag := name[idx+1:]
+	if version := markLogicVersionPattern.FindString(tag); version != "" {
+		return version
+	}
+	return tag
+}
@@ -0,0 +1,167 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"context"
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileClusterStatusRollsUpGroups(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	if err := marklogicv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add marklogic scheme: %v", err)
+	}
+
+	cluster := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default", Generation: 3},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{
+				{Name: "dnode", IsBootstrap: true, Replicas: int32Ptr(3)},
+				{Name: "dynamic", IsDynamic: true, Replicas: int32Ptr(2)},
+				{Name: "enode", Replicas: int32Ptr(1)},
+			},
+		},
+	}
+	dnode := &marklogicv1.MarklogicGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "dnode", Namespace: "default"},
+		Spec: marklogicv1.MarklogicGroupSpec{
+			Replicas: int32Ptr(3),
+			Image:    "progressofficial/marklogic-db:12.0.3-ubi9-rootless-2.2.6",
+		},
+		Status: marklogicv1.MarklogicGroupStatus{
+			ReadyReplicas: 3,
+			Stage:         "STS_CREATED",
+			VolumeResizeStatus: &marklogicv1.VolumeResizeStatus{
+				Phase: marklogicv1.VolumeResizePhaseCompleted,
+			},
+		},
+	}
+	dynamic := &marklogicv1.MarklogicGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "dynamic", Namespace: "default"},
+		Spec: marklogicv1.MarklogicGroupSpec{
+			Replicas:  int32Ptr(2),
+			IsDynamic: true,
+			Image:     "progressofficial/marklogic-db:12.0.3-ubi9-rootless-2.2.6",
+		},
+		Status: marklogicv1.MarklogicGroupStatus{
+			ReadyReplicas: 1,
+			Dynamic:       &marklogicv1.DynamicGroupStatus{Phase: dynamicPhaseReconciling},
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithStatusSubresource(&marklogicv1.MarklogicCluster{}, &marklogicv1.MarklogicGroup{}).
+		WithObjects(cluster, dnode, dynamic).
+		Build()
+
+	cc := &ClusterContext{
+		Ctx:              context.Background(),
+		Client:           fakeClient,
+		Scheme:           scheme,
+		MarklogicCluster: cluster,
+		Recorder:         record.NewFakeRecorder(10),
+	}
+
+	if res := cc.ReconcileClusterStatus(); res.Completed() {
+		_, err := res.Output()
+		t.Fatalf("expected status reconcile to continue, got err=%v", err)
+	}
+
+	current := &marklogicv1.MarklogicCluster{}
+	if err := fakeClient.Get(context.Background(), types.NamespacedName{Name: "ml", Namespace: "default"}, current); err != nil {
+		t.Fatalf("failed to fetch MarklogicCluster: %v", err)
+	}
+	status := current.Status
+	if status.Hosts != "4/6" || status.ReadyReplicas != 4 || status.Replicas != 6 {
+		t.Fatalf("unexpected host rollup: hosts=%q ready=%d desired=%d", status.Hosts, status.ReadyReplicas, status.Replicas)
+	}
+	if status.MarkLogicVersion != "12.0.3" {
+		t.Fatalf("expected version 12.0.3, got %q", status.MarkLogicVersion)
+	}
+	if len(status.Groups) != 3 {
+		t.Fatalf("expected 3 group rollups, got %d", len(status.Groups))
+	}
+	if status.Groups[0].VolumeResizePhase != "" {
+		t.Fatalf("expected completed resize to be omitted, got %q", status.Groups[0].VolumeResizePhase)
+	}
+	if status.Groups[1].DynamicPhase != dynamicPhaseReconciling || !status.Groups[1].IsDynamic {
+		t.Fatalf("unexpected dynamic rollup: %+v", status.Groups[1])
+	}
+	if !apimeta.IsStatusConditionFalse(status.Conditions, string(marklogicv1.ClusterReady)) {
+		t.Fatalf("expected Ready=False, got %+v", status.Conditions)
+	}
+	if !apimeta.IsStatusConditionTrue(status.Conditions, string(marklogicv1.ClusterProgressing)) {
+		t.Fatalf("expected Progressing=True, got %+v", status.Conditions)
+	}
+	if !apimeta.IsStatusConditionFalse(status.Conditions, string(marklogicv1.ClusterDegraded)) {
+		t.Fatalf("expected Degraded=False, got %+v", status.Conditions)
+	}
+}
+
+func TestApplyClusterRollupConditions(t *testing.T) {
+	t.Parallel()
+
+	readyGroup := &marklogicv1.MarklogicGroup{}
+	t.Run("all groups ready", func(t *testing.T) {
+		status := &marklogicv1.MarklogicClusterStatus{}
+		rollups := []marklogicv1.MarklogicGroupRollup{{Name: "dnode", Replicas: 2, ReadyReplicas: 2, MarkLogicVersion: "12.0.3"}}
+		applyClusterRollup(status, rollups, []*marklogicv1.MarklogicGroup{readyGroup}, 1)
+		if !apimeta.IsStatusConditionTrue(status.Conditions, string(marklogicv1.ClusterReady)) {
+			t.Fatalf("expected Ready=True, got %+v", status.Conditions)
+		}
+		if !apimeta.IsStatusConditionFalse(status.Conditions, string(marklogicv1.ClusterProgressing)) {
+			t.Fatalf("expected Progressing=False, got %+v", status.Conditions)
+		}
+	})
+
+	t.Run("failed resize degrades the cluster", func(t *testing.T) {
+		status := &marklogicv1.MarklogicClusterStatus{}
+		rollups := []marklogicv1.MarklogicGroupRollup{
+			{Name: "dnode", Replicas: 2, ReadyReplicas: 2, MarkLogicVersion: "12.0.3", VolumeResizePhase: marklogicv1.VolumeResizePhaseFailed},
+			{Name: "enode", Replicas: 1, ReadyReplicas: 1, MarkLogicVersion: "11.3.1"},
+		}
+		applyClusterRollup(status, rollups, []*marklogicv1.MarklogicGroup{readyGroup, readyGroup}, 1)
+		if !apimeta.IsStatusConditionTrue(status.Conditions, string(marklogicv1.ClusterDegraded)) {
+			t.Fatalf("expected Degraded=True, got %+v", status.Conditions)
+		}
+		if !apimeta.IsStatusConditionFalse(status.Conditions, string(marklogicv1.ClusterReady)) {
+			t.Fatalf("expected Ready=False, got %+v", status.Conditions)
+		}
+		if status.MarkLogicVersion != mixedMarkLogicVersion {
+			t.Fatalf("expected mixed version, got %q", status.MarkLogicVersion)
+		}
+	})
+}
+
+func TestMarkLogicVersionFromImage(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"progressofficial/marklogic-db:12.0.3-ubi9-rootless-2.2.6": "12.0.3",
+		"registry.local:5000/marklogic-db:11.3.1":                  "11.3.1",
+		"progressofficial/marklogic-db:latest":                     "latest",
+		"registry.local:5000/marklogic-db":                         "",
+		"progressofficial/marklogic-db@sha256:abcdef":              "",
+	}
+	for image, expected := range cases {
+		if got := markLogicVersionFromImage(image); got != expected {
+			t.Errorf("markLogicVersionFromImage(%q) = %q, expected %q", image, got, expected)
+		}
+	}
+}
+
+func int32Ptr(v int32) *int32 {
+	return &v
+}
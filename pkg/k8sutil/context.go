@@ -45,6 +45,46 @@ type ClusterContext struct {
 	StatefulSets []*appsv1.StatefulSet
 }
 
+type BackupContext struct {
+	Ctx             context.Context
+	Request         *reconcile.Request
+	Client          controllerClient.Client
+	Scheme          *runtime.Scheme
+	MarklogicBackup *marklogicv1.MarklogicBackup
+	ReqLogger       logr.Logger
+	Recorder        record.EventRecorder
+}
+
+type RestoreContext struct {
+	Ctx              context.Context
+	Request          *reconcile.Request
+	Client           controllerClient.Client
+	Scheme           *runtime.Scheme
+	MarklogicRestore *marklogicv1.MarklogicRestore
+	ReqLogger        logr.Logger
+	Recorder         record.EventRecorder
+}
+
+type DatabaseContext struct {
+	Ctx               context.Context
+	Request           *reconcile.Request
+	Client            controllerClient.Client
+	Scheme            *runtime.Scheme
+	MarklogicDatabase *marklogicv1.MarklogicDatabase
+	ReqLogger         logr.Logger
+	Recorder          record.EventRecorder
+}
+
+type ReplicationContext struct {
+	Ctx                  context.Context
+	Request              *reconcile.Request
+	Client               controllerClient.Client
+	Scheme               *runtime.Scheme
+	MarklogicReplication *marklogicv1.MarklogicReplication
+	ReqLogger            logr.Logger
+	Recorder             record.EventRecorder
+}
+
 func CreateOperatorContext(
 	ctx context.Context,
 	request *reconcile.Request,
@@ -112,6 +152,56 @@ func CreateClusterContext(
 	return cc, nil
 }
 
+func CreateBackupContext(
+	ctx context.Context,
+	request *reconcile.Request,
+	client controllerClient.Client,
+	scheme *runtime.Scheme,
+	rec record.EventRecorder) (*BackupContext, error) {
+
+	bc := &BackupContext{
+		Ctx:       ctx,
+		Request:   request,
+		Client:    client,
+		Scheme:    scheme,
+		ReqLogger: log.FromContext(ctx),
+		Recorder:  rec,
+	}
+	mlb := &marklogicv1.MarklogicBackup{}
+	if err := client.Get(ctx, request.NamespacedName, mlb); err != nil {
+		bc.ReqLogger.Error(err, "Failed to retrieve MarklogicBackup")
+		return nil, err
+	}
+	bc.MarklogicBackup = mlb
+	bc.ReqLogger = bc.ReqLogger.WithValues("backup", mlb.Name)
+	return bc, nil
+}
+
+func CreateRestoreContext(
+	ctx context.Context,
+	request *reconcile.Request,
+	client controllerClient.Client,
+	scheme *runtime.Scheme,
+	rec record.EventRecorder) (*RestoreContext, error) {
+
+	rc := &RestoreContext{
+		Ctx:       ctx,
+		Request:   request,
+		Client:    client,
+		Scheme:    scheme,
+		ReqLogger: log.FromContext(ctx),
+		Recorder:  rec,
+	}
+	mlr := &marklogicv1.MarklogicRestore{}
+	if err := client.Get(ctx, request.NamespacedName, mlr); err != nil {
+		rc.ReqLogger.Error(err, "Failed to retrieve MarklogicRestore")
+		return nil, err
+	}
+	rc.MarklogicRestore = mlr
+	rc.ReqLogger = rc.ReqLogger.WithValues("restore", mlr.Name)
+	return rc, nil
+}
+
 func retrieveMarkLogicGroup(oc *OperatorContext, request *reconcile.Request, mlg *marklogicv1.MarklogicGroup) error {
 	err := oc.Client.Get(oc.Ctx, request.NamespacedName, mlg)
 	return err
@@ -223,3 +313,53 @@ func (oc *OperatorContext) SetOperatorAnnotations(annotations map[string]string)
 	delete(annotations, "e2e.marklogic.progress.com/reconcile-kick")
 	oc.Annotations = annotations
 }
+
+func CreateDatabaseContext(
+	ctx context.Context,
+	request *reconcile.Request,
+	client controllerClient.Client,
+	scheme *runtime.Scheme,
+	rec record.EventRecorder) (*DatabaseContext, error) {
+
+	dc := &DatabaseContext{
+		Ctx:       ctx,
+		Request:   request,
+		Client:    client,
+		Scheme:    scheme,
+		ReqLogger: log.FromContext(ctx),
+		Recorder:  rec,
+	}
+	mldb := &marklogicv1.MarklogicDatabase{}
+	if err := client.Get(ctx, request.NamespacedName, mldb); err != nil {
+		dc.ReqLogger.Error(err, "Failed to retrieve MarklogicDatabase")
+		return nil, err
+	}
+	dc.MarklogicDatabase = mldb
+	dc.ReqLogger = dc.ReqLogger.WithValues("database", mldb.Name)
+	return dc, nil
+}
+
+func CreateReplicationContext(
+	ctx context.Context,
+	request *reconcile.Request,
+	client controllerClient.Client,
+	scheme *runtime.Scheme,
+	rec record.EventRecorder) (*ReplicationContext, error) {
+
+	rc := &ReplicationContext{
+		Ctx:       ctx,
+		Request:   request,
+		Client:    client,
+		Scheme:    scheme,
+		ReqLogger: log.FromContext(ctx),
+		Recorder:  rec,
+	}
+	replication := &marklogicv1.MarklogicReplication{}
+	if err := client.Get(ctx, request.NamespacedName, replication); err != nil {
+		rc.ReqLogger.Error(err, "Failed to retrieve MarklogicReplication")
+		return nil, err
+	}
+	rc.MarklogicReplication = replication
+	rc.ReqLogger = rc.ReqLogger.WithValues("replication", replication.Name)
+	return rc, nil
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCronScheduleNext(t *testing.T) {
+	t.Parallel()
+
+	from := time.Date(2026, 1, 10, 12, 7, 30, 0, time.UTC) // a Saturday
+	cases := []struct {
+		expr     string
+		expected time.Time
+	}{
+		{"*/15 * * * *", time.Date(2026, 1, 10, 12, 15, 0, 0, time.UTC)},
+		{"0 2 * * *", time.Date(2026, 1, 11, 2, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, 1, 10, 13, 0, 0, 0, time.UTC)},
+		{"30 1 * * 1-5", time.Date(2026, 1, 12, 1, 30, 0, 0, time.UTC)},
+		{"0 0 1 */3 *", time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2026, 1, 11, 0, 0, 0, 0, time.UTC)},
+		// Both day fields restricted: either one matches.
+		{"0 0 15 * 1", time.Date(2026, 1, 12, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tc := range cases {
+		schedule, err := parseCronSchedule(tc.expr)
+		if err != nil {
+			t.Fatalf("parseCronSchedule(%q) returned error: %v", tc.expr, err)
+		}
+		if got := schedule.next(from); !got.Equal(tc.expected) {
+			t.Errorf("next(%q) = %s, expected %s", tc.expr, got, tc.expected)
+		}
+	}
+}
+
+func TestParseCronScheduleRejectsInvalidExpressions(t *testing.T) {
+	t.Parallel()
+
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "a * * * *", "5-1 * * * *"} {
+		if _, err := parseCronSchedule(expr); err == nil {
+			t.Errorf("expected %q to be rejected", expr)
+		}
+	}
+}
+
+func TestCronScheduleNeverFires(t *testing.T) {
+	t.Parallel()
+
+	schedule, err := parseCronSchedule("0 0 31 2 *")
+	if err != nil {
+		t.Fatalf("parseCronSchedule returned error: %v", err)
+	}
+	if got := schedule.next(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)); !got.IsZero() {
+		t.Fatalf("expected no schedule time, got %s", got)
+	}
+}
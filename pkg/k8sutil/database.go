@@ -0,0 +1,574 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	databaseCleanupFinalizer = "marklogic.progress.com/database-cleanup"
+
+	// Server-side drift is only noticed by polling.
+	databaseResyncSeconds = 300
+	databaseRetrySeconds  = 30
+
+	databaseReasonReady              = "Ready"
+	databaseReasonClusterNotFound    = "ClusterNotFound"
+	databaseReasonClusterUnavailable = "ClusterUnavailable"
+	databaseReasonGroupNotFound      = "GroupNotFound"
+	databaseReasonNoHosts            = "NoHosts"
+	databaseReasonNotEnoughHosts     = "NotEnoughHosts"
+	databaseReasonInSync             = "InSync"
+	databaseReasonDriftCorrected     = "DriftCorrected"
+	databaseReasonDrifted            = "Drifted"
+
+	rootCollation = "http://marklogic.com/collation/"
+)
+
+// ReconcileDatabase creates the database described by a MarklogicDatabase, places its
+// forests and replicas on the hosts of the selected groups and keeps its index settings
+// in line with the spec.
+func (dc *DatabaseContext) ReconcileDatabase() result.ReconcileResult {
+	cr := dc.MarklogicDatabase
+	if cr.DeletionTimestamp != nil {
+		return dc.finalizeDatabase()
+	}
+	if err := dc.syncDatabaseFinalizer(); err != nil {
+		return result.Error(err)
+	}
+
+	patchClient := client.MergeFrom(cr.DeepCopy())
+	status := cr.Status.DeepCopy()
+	status.ObservedGeneration = cr.Generation
+
+	requeueSecs, err := dc.syncDatabase(status)
+	if err != nil {
+		return result.Error(err)
+	}
+	if !reflect.DeepEqual(*status, cr.Status) {
+		cr.Status = *status
+		if err := dc.Client.Status().Patch(dc.Ctx, cr, patchClient); err != nil {
+			dc.ReqLogger.Error(err, "Failed to update MarklogicDatabase status")
+			return result.Error(err)
+		}
+	}
+	return result.RequeueSoon(requeueSecs)
+}
+
+// syncDatabase brings the server in line with the spec and returns how long to wait
+// before the next check.
+func (dc *DatabaseContext) syncDatabase(status *marklogicv1.MarklogicDatabaseStatus) (int, error) {
+	cr := dc.MarklogicDatabase
+	name := cr.Spec.DatabaseName
+
+	cluster := &marklogicv1.MarklogicCluster{}
+	if err := dc.Client.Get(dc.Ctx, types.NamespacedName{Name: cr.Spec.ClusterRef.Name, Namespace: cr.Namespace}, cluster); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return 0, err
+		}
+		setDatabaseCondition(status, cr.Generation, marklogicv1.DatabaseReady, metav1.ConditionFalse, databaseReasonClusterNotFound,
+			fmt.Sprintf("MarklogicCluster %s not found", cr.Spec.ClusterRef.Name))
+		return databaseRetrySeconds, nil
+	}
+	mc, err := managementClientForCluster(dc.Ctx, dc.Client, cluster)
+	if err != nil {
+		setDatabaseCondition(status, cr.Generation, marklogicv1.DatabaseReady, metav1.ConditionFalse, databaseReasonClusterUnavailable, err.Error())
+		return databaseRetrySeconds, nil
+	}
+
+	hosts, reason, message, err := dc.forestHosts(cluster, mc)
+	if err != nil {
+		setDatabaseCondition(status, cr.Generation, marklogicv1.DatabaseReady, metav1.ConditionFalse, databaseReasonClusterUnavailable, err.Error())
+		return databaseRetrySeconds, nil
+	}
+	if reason == "" && int(cr.Spec.Replicas) >= len(hosts) {
+		reason = databaseReasonNotEnoughHosts
+		message = fmt.Sprintf("%d replica(s) per forest need at least %d hosts in the selected groups, found %d",
+			cr.Spec.Replicas, cr.Spec.Replicas+1, len(hosts))
+	}
+	if reason != "" {
+		setDatabaseCondition(status, cr.Generation, marklogicv1.DatabaseReady, metav1.ConditionFalse, reason, message)
+		return databaseRetrySeconds, nil
+	}
+	plan := planDatabaseForests(name, hosts, int(cr.Spec.Forests.PerHost), int(cr.Spec.Replicas), status.Forests)
+
+	var corrected, drifted []string
+	desired := databaseProperties(cr.Spec.Indexes)
+	info, err := mc.GetDatabase(dc.Ctx, name)
+	if err == nil && !info.Exists {
+		if err = mc.CreateDatabase(dc.Ctx, name, desired); err == nil {
+			dc.Recorder.Eventf(cr, corev1.EventTypeNormal, "DatabaseCreated", "Created database %s", name)
+		}
+	} else if err == nil {
+		if changed := databasePropertyDrift(desired, info.Properties); len(changed) > 0 {
+			if err = mc.UpdateDatabaseProperties(dc.Ctx, name, desired); err == nil {
+				corrected = append(corrected, "properties "+strings.Join(changed, ", "))
+			}
+		}
+	}
+	if err != nil {
+		setDatabaseCondition(status, cr.Generation, marklogicv1.DatabaseReady, metav1.ConditionFalse, databaseReasonClusterUnavailable, err.Error())
+		return databaseRetrySeconds, nil
+	}
+
+	current := make(map[string]bool, len(hosts))
+	for _, host := range hosts {
+		current[host] = true
+	}
+	planned := map[string]bool{}
+	for i := range plan {
+		planned[plan[i].Name] = true
+		forestCorrected, forestDrifted, err := dc.syncForest(mc, name, &plan[i], current)
+		if err != nil {
+			setDatabaseCondition(status, cr.Generation, marklogicv1.DatabaseReady, metav1.ConditionFalse, databaseReasonClusterUnavailable, err.Error())
+			return databaseRetrySeconds, nil
+		}
+		corrected = append(corrected, forestCorrected...)
+		drifted = append(drifted, forestDrifted...)
+	}
+	var unmanaged []string
+	for _, forest := range info.Forests {
+		if !planned[forest] {
+			unmanaged = append(unmanaged, forest)
+		}
+	}
+	if len(unmanaged) > 0 {
+		sort.Strings(unmanaged)
+		drifted = append(drifted, "forests not managed by this resource: "+strings.Join(unmanaged, ", "))
+	}
+
+	status.Forests = plan
+	setDatabaseCondition(status, cr.Generation, marklogicv1.DatabaseReady, metav1.ConditionTrue, databaseReasonReady,
+		fmt.Sprintf("database %s has %d forest(s) on %d host(s)", name, len(plan), len(hosts)))
+	switch {
+	case len(drifted) > 0:
+		message := strings.Join(append(drifted, corrected...), "; ")
+		setDatabaseCondition(status, cr.Generation, marklogicv1.DatabaseDrifted, metav1.ConditionTrue, databaseReasonDrifted, message)
+		dc.Recorder.Eventf(cr, corev1.EventTypeWarning, databaseReasonDrifted, "Database %s drifted: %s", name, message)
+	case len(corrected) > 0:
+		message := "corrected " + strings.Join(corrected, "; ")
+		setDatabaseCondition(status, cr.Generation, marklogicv1.DatabaseDrifted, metav1.ConditionTrue, databaseReasonDriftCorrected, message)
+		dc.Recorder.Eventf(cr, corev1.EventTypeWarning, databaseReasonDriftCorrected, "Database %s drifted: %s", name, message)
+	default:
+		setDatabaseCondition(status, cr.Generation, marklogicv1.DatabaseDrifted, metav1.ConditionFalse, databaseReasonInSync,
+			"server configuration matches the spec")
+	}
+	return databaseResyncSeconds, nil
+}
+
+// forestHosts returns the sorted MarkLogic host names of the selected groups. A
+// non-empty reason means the forests cannot be placed yet. Dynamic groups are only used
+// when listed: their hosts come and go with the autoscaler.
+func (dc *DatabaseContext) forestHosts(cluster *marklogicv1.MarklogicCluster, mc mlmanage.HostClient) ([]string, string, string, error) {
+	groups := dc.MarklogicDatabase.Spec.Forests.Groups
+	if len(groups) == 0 {
+		for _, group := range cluster.Spec.MarkLogicGroups {
+			if group != nil && !group.IsDynamic {
+				groups = append(groups, group.Name)
+			}
+		}
+	}
+	var hosts []string
+	for _, groupName := range groups {
+		if !clusterHasGroup(cluster, groupName) {
+			return nil, databaseReasonGroupNotFound, fmt.Sprintf("MarklogicCluster %s has no group %s", cluster.Name, groupName), nil
+		}
+		group := &marklogicv1.MarklogicGroup{}
+		if err := dc.Client.Get(dc.Ctx, types.NamespacedName{Name: groupName, Namespace: cluster.Namespace}, group); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, databaseReasonGroupNotFound, fmt.Sprintf("MarklogicGroup %s not created yet", groupName), nil
+			}
+			return nil, "", "", err
+		}
+		members, err := mc.ListGroupHosts(dc.Ctx, resolvedMarkLogicGroupName(group))
+		if err != nil {
+			return nil, "", "", err
+		}
+		for _, member := range members {
+			hosts = append(hosts, member.Name)
+		}
+	}
+	if len(hosts) == 0 {
+		return nil, databaseReasonNoHosts, "the selected groups have no hosts", nil
+	}
+	sort.Strings(hosts)
+	return hosts, "", "", nil
+}
+
+func clusterHasGroup(cluster *marklogicv1.MarklogicCluster, name string) bool {
+	for _, group := range cluster.Spec.MarkLogicGroups {
+		if group != nil && group.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// planDatabaseForests lays out perHost forests on every host. Placements recorded in the
+// status are kept, so existing replicas stay where they are when hosts come and go; a new
+// replica n of a forest goes to the first free host n or more places further along the
+// sorted host list, so no two copies share a host. Recorded forests of hosts that left
+// the groups were relocated by a decommission and stay part of the database.
+func planDatabaseForests(database string, hosts []string, perHost, replicas int, recorded []marklogicv1.DatabaseForestStatus) []marklogicv1.DatabaseForestStatus {
+	previous := make(map[string]marklogicv1.DatabaseForestStatus, len(recorded))
+	for _, forest := range recorded {
+		previous[forest.Name] = forest
+	}
+	plan := make([]marklogicv1.DatabaseForestStatus, 0, len(hosts)*perHost)
+	for i, host := range hosts {
+		for n := 1; n <= perHost; n++ {
+			name := fmt.Sprintf("%s-%s-%d", database, shortHostName(host), n)
+			forest := marklogicv1.DatabaseForestStatus{Name: name, Host: host}
+			if known, ok := previous[name]; ok {
+				forest.Host = known.Host
+			}
+			plan = append(plan, planForestReplicas(forest, previous[name].Replicas, hosts, i, replicas))
+		}
+	}
+	for _, forest := range recorded {
+		if forestOfHost(database, forest.Name, hosts) {
+			continue
+		}
+		i := sort.SearchStrings(hosts, forest.Host)
+		plan = append(plan, planForestReplicas(marklogicv1.DatabaseForestStatus{Name: forest.Name, Host: forest.Host}, forest.Replicas, hosts, i, replicas))
+	}
+	return plan
+}
+
+// planForestReplicas keeps the first replicas of the recorded ones and places the
+// missing replicas on hosts that hold no other copy of the forest. i is the position
+// of the forest's host in hosts.
+func planForestReplicas(forest marklogicv1.DatabaseForestStatus, recorded []marklogicv1.DatabaseForestReplica, hosts []string, i, replicas int) marklogicv1.DatabaseForestStatus {
+	used := map[string]bool{forest.Host: true}
+	known := map[string]string{}
+	for _, replica := range recorded {
+		known[replica.Name] = replica.Host
+		used[replica.Host] = true
+	}
+	for r := 1; r <= replicas; r++ {
+		replica := marklogicv1.DatabaseForestReplica{Name: fmt.Sprintf("%s-replica-%d", forest.Name, r)}
+		if host, ok := known[replica.Name]; ok {
+			replica.Host = host
+		} else {
+			for step := 0; step < len(hosts); step++ {
+				if candidate := hosts[(i+r+step)%len(hosts)]; !used[candidate] {
+					replica.Host = candidate
+					break
+				}
+			}
+			used[replica.Host] = true
+		}
+		forest.Replicas = append(forest.Replicas, replica)
+	}
+	return forest
+}
+
+// forestOfHost reports whether name is a forest the plan derives from one of hosts.
+func forestOfHost(database, name string, hosts []string) bool {
+	for _, host := range hosts {
+		n, found := strings.CutPrefix(name, database+"-"+shortHostName(host)+"-")
+		if _, err := strconv.Atoi(n); found && err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func shortHostName(host string) string {
+	if i := strings.Index(host, "."); i > 0 {
+		return host[:i]
+	}
+	return host
+}
+
+// syncForest creates a planned forest and its replicas when missing and corrects its
+// replica configuration. Forests that exist elsewhere are reported, never moved, unless
+// their planned host is no longer one of the current hosts: a decommission relocated
+// them, and the plan takes over their new host.
+func (dc *DatabaseContext) syncForest(mc mlmanage.ForestClient, database string, forest *marklogicv1.DatabaseForestStatus, current map[string]bool) ([]string, []string, error) {
+	var corrected, drifted []string
+	dataDirectory := dc.MarklogicDatabase.Spec.Forests.DataDirectory
+
+	desiredReplicas := make([]mlmanage.ForestReplica, 0, len(forest.Replicas))
+	for i := range forest.Replicas {
+		replica := &forest.Replicas[i]
+		info, err := mc.GetForest(dc.Ctx, replica.Name)
+		if err != nil {
+			return nil, nil, err
+		}
+		switch {
+		case !info.Exists:
+			if err := mc.CreateForest(dc.Ctx, mlmanage.ForestOptions{Name: replica.Name, Host: replica.Host, DataDirectory: dataDirectory}); err != nil {
+				return nil, nil, err
+			}
+		case info.Host != replica.Host && !current[replica.Host]:
+			replica.Host = info.Host
+		case info.Host != replica.Host:
+			drifted = append(drifted, fmt.Sprintf("replica forest %s is on host %s instead of %s", replica.Name, info.Host, replica.Host))
+		}
+		desiredReplicas = append(desiredReplicas, mlmanage.ForestReplica{Name: replica.Name, Host: replica.Host})
+	}
+
+	info, err := mc.GetForest(dc.Ctx, forest.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !info.Exists {
+		if err := mc.CreateForest(dc.Ctx, mlmanage.ForestOptions{Name: forest.Name, Host: forest.Host, Database: database, DataDirectory: dataDirectory}); err != nil {
+			return nil, nil, err
+		}
+		if len(desiredReplicas) > 0 {
+			if err := mc.SetForestReplicas(dc.Ctx, forest.Name, desiredReplicas); err != nil {
+				return nil, nil, err
+			}
+		}
+		return corrected, drifted, nil
+	}
+	if info.Database != database {
+		drifted = append(drifted, fmt.Sprintf("forest %s is attached to %q instead of %s", forest.Name, info.Database, database))
+	}
+	if info.Host != forest.Host && !current[forest.Host] {
+		forest.Host = info.Host
+	} else if info.Host != forest.Host {
+		drifted = append(drifted, fmt.Sprintf("forest %s is on host %s instead of %s", forest.Name, info.Host, forest.Host))
+	}
+	// Replicas added by the failover policy of a group are left in place.
+	var configured, failover []mlmanage.ForestReplica
+	for _, replica := range info.Replicas {
+		if isFailoverReplica(forest.Name, replica.Name) {
+			failover = append(failover, replica)
+		} else {
+			configured = append(configured, replica)
+		}
+	}
+	if !sameForestReplicas(configured, desiredReplicas) {
+		if err := mc.SetForestReplicas(dc.Ctx, forest.Name, append(desiredReplicas, failover...)); err != nil {
+			return nil, nil, err
+		}
+		corrected = append(corrected, "replicas of forest "+forest.Name)
+	}
+	return corrected, drifted, nil
+}
+
+func sameForestReplicas(actual, desired []mlmanage.ForestReplica) bool {
+	if len(actual) != len(desired) {
+		return false
+	}
+	seen := map[mlmanage.ForestReplica]bool{}
+	for _, replica := range actual {
+		seen[replica] = true
+	}
+	for _, replica := range desired {
+		if !seen[replica] {
+			return false
+		}
+	}
+	return true
+}
+
+// databaseProperties converts the index settings of the spec into Management API
+// database properties. Unset settings are left out so the server keeps its value.
+func databaseProperties(indexes *marklogicv1.DatabaseIndexes) map[string]any {
+	properties := map[string]any{}
+	if indexes == nil {
+		return properties
+	}
+	if indexes.StemmedSearches != "" {
+		properties["stemmed-searches"] = indexes.StemmedSearches
+	}
+	for key, value := range map[string]*bool{
+		"word-searches":        indexes.WordSearches,
+		"fast-phrase-searches": indexes.FastPhraseSearches,
+		"triple-index":         indexes.TripleIndex,
+		"collection-lexicon":   indexes.CollectionLexicon,
+		"uri-lexicon":          indexes.URILexicon,
+	} {
+		if value != nil {
+			properties[key] = *value
+		}
+	}
+	if len(indexes.RangeElementIndexes) > 0 {
+		elementIndexes := make([]map[string]any, 0, len(indexes.RangeElementIndexes))
+		for _, index := range indexes.RangeElementIndexes {
+			elementIndexes = append(elementIndexes, map[string]any{
+				"scalar-type":           index.ScalarType,
+				"namespace-uri":         index.NamespaceURI,
+				"localname":             index.LocalName,
+				"collation":             rangeIndexCollation(index.ScalarType, index.Collation),
+				"range-value-positions": index.RangeValuePositions,
+				"invalid-values":        "reject",
+			})
+		}
+		properties["range-element-index"] = elementIndexes
+	}
+	if len(indexes.RangePathIndexes) > 0 {
+		pathIndexes := make([]map[string]any, 0, len(indexes.RangePathIndexes))
+		for _, index := range indexes.RangePathIndexes {
+			pathIndexes = append(pathIndexes, map[string]any{
+				"scalar-type":           index.ScalarType,
+				"path-expression":       index.PathExpression,
+				"collation":             rangeIndexCollation(index.ScalarType, index.Collation),
+				"range-value-positions": index.RangeValuePositions,
+				"invalid-values":        "reject",
+			})
+		}
+		properties["range-path-index"] = pathIndexes
+	}
+	return properties
+}
+
+// rangeIndexCollation returns the collation MarkLogic stores for a range index: only
+// string and anyURI indexes have one.
+func rangeIndexCollation(scalarType, collation string) string {
+	if scalarType != "string" && scalarType != "anyURI" {
+		return ""
+	}
+	if collation == "" {
+		return rootCollation
+	}
+	return collation
+}
+
+// databasePropertyDrift lists the desired properties whose server value differs.
+// Range index lists are compared as sets over the fields the operator sets.
+func databasePropertyDrift(desired, actual map[string]any) []string {
+	var changed []string
+	for key, want := range desired {
+		have := actual[key]
+		if indexes, ok := want.([]map[string]any); ok {
+			if !sameRangeIndexes(indexes, have) {
+				changed = append(changed, key)
+			}
+			continue
+		}
+		if fmt.Sprint(want) != fmt.Sprint(have) {
+			changed = append(changed, key)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+func sameRangeIndexes(desired []map[string]any, actual any) bool {
+	var actualEntries []map[string]any
+	switch v := actual.(type) {
+	case []any:
+		for _, item := range v {
+			if entry, ok := item.(map[string]any); ok {
+				actualEntries = append(actualEntries, entry)
+			}
+		}
+	case map[string]any:
+		actualEntries = append(actualEntries, v)
+	}
+	if len(actualEntries) != len(desired) {
+		return false
+	}
+	if len(desired) == 0 {
+		return true
+	}
+	// Every entry of one index kind carries the same fields.
+	fields := make([]string, 0, len(desired[0]))
+	for field := range desired[0] {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	indexKey := func(entry map[string]any) string {
+		values := make([]string, 0, len(fields))
+		for _, field := range fields {
+			values = append(values, fmt.Sprint(entry[field]))
+		}
+		return strings.Join(values, "\x00")
+	}
+	remaining := map[string]int{}
+	for i := range desired {
+		remaining[indexKey(desired[i])]++
+		remaining[indexKey(actualEntries[i])]--
+	}
+	for _, count := range remaining {
+		if count != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (dc *DatabaseContext) syncDatabaseFinalizer() error {
+	cr := dc.MarklogicDatabase
+	wantFinalizer := cr.Spec.DeletionPolicy == marklogicv1.DatabaseDeletionPolicyDelete
+	if wantFinalizer == controllerutil.ContainsFinalizer(cr, databaseCleanupFinalizer) {
+		return nil
+	}
+	patch := client.MergeFrom(cr.DeepCopy())
+	if wantFinalizer {
+		controllerutil.AddFinalizer(cr, databaseCleanupFinalizer)
+	} else {
+		controllerutil.RemoveFinalizer(cr, databaseCleanupFinalizer)
+	}
+	return dc.Client.Patch(dc.Ctx, cr, patch)
+}
+
+// finalizeDatabase deletes the database, its forests and their replicas before
+// releasing the finalizer. A missing cluster has nothing left to clean up.
+func (dc *DatabaseContext) finalizeDatabase() result.ReconcileResult {
+	cr := dc.MarklogicDatabase
+	if !controllerutil.ContainsFinalizer(cr, databaseCleanupFinalizer) {
+		return result.Done()
+	}
+	cluster := &marklogicv1.MarklogicCluster{}
+	err := dc.Client.Get(dc.Ctx, types.NamespacedName{Name: cr.Spec.ClusterRef.Name, Namespace: cr.Namespace}, cluster)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return result.Error(err)
+	}
+	if err == nil && cluster.DeletionTimestamp == nil {
+		mc, err := managementClientForCluster(dc.Ctx, dc.Client, cluster)
+		if err != nil {
+			return result.Error(err)
+		}
+		if err := mc.DeleteDatabase(dc.Ctx, cr.Spec.DatabaseName); err != nil {
+			dc.ReqLogger.Error(err, "Failed to delete database", "database", cr.Spec.DatabaseName)
+			return result.RequeueSoon(databaseRetrySeconds)
+		}
+		for _, forest := range cr.Status.Forests {
+			for _, replica := range forest.Replicas {
+				if err := mc.DeleteForest(dc.Ctx, replica.Name); err != nil {
+					dc.ReqLogger.Error(err, "Failed to delete replica forest", "forest", replica.Name)
+					return result.RequeueSoon(databaseRetrySeconds)
+				}
+			}
+		}
+		dc.Recorder.Eventf(cr, corev1.EventTypeNormal, "DatabaseDeleted", "Deleted database %s", cr.Spec.DatabaseName)
+	}
+	patch := client.MergeFrom(cr.DeepCopy())
+	controllerutil.RemoveFinalizer(cr, databaseCleanupFinalizer)
+	if err := dc.Client.Patch(dc.Ctx, cr, patch); err != nil {
+		return result.Error(err)
+	}
+	return result.Done()
+}
+
+func setDatabaseCondition(status *marklogicv1.MarklogicDatabaseStatus, generation int64, conditionType marklogicv1.MarkLogicConditionType, conditionStatus metav1.ConditionStatus, reason, message string) {
+	apimeta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               string(conditionType),
+		Status:             conditionStatus,
+		ObservedGeneration: generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
@@ -0,0 +1,374 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+)
+
+// stubDatabaseClient holds the databases and forests the database, failover and decommission
+// calls see.
+type stubDatabaseClient struct {
+	databases map[string]mlmanage.DatabaseInfo
+	forests   map[string]mlmanage.ForestInfo
+	// forestStates holds the state reported per forest; forests without one are open, or
+	// sync replicating when they are a replica.
+	forestStates map[string]string
+	// migrateFn observes forest migrations; migrations move the forest at once.
+	migrateFn func(forest, targetHost string) error
+}
+
+func (s *stubDatabaseClient) GetDatabase(ctx context.Context, database string) (mlmanage.DatabaseInfo, error) {
+	return s.databases[database], nil
+}
+
+func (s *stubDatabaseClient) CreateDatabase(ctx context.Context, database string, properties map[string]any) error {
+	if s.databases == nil {
+		s.databases = map[string]mlmanage.DatabaseInfo{}
+	}
+	if s.databases[database].Exists {
+		return fmt.Errorf("database %s already exists", database)
+	}
+	s.databases[database] = mlmanage.DatabaseInfo{Exists: true, Properties: properties}
+	return nil
+}
+
+func (s *stubDatabaseClient) UpdateDatabaseProperties(ctx context.Context, database string, properties map[string]any) error {
+	info := s.databases[database]
+	if !info.Exists {
+		return fmt.Errorf("database %s does not exist", database)
+	}
+	merged := map[string]any{}
+	for key, value := range info.Properties {
+		merged[key] = value
+	}
+	for key, value := range properties {
+		merged[key] = value
+	}
+	info.Properties = merged
+	if replication, ok := properties["database-replication"].(map[string]any); ok {
+		info.ForeignReplicas, info.ForeignMaster = stubDatabaseReplication(replication)
+	}
+	s.databases[database] = info
+	return nil
+}
+
+func (s *stubDatabaseClient) DeleteDatabase(ctx context.Context, database string) error {
+	for _, forest := range s.databases[database].Forests {
+		delete(s.forests, forest)
+	}
+	delete(s.databases, database)
+	return nil
+}
+
+func (s *stubDatabaseClient) GetForest(ctx context.Context, forest string) (mlmanage.ForestInfo, error) {
+	return s.forests[forest], nil
+}
+
+func (s *stubDatabaseClient) GetForestState(ctx context.Context, forest string) (string, error) {
+	if state, ok := s.forestStates[forest]; ok {
+		return state, nil
+	}
+	info := s.forests[forest]
+	switch {
+	case !info.Exists:
+		return "", nil
+	case info.Database == "":
+		return "sync replicating", nil
+	}
+	return "open", nil
+}
+
+func (s *stubDatabaseClient) CreateForest(ctx context.Context, opts mlmanage.ForestOptions) error {
+	if s.forests == nil {
+		s.forests = map[string]mlmanage.ForestInfo{}
+	}
+	if s.forests[opts.Name].Exists {
+		return fmt.Errorf("forest %s already exists", opts.Name)
+	}
+	s.forests[opts.Name] = mlmanage.ForestInfo{Exists: true, Host: opts.Host, Database: opts.Database}
+	if opts.Database != "" {
+		info := s.databases[opts.Database]
+		info.Forests = append(info.Forests, opts.Name)
+		s.databases[opts.Database] = info
+	}
+	return nil
+}
+
+func (s *stubDatabaseClient) SetForestReplicas(ctx context.Context, forest string, replicas []mlmanage.ForestReplica) error {
+	info := s.forests[forest]
+	info.Replicas = replicas
+	s.forests[forest] = info
+	return nil
+}
+
+func (s *stubDatabaseClient) DeleteForest(ctx context.Context, forest string) error {
+	delete(s.forests, forest)
+	return nil
+}
+
+func (s *stubDatabaseClient) ListHostForests(ctx context.Context, hostName string) ([]string, error) {
+	var forests []string
+	for name, info := range s.forests {
+		if info.Host == hostName {
+			forests = append(forests, name)
+		}
+	}
+	sort.Strings(forests)
+	return forests, nil
+}
+
+func (s *stubDatabaseClient) MigrateForest(ctx context.Context, forest, targetHost string) error {
+	if s.migrateFn != nil {
+		if err := s.migrateFn(forest, targetHost); err != nil {
+			return err
+		}
+	}
+	info := s.forests[forest]
+	info.Host = targetHost
+	s.forests[forest] = info
+	return nil
+}
+
+func TestPlanDatabaseForestsSpreadsReplicasAcrossHosts(t *testing.T) {
+	t.Parallel()
+
+	hosts := []string{"dnode-0.dnode.default.svc.cluster.local", "dnode-1.dnode.default.svc.cluster.local", "enode-0.enode.default.svc.cluster.local"}
+	plan := planDatabaseForests("orders", hosts, 2, 1, nil)
+	if len(plan) != 6 {
+		t.Fatalf("expected 6 forests, got %d", len(plan))
+	}
+	if plan[0].Name != "orders-dnode-0-1" || plan[1].Name != "orders-dnode-0-2" || plan[0].Host != hosts[0] {
+		t.Fatalf("unexpected forest names %s, %s", plan[0].Name, plan[1].Name)
+	}
+	for _, forest := range plan {
+		if len(forest.Replicas) != 1 {
+			t.Fatalf("expected one replica for %s, got %v", forest.Name, forest.Replicas)
+		}
+		if forest.Replicas[0].Host == forest.Host {
+			t.Fatalf("replica of %s shares its host", forest.Name)
+		}
+	}
+	if last := plan[5].Replicas[0]; last.Name != "orders-enode-0-2-replica-1" || last.Host != hosts[0] {
+		t.Fatalf("expected the last host's replicas to wrap around, got %+v", last)
+	}
+}
+
+func TestPlanDatabaseForestsKeepsRecordedPlacement(t *testing.T) {
+	t.Parallel()
+
+	hosts := []string{"dnode-0.dnode", "dnode-1.dnode", "dnode-2.dnode"}
+	recorded := planDatabaseForests("orders", hosts, 1, 1, nil)
+	replicaHosts := map[string]string{}
+	for _, forest := range recorded {
+		replicaHosts[forest.Replicas[0].Name] = forest.Replicas[0].Host
+	}
+
+	// dnode-10 sorts between dnode-1 and dnode-2, which would shift every replica.
+	scaled := planDatabaseForests("orders", append(hosts, "dnode-10.dnode"), 1, 1, recorded)
+	if len(scaled) != 4 {
+		t.Fatalf("expected 4 forests, got %d", len(scaled))
+	}
+	for _, forest := range scaled {
+		replica := forest.Replicas[0]
+		if host, ok := replicaHosts[replica.Name]; ok && replica.Host != host {
+			t.Fatalf("expected %s to stay on %s, got %s", replica.Name, host, replica.Host)
+		}
+		if replica.Host == forest.Host {
+			t.Fatalf("replica of %s shares its host", forest.Name)
+		}
+	}
+
+	// dnode-2 was decommissioned; its forest was moved to dnode-1 and stays managed.
+	recorded[2].Host = "dnode-1.dnode"
+	shrunk := planDatabaseForests("orders", hosts[:2], 1, 1, recorded)
+	if len(shrunk) != 3 || shrunk[2].Name != "orders-dnode-2-1" || shrunk[2].Host != "dnode-1.dnode" {
+		t.Fatalf("expected the relocated forest to be kept, got %+v", shrunk)
+	}
+}
+
+func TestReconcileDatabaseCreatesDatabaseAndForests(t *testing.T) {
+	tripleIndex := true
+	database := &marklogicv1.MarklogicDatabase{
+		ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "default"},
+		Spec: marklogicv1.MarklogicDatabaseSpec{
+			ClusterRef:   corev1.LocalObjectReference{Name: "ml"},
+			DatabaseName: "orders",
+			Forests:      marklogicv1.DatabaseForests{PerHost: 1},
+			Replicas:     1,
+			Indexes: &marklogicv1.DatabaseIndexes{
+				TripleIndex: &tripleIndex,
+				RangeElementIndexes: []marklogicv1.RangeElementIndex{
+					{ScalarType: "dateTime", LocalName: "created"},
+					{ScalarType: "string", LocalName: "status"},
+				},
+			},
+		},
+	}
+	group := &marklogicv1.MarklogicGroup{ObjectMeta: metav1.ObjectMeta{Name: "dnode", Namespace: "default"}}
+	fakeClient := newBackupTestClient(t, group, database)
+	hosts := &stubDynamicManagementClient{
+		listGroupFn: func(groupName string) ([]mlmanage.GroupHost, error) {
+			return []mlmanage.GroupHost{{Name: "dnode-1.dnode"}, {Name: "dnode-0.dnode"}}, nil
+		},
+	}
+	stub := &stubDatabaseClient{}
+	useStubClusterManagementClient(t, managementStub{HostClient: hosts, DatabaseClient: stub, ForestClient: stub})
+
+	dc := &DatabaseContext{Ctx: context.Background(), Client: fakeClient, MarklogicDatabase: database, Recorder: record.NewFakeRecorder(10)}
+	dc.ReconcileDatabase()
+
+	info := stub.databases["orders"]
+	if !info.Exists || len(info.Forests) != 2 || info.Properties["triple-index"] != true {
+		t.Fatalf("unexpected database %+v", info)
+	}
+	forest := stub.forests["orders-dnode-0-1"]
+	if forest.Host != "dnode-0.dnode" || forest.Database != "orders" {
+		t.Fatalf("unexpected forest %+v", forest)
+	}
+	if len(forest.Replicas) != 1 || forest.Replicas[0] != (mlmanage.ForestReplica{Name: "orders-dnode-0-1-replica-1", Host: "dnode-1.dnode"}) {
+		t.Fatalf("unexpected replicas %+v", forest.Replicas)
+	}
+	if replica := stub.forests["orders-dnode-0-1-replica-1"]; !replica.Exists || replica.Database != "" {
+		t.Fatalf("expected an unattached replica forest, got %+v", replica)
+	}
+	if !apimeta.IsStatusConditionTrue(database.Status.Conditions, string(marklogicv1.DatabaseReady)) {
+		t.Fatalf("expected the database to be ready, got %+v", database.Status.Conditions)
+	}
+	if !apimeta.IsStatusConditionFalse(database.Status.Conditions, string(marklogicv1.DatabaseDrifted)) {
+		t.Fatalf("expected no drift right after creation, got %+v", database.Status.Conditions)
+	}
+	if len(database.Status.Forests) != 2 {
+		t.Fatalf("expected two forests in status, got %+v", database.Status.Forests)
+	}
+}
+
+func TestReconcileDatabaseReportsDrift(t *testing.T) {
+	tripleIndex := true
+	database := &marklogicv1.MarklogicDatabase{
+		ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "default"},
+		Spec: marklogicv1.MarklogicDatabaseSpec{
+			ClusterRef:   corev1.LocalObjectReference{Name: "ml"},
+			DatabaseName: "orders",
+			Forests:      marklogicv1.DatabaseForests{PerHost: 1},
+			Indexes: &marklogicv1.DatabaseIndexes{
+				TripleIndex:         &tripleIndex,
+				RangeElementIndexes: []marklogicv1.RangeElementIndex{{ScalarType: "string", LocalName: "status"}},
+			},
+		},
+	}
+	group := &marklogicv1.MarklogicGroup{ObjectMeta: metav1.ObjectMeta{Name: "dnode", Namespace: "default"}}
+	fakeClient := newBackupTestClient(t, group, database)
+	// The server has the index the spec asks for, decoded from JSON, but someone switched
+	// the triple index off and attached a forest by hand. The failover policy of the group
+	// added a replica to the managed forest.
+	hosts := &stubDynamicManagementClient{
+		listGroupFn: func(groupName string) ([]mlmanage.GroupHost, error) {
+			return []mlmanage.GroupHost{{Name: "dnode-0.dnode"}}, nil
+		},
+	}
+	stub := &stubDatabaseClient{
+		databases: map[string]mlmanage.DatabaseInfo{"orders": {
+			Exists:  true,
+			Forests: []string{"orders-dnode-0-1", "orders-manual"},
+			Properties: map[string]any{
+				"triple-index": false,
+				"range-element-index": []any{map[string]any{
+					"scalar-type": "string", "namespace-uri": "", "localname": "status",
+					"collation": rootCollation, "range-value-positions": false, "invalid-values": "reject",
+				}},
+			},
+		}},
+		forests: map[string]mlmanage.ForestInfo{
+			"orders-dnode-0-1": {Exists: true, Host: "dnode-0.dnode", Database: "orders",
+				Replicas: []mlmanage.ForestReplica{{Name: "orders-dnode-0-1-failover-1", Host: "dnode-1.dnode"}}},
+		},
+	}
+	useStubClusterManagementClient(t, managementStub{HostClient: hosts, DatabaseClient: stub, ForestClient: stub})
+
+	dc := &DatabaseContext{Ctx: context.Background(), Client: fakeClient, MarklogicDatabase: database, Recorder: record.NewFakeRecorder(10)}
+	dc.ReconcileDatabase()
+
+	if stub.databases["orders"].Properties["triple-index"] != true {
+		t.Fatal("expected the triple index setting to be corrected")
+	}
+	if replicas := stub.forests["orders-dnode-0-1"].Replicas; len(replicas) != 1 {
+		t.Fatalf("expected the failover replica to be kept, got %+v", replicas)
+	}
+	drifted := apimeta.FindStatusCondition(database.Status.Conditions, string(marklogicv1.DatabaseDrifted))
+	if drifted == nil || drifted.Status != metav1.ConditionTrue || drifted.Reason != databaseReasonDrifted {
+		t.Fatalf("expected a Drifted condition, got %+v", drifted)
+	}
+	if !strings.Contains(drifted.Message, "orders-manual") || !strings.Contains(drifted.Message, "triple-index") {
+		t.Fatalf("expected the drift message to name the unmanaged forest and the corrected property, got %q", drifted.Message)
+	}
+	if strings.Contains(drifted.Message, "range-element-index") {
+		t.Fatalf("expected the matching range index not to be reported, got %q", drifted.Message)
+	}
+}
+
+func TestReconcileDatabaseAdoptsRelocatedForestsAndSkipsDynamicGroups(t *testing.T) {
+	database := &marklogicv1.MarklogicDatabase{
+		ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "default"},
+		Spec: marklogicv1.MarklogicDatabaseSpec{
+			ClusterRef:   corev1.LocalObjectReference{Name: "ml"},
+			DatabaseName: "orders",
+			Forests:      marklogicv1.DatabaseForests{PerHost: 1},
+		},
+		Status: marklogicv1.MarklogicDatabaseStatus{Forests: []marklogicv1.DatabaseForestStatus{
+			{Name: "orders-dnode-0-1", Host: "dnode-0.dnode"},
+			{Name: "orders-dnode-1-1", Host: "dnode-1.dnode"},
+		}},
+	}
+	group := &marklogicv1.MarklogicGroup{ObjectMeta: metav1.ObjectMeta{Name: "dnode", Namespace: "default"}}
+	dynamic := &marklogicv1.MarklogicGroup{ObjectMeta: metav1.ObjectMeta{Name: "enode", Namespace: "default"}}
+	fakeClient := newBackupTestClient(t, group, dynamic, database)
+	cluster := &marklogicv1.MarklogicCluster{}
+	if err := fakeClient.Get(context.Background(), types.NamespacedName{Name: "ml", Namespace: "default"}, cluster); err != nil {
+		t.Fatalf("failed to get cluster: %v", err)
+	}
+	cluster.Spec.MarkLogicGroups = append(cluster.Spec.MarkLogicGroups, &marklogicv1.MarklogicGroups{Name: "enode", IsDynamic: true})
+	if err := fakeClient.Update(context.Background(), cluster); err != nil {
+		t.Fatalf("failed to update cluster: %v", err)
+	}
+	// dnode-1 was decommissioned and its forest moved to dnode-0.
+	hosts := &stubDynamicManagementClient{
+		listGroupFn: func(groupName string) ([]mlmanage.GroupHost, error) {
+			if groupName != "dnode" {
+				t.Errorf("expected only the dnode group to hold forests, got %s", groupName)
+			}
+			return []mlmanage.GroupHost{{Name: "dnode-0.dnode"}}, nil
+		},
+	}
+	stub := &stubDatabaseClient{
+		databases: map[string]mlmanage.DatabaseInfo{"orders": {Exists: true, Forests: []string{"orders-dnode-0-1", "orders-dnode-1-1"}, Properties: map[string]any{}}},
+		forests: map[string]mlmanage.ForestInfo{
+			"orders-dnode-0-1": {Exists: true, Host: "dnode-0.dnode", Database: "orders"},
+			"orders-dnode-1-1": {Exists: true, Host: "dnode-0.dnode", Database: "orders"},
+		},
+	}
+	useStubClusterManagementClient(t, managementStub{HostClient: hosts, DatabaseClient: stub, ForestClient: stub})
+
+	dc := &DatabaseContext{Ctx: context.Background(), Client: fakeClient, MarklogicDatabase: database, Recorder: record.NewFakeRecorder(10)}
+	dc.ReconcileDatabase()
+
+	if !apimeta.IsStatusConditionFalse(database.Status.Conditions, string(marklogicv1.DatabaseDrifted)) {
+		t.Fatalf("expected the relocated forest not to be reported as drift, got %+v", database.Status.Conditions)
+	}
+	forests := database.Status.Forests
+	if len(forests) != 2 || forests[1].Name != "orders-dnode-1-1" || forests[1].Host != "dnode-0.dnode" {
+		t.Fatalf("expected the new host of the relocated forest to be recorded, got %+v", forests)
+	}
+}
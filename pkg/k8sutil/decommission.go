@@ -0,0 +1,415 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	decommissionRequeueSeconds        = 15
+	decommissionBlockedRequeueSeconds = 60
+
+	// A forest that has not reached its target host this long after a migration was issued
+	// is migrated again, up to decommissionMaxMigrationAttempts times.
+	decommissionMigrationTimeout     = 10 * time.Minute
+	decommissionMaxMigrationAttempts = 3
+
+	decommissionReasonCompleted = "Completed"
+	decommissionReasonCancelled = "Cancelled"
+)
+
+// hostForestClient is the part of the Management API that places forests on the hosts of a
+// group.
+type hostForestClient interface {
+	mlmanage.HostClient
+	mlmanage.ForestClient
+}
+
+// ReconcileScaleDown removes the hosts above the desired replicas of a non-dynamic group,
+// highest ordinal first. The forests of the departing host are migrated to the hosts that
+// stay, the host leaves the cluster, and only then does ReconcileStatefulset shrink the
+// StatefulSet by one. Scale-down stops with a Blocked phase when the data cannot be moved.
+func (oc *OperatorContext) ReconcileScaleDown() result.ReconcileResult {
+	cr := oc.MarklogicGroup
+	logger := oc.ReqLogger
+	if cr.Spec.IsDynamic || cr.DeletionTimestamp != nil {
+		return result.Continue()
+	}
+	sts, err := oc.GetStatefulSet(cr.Namespace, cr.Spec.Name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return result.Continue()
+		}
+		return result.Error(err)
+	}
+	desired := int32(1)
+	if cr.Spec.Replicas != nil {
+		desired = *cr.Spec.Replicas
+	}
+	current := desired
+	if sts.Spec.Replicas != nil {
+		current = *sts.Spec.Replicas
+	}
+
+	now := metav1.Now()
+	patchClient := client.MergeFrom(cr.DeepCopy())
+	original := cr.Status.DeepCopy()
+	if desired >= current {
+		if cr.Status.Decommission == nil {
+			return result.Continue()
+		}
+		reason, message := decommissionReasonCompleted, fmt.Sprintf("scaled down to %d replicas", current)
+		if cr.Status.Decommission.Phase != marklogicv1.DecommissionPhaseHostRemoved {
+			reason, message = decommissionReasonCancelled, fmt.Sprintf("scale-down of %s cancelled, %d replicas requested", cr.Status.Decommission.PodName, desired)
+		}
+		cr.Status.Decommission = nil
+		setDecommissionCondition(&cr.Status, cr.Generation, metav1.ConditionFalse, reason, message, now)
+		return oc.patchDecommissionStatus(patchClient, original, result.Continue())
+	}
+
+	podName := fmt.Sprintf("%s-%d", cr.Spec.Name, current-1)
+	status := cr.Status.Decommission.DeepCopy()
+	if status == nil || status.PodName != podName {
+		status = &marklogicv1.DecommissionStatus{
+			PodName:   podName,
+			Host:      dynamicPodFQDN(cr, podName),
+			Phase:     marklogicv1.DecommissionPhaseEvacuating,
+			StartTime: &now,
+		}
+		oc.Recorder.Eventf(cr, corev1.EventTypeNormal, "DecommissionStarted", "Decommissioning %s before scaling down to %d replicas", podName, desired)
+	}
+	if status.Phase == marklogicv1.DecommissionPhaseHostRemoved {
+		// ReconcileStatefulset releases the pod on the next pass.
+		return result.RequeueSoon(1)
+	}
+
+	previousPhase := status.Phase
+	mc, err := oc.groupManagementClient()
+	if err == nil {
+		err = oc.advanceDecommission(mc, status, desired, now)
+	}
+	if err != nil {
+		logger.Error(err, "Failed to advance decommission", "pod", podName)
+		status.Message = err.Error()
+	}
+	if status.Phase == marklogicv1.DecommissionPhaseBlocked && previousPhase != marklogicv1.DecommissionPhaseBlocked {
+		oc.Recorder.Eventf(cr, corev1.EventTypeWarning, "DecommissionBlocked", "Scale-down blocked: %s", status.Message)
+	}
+	if status.Phase == marklogicv1.DecommissionPhaseHostRemoved && previousPhase != marklogicv1.DecommissionPhaseHostRemoved {
+		oc.Recorder.Eventf(cr, corev1.EventTypeNormal, "HostDecommissioned", "Host %s left the cluster", status.Host)
+	}
+	cr.Status.Decommission = status
+	setDecommissionCondition(&cr.Status, cr.Generation, metav1.ConditionTrue, string(status.Phase), status.Message, now)
+
+	requeue := result.RequeueSoon(decommissionRequeueSeconds)
+	switch status.Phase {
+	case marklogicv1.DecommissionPhaseHostRemoved:
+		requeue = result.RequeueSoon(1)
+	case marklogicv1.DecommissionPhaseBlocked:
+		requeue = result.RequeueSoon(decommissionBlockedRequeueSeconds)
+	}
+	return oc.patchDecommissionStatus(patchClient, original, requeue)
+}
+
+// advanceDecommission moves the departing host one step further and records the phase it
+// reached. Errors leave the phase unchanged so the step is retried.
+func (oc *OperatorContext) advanceDecommission(mc hostForestClient, status *marklogicv1.DecommissionStatus, desired int32, now metav1.Time) error {
+	cr := oc.MarklogicGroup
+	groupName := resolvedMarkLogicGroupName(cr)
+	members, err := mc.ListGroupHosts(oc.Ctx, groupName)
+	if err != nil {
+		return err
+	}
+	member, found := findGroupHostForPod(status.PodName, status.Host, members)
+	if !found {
+		status.Phase = marklogicv1.DecommissionPhaseHostRemoved
+		status.Message = fmt.Sprintf("host %s is no longer a member of group %s", status.Host, groupName)
+		return nil
+	}
+	if status.Phase == marklogicv1.DecommissionPhaseRemovingHost {
+		status.Message = fmt.Sprintf("waiting for host %s to leave the cluster", member.Name)
+		return nil
+	}
+	if strings.TrimSpace(cr.Spec.BootstrapHost) == "" && podOrdinal(status.PodName) == 0 {
+		status.Phase = marklogicv1.DecommissionPhaseBlocked
+		status.Message = fmt.Sprintf("host %s is the bootstrap host and cannot be removed", member.Name)
+		return nil
+	}
+	if !member.Online {
+		status.Phase = marklogicv1.DecommissionPhaseBlocked
+		status.Message = fmt.Sprintf("host %s is offline, its forests cannot be migrated", member.Name)
+		return nil
+	}
+
+	forests, err := mc.ListHostForests(oc.Ctx, member.Name)
+	if err != nil {
+		return err
+	}
+	pending, blocked, err := oc.verifyForestMigrations(mc, status, now)
+	if err != nil {
+		return err
+	}
+	if blocked != "" {
+		status.Phase = marklogicv1.DecommissionPhaseBlocked
+		status.Message = blocked
+		return nil
+	}
+	if len(forests) == 0 {
+		if pending > 0 {
+			status.Phase = marklogicv1.DecommissionPhaseEvacuating
+			status.Message = fmt.Sprintf("waiting for %d forests of host %s to open on their new hosts", pending, member.Name)
+			return nil
+		}
+		if err := mc.LeaveCluster(oc.Ctx, member.Name); err != nil {
+			return err
+		}
+		status.Phase = marklogicv1.DecommissionPhaseRemovingHost
+		status.Message = fmt.Sprintf("host %s has no forests left and is leaving the cluster", member.Name)
+		return nil
+	}
+
+	targets := decommissionTargets(cr.Spec.Name, members, desired)
+	if len(targets) == 0 {
+		status.Phase = marklogicv1.DecommissionPhaseBlocked
+		status.Message = fmt.Sprintf("no online host stays in group %s to take the %d forests of %s", groupName, len(forests), member.Name)
+		return nil
+	}
+	load := map[string]int{}
+	placed := map[string]bool{}
+	for _, target := range targets {
+		hosted, err := mc.ListHostForests(oc.Ctx, target)
+		if err != nil {
+			return err
+		}
+		load[target] = len(hosted)
+		for _, forest := range hosted {
+			placed[forest] = true
+		}
+	}
+	migrating := map[string]bool{}
+	for _, forest := range status.Forests {
+		if !placed[forest.Name] {
+			load[forest.TargetHost]++
+		}
+		migrating[forest.Name] = true
+	}
+	for _, forest := range forests {
+		if migrating[forest] {
+			continue
+		}
+		info, err := mc.GetForest(oc.Ctx, forest)
+		if err != nil {
+			return err
+		}
+		target := leastLoadedTarget(targets, load, info.Replicas)
+		if target == "" {
+			status.Phase = marklogicv1.DecommissionPhaseBlocked
+			status.Message = fmt.Sprintf("forest %s has a replica on every host that stays in group %s", forest, groupName)
+			return nil
+		}
+		if err := mc.MigrateForest(oc.Ctx, forest, target); err != nil {
+			status.Phase = marklogicv1.DecommissionPhaseBlocked
+			status.Message = fmt.Sprintf("migrating forest %s to %s failed: %v", forest, target, err)
+			return nil
+		}
+		status.Forests = append(status.Forests, marklogicv1.DecommissionForest{Name: forest, TargetHost: target, Attempts: 1, MigrationTime: &now})
+		load[target]++
+	}
+	status.Phase = marklogicv1.DecommissionPhaseEvacuating
+	status.Message = fmt.Sprintf("migrating %d forests off host %s", len(forests), member.Name)
+	return nil
+}
+
+// verifyForestMigrations re-reads the host and state of the forests already migrated off the
+// departing host. A forest still away from its target after decommissionMigrationTimeout is
+// migrated again; once the attempts are used up, or when a forest does not open on its
+// target, it returns the message the scale-down is blocked with. pending counts the
+// migrations that have not completed yet.
+func (oc *OperatorContext) verifyForestMigrations(mc mlmanage.ForestClient, status *marklogicv1.DecommissionStatus, now metav1.Time) (int, string, error) {
+	pending := 0
+	for i := range status.Forests {
+		forest := &status.Forests[i]
+		info, err := mc.GetForest(oc.Ctx, forest.Name)
+		if err != nil {
+			return 0, "", err
+		}
+		if !info.Exists {
+			continue
+		}
+		issued := forest.MigrationTime
+		if issued == nil {
+			issued = status.StartTime
+		}
+		timedOut := issued == nil || now.Sub(issued.Time) >= decommissionMigrationTimeout
+		if normalizeManagedHostName(info.Host) != normalizeManagedHostName(forest.TargetHost) {
+			pending++
+			if !timedOut {
+				continue
+			}
+			if forest.Attempts >= decommissionMaxMigrationAttempts {
+				return 0, fmt.Sprintf("forest %s is still on %s after %d migrations to %s", forest.Name, info.Host, forest.Attempts, forest.TargetHost), nil
+			}
+			if err := mc.MigrateForest(oc.Ctx, forest.Name, forest.TargetHost); err != nil {
+				return 0, fmt.Sprintf("migrating forest %s to %s failed: %v", forest.Name, forest.TargetHost, err), nil
+			}
+			forest.Attempts++
+			forest.MigrationTime = now.DeepCopy()
+			continue
+		}
+		state, err := mc.GetForestState(oc.Ctx, forest.Name)
+		if err != nil {
+			return 0, "", err
+		}
+		if state == forestStateOpen || state == forestStateSyncReplicating {
+			continue
+		}
+		pending++
+		if timedOut {
+			if state == "" {
+				state = "offline"
+			}
+			return 0, fmt.Sprintf("forest %s is %s on %s after its migration", forest.Name, state, forest.TargetHost), nil
+		}
+	}
+	return pending, "", nil
+}
+
+// decommissionTargets are the online members of the group whose pods stay after the
+// scale-down, so forests are moved at most once.
+func decommissionTargets(stsName string, members []mlmanage.GroupHost, desired int32) []string {
+	var targets []string
+	for _, member := range members {
+		podName := hostnameToPodName(member.Name)
+		if !member.Online || !strings.HasPrefix(podName, stsName+"-") || podOrdinal(podName) >= int(desired) {
+			continue
+		}
+		targets = append(targets, member.Name)
+	}
+	sort.Strings(targets)
+	return targets
+}
+
+// leastLoadedTarget picks the target with the fewest forests, counting the ones it
+// already hosts and the migrations still heading to it, that does not already hold a
+// replica of the forest.
+func leastLoadedTarget(targets []string, load map[string]int, replicas []mlmanage.ForestReplica) string {
+	excluded := map[string]bool{}
+	for _, replica := range replicas {
+		excluded[normalizeManagedHostName(replica.Host)] = true
+	}
+	best := ""
+	for _, target := range targets {
+		if excluded[normalizeManagedHostName(target)] {
+			continue
+		}
+		if best == "" || load[target] < load[best] {
+			best = target
+		}
+	}
+	return best
+}
+
+// decommissionReplicas is the StatefulSet replica count a non-dynamic group may run while
+// scaling down: the current count until the departing host has left the cluster, then one
+// less. It returns nil when no scale-down is pending.
+func decommissionReplicas(cr *marklogicv1.MarklogicGroup, currentSts *appsv1.StatefulSet) *int32 {
+	if cr == nil || currentSts == nil || cr.Spec.IsDynamic || cr.Spec.Replicas == nil || currentSts.Spec.Replicas == nil {
+		return nil
+	}
+	current := *currentSts.Spec.Replicas
+	if *cr.Spec.Replicas >= current {
+		return nil
+	}
+	if d := cr.Status.Decommission; d != nil && d.Phase == marklogicv1.DecommissionPhaseHostRemoved && d.PodName == fmt.Sprintf("%s-%d", cr.Spec.Name, current-1) {
+		next := current - 1
+		return &next
+	}
+	return &current
+}
+
+// groupManagementClient connects to the bootstrap host of the owning MarklogicCluster, or to
+// the bootstrap host named on the group when it is not owned by a cluster.
+func (oc *OperatorContext) groupManagementClient() (mlmanage.Client, error) {
+	group := oc.MarklogicGroup
+	cluster, err := oc.owningCluster()
+	if err != nil {
+		return nil, err
+	}
+	if cluster != nil {
+		return managementClientForCluster(oc.Ctx, oc.Client, cluster)
+	}
+	if strings.TrimSpace(group.Spec.SecretName) == "" {
+		return nil, fmt.Errorf("marklogicgroup %s/%s has no admin secret", group.Namespace, group.Name)
+	}
+	host := strings.TrimSpace(group.Spec.BootstrapHost)
+	if host == "" {
+		host = dynamicPodFQDN(group, group.Spec.Name+"-0")
+	}
+	username, password, err := readAdminCredentials(oc.Ctx, oc.Client, group.Namespace, group.Spec.SecretName)
+	if err != nil {
+		return nil, err
+	}
+	useTLS := group.Spec.Tls != nil && group.Spec.Tls.EnableOnDefaultAppServers
+	return NewClusterManagementClient(mlmanage.ClientOptions{
+		Host:               host,
+		Username:           username,
+		Password:           password,
+		UseTLS:             useTLS,
+		InsecureSkipVerify: useTLS,
+	}), nil
+}
+
+// owningCluster returns the MarklogicCluster that owns the group, or nil for a group created
+// on its own.
+func (oc *OperatorContext) owningCluster() (*marklogicv1.MarklogicCluster, error) {
+	group := oc.MarklogicGroup
+	for _, ownerRef := range group.OwnerReferences {
+		if ownerRef.Kind != "MarklogicCluster" {
+			continue
+		}
+		cluster := &marklogicv1.MarklogicCluster{}
+		if err := oc.Client.Get(oc.Ctx, types.NamespacedName{Name: ownerRef.Name, Namespace: group.Namespace}, cluster); err != nil {
+			return nil, err
+		}
+		return cluster, nil
+	}
+	return nil, nil
+}
+
+func (oc *OperatorContext) patchDecommissionStatus(patchClient client.Patch, original *marklogicv1.MarklogicGroupStatus, res result.ReconcileResult) result.ReconcileResult {
+	if reflect.DeepEqual(*original, oc.MarklogicGroup.Status) {
+		return res
+	}
+	if err := oc.Client.Status().Patch(oc.Ctx, oc.MarklogicGroup, patchClient); err != nil {
+		oc.ReqLogger.Error(err, "Failed to update MarkLogicGroup decommission status")
+		return result.Error(err)
+	}
+	return res
+}
+
+func setDecommissionCondition(status *marklogicv1.MarklogicGroupStatus, generation int64, conditionStatus metav1.ConditionStatus, reason, message string, now metav1.Time) {
+	apimeta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               string(marklogicv1.ServerDecommission),
+		Status:             conditionStatus,
+		ObservedGeneration: generation,
+		Reason:             reason,
+		Message:            message,
+		LastTransitionTime: now,
+	})
+}
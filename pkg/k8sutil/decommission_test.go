@@ -0,0 +1,306 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const decommissionTestDomain = ".dnode.default.svc.cluster.local"
+
+func newDecommissionTestContext(t *testing.T, desired, current int32) (*OperatorContext, *appsv1.StatefulSet) {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := marklogicv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add marklogic scheme: %v", err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add core scheme: %v", err)
+	}
+	if err := appsv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add apps scheme: %v", err)
+	}
+	group := &marklogicv1.MarklogicGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "dnode",
+			Namespace:       "default",
+			OwnerReferences: []metav1.OwnerReference{{APIVersion: "marklogic.progress.com/v1", Kind: "MarklogicCluster", Name: "ml", UID: "ml-uid"}},
+		},
+		Spec: marklogicv1.MarklogicGroupSpec{
+			Name:          "dnode",
+			Replicas:      int32Ptr(desired),
+			ClusterDomain: "cluster.local",
+			GroupConfig:   &marklogicv1.GroupConfig{Name: "Default"},
+		},
+	}
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "dnode", Namespace: "default"},
+		Spec:       appsv1.StatefulSetSpec{Replicas: int32Ptr(current)},
+	}
+	objects := []client.Object{
+		group,
+		sts,
+		&marklogicv1.MarklogicCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+			Spec: marklogicv1.MarklogicClusterSpec{
+				ClusterDomain:   "cluster.local",
+				MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "dnode", IsBootstrap: true}},
+			},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "ml-admin", Namespace: "default"},
+			Data:       map[string][]byte{"username": []byte("admin"), "password": []byte("admin")},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithStatusSubresource(&marklogicv1.MarklogicGroup{}).
+		WithObjects(objects...).
+		Build()
+	oc := &OperatorContext{
+		Ctx:            context.Background(),
+		Client:         fakeClient,
+		Scheme:         scheme,
+		MarklogicGroup: group,
+		Recorder:       record.NewFakeRecorder(20),
+	}
+	return oc, sts
+}
+
+// decommissionMembers lists dnode-0..n-1 as online members, leaving out the hosts in left.
+func decommissionMembers(n int, left map[string]bool) func(string) ([]mlmanage.GroupHost, error) {
+	return func(groupName string) ([]mlmanage.GroupHost, error) {
+		var hosts []mlmanage.GroupHost
+		for i := 0; i < n; i++ {
+			name := fmt.Sprintf("dnode-%d%s", i, decommissionTestDomain)
+			if !left[name] {
+				hosts = append(hosts, mlmanage.GroupHost{Name: name, HostID: name, Online: true})
+			}
+		}
+		return hosts, nil
+	}
+}
+
+func TestReconcileScaleDownEvacuatesBeforeShrinking(t *testing.T) {
+	oc, sts := newDecommissionTestContext(t, 2, 3)
+	departing := "dnode-2" + decommissionTestDomain
+	left := map[string]bool{}
+	hosts := &stubDynamicManagementClient{
+		listGroupFn: decommissionMembers(3, left),
+		leaveFn: func(hostFQDN string) error {
+			left[hostFQDN] = true
+			return nil
+		},
+	}
+	stub := &stubDatabaseClient{
+		forests: map[string]mlmanage.ForestInfo{
+			"orders-dnode-2-1": {Exists: true, Host: departing, Database: "orders", Replicas: []mlmanage.ForestReplica{{Name: "orders-dnode-2-1-replica-1", Host: "dnode-0" + decommissionTestDomain}}},
+			"orders-dnode-2-2": {Exists: true, Host: departing, Database: "orders"},
+		},
+	}
+	useStubClusterManagementClient(t, managementStub{HostClient: hosts, ForestClient: stub})
+	group := oc.MarklogicGroup
+
+	if res := oc.ReconcileScaleDown(); !res.Completed() {
+		t.Fatal("expected a requeue while forests are migrated")
+	}
+	decommission := group.Status.Decommission
+	if decommission == nil || decommission.PodName != "dnode-2" || decommission.Phase != marklogicv1.DecommissionPhaseEvacuating {
+		t.Fatalf("unexpected decommission status %+v", decommission)
+	}
+	if got := stub.forests["orders-dnode-2-1"].Host; got != "dnode-1"+decommissionTestDomain {
+		t.Fatalf("expected the forest to avoid the host of its replica, got %s", got)
+	}
+	if got := stub.forests["orders-dnode-2-2"].Host; got != "dnode-0"+decommissionTestDomain {
+		t.Fatalf("expected the second forest on the least loaded host, got %s", got)
+	}
+	if replicas := decommissionReplicas(group, sts); replicas == nil || *replicas != 3 {
+		t.Fatalf("expected the StatefulSet to be held at 3 replicas, got %v", replicas)
+	}
+	if !apimeta.IsStatusConditionTrue(group.Status.Conditions, string(marklogicv1.ServerDecommission)) {
+		t.Fatalf("expected Decommission=True, got %+v", group.Status.Conditions)
+	}
+
+	oc.ReconcileScaleDown()
+	if group.Status.Decommission.Phase != marklogicv1.DecommissionPhaseRemovingHost || !left[departing] {
+		t.Fatalf("expected the empty host to leave the cluster, got %+v", group.Status.Decommission)
+	}
+
+	oc.ReconcileScaleDown()
+	if group.Status.Decommission.Phase != marklogicv1.DecommissionPhaseHostRemoved {
+		t.Fatalf("expected the host to be removed, got %+v", group.Status.Decommission)
+	}
+	replicas := decommissionReplicas(group, sts)
+	if replicas == nil || *replicas != 2 {
+		t.Fatalf("expected the StatefulSet to shrink to 2 replicas, got %v", replicas)
+	}
+
+	sts.Spec.Replicas = replicas
+	if err := oc.Client.Update(context.Background(), sts); err != nil {
+		t.Fatalf("failed to update statefulset: %v", err)
+	}
+	if res := oc.ReconcileScaleDown(); res.Completed() {
+		t.Fatal("expected the handler to continue once the scale-down is complete")
+	}
+	if group.Status.Decommission != nil {
+		t.Fatalf("expected the decommission status to be cleared, got %+v", group.Status.Decommission)
+	}
+	cond := apimeta.FindStatusCondition(group.Status.Conditions, string(marklogicv1.ServerDecommission))
+	if cond == nil || cond.Status != metav1.ConditionFalse || cond.Reason != decommissionReasonCompleted {
+		t.Fatalf("expected Decommission=False/Completed, got %+v", cond)
+	}
+}
+
+func TestReconcileScaleDownCountsForestsAlreadyOnTargets(t *testing.T) {
+	oc, _ := newDecommissionTestContext(t, 2, 3)
+	departing := "dnode-2" + decommissionTestDomain
+	hosts := &stubDynamicManagementClient{listGroupFn: decommissionMembers(3, nil)}
+	stub := &stubDatabaseClient{
+		forests: map[string]mlmanage.ForestInfo{
+			"orders-dnode-0-1": {Exists: true, Host: "dnode-0" + decommissionTestDomain, Database: "orders"},
+			"orders-dnode-0-2": {Exists: true, Host: "dnode-0" + decommissionTestDomain, Database: "orders"},
+			"orders-dnode-2-1": {Exists: true, Host: departing, Database: "orders"},
+			"orders-dnode-2-2": {Exists: true, Host: departing, Database: "orders"},
+		},
+	}
+	useStubClusterManagementClient(t, managementStub{HostClient: hosts, ForestClient: stub})
+
+	if res := oc.ReconcileScaleDown(); !res.Completed() {
+		t.Fatal("expected a requeue while forests are migrated")
+	}
+	for _, forest := range []string{"orders-dnode-2-1", "orders-dnode-2-2"} {
+		if got := stub.forests[forest].Host; got != "dnode-1"+decommissionTestDomain {
+			t.Fatalf("expected %s on the host without forests, got %s", forest, got)
+		}
+	}
+}
+
+func TestReconcileScaleDownBlocksWhenForestsCannotMove(t *testing.T) {
+	oc, sts := newDecommissionTestContext(t, 1, 2)
+	hosts := &stubDynamicManagementClient{
+		listGroupFn: decommissionMembers(2, nil),
+		leaveFn: func(hostFQDN string) error {
+			t.Fatalf("host %s must not leave while it holds forests", hostFQDN)
+			return nil
+		},
+	}
+	stub := &stubDatabaseClient{
+		forests: map[string]mlmanage.ForestInfo{
+			"orders-dnode-1-1": {Exists: true, Host: "dnode-1" + decommissionTestDomain, Replicas: []mlmanage.ForestReplica{{Name: "orders-dnode-1-1-replica-1", Host: "dnode-0" + decommissionTestDomain}}},
+		},
+	}
+	useStubClusterManagementClient(t, managementStub{HostClient: hosts, ForestClient: stub})
+	group := oc.MarklogicGroup
+
+	if res := oc.ReconcileScaleDown(); !res.Completed() {
+		t.Fatal("expected a requeue while the scale-down is blocked")
+	}
+	if group.Status.Decommission == nil || group.Status.Decommission.Phase != marklogicv1.DecommissionPhaseBlocked {
+		t.Fatalf("expected a blocked decommission, got %+v", group.Status.Decommission)
+	}
+	if replicas := decommissionReplicas(group, sts); replicas == nil || *replicas != 2 {
+		t.Fatalf("expected the StatefulSet to be held at 2 replicas, got %v", replicas)
+	}
+
+	// Raising replicas again cancels the scale-down.
+	group.Spec.Replicas = int32Ptr(2)
+	oc.ReconcileScaleDown()
+	cond := apimeta.FindStatusCondition(group.Status.Conditions, string(marklogicv1.ServerDecommission))
+	if group.Status.Decommission != nil || cond == nil || cond.Reason != decommissionReasonCancelled {
+		t.Fatalf("expected a cancelled decommission, got %+v / %+v", group.Status.Decommission, cond)
+	}
+}
+
+func TestReconcileScaleDownReissuesStuckMigrations(t *testing.T) {
+	oc, _ := newDecommissionTestContext(t, 1, 2)
+	departing := "dnode-1" + decommissionTestDomain
+	migrations := 0
+	hosts := &stubDynamicManagementClient{
+		listGroupFn: decommissionMembers(2, nil),
+		leaveFn: func(hostFQDN string) error {
+			t.Fatalf("host %s must not leave while it holds forests", hostFQDN)
+			return nil
+		},
+	}
+	stub := &stubDatabaseClient{
+		forests: map[string]mlmanage.ForestInfo{
+			"orders-dnode-1-1": {Exists: true, Host: departing, Database: "orders"},
+		},
+		migrateFn: func(forest, targetHost string) error {
+			migrations++
+			return nil
+		},
+	}
+	useStubClusterManagementClient(t, managementStub{HostClient: hosts, ForestClient: stub})
+	group := oc.MarklogicGroup
+	// The Management API accepts each migration, but the forest stays on the departing host.
+	stuck := func() {
+		info := stub.forests["orders-dnode-1-1"]
+		info.Host = departing
+		stub.forests["orders-dnode-1-1"] = info
+	}
+	expire := func() {
+		group.Status.Decommission.Forests[0].MigrationTime = &metav1.Time{Time: time.Now().Add(-decommissionMigrationTimeout)}
+		if err := oc.Client.Status().Update(context.Background(), group); err != nil {
+			t.Fatalf("failed to update group status: %v", err)
+		}
+	}
+
+	oc.ReconcileScaleDown()
+	stuck()
+	oc.ReconcileScaleDown()
+	if migrations != 1 || group.Status.Decommission.Phase != marklogicv1.DecommissionPhaseEvacuating {
+		t.Fatalf("expected the migration to be waited for, got %d migrations and %+v", migrations, group.Status.Decommission)
+	}
+	for attempt := 2; attempt <= decommissionMaxMigrationAttempts; attempt++ {
+		expire()
+		oc.ReconcileScaleDown()
+		stuck()
+		if migrations != attempt || group.Status.Decommission.Forests[0].Attempts != int32(attempt) {
+			t.Fatalf("expected migration %d to be issued, got %d and %+v", attempt, migrations, group.Status.Decommission.Forests)
+		}
+	}
+	expire()
+	oc.ReconcileScaleDown()
+	decommission := group.Status.Decommission
+	if migrations != decommissionMaxMigrationAttempts || decommission.Phase != marklogicv1.DecommissionPhaseBlocked || !strings.Contains(decommission.Message, "after 3 migrations") {
+		t.Fatalf("expected the scale-down to block after %d migrations, got %d and %+v", decommissionMaxMigrationAttempts, migrations, decommission)
+	}
+
+	// A forest that reached its target but does not open blocks as well.
+	stub.forests["orders-dnode-1-1"] = mlmanage.ForestInfo{Exists: true, Host: "dnode-0" + decommissionTestDomain, Database: "orders"}
+	stub.forestStates = map[string]string{"orders-dnode-1-1": "error"}
+	oc.ReconcileScaleDown()
+	if decommission := group.Status.Decommission; decommission.Phase != marklogicv1.DecommissionPhaseBlocked || !strings.Contains(decommission.Message, "is error on") {
+		t.Fatalf("expected the scale-down to block on the forest state, got %+v", decommission)
+	}
+}
+
+func TestApplyClusterRollupReportsDecommission(t *testing.T) {
+	t.Parallel()
+
+	status := &marklogicv1.MarklogicClusterStatus{}
+	rollups := []marklogicv1.MarklogicGroupRollup{{Name: "dnode", Replicas: 3, ReadyReplicas: 3, DecommissionPhase: marklogicv1.DecommissionPhaseBlocked}}
+	applyClusterRollup(status, rollups, []*marklogicv1.MarklogicGroup{{}}, 1)
+	if !apimeta.IsStatusConditionTrue(status.Conditions, string(marklogicv1.ClusterDecommission)) {
+		t.Fatalf("expected Decommission=True, got %+v", status.Conditions)
+	}
+	if !apimeta.IsStatusConditionTrue(status.Conditions, string(marklogicv1.ClusterDegraded)) {
+		t.Fatalf("expected a blocked scale-down to degrade the cluster, got %+v", status.Conditions)
+	}
+}
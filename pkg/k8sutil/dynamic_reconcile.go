@@ -15,6 +15,7 @@ import (
 	"time"
 
 	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/metrics"
 	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
 	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
 	batchv1 "k8s.io/api/batch/v1"
@@ -26,7 +27,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
-var NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+var NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.DynamicHostClient {
 	return mlmanage.NewClient(opts)
 }
 
@@ -143,7 +144,7 @@ func (oc *OperatorContext) ReconcileDynamicGroupConfig() result.ReconcileResult
 		return result.Done()
 	}
 
-	adminUser, adminPass, err := oc.readCredentialSecret(adminSecretName)
+	adminUser, adminPass, err := readAdminCredentials(oc.Ctx, oc.Client, oc.MarklogicGroup.Namespace, adminSecretName)
 	if err != nil {
 		if clusterOwnerTearingDown {
 			return oc.releaseDynamicFinalizersWithoutBootstrap()
@@ -358,7 +359,7 @@ func (oc *OperatorContext) ReconcileDynamicGroupConfig() result.ReconcileResult
 	return oc.reconcileDynamicLifecycle(groupClient, clusterName, groupName, tokenDuration, desiredReplicas)
 }
 
-func (oc *OperatorContext) reconcileDynamicLifecycle(groupClient mlmanage.Client, clusterName, groupName, tokenDuration string, desiredReplicas int32) result.ReconcileResult {
+func (oc *OperatorContext) reconcileDynamicLifecycle(groupClient mlmanage.DynamicHostClient, clusterName, groupName, tokenDuration string, desiredReplicas int32) result.ReconcileResult {
 	pods, err := oc.listDynamicPods()
 	if err != nil {
 		if statusErr := oc.setDynamicStatus(dynamicPhaseDegraded, dynamicReasonJoinFailed, fmt.Sprintf("failed to list dynamic pods: %v", err), true, true, true); statusErr != nil {
@@ -522,7 +523,7 @@ func (oc *OperatorContext) reconcileDynamicLifecycle(groupClient mlmanage.Client
 	return result.RequeueSoon(dynamicJoinRequeueSeconds)
 }
 
-func (oc *OperatorContext) reconcileDynamicDeletionLifecycle(groupClient mlmanage.Client, clusterName, groupName string, desiredReplicas int32, pods []corev1.Pod) result.ReconcileResult {
+func (oc *OperatorContext) reconcileDynamicDeletionLifecycle(groupClient mlmanage.DynamicHostClient, clusterName, groupName string, desiredReplicas int32, pods []corev1.Pod) result.ReconcileResult {
 	members, err := groupClient.ListGroupHosts(oc.Ctx, groupName)
 	if err != nil {
 		if oc.isOwningClusterDeletingOrGone() {
@@ -568,7 +569,7 @@ func (oc *OperatorContext) reconcileDynamicDeletionLifecycle(groupClient mlmanag
 	return result.Done()
 }
 
-func (oc *OperatorContext) reconcileDynamicScaleDown(groupClient mlmanage.Client, clusterName, groupName string, desiredReplicas int32, deleting bool, pods []corev1.Pod, members []mlmanage.GroupHost, hostStatuses []marklogicv1.DynamicHostStatus, localReadyReplicas, readyReplicas int32) result.ReconcileResult {
+func (oc *OperatorContext) reconcileDynamicScaleDown(groupClient mlmanage.DynamicHostClient, clusterName, groupName string, desiredReplicas int32, deleting bool, pods []corev1.Pod, members []mlmanage.GroupHost, hostStatuses []marklogicv1.DynamicHostStatus, localReadyReplicas, readyReplicas int32) result.ReconcileResult {
 	storageRequiresRemove := deleting || !isDynamicPVCBacked(oc.MarklogicGroup)
 	candidates := hostsAboveDesiredOrdinal(hostStatuses, desiredReplicas)
 
@@ -662,7 +663,7 @@ func (oc *OperatorContext) reconcileDynamicScaleDown(groupClient mlmanage.Client
 	return result.Continue()
 }
 
-func (oc *OperatorContext) reconcileDynamicStaleReplacement(groupClient mlmanage.Client, clusterName string, desiredReplicas int32, hostStatuses []marklogicv1.DynamicHostStatus, localReadyReplicas, readyReplicas int32, staleCandidates []marklogicv1.DynamicHostStatus) result.ReconcileResult {
+func (oc *OperatorContext) reconcileDynamicStaleReplacement(groupClient mlmanage.DynamicHostClient, clusterName string, desiredReplicas int32, hostStatuses []marklogicv1.DynamicHostStatus, localReadyReplicas, readyReplicas int32, staleCandidates []marklogicv1.DynamicHostStatus) result.ReconcileResult {
 	candidate := staleCandidates[0]
 	hostStatuses = setDynamicHostStatus(hostStatuses, candidate.PodName, candidate.Hostname, dynamicHostStateRemoving, "removing stale host membership before rejoin", candidate.HostID, incrementDynamicHostAttempts(hostStatuses, candidate.PodName))
 	if err := oc.setDynamicStatusDetailed(dynamicPhaseReconciling, "", fmt.Sprintf("removing stale dynamic host entry for %s", candidate.PodName), true, true, true, desiredReplicas, localReadyReplicas, readyReplicas, hostStatuses); err != nil {
@@ -794,7 +795,7 @@ func isDynamicRestartRecoveryExpectedHost(host marklogicv1.DynamicHostStatus) bo
 	}
 }
 
-func (oc *OperatorContext) reconcileDynamicRestartRecovery(groupClient mlmanage.Client, clusterName, groupName, tokenDuration string, desiredReplicas int32, pods []corev1.Pod, members []mlmanage.GroupHost, hostStatuses []marklogicv1.DynamicHostStatus, localReadyReplicas, readyReplicas int32, restartCandidates []corev1.Pod) result.ReconcileResult {
+func (oc *OperatorContext) reconcileDynamicRestartRecovery(groupClient mlmanage.DynamicHostClient, clusterName, groupName, tokenDuration string, desiredReplicas int32, pods []corev1.Pod, members []mlmanage.GroupHost, hostStatuses []marklogicv1.DynamicHostStatus, localReadyReplicas, readyReplicas int32, restartCandidates []corev1.Pod) result.ReconcileResult {
 	for _, candidate := range restartCandidates {
 		hostFQDN := dynamicPodFQDN(oc.MarklogicGroup, candidate.Name)
 		hostID := dynamicHostID(hostStatuses, candidate.Name)
@@ -1436,7 +1437,7 @@ func isValidDynamicTokenDuration(value string) bool {
 	return iso8601DurationRegex.MatchString(trimmed)
 }
 
-func (oc *OperatorContext) reconcileDynamicScaleUp(groupClient mlmanage.Client, clusterName, groupName, tokenDuration string, desiredReplicas int32) result.ReconcileResult {
+func (oc *OperatorContext) reconcileDynamicScaleUp(groupClient mlmanage.DynamicHostClient, clusterName, groupName, tokenDuration string, desiredReplicas int32) result.ReconcileResult {
 	pods, err := oc.listDynamicPods()
 	if err != nil {
 		if statusErr := oc.setDynamicStatus(dynamicPhaseDegraded, dynamicReasonJoinFailed, fmt.Sprintf("failed to list dynamic pods: %v", err), true, true, true); statusErr != nil {
@@ -1643,7 +1644,8 @@ func (oc *OperatorContext) handleDynamicRemoveFailure(hostStatuses []marklogicv1
 	return result.Done()
 }
 
-func (oc *OperatorContext) joinDynamicPod(groupClient mlmanage.Client, clusterName, groupName, hostFQDN, tokenDuration string) (mlmanage.GroupHost, error) {
+func (oc *OperatorContext) joinDynamicPod(groupClient mlmanage.DynamicHostClient, clusterName, groupName, hostFQDN, tokenDuration string) (member mlmanage.GroupHost, err error) {
+	defer func() { oc.countDynamicHostOperation(metrics.DynamicHostOperationJoin, err) }()
 	effectiveClusterName := clusterName
 	token, tokenHost, err := oc.requestDynamicTokenWithHostFallback(groupClient, effectiveClusterName, groupName, hostFQDN, tokenDuration)
 	if err != nil && isNoSuchClusterManagementError(err) {
@@ -1665,6 +1667,7 @@ func (oc *OperatorContext) joinDynamicPod(groupClient mlmanage.Client, clusterNa
 	err = groupClient.JoinDynamicHost(oc.Ctx, hostFQDN, token)
 	if err != nil && isTokenExpiredError(err) {
 		token, tokenErr := groupClient.RequestDynamicHostToken(oc.Ctx, effectiveClusterName, groupName, tokenHost, tokenDuration)
+		oc.countDynamicHostOperation(metrics.DynamicHostOperationToken, tokenErr)
 		if tokenErr != nil {
 			return mlmanage.GroupHost{}, tokenErr
 		}
@@ -1685,7 +1688,15 @@ func (oc *OperatorContext) joinDynamicPod(groupClient mlmanage.Client, clusterNa
 	return member, nil
 }
 
-func (oc *OperatorContext) requestDynamicTokenWithHostFallback(groupClient mlmanage.Client, clusterName, groupName, hostFQDN, tokenDuration string) (string, string, error) {
+func (oc *OperatorContext) countDynamicHostOperation(operation string, err error) {
+	namespace, group := "", ""
+	if oc.MarklogicGroup != nil {
+		namespace, group = oc.MarklogicGroup.Namespace, oc.MarklogicGroup.Name
+	}
+	metrics.CountDynamicHostOperation(namespace, group, operation, err)
+}
+
+func (oc *OperatorContext) requestDynamicTokenWithHostFallback(groupClient mlmanage.DynamicHostClient, clusterName, groupName, hostFQDN, tokenDuration string) (string, string, error) {
 	tokenHost := hostFQDN
 	token, err := groupClient.RequestDynamicHostToken(oc.Ctx, clusterName, groupName, tokenHost, tokenDuration)
 	if err != nil && isNoSuchHostManagementError(err) && oc.MarklogicGroup != nil {
@@ -1695,11 +1706,12 @@ func (oc *OperatorContext) requestDynamicTokenWithHostFallback(groupClient mlman
 			token, err = groupClient.RequestDynamicHostToken(oc.Ctx, clusterName, groupName, tokenHost, tokenDuration)
 		}
 	}
+	oc.countDynamicHostOperation(metrics.DynamicHostOperationToken, err)
 
 	return token, tokenHost, err
 }
 
-func (oc *OperatorContext) resolveDynamicClusterNameCandidates(groupClient mlmanage.Client, currentClusterName string) []string {
+func (oc *OperatorContext) resolveDynamicClusterNameCandidates(groupClient mlmanage.DynamicHostClient, currentClusterName string) []string {
 	candidates := make([]string, 0, 4)
 	seen := make(map[string]struct{})
 
@@ -1736,8 +1748,9 @@ func appendUniqueDynamicClusterCandidate(candidates []string, seen map[string]st
 	return append(candidates, candidate)
 }
 
-func (oc *OperatorContext) removeDynamicHostWithClusterFallback(groupClient mlmanage.Client, clusterName, hostID string) error {
-	err := groupClient.RemoveDynamicHost(oc.Ctx, clusterName, hostID)
+func (oc *OperatorContext) removeDynamicHostWithClusterFallback(groupClient mlmanage.DynamicHostClient, clusterName, hostID string) (err error) {
+	defer func() { oc.countDynamicHostOperation(metrics.DynamicHostOperationRemove, err) }()
+	err = groupClient.RemoveDynamicHost(oc.Ctx, clusterName, hostID)
 	if err == nil || !isNoSuchClusterManagementError(err) {
 		return err
 	}
@@ -1757,7 +1770,12 @@ func (oc *OperatorContext) removeDynamicHostWithClusterFallback(groupClient mlma
 }
 
 func (oc *OperatorContext) listDynamicPods() ([]corev1.Pod, error) {
-	labels := getSelectorLabelsByComponent(oc.MarklogicGroup.Spec.Name, true)
+	return oc.listGroupPods(true)
+}
+
+// listGroupPods returns the pods of the group StatefulSet ordered by ordinal.
+func (oc *OperatorContext) listGroupPods(isDynamic bool) ([]corev1.Pod, error) {
+	labels := getSelectorLabelsByComponent(oc.MarklogicGroup.Spec.Name, isDynamic)
 	podList := &corev1.PodList{}
 	if err := oc.Client.List(oc.Ctx, podList, client.InNamespace(oc.MarklogicGroup.Namespace), client.MatchingLabels(labels)); err != nil {
 		return nil, err
@@ -16,6 +16,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// stubDynamicManagementClient stands in for the hosts and groups of a cluster.
 type stubDynamicManagementClient struct {
 	requestTokenFn      func(clusterName, groupName, hostFQDN, duration string) (string, error)
 	joinFn              func(hostFQDN, token string) error
@@ -23,9 +24,17 @@ type stubDynamicManagementClient struct {
 	resolveNameFn       func() (string, error)
 	resolveCandidatesFn func() ([]string, error)
 	removeFn            func(clusterName, hostID string) error
+	listHostsFn         func() ([]mlmanage.HostStatus, error)
+	// leaveFn observes hosts leaving the cluster.
+	leaveFn         func(hostFQDN string) error
+	groups          map[string]bool
+	groupProperties map[string]map[string]any
 }
 
 func (s *stubDynamicManagementClient) ListHostsStatus(ctx context.Context) ([]mlmanage.HostStatus, error) {
+	if s.listHostsFn != nil {
+		return s.listHostsFn()
+	}
 	return nil, nil
 }
 
@@ -34,10 +43,14 @@ func (s *stubDynamicManagementClient) GetHostGroupName(ctx context.Context, host
 }
 
 func (s *stubDynamicManagementClient) GetGroup(ctx context.Context, groupName string) (mlmanage.GroupInfo, error) {
-	return mlmanage.GroupInfo{}, nil
+	return mlmanage.GroupInfo{Exists: s.groups[groupName]}, nil
 }
 
 func (s *stubDynamicManagementClient) CreateGroup(ctx context.Context, groupName string) error {
+	if s.groups == nil {
+		s.groups = map[string]bool{}
+	}
+	s.groups[groupName] = true
 	return nil
 }
 
@@ -145,6 +158,21 @@ func (s *stubDynamicManagementClient) RemoveDynamicHost(ctx context.Context, clu
 	return nil
 }
 
+func (s *stubDynamicManagementClient) LeaveCluster(ctx context.Context, hostFQDN string) error {
+	if s.leaveFn != nil {
+		return s.leaveFn(hostFQDN)
+	}
+	return nil
+}
+
+func (s *stubDynamicManagementClient) UpdateGroupProperties(ctx context.Context, groupName string, properties map[string]any) error {
+	if s.groupProperties == nil {
+		s.groupProperties = map[string]map[string]any{}
+	}
+	s.groupProperties[groupName] = properties
+	return nil
+}
+
 func TestJoinDynamicPodSuccess(t *testing.T) {
 	oc := &OperatorContext{Ctx: context.Background()}
 
@@ -0,0 +1,402 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// failoverPollInterval is how often forest states are read; failover happens inside
+	// MarkLogic without any Kubernetes event.
+	failoverPollInterval = 60 * time.Second
+
+	defaultFailoverTopologyKey = corev1.LabelTopologyZone
+	failoverReplicaInfix       = "-failover-"
+
+	// MarkLogic forest states.
+	forestStateOpen            = "open"
+	forestStateSyncReplicating = "sync replicating"
+
+	failoverReasonUnavailable = "ClusterUnavailable"
+	failoverReasonProtected   = "Protected"
+)
+
+func failoverEnabled(cr *marklogicv1.MarklogicGroup) bool {
+	return !cr.Spec.IsDynamic && cr.Spec.Failover != nil && cr.Spec.Failover.Enabled
+}
+
+// requeueForFailover polls groups with a failover policy so the forest states stay current.
+func requeueForFailover(cr *marklogicv1.MarklogicGroup, res reconcile.Result, err error) (reconcile.Result, error) {
+	if err == nil && failoverEnabled(cr) && res.RequeueAfter == 0 {
+		res.RequeueAfter = failoverPollInterval
+	}
+	return res, err
+}
+
+// failoverTopologyKey is the label zones are read from: the policy's key, else the key the
+// group spreads its pods by.
+func failoverTopologyKey(cr *marklogicv1.MarklogicGroup) string {
+	if key := strings.TrimSpace(cr.Spec.Failover.TopologyKey); key != "" {
+		return key
+	}
+	if len(cr.Spec.TopologySpreadConstraints) > 0 && cr.Spec.TopologySpreadConstraints[0].TopologyKey != "" {
+		return cr.Spec.TopologySpreadConstraints[0].TopologyKey
+	}
+	return defaultFailoverTopologyKey
+}
+
+func failoverReplicaName(forest string, n int) string {
+	return fmt.Sprintf("%s%s%d", forest, failoverReplicaInfix, n)
+}
+
+// failoverMasterName returns the forest a replica created by the failover policy protects.
+func failoverMasterName(replica string) (string, bool) {
+	i := strings.LastIndex(replica, failoverReplicaInfix)
+	if i <= 0 {
+		return "", false
+	}
+	if _, err := strconv.Atoi(replica[i+len(failoverReplicaInfix):]); err != nil {
+		return "", false
+	}
+	return replica[:i], true
+}
+
+func isFailoverReplica(forest, replica string) bool {
+	master, ok := failoverMasterName(replica)
+	return ok && master == forest
+}
+
+// ReconcileFailover keeps the failover replicas asked for by the group's failover policy
+// on the forests whose master is on a host of the group and reports their state.
+func (oc *OperatorContext) ReconcileFailover() result.ReconcileResult {
+	cr := oc.MarklogicGroup
+	patchClient := client.MergeFrom(cr.DeepCopy())
+	original := cr.Status.DeepCopy()
+	if !failoverEnabled(cr) {
+		cr.Status.Failover = nil
+		apimeta.RemoveStatusCondition(&cr.Status.Conditions, string(marklogicv1.GroupFailoverReady))
+		return oc.patchFailoverStatus(patchClient, original, result.Continue())
+	}
+
+	mc, err := oc.groupManagementClient()
+	if err == nil {
+		err = oc.syncFailover(mc)
+	}
+	if err != nil {
+		oc.ReqLogger.Error(err, "Failed to reconcile forest failover")
+		setFailoverCondition(&cr.Status, cr.Generation, metav1.ConditionFalse, failoverReasonUnavailable, err.Error())
+	}
+	return oc.patchFailoverStatus(patchClient, original, result.Continue())
+}
+
+func (oc *OperatorContext) syncFailover(mc hostForestClient) error {
+	cr := oc.MarklogicGroup
+	policy := cr.Spec.Failover
+	topologyKey := failoverTopologyKey(cr)
+
+	members, err := mc.ListGroupHosts(oc.Ctx, resolvedMarkLogicGroupName(cr))
+	if err != nil {
+		return err
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].Name < members[j].Name })
+	placement := &failoverPlacement{zones: map[string]string{}, load: map[string]int{}}
+	hostForests := map[string][]string{}
+	for _, member := range members {
+		placement.zones[member.Name] = oc.hostZone(member.Name, topologyKey)
+		if member.Online {
+			placement.hosts = append(placement.hosts, member.Name)
+		}
+		forests, err := mc.ListHostForests(oc.Ctx, member.Name)
+		if err != nil {
+			return err
+		}
+		hostForests[member.Name] = forests
+		placement.load[member.Name] = len(forests)
+	}
+
+	previous := map[string]marklogicv1.ForestFailoverPhase{}
+	if cr.Status.Failover != nil {
+		for _, forest := range cr.Status.Failover.Forests {
+			previous[forest.Name] = forest.Phase
+		}
+	}
+	status := &marklogicv1.FailoverStatus{TopologyKey: topologyKey}
+	var orphans []string
+	for _, member := range members {
+		for _, name := range hostForests[member.Name] {
+			info, err := mc.GetForest(oc.Ctx, name)
+			if err != nil {
+				return err
+			}
+			if !info.Exists || info.Host != member.Name {
+				continue
+			}
+			if info.Database == "" {
+				if _, ok := failoverMasterName(name); ok {
+					orphans = append(orphans, name)
+				}
+				continue
+			}
+			if len(policy.Databases) > 0 && !slices.Contains(policy.Databases, info.Database) {
+				continue
+			}
+			forest, err := oc.protectForest(mc, name, info, placement)
+			if err != nil {
+				return err
+			}
+			if forest.Phase == marklogicv1.ForestFailoverFailedOver && previous[name] != marklogicv1.ForestFailoverFailedOver {
+				oc.Recorder.Eventf(cr, corev1.EventTypeWarning, "ForestFailedOver", "Forest %s on %s failed over: %s", name, member.Name, forest.Message)
+			}
+			if forest.Phase == marklogicv1.ForestFailoverProtected {
+				status.ProtectedForests++
+			}
+			status.Forests = append(status.Forests, forest)
+		}
+	}
+	if err := oc.deleteOrphanedFailoverReplicas(mc, orphans); err != nil {
+		return err
+	}
+
+	cr.Status.Failover = status
+	reason, message := failoverReadiness(status)
+	conditionStatus := metav1.ConditionFalse
+	if reason == failoverReasonProtected {
+		conditionStatus = metav1.ConditionTrue
+	}
+	setFailoverCondition(&cr.Status, cr.Generation, conditionStatus, reason, message)
+	return nil
+}
+
+// failoverPlacement tracks where replicas may go during one reconcile. Hosts are the
+// online hosts of the group in name order; load counts the forests on each host.
+type failoverPlacement struct {
+	hosts []string
+	zones map[string]string
+	load  map[string]int
+}
+
+// pick returns the host for a new copy of a forest: a host holding no copy yet, preferring
+// a zone holding no copy, then the host with the fewest forests. With requireZoneSpread,
+// only hosts in a zone without a copy qualify.
+func (p *failoverPlacement) pick(copies []string, requireZoneSpread bool) string {
+	usedZones := map[string]bool{}
+	for _, host := range copies {
+		if zone := p.zones[host]; zone != "" {
+			usedZones[zone] = true
+		}
+	}
+	best, bestNewZone := "", false
+	for _, host := range p.hosts {
+		if slices.Contains(copies, host) {
+			continue
+		}
+		zone := p.zones[host]
+		newZone := zone != "" && !usedZones[zone]
+		if requireZoneSpread && !newZone {
+			continue
+		}
+		if best == "" || (newZone && !bestNewZone) || (newZone == bestNewZone && p.load[host] < p.load[best]) {
+			best, bestNewZone = host, newZone
+		}
+	}
+	return best
+}
+
+// protectForest brings the replicas of a master forest to the count of the failover policy
+// and reads the state of the forest and its replicas. Replicas configured by other means
+// are kept and counted; only replicas created by the policy are removed.
+func (oc *OperatorContext) protectForest(mc mlmanage.ForestClient, name string, info mlmanage.ForestInfo, placement *failoverPlacement) (marklogicv1.ForestFailoverStatus, error) {
+	cr := oc.MarklogicGroup
+	policy := cr.Spec.Failover
+	desired := int(policy.Replicas)
+	forest := marklogicv1.ForestFailoverStatus{Name: name, Database: info.Database, Host: info.Host, Zone: placement.zones[info.Host]}
+
+	replicas := slices.Clone(info.Replicas)
+	changed := false
+	var removed []string
+	for i := len(replicas) - 1; i >= 0 && len(replicas) > desired; i-- {
+		if !isFailoverReplica(name, replicas[i].Name) {
+			continue
+		}
+		removed = append(removed, replicas[i].Name)
+		replicas = slices.Delete(replicas, i, i+1)
+		changed = true
+	}
+	for n := 1; len(replicas) < desired; n++ {
+		replicaName := failoverReplicaName(name, n)
+		if slices.ContainsFunc(replicas, func(replica mlmanage.ForestReplica) bool { return replica.Name == replicaName }) {
+			continue
+		}
+		copies := []string{info.Host}
+		for _, replica := range replicas {
+			copies = append(copies, replica.Host)
+		}
+		existing, err := mc.GetForest(oc.Ctx, replicaName)
+		if err != nil {
+			return forest, err
+		}
+		host := existing.Host
+		if !existing.Exists {
+			if host = placement.pick(copies, policy.RequireZoneSpread); host == "" {
+				forest.Message = fmt.Sprintf("%d of %d replicas placed: no host left without a copy of the forest", len(replicas), desired)
+				if policy.RequireZoneSpread {
+					forest.Message = fmt.Sprintf("%d of %d replicas placed: no host left in a zone without a copy of the forest", len(replicas), desired)
+				}
+				break
+			}
+			if err := mc.CreateForest(oc.Ctx, mlmanage.ForestOptions{Name: replicaName, Host: host}); err != nil {
+				return forest, err
+			}
+			oc.Recorder.Eventf(cr, corev1.EventTypeNormal, "FailoverReplicaCreated", "Created replica %s of forest %s on %s", replicaName, name, host)
+		}
+		placement.load[host]++
+		replicas = append(replicas, mlmanage.ForestReplica{Name: replicaName, Host: host})
+		changed = true
+	}
+	if changed {
+		if err := mc.SetForestReplicas(oc.Ctx, name, replicas); err != nil {
+			return forest, err
+		}
+		for _, replicaName := range removed {
+			if err := mc.DeleteForest(oc.Ctx, replicaName); err != nil {
+				return forest, err
+			}
+		}
+	}
+
+	state, err := mc.GetForestState(oc.Ctx, name)
+	if err != nil {
+		return forest, err
+	}
+	forest.State = state
+	synchronized, servingReplica := true, ""
+	for _, replica := range replicas {
+		replicaState, err := mc.GetForestState(oc.Ctx, replica.Name)
+		if err != nil {
+			return forest, err
+		}
+		forest.Replicas = append(forest.Replicas, marklogicv1.FailoverReplicaStatus{
+			Name:  replica.Name,
+			Host:  replica.Host,
+			Zone:  placement.zones[replica.Host],
+			State: replicaState,
+		})
+		if replicaState == forestStateOpen {
+			servingReplica = replica.Name
+		} else if replicaState != forestStateSyncReplicating {
+			synchronized = false
+		}
+	}
+	switch {
+	case servingReplica != "":
+		forest.Phase = marklogicv1.ForestFailoverFailedOver
+		forest.Message = fmt.Sprintf("replica %s is serving the forest, which is %s", servingReplica, state)
+	case len(replicas) < desired:
+		forest.Phase = marklogicv1.ForestFailoverUnprotected
+	case state == forestStateOpen && synchronized:
+		forest.Phase = marklogicv1.ForestFailoverProtected
+	default:
+		forest.Phase = marklogicv1.ForestFailoverSynchronizing
+	}
+	return forest, nil
+}
+
+// deleteOrphanedFailoverReplicas deletes replicas the policy created for forests that no
+// longer exist, such as the forests of a deleted database.
+func (oc *OperatorContext) deleteOrphanedFailoverReplicas(mc mlmanage.ForestClient, replicas []string) error {
+	for _, replica := range replicas {
+		master, _ := failoverMasterName(replica)
+		info, err := mc.GetForest(oc.Ctx, master)
+		if err != nil {
+			return err
+		}
+		if info.Exists {
+			continue
+		}
+		if err := mc.DeleteForest(oc.Ctx, replica); err != nil {
+			return err
+		}
+		oc.Recorder.Eventf(oc.MarklogicGroup, corev1.EventTypeNormal, "FailoverReplicaDeleted", "Deleted replica %s of deleted forest %s", replica, master)
+	}
+	return nil
+}
+
+// hostZone reads the zone of a MarkLogic host from the labels of its pod, or from the node
+// the pod runs on. The zone is unknown when neither carries the topology key.
+func (oc *OperatorContext) hostZone(hostName, topologyKey string) string {
+	pod := &corev1.Pod{}
+	if err := oc.Client.Get(oc.Ctx, types.NamespacedName{Name: hostnameToPodName(hostName), Namespace: oc.MarklogicGroup.Namespace}, pod); err != nil {
+		return ""
+	}
+	if zone := pod.Labels[topologyKey]; zone != "" {
+		return zone
+	}
+	if pod.Spec.NodeName == "" {
+		return ""
+	}
+	node := &corev1.Node{}
+	if err := oc.Client.Get(oc.Ctx, types.NamespacedName{Name: pod.Spec.NodeName}, node); err != nil {
+		oc.ReqLogger.V(1).Info("Zone of node unavailable", "node", pod.Spec.NodeName, "error", err.Error())
+		return ""
+	}
+	return node.Labels[topologyKey]
+}
+
+// failoverReadiness summarizes the forests for the FailoverReady condition, reporting the
+// most severe phase first.
+func failoverReadiness(status *marklogicv1.FailoverStatus) (string, string) {
+	for _, phase := range []marklogicv1.ForestFailoverPhase{
+		marklogicv1.ForestFailoverFailedOver,
+		marklogicv1.ForestFailoverUnprotected,
+		marklogicv1.ForestFailoverSynchronizing,
+	} {
+		var forests []string
+		for _, forest := range status.Forests {
+			if forest.Phase == phase {
+				forests = append(forests, forest.Name)
+			}
+		}
+		if len(forests) > 0 {
+			return string(phase), fmt.Sprintf("%d forest(s) %s: %s", len(forests), strings.ToLower(string(phase)), strings.Join(forests, ", "))
+		}
+	}
+	return failoverReasonProtected, fmt.Sprintf("%d forest(s) protected", status.ProtectedForests)
+}
+
+func setFailoverCondition(status *marklogicv1.MarklogicGroupStatus, generation int64, conditionStatus metav1.ConditionStatus, reason, message string) {
+	apimeta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               string(marklogicv1.GroupFailoverReady),
+		Status:             conditionStatus,
+		ObservedGeneration: generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+func (oc *OperatorContext) patchFailoverStatus(patchClient client.Patch, original *marklogicv1.MarklogicGroupStatus, res result.ReconcileResult) result.ReconcileResult {
+	if reflect.DeepEqual(*original, oc.MarklogicGroup.Status) {
+		return res
+	}
+	if err := oc.Client.Status().Patch(oc.Ctx, oc.MarklogicGroup, patchClient); err != nil {
+		oc.ReqLogger.Error(err, "Failed to update MarkLogicGroup failover status")
+		return result.Error(err)
+	}
+	return res
+}
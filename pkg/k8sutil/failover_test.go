@@ -0,0 +1,134 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"strings"
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+// newFailoverTestContext runs dnode-0 and dnode-2 in zone a, labelled on the pod, and
+// dnode-1 on a node in zone b.
+func newFailoverTestContext(t *testing.T, policy *marklogicv1.ForestFailover) (*OperatorContext, *stubDatabaseClient) {
+	t.Helper()
+	oc, _ := newDecommissionTestContext(t, 3, 3)
+	oc.MarklogicGroup.Spec.Failover = policy
+	if err := oc.Client.Update(oc.Ctx, oc.MarklogicGroup); err != nil {
+		t.Fatalf("failed to update group: %v", err)
+	}
+	for _, pod := range []*corev1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "dnode-0", Namespace: "default", Labels: map[string]string{corev1.LabelTopologyZone: "a"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "dnode-1", Namespace: "default"}, Spec: corev1.PodSpec{NodeName: "node-b"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "dnode-2", Namespace: "default", Labels: map[string]string{corev1.LabelTopologyZone: "a"}}},
+	} {
+		if err := oc.Client.Create(oc.Ctx, pod); err != nil {
+			t.Fatalf("failed to create pod: %v", err)
+		}
+	}
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-b", Labels: map[string]string{corev1.LabelTopologyZone: "b"}}}
+	if err := oc.Client.Create(oc.Ctx, node); err != nil {
+		t.Fatalf("failed to create node: %v", err)
+	}
+	stub := &stubDatabaseClient{}
+	useStubClusterManagementClient(t, managementStub{
+		HostClient:   &stubDynamicManagementClient{listGroupFn: decommissionMembers(3, nil)},
+		ForestClient: stub,
+	})
+	return oc, stub
+}
+
+func TestReconcileFailoverPlacesReplicasInOtherZones(t *testing.T) {
+	oc, stub := newFailoverTestContext(t, &marklogicv1.ForestFailover{Enabled: true, Replicas: 1})
+	stub.forests = map[string]mlmanage.ForestInfo{
+		"orders-1": {Exists: true, Host: "dnode-0" + decommissionTestDomain, Database: "orders"},
+		"orders-2": {Exists: true, Host: "dnode-1" + decommissionTestDomain, Database: "orders"},
+	}
+
+	if res := oc.ReconcileFailover(); res.Completed() {
+		t.Fatal("expected the group reconcile to continue")
+	}
+	if replicas := stub.forests["orders-1"].Replicas; len(replicas) != 1 || replicas[0] != (mlmanage.ForestReplica{Name: "orders-1-failover-1", Host: "dnode-1" + decommissionTestDomain}) {
+		t.Fatalf("expected the replica of orders-1 in zone b, got %+v", replicas)
+	}
+	if replicas := stub.forests["orders-2"].Replicas; len(replicas) != 1 || replicas[0].Host != "dnode-2"+decommissionTestDomain {
+		t.Fatalf("expected the replica of orders-2 on the least loaded host in zone a, got %+v", replicas)
+	}
+	status := oc.MarklogicGroup.Status.Failover
+	if status == nil || status.ProtectedForests != 2 || status.TopologyKey != corev1.LabelTopologyZone {
+		t.Fatalf("expected both forests to be protected, got %+v", status)
+	}
+	if forest := status.Forests[0]; forest.Zone != "a" || forest.Replicas[0].Zone != "b" || forest.Replicas[0].State != forestStateSyncReplicating {
+		t.Fatalf("unexpected forest status %+v", forest)
+	}
+	if !apimeta.IsStatusConditionTrue(oc.MarklogicGroup.Status.Conditions, string(marklogicv1.GroupFailoverReady)) {
+		t.Fatalf("expected FailoverReady, got %+v", oc.MarklogicGroup.Status.Conditions)
+	}
+
+	stub.forestStates = map[string]string{"orders-1": "error", "orders-1-failover-1": forestStateOpen}
+	oc.ReconcileFailover()
+	condition := apimeta.FindStatusCondition(oc.MarklogicGroup.Status.Conditions, string(marklogicv1.GroupFailoverReady))
+	if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != string(marklogicv1.ForestFailoverFailedOver) {
+		t.Fatalf("expected the failed over forest to be reported, got %+v", condition)
+	}
+	var failedOver int
+	for len(oc.Recorder.(*record.FakeRecorder).Events) > 0 {
+		if event := <-oc.Recorder.(*record.FakeRecorder).Events; strings.HasPrefix(event, "Warning ForestFailedOver") {
+			failedOver++
+		}
+	}
+	if failedOver != 1 || len(stub.forests) != 4 {
+		t.Fatalf("expected one failover event and no new replicas, got %d events and forests %v", failedOver, stub.forests)
+	}
+}
+
+func TestReconcileFailoverRequiresZoneSpreadAndDeletesOrphans(t *testing.T) {
+	oc, stub := newFailoverTestContext(t, &marklogicv1.ForestFailover{Enabled: true, Replicas: 2, RequireZoneSpread: true, Databases: []string{"orders"}})
+	stub.forests = map[string]mlmanage.ForestInfo{
+		"orders-1": {Exists: true, Host: "dnode-0" + decommissionTestDomain, Database: "orders"},
+		"Security": {Exists: true, Host: "dnode-0" + decommissionTestDomain, Database: "Security"},
+		// Left behind by a deleted database.
+		"gone-1-failover-1": {Exists: true, Host: "dnode-2" + decommissionTestDomain},
+	}
+
+	oc.ReconcileFailover()
+	if _, ok := stub.forests["gone-1-failover-1"]; ok {
+		t.Fatal("expected the replica of a deleted forest to be removed")
+	}
+	if len(stub.forests["Security"].Replicas) != 0 {
+		t.Fatal("expected forests of other databases to be left alone")
+	}
+	replicas := stub.forests["orders-1"].Replicas
+	if len(replicas) != 1 || replicas[0].Host != "dnode-1"+decommissionTestDomain {
+		t.Fatalf("expected a single replica in zone b, got %+v", replicas)
+	}
+	status := oc.MarklogicGroup.Status.Failover
+	if len(status.Forests) != 1 || status.Forests[0].Phase != marklogicv1.ForestFailoverUnprotected || !strings.Contains(status.Forests[0].Message, "1 of 2") {
+		t.Fatalf("expected orders-1 to be unprotected, got %+v", status.Forests)
+	}
+
+	oc.MarklogicGroup.Spec.Failover.Replicas = 1
+	oc.MarklogicGroup.Spec.Failover.RequireZoneSpread = false
+	if err := oc.Client.Update(oc.Ctx, oc.MarklogicGroup); err != nil {
+		t.Fatalf("failed to update group: %v", err)
+	}
+	oc.ReconcileFailover()
+	if !apimeta.IsStatusConditionTrue(oc.MarklogicGroup.Status.Conditions, string(marklogicv1.GroupFailoverReady)) {
+		t.Fatalf("expected FailoverReady with one replica, got %+v", oc.MarklogicGroup.Status.Conditions)
+	}
+
+	oc.MarklogicGroup.Spec.Failover.Enabled = false
+	if err := oc.Client.Update(oc.Ctx, oc.MarklogicGroup); err != nil {
+		t.Fatalf("failed to update group: %v", err)
+	}
+	oc.ReconcileFailover()
+	if oc.MarklogicGroup.Status.Failover != nil || apimeta.FindStatusCondition(oc.MarklogicGroup.Status.Conditions, string(marklogicv1.GroupFailoverReady)) != nil {
+		t.Fatal("expected the failover status to be cleared once the policy is disabled")
+	}
+}
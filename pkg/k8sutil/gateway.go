@@ -0,0 +1,389 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	controllerClient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+)
+
+const (
+	gatewayAPIGroup = "gateway.networking.k8s.io"
+	// haproxyServiceName is the Service generateHAProxyService creates for the cluster.
+	haproxyServiceName = "marklogic-haproxy"
+
+	gatewayRoutesReasonApplied = "RoutesApplied"
+	gatewayRoutesReasonFailed  = "RouteFailed"
+)
+
+var (
+	httpRouteGVK = schema.GroupVersionKind{Group: gatewayAPIGroup, Version: "v1", Kind: "HTTPRoute"}
+	tcpRouteGVK  = schema.GroupVersionKind{Group: gatewayAPIGroup, Version: "v1alpha2", Kind: "TCPRoute"}
+)
+
+func gatewayEnabled(cr *marklogicv1.MarklogicCluster) bool {
+	haproxy := cr.Spec.HAProxy
+	return haproxy != nil && haproxy.Enabled && haproxy.Gateway != nil && haproxy.Gateway.Enabled
+}
+
+// gatewayListeners returns the ports HAProxy listens on, keyed by Service port name, the
+// same way generateHAProxyConfig binds them: the frontend of every App Server outside
+// path-based routing, the shared path-based frontend and every TCP port.
+func gatewayListeners(cr *marklogicv1.MarklogicCluster, config *HAProxyConfig) []corev1.ServicePort {
+	var ports []corev1.ServicePort
+	for _, key := range slices.Sorted(maps.Keys(config.FrontEndConfigMap)) {
+		frontend := config.FrontEndConfigMap[key]
+		ports = append(ports, corev1.ServicePort{Name: frontend.AppServerName, Port: int32(frontend.Port)})
+	}
+	if config.IsPathBased {
+		ports = append(ports, corev1.ServicePort{Name: "frontend", Port: cr.Spec.HAProxy.FrontendPort})
+	}
+	for _, key := range slices.Sorted(maps.Keys(config.TCPConfigMap)) {
+		tcpConfig := config.TCPConfigMap[key][0]
+		ports = append(ports, corev1.ServicePort{Name: tcpConfig.PortName, Port: int32(tcpConfig.Port)})
+	}
+	return ports
+}
+
+// gatewayServicePorts exposes the ports HAProxy listens on that the Service does not yet
+// carry, such as the App Servers and TCP ports of a group, so that every route has a
+// backend port to point at.
+func (cc *ClusterContext) gatewayServicePorts(existing []corev1.ServicePort) []corev1.ServicePort {
+	cr := cc.MarklogicCluster
+	if !gatewayEnabled(cr) {
+		return nil
+	}
+	takenPorts, takenNames := map[int32]bool{}, map[string]bool{}
+	for _, port := range existing {
+		takenPorts[port.Port] = true
+		takenNames[port.Name] = true
+	}
+	var ports []corev1.ServicePort
+	for _, listener := range gatewayListeners(cr, generateHAProxyConfig(cc.Ctx, cr)) {
+		if takenPorts[listener.Port] {
+			continue
+		}
+		name := listener.Name
+		if name == "" || takenNames[name] {
+			name = fmt.Sprintf("port-%d", listener.Port)
+		}
+		takenPorts[listener.Port] = true
+		takenNames[name] = true
+		ports = append(ports, corev1.ServicePort{
+			Name:       name,
+			Port:       listener.Port,
+			TargetPort: intstr.FromInt(int(listener.Port)),
+			Protocol:   corev1.ProtocolTCP,
+		})
+	}
+	return ports
+}
+
+func gatewayParentRef(parentRef marklogicv1.GatewayParentRef) map[string]any {
+	// group and kind are defaulted by the API server; setting them keeps the routes read
+	// back equal to the generated spec.
+	ref := map[string]any{"group": gatewayAPIGroup, "kind": "Gateway", "name": parentRef.Name}
+	if parentRef.Namespace != "" {
+		ref["namespace"] = parentRef.Namespace
+	}
+	return ref
+}
+
+// httpParentRefs renders the parentRefs of an HTTPRoute. A non-empty sectionName replaces
+// the listener of every parentRef; otherwise a parentRef without a sectionName or port is
+// attached to defaultPort when it is set.
+func httpParentRefs(gateway *marklogicv1.HAProxyGateway, sectionName string, defaultPort int32) []any {
+	refs := make([]any, 0, len(gateway.ParentRefs))
+	for _, parentRef := range gateway.ParentRefs {
+		ref := gatewayParentRef(parentRef)
+		switch {
+		case sectionName != "":
+			ref["sectionName"] = sectionName
+		case parentRef.SectionName != "" || parentRef.Port != 0:
+			if parentRef.SectionName != "" {
+				ref["sectionName"] = parentRef.SectionName
+			}
+			if parentRef.Port != 0 {
+				ref["port"] = int64(parentRef.Port)
+			}
+		case defaultPort != 0:
+			ref["port"] = int64(defaultPort)
+		}
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+// tcpParentRefs attaches a TCPRoute to the listener on its port of every parentRef.
+func tcpParentRefs(gateway *marklogicv1.HAProxyGateway, port int32) []any {
+	refs := make([]any, 0, len(gateway.ParentRefs))
+	for _, parentRef := range gateway.ParentRefs {
+		ref := gatewayParentRef(parentRef)
+		ref["port"] = int64(port)
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+// haproxyBackendRef points a route at the HAProxy Service, again with the defaulted fields set.
+func haproxyBackendRef(port int32) map[string]any {
+	return map[string]any{"group": "", "kind": "Service", "name": haproxyServiceName, "port": int64(port), "weight": int64(1)}
+}
+
+func pathPrefixMatch(path string) []any {
+	return []any{map[string]any{"path": map[string]any{"type": "PathPrefix", "value": path}}}
+}
+
+func generateRoute(gvk schema.GroupVersionKind, name, namespace string, labels, annotations map[string]string, owner metav1.OwnerReference, spec map[string]any) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	obj.SetName(name)
+	obj.SetNamespace(namespace)
+	obj.SetLabels(labels)
+	if len(annotations) > 0 {
+		obj.SetAnnotations(annotations)
+	}
+	obj.SetOwnerReferences([]metav1.OwnerReference{owner})
+	obj.Object["spec"] = spec
+	return obj
+}
+
+func gatewayHostnames(gateway *marklogicv1.HAProxyGateway, spec map[string]any) map[string]any {
+	if len(gateway.Hostnames) == 0 {
+		return spec
+	}
+	hostnames := make([]any, 0, len(gateway.Hostnames))
+	for _, hostname := range gateway.Hostnames {
+		hostnames = append(hostnames, hostname)
+	}
+	spec["hostnames"] = hostnames
+	return spec
+}
+
+// routeName names a route after its App Server, falling back to the HAProxy key when the
+// name is empty or already taken by another route.
+func routeName(cr *marklogicv1.MarklogicCluster, appServerName, key string, used map[string]bool) string {
+	name := fmt.Sprintf("%s-%s", cr.Name, defaultString(appServerName, key))
+	if used[name] {
+		name = fmt.Sprintf("%s-%s", cr.Name, key)
+	}
+	used[name] = true
+	return name
+}
+
+// generateGatewayRoutes returns the routes of the cluster. They are derived from
+// generateHAProxyConfig, so the App Servers and TCP ports of the groups are routed the way
+// HAProxy binds them: an App Server of a path-based group is a path prefix on the frontend
+// port, any other App Server has a port of its own.
+func (cc *ClusterContext) generateGatewayRoutes() []*unstructured.Unstructured {
+	cr := cc.MarklogicCluster
+	gateway := cr.Spec.HAProxy.Gateway
+	owner := marklogicClusterAsOwner(cr)
+	labels := map[string]string{}
+	for k, v := range gateway.Labels {
+		labels[k] = v
+	}
+	for k, v := range cc.GetClusterLabels(cr.GetObjectMeta().GetName()) {
+		labels[k] = v
+	}
+	httpSection := ""
+	if gateway.TLS != nil {
+		httpSection = gateway.TLS.SectionName
+	}
+	config := generateHAProxyConfig(cc.Ctx, cr)
+	used := map[string]bool{}
+
+	var routes []*unstructured.Unstructured
+	httpRoute := func(name, path string, port, listenerPort int32) {
+		spec := gatewayHostnames(gateway, map[string]any{
+			"parentRefs": httpParentRefs(gateway, httpSection, listenerPort),
+			"rules": []any{map[string]any{
+				"matches":     pathPrefixMatch(path),
+				"backendRefs": []any{haproxyBackendRef(port)},
+			}},
+		})
+		routes = append(routes, generateRoute(httpRouteGVK, name, cr.Namespace, labels, gateway.Annotations, owner, spec))
+	}
+	for _, key := range slices.Sorted(maps.Keys(config.FrontEndConfigMap)) {
+		frontend := config.FrontEndConfigMap[key]
+		port := int32(frontend.Port)
+		httpRoute(routeName(cr, frontend.AppServerName, key, used), "/", port, port)
+	}
+	for _, key := range slices.Sorted(maps.Keys(config.BackendConfigMap)) {
+		backend := config.BackendConfigMap[key][0]
+		if !backend.IsPathBased {
+			continue
+		}
+		httpRoute(routeName(cr, backend.AppServerName, key, used), backend.Path, cr.Spec.HAProxy.FrontendPort, 0)
+	}
+	if gateway.TLS != nil && gateway.TLS.RedirectHTTP {
+		spec := gatewayHostnames(gateway, map[string]any{
+			"parentRefs": httpParentRefs(gateway, "", 0),
+			"rules": []any{map[string]any{
+				"matches": pathPrefixMatch("/"),
+				"filters": []any{map[string]any{
+					"type":            "RequestRedirect",
+					"requestRedirect": map[string]any{"scheme": "https", "statusCode": int64(301)},
+				}},
+			}},
+		})
+		routes = append(routes, generateRoute(httpRouteGVK, cr.Name+"-https-redirect", cr.Namespace, labels, gateway.Annotations, owner, spec))
+	}
+	tcpPorts := map[int32]bool{}
+	for _, key := range slices.Sorted(maps.Keys(config.TCPConfigMap)) {
+		port := int32(config.TCPConfigMap[key][0].Port)
+		if tcpPorts[port] {
+			continue
+		}
+		tcpPorts[port] = true
+		spec := map[string]any{
+			"parentRefs": tcpParentRefs(gateway, port),
+			"rules":      []any{map[string]any{"backendRefs": []any{haproxyBackendRef(port)}}},
+		}
+		name := fmt.Sprintf("%s-tcp-%d", cr.Name, port)
+		routes = append(routes, generateRoute(tcpRouteGVK, name, cr.Namespace, labels, gateway.Annotations, owner, spec))
+	}
+	return routes
+}
+
+// applyRoute creates a route or corrects any drift from the generated spec, labels and
+// annotations.
+func applyRoute(ctx context.Context, c controllerClient.Client, logger logr.Logger, desired *unstructured.Unstructured) (bool, error) {
+	kind := desired.GetKind()
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(desired.GroupVersionKind())
+	err := c.Get(ctx, types.NamespacedName{Name: desired.GetName(), Namespace: desired.GetNamespace()}, current)
+	switch {
+	case apimeta.IsNoMatchError(err):
+		err = fmt.Errorf("gateway API %s CRD is not installed: %w", kind, err)
+		logger.Error(err, "Cannot create route", "name", desired.GetName())
+		return false, err
+	case apierrors.IsNotFound(err):
+		logger.Info("Creating Gateway API route", "kind", kind, "name", desired.GetName())
+		if err := c.Create(ctx, desired); err != nil {
+			logger.Error(err, "Route creation has failed", "kind", kind)
+			return false, err
+		}
+		return true, nil
+	case err != nil:
+		logger.Error(err, "Failed to get route", "kind", kind)
+		return false, err
+	}
+	if equality.Semantic.DeepEqual(current.Object["spec"], desired.Object["spec"]) &&
+		equality.Semantic.DeepEqual(current.GetLabels(), desired.GetLabels()) &&
+		equality.Semantic.DeepEqual(current.GetAnnotations(), desired.GetAnnotations()) {
+		return false, nil
+	}
+	logger.Info("Route is different from the spec, updating it", "kind", kind, "name", desired.GetName())
+	current.Object["spec"] = desired.Object["spec"]
+	current.SetLabels(desired.GetLabels())
+	current.SetAnnotations(desired.GetAnnotations())
+	if err := c.Update(ctx, current); err != nil {
+		logger.Error(err, "Error updating route", "kind", kind)
+		return false, err
+	}
+	return false, nil
+}
+
+// deleteStaleRoutes removes the routes the cluster created earlier that are no longer
+// generated, such as the route of a removed App Server.
+func (cc *ClusterContext) deleteStaleRoutes(gvk schema.GroupVersionKind, keep map[string]bool) error {
+	cr := cc.MarklogicCluster
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	err := cc.Client.List(cc.Ctx, list, controllerClient.InNamespace(cr.Namespace), controllerClient.MatchingLabels(getSelectorLabels(cr.Name)))
+	if apimeta.IsNoMatchError(err) || apierrors.IsForbidden(err) {
+		// Without the CRD or the permission to read it there are no routes to clean up.
+		cc.ReqLogger.Info("Skipping stale route cleanup", "kind", gvk.Kind, "reason", err.Error())
+		return nil
+	}
+	if err != nil {
+		cc.ReqLogger.Error(err, "Failed to list routes", "kind", gvk.Kind)
+		return err
+	}
+	for i := range list.Items {
+		route := &list.Items[i]
+		if keep[route.GetName()] {
+			continue
+		}
+		if controller := metav1.GetControllerOf(route); controller == nil || controller.UID != cr.UID {
+			continue
+		}
+		cc.ReqLogger.Info("Route is no longer generated, deleting it", "kind", gvk.Kind, "name", route.GetName())
+		if err := cc.Client.Delete(cc.Ctx, route); err != nil && !apierrors.IsNotFound(err) {
+			cc.ReqLogger.Error(err, "Failed to delete route", "kind", gvk.Kind)
+			return err
+		}
+	}
+	return nil
+}
+
+// ReconcileGatewayRoutes keeps an HTTPRoute per App Server and a TCPRoute per TCP port
+// attached to the Gateways in haproxy.gateway, next to or instead of the Ingress. The
+// GatewayRoutesReady condition records that routes were created, so clusters that never
+// enabled the gateway are not listed for stale routes.
+func (cc *ClusterContext) ReconcileGatewayRoutes() result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	conditionType := string(marklogicv1.ClusterGatewayRoutesReady)
+	if !gatewayEnabled(cr) && apimeta.FindStatusCondition(cr.Status.Conditions, conditionType) == nil {
+		return result.Continue()
+	}
+	keep := map[schema.GroupVersionKind]map[string]bool{httpRouteGVK: {}, tcpRouteGVK: {}}
+	if gatewayEnabled(cr) {
+		for _, route := range cc.generateGatewayRoutes() {
+			created, err := applyRoute(cc.Ctx, cc.Client, cc.ReqLogger, route)
+			if err != nil {
+				cc.Recorder.Event(cr, "Warning", "GatewayRouteFailed", err.Error())
+				message := fmt.Sprintf("%s %s: %v", route.GetKind(), route.GetName(), err)
+				if err := cc.setGatewayRoutesCondition(metav1.ConditionFalse, gatewayRoutesReasonFailed, message); err != nil {
+					return result.Error(err)
+				}
+				return result.Error(err)
+			}
+			if created {
+				cc.Recorder.Event(cr, "Normal", "GatewayRouteCreated", fmt.Sprintf("%s %s creation is successful", route.GetKind(), route.GetName()))
+			}
+			keep[route.GroupVersionKind()][route.GetName()] = true
+		}
+	}
+	for _, gvk := range []schema.GroupVersionKind{httpRouteGVK, tcpRouteGVK} {
+		if err := cc.deleteStaleRoutes(gvk, keep[gvk]); err != nil {
+			return result.Error(err)
+		}
+	}
+	var err error
+	if gatewayEnabled(cr) {
+		err = cc.setGatewayRoutesCondition(metav1.ConditionTrue, gatewayRoutesReasonApplied, "the routes are attached to the Gateways")
+	} else {
+		err = cc.patchClusterStatus(func(status *marklogicv1.MarklogicClusterStatus) {
+			apimeta.RemoveStatusCondition(&status.Conditions, conditionType)
+		})
+	}
+	if err != nil {
+		return result.Error(err)
+	}
+	return result.Continue()
+}
+
+func (cc *ClusterContext) setGatewayRoutesCondition(conditionStatus metav1.ConditionStatus, reason, message string) error {
+	return cc.patchClusterStatus(func(status *marklogicv1.MarklogicClusterStatus) {
+		setClusterCondition(status, cc.MarklogicCluster.Generation, marklogicv1.ClusterGatewayRoutesReady, conditionStatus, reason, message)
+	})
+}
@@ -0,0 +1,200 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"context"
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func getRoute(t *testing.T, cc *ClusterContext, kind, name string) *unstructured.Unstructured {
+	t.Helper()
+	route := &unstructured.Unstructured{}
+	route.SetGroupVersionKind(httpRouteGVK)
+	if kind == tcpRouteGVK.Kind {
+		route.SetGroupVersionKind(tcpRouteGVK)
+	}
+	if err := cc.Client.Get(cc.Ctx, types.NamespacedName{Name: name, Namespace: "default"}, route); err != nil {
+		t.Fatalf("expected %s %s: %v", kind, name, err)
+	}
+	return route
+}
+
+// updateGatewayCluster stores the spec of the test cluster, since the status patches of the
+// reconcile read the cluster back.
+func updateGatewayCluster(t *testing.T, cc *ClusterContext) {
+	t.Helper()
+	if err := cc.Client.Update(cc.Ctx, cc.MarklogicCluster); err != nil {
+		t.Fatalf("failed to update cluster: %v", err)
+	}
+}
+
+func gatewayTestGroup(haproxy *marklogicv1.HAProxyGroup) *marklogicv1.MarklogicGroups {
+	return &marklogicv1.MarklogicGroups{Name: "dnode", Replicas: int32Ptr(1), HAProxy: haproxy}
+}
+
+func TestReconcileGatewayRoutesFollowsFrontendPorts(t *testing.T) {
+	cc := newAppServerTestContext(t, gatewayTestGroup(nil))
+	cc.MarklogicCluster.UID = "ml-uid"
+	cc.MarklogicCluster.Spec.HAProxy.AppServers = append(cc.MarklogicCluster.Spec.HAProxy.AppServers,
+		marklogicv1.AppServers{Name: "manage", Type: "http", Port: 8002, Path: "/manage"})
+	cc.MarklogicCluster.Spec.HAProxy.TcpPorts = &marklogicv1.Tcpports{Enabled: true, Ports: []marklogicv1.TcpPort{{Name: "odbc", Port: 5432}}}
+	cc.MarklogicCluster.Spec.HAProxy.Gateway = &marklogicv1.HAProxyGateway{
+		Enabled:    true,
+		ParentRefs: []marklogicv1.GatewayParentRef{{Name: "platform", Namespace: "gateway-system"}},
+		Hostnames:  []string{"marklogic.example.com"},
+	}
+
+	updateGatewayCluster(t, cc)
+	if res := cc.ReconcileGatewayRoutes(); res.Completed() {
+		t.Fatalf("expected reconcile to continue, got %+v", res)
+	}
+	route := getRoute(t, cc, "HTTPRoute", "ml-app-service")
+	parentRefs, _, _ := unstructured.NestedSlice(route.Object, "spec", "parentRefs")
+	if len(parentRefs) != 1 || parentRefs[0].(map[string]any)["port"] != int64(8000) {
+		t.Fatalf("expected the route to attach to the listener on 8000, got %v", parentRefs)
+	}
+	rules, _, _ := unstructured.NestedSlice(route.Object, "spec", "rules")
+	backend := rules[0].(map[string]any)["backendRefs"].([]any)[0].(map[string]any)
+	if backend["name"] != "marklogic-haproxy" || backend["port"] != int64(8000) {
+		t.Fatalf("expected the route to point at the app-service port, got %v", backend)
+	}
+	if hostnames, _, _ := unstructured.NestedStringSlice(route.Object, "spec", "hostnames"); len(hostnames) != 1 {
+		t.Fatalf("expected the hostname, got %v", hostnames)
+	}
+	tcpRoute := getRoute(t, cc, "TCPRoute", "ml-tcp-5432")
+	if owner := tcpRoute.GetOwnerReferences(); len(owner) != 1 || owner[0].UID != "ml-uid" {
+		t.Fatalf("expected the TCPRoute to be owned by the cluster, got %v", owner)
+	}
+
+	// Path-based routing moves every App Server to a path on the frontend port.
+	pathBased := true
+	cc.MarklogicCluster.Spec.HAProxy.PathBasedRouting = &pathBased
+	cc.MarklogicCluster.Spec.HAProxy.FrontendPort = 80
+	cc.MarklogicCluster.Spec.HAProxy.Gateway.TLS = &marklogicv1.GatewayTLS{SectionName: "https"}
+	updateGatewayCluster(t, cc)
+	cc.ReconcileGatewayRoutes()
+	route = getRoute(t, cc, "HTTPRoute", "ml-manage")
+	rules, _, _ = unstructured.NestedSlice(route.Object, "spec", "rules")
+	match := rules[0].(map[string]any)["matches"].([]any)[0].(map[string]any)["path"].(map[string]any)
+	backend = rules[0].(map[string]any)["backendRefs"].([]any)[0].(map[string]any)
+	if match["value"] != "/manage" || backend["port"] != int64(80) {
+		t.Fatalf("expected /manage on the frontend port, got %v and %v", match, backend)
+	}
+	parentRefs, _, _ = unstructured.NestedSlice(route.Object, "spec", "parentRefs")
+	if ref := parentRefs[0].(map[string]any); ref["sectionName"] != "https" || ref["port"] != nil {
+		t.Fatalf("expected the route to attach to the https listener, got %v", ref)
+	}
+}
+
+func TestReconcileGatewayRoutesCorrectsDriftAndDeletesStaleRoutes(t *testing.T) {
+	cc := newAppServerTestContext(t, gatewayTestGroup(nil))
+	cc.MarklogicCluster.UID = "ml-uid"
+	cc.MarklogicCluster.Spec.HAProxy.Gateway = &marklogicv1.HAProxyGateway{
+		Enabled:    true,
+		ParentRefs: []marklogicv1.GatewayParentRef{{Name: "platform", SectionName: "http"}},
+	}
+	updateGatewayCluster(t, cc)
+	cc.ReconcileGatewayRoutes()
+
+	route := getRoute(t, cc, "HTTPRoute", "ml-app-service")
+	if err := unstructured.SetNestedField(route.Object, []any{"edited.example.com"}, "spec", "hostnames"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cc.Client.Update(cc.Ctx, route); err != nil {
+		t.Fatalf("failed to edit route: %v", err)
+	}
+	cc.ReconcileGatewayRoutes()
+	route = getRoute(t, cc, "HTTPRoute", "ml-app-service")
+	if _, found, _ := unstructured.NestedSlice(route.Object, "spec", "hostnames"); found {
+		t.Fatal("expected the edited hostnames to be reverted")
+	}
+	parentRefs, _, _ := unstructured.NestedSlice(route.Object, "spec", "parentRefs")
+	if ref := parentRefs[0].(map[string]any); ref["sectionName"] != "http" || ref["port"] != nil {
+		t.Fatalf("expected the sectionName of the parentRef to be kept, got %v", ref)
+	}
+
+	cc.MarklogicCluster.Spec.HAProxy.Gateway.Enabled = false
+	updateGatewayCluster(t, cc)
+	cc.ReconcileGatewayRoutes()
+	err := cc.Client.Get(cc.Ctx, types.NamespacedName{Name: "ml-app-service", Namespace: "default"}, route)
+	if !apierrors.IsNotFound(err) {
+		t.Fatalf("expected the route to be deleted once the gateway is disabled, got %v", err)
+	}
+	if condition := apimeta.FindStatusCondition(cc.MarklogicCluster.Status.Conditions, string(marklogicv1.ClusterGatewayRoutesReady)); condition != nil {
+		t.Fatalf("expected the condition to be removed with the routes, got %+v", condition)
+	}
+}
+
+func TestReconcileGatewayRoutesFollowGroupConfig(t *testing.T) {
+	pathBased := true
+	cc := newAppServerTestContext(t, gatewayTestGroup(&marklogicv1.HAProxyGroup{
+		Enabled:          true,
+		PathBasedRouting: &pathBased,
+		AppServers:       []marklogicv1.AppServers{{Name: "search", Type: "http", Port: 8010, TargetPort: 8010, Path: "/search"}},
+		TcpPorts:         &marklogicv1.Tcpports{Enabled: true, Ports: []marklogicv1.TcpPort{{Name: "xdbc", Port: 8020}}},
+	}))
+	cc.MarklogicCluster.UID = "ml-uid"
+	cc.MarklogicCluster.Spec.HAProxy.FrontendPort = 80
+	cc.MarklogicCluster.Spec.HAProxy.Gateway = &marklogicv1.HAProxyGateway{
+		Enabled:    true,
+		ParentRefs: []marklogicv1.GatewayParentRef{{Name: "platform"}},
+	}
+
+	updateGatewayCluster(t, cc)
+	if res := cc.ReconcileGatewayRoutes(); res.Completed() {
+		t.Fatalf("expected reconcile to continue, got %+v", res)
+	}
+	route := getRoute(t, cc, "HTTPRoute", "ml-search")
+	rules, _, _ := unstructured.NestedSlice(route.Object, "spec", "rules")
+	match := rules[0].(map[string]any)["matches"].([]any)[0].(map[string]any)["path"].(map[string]any)
+	backend := rules[0].(map[string]any)["backendRefs"].([]any)[0].(map[string]any)
+	if match["value"] != "/search" || backend["port"] != int64(80) {
+		t.Fatalf("expected the group App Server on the frontend port, got %v and %v", match, backend)
+	}
+	getRoute(t, cc, "TCPRoute", "ml-tcp-8020")
+
+	cc.Request = &reconcile.Request{NamespacedName: types.NamespacedName{Name: "ml", Namespace: "default"}}
+	ports := map[int32]bool{}
+	for _, port := range cc.generateHaproxyServiceDef(metav1.ObjectMeta{}).Spec.Ports {
+		ports[port.Port] = true
+	}
+	if !ports[80] || !ports[8020] {
+		t.Fatalf("expected the Service to expose the frontend and TCP ports, got %v", ports)
+	}
+}
+
+func TestReconcileGatewayRoutesSkipsClustersWithoutRoutes(t *testing.T) {
+	cc := newAppServerTestContext(t, gatewayTestGroup(nil))
+	cc.Client = interceptor.NewClient(cc.Client.(client.WithWatch), interceptor.Funcs{
+		List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+			t.Fatalf("expected no routes to be listed, got a list of %T", list)
+			return nil
+		},
+	})
+	if res := cc.ReconcileGatewayRoutes(); res.Completed() {
+		t.Fatalf("expected reconcile to continue, got %+v", res)
+	}
+
+	// A cluster that created routes still cleans up when the routes cannot be listed.
+	cc.Client = interceptor.NewClient(cc.Client.(client.WithWatch), interceptor.Funcs{
+		List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+			return apierrors.NewForbidden(schema.GroupResource{Group: gatewayAPIGroup, Resource: "httproutes"}, "", nil)
+		},
+	})
+	setClusterCondition(&cc.MarklogicCluster.Status, 0, marklogicv1.ClusterGatewayRoutesReady, metav1.ConditionTrue, gatewayRoutesReasonApplied, "")
+	if res := cc.ReconcileGatewayRoutes(); res.Completed() {
+		t.Fatalf("expected a forbidden list to be tolerated, got %+v", res)
+	}
+}
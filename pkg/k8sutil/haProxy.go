@@ -6,7 +6,9 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"fmt"
 	"sort"
+	"strings"
 
 	"github.com/cisco-open/k8s-objectmatcher/patch"
 	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
@@ -40,7 +42,20 @@ func (cc *ClusterContext) ReconcileHAProxy() result.ReconcileResult {
 	configMapDef := generateHAProxyConfigMap(objectMeta, marklogicClusterAsOwner(cr), data)
 	haproxyDeploymentDef := cc.createHAProxyDeploymentDef(objectMeta)
 	haproxyServiceDef := cc.generateHaproxyServiceDef(objectMeta)
-	configmapHash := calculateHash(configMapDef.Data)
+	configmapHash := haproxyRolloutHash(cc.Ctx, cr, configMapDef.Data)
+	haproxyDeploymentDef.Spec.Template.Annotations["configmap-hash"] = configmapHash
+	if haproxySnippetsConfigured(cr) {
+		deployedHash, err := cc.deployedHAProxyConfigHash()
+		if err != nil {
+			logger.Error(err, "Failed to get HAProxy Deployment")
+			return result.Error(err)
+		}
+		if deployedHash != configmapHash {
+			if result := cc.preflightHAProxyConfig(configMapDef.Data, configmapHash, haproxyDeploymentDef); result.Completed() {
+				return result
+			}
+		}
+	}
 	if err != nil {
 		if errors.IsNotFound(err) {
 			logger.Info("HAProxy ConfigMap is not found, creating a new one")
@@ -67,7 +82,7 @@ func (cc *ClusterContext) ReconcileHAProxy() result.ReconcileResult {
 				return result.Error(err)
 			}
 			logger.Info("HAProxy Deployed is successful")
-			return result.Continue()
+			return cc.finishHAProxyPreflight()
 		} else {
 			logger.Error(err, "HAProxy configmap creation is failed")
 			return result.Error(err)
@@ -126,6 +141,10 @@ func (cc *ClusterContext) ReconcileHAProxy() result.ReconcileResult {
 		logger.Error(err, "Failed to get HAProxy Deployment")
 		return result.Error(err)
 	}
+	if cr.Spec.HAProxy.Autoscaling != nil {
+		// The HorizontalPodAutoscaler owns the replicas.
+		haproxyDeploymentDef.Spec.Replicas = haproxyDeployment.Spec.Replicas
+	}
 	patchDiff, err = patch.DefaultPatchMaker.Calculate(haproxyDeployment, haproxyDeploymentDef,
 		patch.IgnoreStatusFields(),
 		patch.IgnoreVolumeClaimTemplateTypeMetaAndStatus(),
@@ -146,7 +165,7 @@ func (cc *ClusterContext) ReconcileHAProxy() result.ReconcileResult {
 			return result.Error(err)
 		}
 	}
-	return result.Continue()
+	return cc.finishHAProxyPreflight()
 }
 
 // generateHAProxyData generates the HAProxy Config Data
@@ -159,13 +178,19 @@ global
   log stdout format raw local0
   maxconn 1024
 `
+	if haproxyRuntimeAPIEnabled(cr) {
+		haProxyData["haproxy.cfg"] += fmt.Sprintf("  stats socket ipv4@127.0.0.1:%d level admin\n", haproxyRuntimeAPIPort(cr))
+	}
+	if snippet := configSnippet(cr.Spec.HAProxy.GlobalConfig); snippet != "" {
+		haProxyData["haproxy.cfg"] += strings.TrimPrefix(snippet, "\n") + "\n"
+	}
 	baseConfig := `
 defaults
   log global
   option forwardfor
   timeout client {{ $.ClientTimeout}}s
   timeout connect {{ $.ConnectTimeout}}s
-  timeout server {{ $.ServerTimeout}}s
+  timeout server {{ $.ServerTimeout}}s{{ $.DefaultsConfig }}
 
 resolvers dns
   # add nameserver from /etc/resolv.conf
@@ -199,6 +224,7 @@ resolvers dns
 		"ClientTimeout":  cr.Spec.HAProxy.Timeout.Client,
 		"ConnectTimeout": cr.Spec.HAProxy.Timeout.Connect,
 		"ServerTimeout":  cr.Spec.HAProxy.Timeout.Server,
+		"DefaultsConfig": configSnippet(cr.Spec.HAProxy.DefaultsConfig),
 	}
 	result += parseTemplateToString(baseConfig, data) + "\n"
 	haProxyData["haproxy.cfg"] += result + "\n"
@@ -222,6 +248,7 @@ func (cc *ClusterContext) createHAProxyDeploymentDef(meta metav1.ObjectMeta) *ap
 	selectorLabels := getHAProxySelectorLabels(cr.GetObjectMeta().GetName())
 	ownerDef := marklogicClusterAsOwner(cr)
 	defaultMode := int32(420)
+	replicas := haproxySeedReplicas(cr)
 	deploymentDef := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        "marklogic-haproxy",
@@ -230,16 +257,15 @@ func (cc *ClusterContext) createHAProxyDeploymentDef(meta metav1.ObjectMeta) *ap
 			Annotations: meta.Annotations,
 		},
 		Spec: appsv1.DeploymentSpec{
-			Replicas: &cr.Spec.HAProxy.ReplicaCount,
+			Replicas: &replicas,
 			Selector: &metav1.LabelSelector{
 				MatchLabels: selectorLabels,
 			},
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
 					Labels: meta.Labels,
-					Annotations: map[string]string{
-						"configmap-hash": calculateHash(generateHAProxyConfigMapData(cc.Ctx, cr)),
-					},
+					// ReconcileHAProxy sets the configmap-hash annotation.
+					Annotations: map[string]string{},
 				},
 				Spec: corev1.PodSpec{
 					SecurityContext: getHAProxyPodSecurityContextOrDefault(cr.Spec.HAProxy.PodSecurityContext),
@@ -263,7 +289,10 @@ func (cc *ClusterContext) createHAProxyDeploymentDef(meta metav1.ObjectMeta) *ap
 							},
 						},
 					},
-					ImagePullSecrets: cr.Spec.HAProxy.ImagePullSecrets,
+					ImagePullSecrets:          cr.Spec.HAProxy.ImagePullSecrets,
+					Affinity:                  defaultHAProxyAffinity(cr.Name),
+					TopologySpreadConstraints: haproxyTopologySpreadConstraints(cr),
+					PriorityClassName:         cr.Spec.HAProxy.PriorityClassName,
 					Volumes: []corev1.Volume{
 						{
 							Name: "haproxy-config",
@@ -287,20 +316,66 @@ func (cc *ClusterContext) createHAProxyDeploymentDef(meta metav1.ObjectMeta) *ap
 	if cr.Spec.HAProxy.NodeSelector != nil {
 		deploymentDef.Spec.Template.Spec.NodeSelector = cr.Spec.HAProxy.NodeSelector
 	}
-	if cr.Spec.HAProxy.Tls != nil && cr.Spec.HAProxy.Tls.Enabled && cr.Spec.HAProxy.Tls.SecretName != "" {
-		deploymentDef.Spec.Template.Spec.Volumes = append(deploymentDef.Spec.Template.Spec.Volumes, corev1.Volume{
-			Name: "ssl-certificate",
-			VolumeSource: corev1.VolumeSource{
-				Secret: &corev1.SecretVolumeSource{
-					SecretName: cr.Spec.HAProxy.Tls.SecretName,
-				},
+	var sslSecret *corev1.SecretVolumeSource
+	if haproxyCertManagerIssued(cr.Spec.HAProxy.Tls) {
+		sslSecret = &corev1.SecretVolumeSource{
+			SecretName: haproxyCertificateName,
+			Items: []corev1.KeyToPath{
+				{Key: "tls.crt", Path: haproxyIssuedCertFile},
+				{Key: "tls.key", Path: haproxyIssuedCertFile + ".key"},
 			},
+		}
+	} else if cr.Spec.HAProxy.Tls != nil && cr.Spec.HAProxy.Tls.Enabled && cr.Spec.HAProxy.Tls.SecretName != "" {
+		sslSecret = &corev1.SecretVolumeSource{
+			SecretName: cr.Spec.HAProxy.Tls.SecretName,
+		}
+	}
+	if sslSecret != nil {
+		deploymentDef.Spec.Template.Spec.Volumes = append(deploymentDef.Spec.Template.Spec.Volumes, corev1.Volume{
+			Name:         "ssl-certificate",
+			VolumeSource: corev1.VolumeSource{Secret: sslSecret},
+		})
+		container := &deploymentDef.Spec.Template.Spec.Containers[0]
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      "ssl-certificate",
+			MountPath: "/usr/local/etc/ssl",
+			ReadOnly:  true,
 		})
 	}
 	AddOwnerRefToObject(deploymentDef, ownerDef)
 	return deploymentDef
 }
 
+// defaultHAProxyAffinity prefers nodes that run no other HAProxy pod of the cluster, so that
+// losing a node does not take all of them down.
+func defaultHAProxyAffinity(name string) *corev1.Affinity {
+	return &corev1.Affinity{
+		PodAntiAffinity: &corev1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{{
+				Weight: 100,
+				PodAffinityTerm: corev1.PodAffinityTerm{
+					LabelSelector: &metav1.LabelSelector{MatchLabels: getHAProxySelectorLabels(name)},
+					TopologyKey:   corev1.LabelHostname,
+				},
+			}},
+		},
+	}
+}
+
+// haproxyTopologySpreadConstraints fills in the HAProxy pods as the selector of constraints
+// that have none.
+func haproxyTopologySpreadConstraints(cr *marklogicv1.MarklogicCluster) []corev1.TopologySpreadConstraint {
+	var constraints []corev1.TopologySpreadConstraint
+	for _, constraint := range cr.Spec.HAProxy.TopologySpreadConstraints {
+		constraint := *constraint.DeepCopy()
+		if constraint.LabelSelector == nil {
+			constraint.LabelSelector = &metav1.LabelSelector{MatchLabels: getHAProxySelectorLabels(cr.Name)}
+		}
+		constraints = append(constraints, constraint)
+	}
+	return constraints
+}
+
 // createHAproxy Deployment
 func (cc *ClusterContext) createHAProxyDeployment(deploymentDef *appsv1.Deployment) error {
 	logger := cc.ReqLogger
@@ -366,6 +441,8 @@ func (cc *ClusterContext) generateHaproxyServiceDef(meta metav1.ObjectMeta) *cor
 			}
 		}
 	}
+	servicePort = append(servicePort, haproxyAppServerPorts(cr, servicePort)...)
+	servicePort = append(servicePort, cc.gatewayServicePorts(servicePort)...)
 	if cr.Spec.HAProxy.Stats.Enabled {
 		servicePort = append(servicePort, corev1.ServicePort{
 			Name: "stats",
@@ -0,0 +1,76 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+)
+
+const defaultBalanceAlgorithm = "leastconn"
+
+var defaultStickyCookies = []string{"HostId", "SessionID"}
+
+func balanceAlgorithm(balance string) string {
+	if balance == "" {
+		return defaultBalanceAlgorithm
+	}
+	return balance
+}
+
+// sessionAffinityOptions renders the stickiness lines of an HTTP backend. HAProxy inserts
+// its own cookie for the clients that keep cookies, and remembers the MarkLogic cookies
+// of a response so that the requests carrying them reach the same host.
+func sessionAffinityOptions(affinity *marklogicv1.SessionAffinity) string {
+	cookies := defaultStickyCookies
+	expire := "4h"
+	if affinity != nil {
+		if !affinity.Enabled {
+			return ""
+		}
+		if len(affinity.Cookies) > 0 {
+			cookies = affinity.Cookies
+		}
+		if affinity.Expire != "" {
+			expire = affinity.Expire
+		}
+	}
+	options := fmt.Sprintf(`
+  cookie haproxy insert indirect httponly nocache maxidle 30m maxlife %s
+  stick-table type string len 32 size 10k expire %s`, expire, expire)
+	for _, cookie := range cookies {
+		options += fmt.Sprintf(`
+  stick store-response res.cook(%s)`, cookie)
+	}
+	for _, cookie := range cookies {
+		options += fmt.Sprintf(`
+  stick match req.cook(%s)`, cookie)
+	}
+	return options
+}
+
+// timeoutOptions renders the connect and server timeouts that override the defaults
+// section. Unset values keep the default.
+func timeoutOptions(timeout *marklogicv1.Timeout) string {
+	if timeout == nil {
+		return ""
+	}
+	options := ""
+	if timeout.Connect > 0 {
+		options += fmt.Sprintf("\n  timeout connect %ds", timeout.Connect)
+	}
+	if timeout.Server > 0 {
+		options += fmt.Sprintf("\n  timeout server %ds", timeout.Server)
+	}
+	return options
+}
+
+// clientTimeoutOption renders the client timeout, which only frontend and listen sections
+// accept.
+func clientTimeoutOption(client int32) string {
+	if client <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("\n  timeout client %ds", client)
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newBalanceTestCluster(appServers []marklogicv1.AppServers, tcpPorts []marklogicv1.TcpPort) *marklogicv1.MarklogicCluster {
+	pathBased := false
+	return &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			ClusterDomain: "cluster.local",
+			HAProxy: &marklogicv1.HAProxy{
+				Enabled:          true,
+				PathBasedRouting: &pathBased,
+				AppServers:       appServers,
+				TcpPorts:         &marklogicv1.Tcpports{Enabled: len(tcpPorts) > 0, Ports: tcpPorts},
+			},
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "dnode", IsBootstrap: true, Replicas: int32Ptr(1)}},
+		},
+	}
+}
+
+func TestBackendsKeepStickySessionsByDefault(t *testing.T) {
+	cr := newBalanceTestCluster([]marklogicv1.AppServers{{Name: "app-service", Port: 8000}}, []marklogicv1.TcpPort{{Name: "odbc", Port: 5432}})
+	config := generateHAProxyConfig(context.Background(), cr)
+
+	expected := `
+backend marklogic-8000-backend
+  mode http
+  balance leastconn
+  option forwardfor
+  cookie haproxy insert indirect httponly nocache maxidle 30m maxlife 4h
+  stick-table type string len 32 size 10k expire 4h
+  stick store-response res.cook(HostId)
+  stick store-response res.cook(SessionID)
+  stick match req.cook(HostId)
+  stick match req.cook(SessionID)
+  default-server check
+  server `
+	if backends := generateBackendConfig(cr, config); !strings.HasPrefix(backends, expected) {
+		t.Fatalf("expected the default backend, got %s", backends)
+	}
+	if tcp := generateTcpConfig(cr, config); !strings.Contains(tcp, "  mode tcp\n  balance leastconn\nserver ") {
+		t.Fatalf("expected the default listener, got %s", tcp)
+	}
+	if frontends := generateFrontendConfig(cr, config); strings.Contains(frontends, "timeout") {
+		t.Fatalf("expected the frontend to keep the default timeouts, got %s", frontends)
+	}
+}
+
+func TestBackendsRenderBalanceAffinityAndTimeouts(t *testing.T) {
+	cr := newBalanceTestCluster(
+		[]marklogicv1.AppServers{
+			{Name: "app-service", Port: 8000, Balance: "roundrobin",
+				SessionAffinity: &marklogicv1.SessionAffinity{Enabled: true, Cookies: []string{"SessionID"}, Expire: "30m"},
+				Timeout:         &marklogicv1.Timeout{Client: 3600, Server: 3600}},
+			{Name: "manage", Port: 8002, Balance: "uri", SessionAffinity: &marklogicv1.SessionAffinity{Enabled: false}},
+		},
+		[]marklogicv1.TcpPort{{Name: "odbc", Port: 5432, Balance: "source", Timeout: &marklogicv1.Timeout{Client: 60, Connect: 5}}},
+	)
+	config := generateHAProxyConfig(context.Background(), cr)
+	backends := generateBackendConfig(cr, config)
+
+	app := `
+backend marklogic-8000-backend
+  mode http
+  balance roundrobin
+  option forwardfor
+  cookie haproxy insert indirect httponly nocache maxidle 30m maxlife 30m
+  stick-table type string len 32 size 10k expire 30m
+  stick store-response res.cook(SessionID)
+  stick match req.cook(SessionID)
+  timeout server 3600s
+  default-server check`
+	manage := `
+backend marklogic-8002-backend
+  mode http
+  balance uri
+  option forwardfor
+  default-server check`
+	if !strings.Contains(backends, app) || !strings.Contains(backends, manage) {
+		t.Fatalf("unexpected backends:\n%s", backends)
+	}
+	frontends := generateFrontendConfig(cr, config)
+	if !strings.Contains(frontends, "\n  timeout client 3600s\n  default_backend marklogic-8000-backend") || strings.Count(frontends, "timeout client") != 1 {
+		t.Fatalf("expected the client timeout on the app-service frontend only, got %s", frontends)
+	}
+	tcp := generateTcpConfig(cr, config)
+	if !strings.Contains(tcp, "  balance source\n  timeout client 60s\n  timeout connect 5s\nserver ") {
+		t.Fatalf("unexpected listener:\n%s", tcp)
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+)
+
+const (
+	defaultHealthCheckPort     int32 = 7997
+	defaultHealthCheckInterval int32 = 5
+	defaultHealthCheckRise     int32 = 2
+	defaultHealthCheckFall     int32 = 3
+
+	// healthCheckBackendOptions turns the checks of a backend into HTTP requests. The
+	// HealthCheck App Server answers 200 while the host is healthy.
+	healthCheckBackendOptions = `
+  option httpchk GET /
+  http-check expect status 200`
+)
+
+func enabledHealthCheck(healthCheck *marklogicv1.HAProxyHealthCheck) *marklogicv1.HAProxyHealthCheck {
+	if healthCheck == nil || !healthCheck.Enabled {
+		return nil
+	}
+	return healthCheck
+}
+
+func backendsHealthChecked(backends []BackendConfig) bool {
+	for _, backend := range backends {
+		if backend.HealthCheck != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func tcpHealthChecked(tcpConfigs []TCPConfig) bool {
+	for _, tcpConfig := range tcpConfigs {
+		if tcpConfig.HealthCheck != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func positiveOr(value, fallback int32) int32 {
+	if value > 0 {
+		return value
+	}
+	return fallback
+}
+
+// healthCheckServerOptions returns the options that send the checks of a server to the
+// health endpoint of its host. A nil healthCheck uses the defaults.
+func healthCheckServerOptions(healthCheck *marklogicv1.HAProxyHealthCheck) string {
+	if healthCheck == nil {
+		healthCheck = &marklogicv1.HAProxyHealthCheck{}
+	}
+	return fmt.Sprintf(" check port %d inter %ds rise %d fall %d",
+		positiveOr(healthCheck.Port, defaultHealthCheckPort),
+		positiveOr(healthCheck.Interval, defaultHealthCheckInterval),
+		positiveOr(healthCheck.Rise, defaultHealthCheckRise),
+		positiveOr(healthCheck.Fall, defaultHealthCheckFall))
+}
+
+// haproxyMaintenancePods returns the pods of health checked groups whose servers are put
+// into maintenance: hosts MarkLogic reports offline and the host a scale-down is
+// decommissioning. Only ReconcileHAProxyRuntime applies it; the generated configuration
+// leaves host status out so that it does not roll HAProxy. Failures to read the host status are logged and leave every server
+// enabled; the health checks still take unreachable hosts out of rotation.
+func (cc *ClusterContext) haproxyMaintenancePods() map[string]bool {
+	logger := cc.ReqLogger
+	cr := cc.MarklogicCluster
+	var groups []string
+	for _, group := range cr.Spec.MarkLogicGroups {
+		if group == nil || (group.HAProxy != nil && !group.HAProxy.Enabled) {
+			continue
+		}
+		if enabledHealthCheck(createEffectiveHAProxyConfig(cr.Spec.HAProxy, group.HAProxy).HealthCheck) != nil {
+			groups = append(groups, group.Name)
+		}
+	}
+	if len(groups) == 0 {
+		return nil
+	}
+
+	maintenance := map[string]bool{}
+	for _, name := range groups {
+		group := &marklogicv1.MarklogicGroup{}
+		if err := cc.Client.Get(cc.Ctx, types.NamespacedName{Name: name, Namespace: cr.Namespace}, group); err != nil {
+			if !apierrors.IsNotFound(err) {
+				logger.Error(err, "Failed to get MarkLogicGroup for HAProxy maintenance", "group", name)
+			}
+			continue
+		}
+		if group.Status.Decommission != nil && group.Status.Decommission.PodName != "" {
+			maintenance[group.Status.Decommission.PodName] = true
+		}
+	}
+
+	mgmt, err := cc.newClusterManagementClient()
+	if err != nil {
+		logger.Info("Cannot read host status for HAProxy maintenance", "reason", err.Error())
+		return maintenance
+	}
+	hosts, err := mgmt.ListHostsStatus(cc.Ctx)
+	if err != nil {
+		logger.Info("Cannot read host status for HAProxy maintenance", "reason", err.Error())
+		return maintenance
+	}
+	for _, host := range hosts {
+		if host.Online {
+			continue
+		}
+		podName := hostnameToPodName(host.Name)
+		for _, name := range groups {
+			if isGroupPodName(podName, name) {
+				maintenance[podName] = true
+			}
+		}
+	}
+	return maintenance
+}
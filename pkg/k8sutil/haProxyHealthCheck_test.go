@@ -0,0 +1,114 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func serverLines(config string) []string {
+	var lines []string
+	for _, line := range strings.Split(config, "\n") {
+		if strings.Contains(line, "server ") && !strings.Contains(line, "default-server") {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func TestHealthCheckIsConfiguredPerGroup(t *testing.T) {
+	pathBased := false
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			ClusterDomain: "cluster.local",
+			Tls:           &marklogicv1.Tls{EnableOnDefaultAppServers: true},
+			HAProxy: &marklogicv1.HAProxy{
+				Enabled:          true,
+				PathBasedRouting: &pathBased,
+				AppServers:       []marklogicv1.AppServers{{Name: "app-service", Port: 8000}},
+				TcpPorts:         &marklogicv1.Tcpports{Enabled: true, Ports: []marklogicv1.TcpPort{{Name: "odbc", Port: 5432}}},
+				HealthCheck:      &marklogicv1.HAProxyHealthCheck{Enabled: true, Port: 7997, Interval: 5, Rise: 2, Fall: 3},
+			},
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{
+				{Name: "dnode", IsBootstrap: true, Replicas: int32Ptr(1)},
+				{Name: "enode", Replicas: int32Ptr(1), HAProxy: &marklogicv1.HAProxyGroup{
+					Enabled:     true,
+					HealthCheck: &marklogicv1.HAProxyHealthCheck{Enabled: true, Port: 7997, Interval: 2, Rise: 1, Fall: 5},
+				}},
+			},
+		},
+	}
+
+	config := generateHAProxyConfig(context.Background(), cr)
+	config.Maintenance = map[string]bool{"enode-0": true}
+	backends := generateBackendConfig(cr, config)
+	tcp := generateTcpConfig(cr, config)
+	if !strings.Contains(backends, "option httpchk GET /") || !strings.Contains(tcp, "option httpchk GET /") {
+		t.Fatalf("expected HTTP checks in the backends, got %s%s", backends, tcp)
+	}
+	for _, line := range serverLines(backends + tcp) {
+		enode := strings.Contains(line, "enode-0.")
+		switch {
+		case enode && !strings.Contains(line, " check port 7997 inter 2s rise 1 fall 5"):
+			t.Fatalf("expected the checks of the enode group, got %q", line)
+		case !enode && !strings.Contains(line, " check port 7997 inter 5s rise 2 fall 3"):
+			t.Fatalf("expected the checks of the cluster, got %q", line)
+		case strings.Contains(line, " disabled"):
+			t.Fatalf("expected maintenance to stay out of the configuration, got %q", line)
+		case strings.Count(line, " check ") != 1:
+			t.Fatalf("expected a single check keyword, got %q", line)
+		case strings.Contains(line, ":8000 ") && !strings.Contains(line, " no-check-ssl"):
+			t.Fatalf("expected the checks to use plain HTTP, got %q", line)
+		}
+	}
+
+	cr.Spec.HAProxy.HealthCheck = nil
+	cr.Spec.MarkLogicGroups[1].HAProxy = nil
+	config = generateHAProxyConfig(context.Background(), cr)
+	if backends := generateBackendConfig(cr, config); strings.Contains(backends, "httpchk") || strings.Contains(backends, "check port") {
+		t.Fatalf("expected no HTTP checks without a health check, got %s", backends)
+	}
+}
+
+func TestHAProxyMaintenancePodsFollowHostStatus(t *testing.T) {
+	cc := newAppServerTestContext(t,
+		&marklogicv1.MarklogicGroups{Name: "dnode", IsBootstrap: true, Replicas: int32Ptr(3)},
+		&marklogicv1.MarklogicGroups{Name: "enode", Replicas: int32Ptr(1), HAProxy: &marklogicv1.HAProxyGroup{Enabled: false}},
+	)
+	cc.MarklogicCluster.Spec.HAProxy.HealthCheck = &marklogicv1.HAProxyHealthCheck{Enabled: true}
+	group := &marklogicv1.MarklogicGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "dnode", Namespace: "default"},
+		Status: marklogicv1.MarklogicGroupStatus{
+			Decommission: &marklogicv1.DecommissionStatus{PodName: "dnode-2", Phase: marklogicv1.DecommissionPhaseEvacuating},
+		},
+	}
+	if err := cc.Client.Create(cc.Ctx, group); err != nil {
+		t.Fatalf("failed to create group: %v", err)
+	}
+	useStubClusterManagementClient(t, managementStub{HostClient: &stubDynamicManagementClient{
+		listHostsFn: func() ([]mlmanage.HostStatus, error) {
+			return []mlmanage.HostStatus{
+				{Name: "dnode-0.dnode.default.svc.cluster.local", Online: true},
+				{Name: "dnode-1.dnode.default.svc.cluster.local", Online: false},
+				{Name: "enode-0.enode.default.svc.cluster.local", Online: false},
+			}, nil
+		},
+	}})
+
+	maintenance := cc.haproxyMaintenancePods()
+	if len(maintenance) != 2 || !maintenance["dnode-1"] || !maintenance["dnode-2"] {
+		t.Fatalf("expected the offline and the decommissioned dnode hosts, got %v", maintenance)
+	}
+
+	cc.MarklogicCluster.Spec.HAProxy.HealthCheck.Enabled = false
+	if maintenance := cc.haproxyMaintenancePods(); maintenance != nil {
+		t.Fatalf("expected no maintenance without health checks, got %v", maintenance)
+	}
+}
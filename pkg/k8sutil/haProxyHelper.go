@@ -6,6 +6,8 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 	"text/template"
 
@@ -18,6 +20,7 @@ type effectiveHAProxyConfig struct {
 	AppServers       []marklogicv1.AppServers
 	PathBasedRouting *bool
 	TcpPorts         *marklogicv1.Tcpports
+	HealthCheck      *marklogicv1.HAProxyHealthCheck
 }
 
 type HAProxyTemplate struct {
@@ -34,8 +37,13 @@ type HAProxyTemplate struct {
 	NSName           string
 	ClusterName      string
 	SslCert          string
+	Balance          string
+	// Options holds the lines a section adds to its template, such as stickiness and timeouts.
+	Options          string
 	sslEnabledServer bool
 	IsPathBased      bool
+	// HealthCheck holds the check options of a server, see healthCheckServerOptions.
+	HealthCheck string
 }
 
 type HAProxyConfig struct {
@@ -43,35 +51,58 @@ type HAProxyConfig struct {
 	FrontEndConfigMap map[string]FrontEndConfig
 	BackendConfigMap  map[string][]BackendConfig
 	TCPConfigMap      map[string][]TCPConfig
+	// Maintenance holds the pods whose servers are put into maintenance, see
+	// haproxyMaintenancePods. It is applied through the runtime API and never rendered, so
+	// that hosts going offline do not change the configuration.
+	Maintenance map[string]bool
 }
 
 type FrontEndConfig struct {
-	FrontendName string
-	IsPathBased  bool
-	Port         int
-	TargetPort   int
-	Path         string
-	BackendName  string
+	FrontendName  string
+	AppServerName string
+	IsPathBased   bool
+	Port          int
+	TargetPort    int
+	Path          string
+	BackendName   string
+	// ClientTimeout overrides the client timeout of the defaults section when set.
+	ClientTimeout int32
+	ExtraConfig   string
 }
 
 type BackendConfig struct {
-	BackendName string
-	IsPathBased bool
-	GroupName   string
-	Port        int
-	TargetPort  int
-	Path        string
-	Replicas    int
+	BackendName   string
+	AppServerName string
+	IsPathBased   bool
+	GroupName     string
+	Port          int
+	TargetPort    int
+	Path          string
+	Replicas      int
+	// Drained servers take no new sessions while their group is in maintenance. Like
+	// HAProxyConfig.Maintenance, it is applied through the runtime API and never rendered,
+	// so that draining a group does not restart every HAProxy pod.
+	Drained bool
+	// HealthCheck is nil unless the group checks the health endpoint of its hosts.
+	HealthCheck     *marklogicv1.HAProxyHealthCheck
+	Balance         string
+	SessionAffinity *marklogicv1.SessionAffinity
+	Timeout         *marklogicv1.Timeout
+	ExtraConfig     *marklogicv1.HAProxyExtraConfig
 }
 
 type TCPConfig struct {
-	TcpName    string
-	Port       int
-	TargetPort int
-	PortName   string
-	PodName    string
-	Replicas   int
-	GroupName  string
+	TcpName     string
+	Port        int
+	TargetPort  int
+	PortName    string
+	PodName     string
+	Replicas    int
+	GroupName   string
+	Drained     bool
+	HealthCheck *marklogicv1.HAProxyHealthCheck
+	Balance     string
+	Timeout     *marklogicv1.Timeout
 }
 
 func generateHAProxyConfig(ctx context.Context, cr *marklogicv1.MarklogicCluster) *HAProxyConfig {
@@ -96,37 +127,45 @@ func generateHAProxyConfig(ctx context.Context, cr *marklogicv1.MarklogicCluster
 		// Create effective configuration by merging cluster and group settings
 		effectiveConfig := createEffectiveHAProxyConfig(cr.Spec.HAProxy, group.HAProxy)
 
+		declaredRoutes, declaredTcpPorts := appServerRoutes(group.AppServers)
+
 		// process tcp ports
+		tcpPorts := []marklogicv1.TcpPort{}
 		if effectiveConfig.TcpPorts != nil && effectiveConfig.TcpPorts.Enabled {
-			tcpPorts := []marklogicv1.TcpPort{}
 			if cr.Spec.HAProxy.TcpPorts != nil {
 				tcpPorts = cr.Spec.HAProxy.TcpPorts.Ports
 			}
 			if effectiveConfig.TcpPorts != nil {
 				tcpPorts = effectiveConfig.TcpPorts.Ports
 			}
-			for _, tcpPort := range tcpPorts {
-				targetPort := int(tcpPort.TargetPort)
-				if tcpPort.TargetPort == 0 {
-					targetPort = int(tcpPort.Port)
-				}
-				var key string
-				if int(tcpPort.Port) == targetPort {
-					key = fmt.Sprintf("%d", tcpPort.Port)
-				} else {
-					key = fmt.Sprintf("%d-%d", tcpPort.Port, targetPort)
-				}
-				tcpConfig := TCPConfig{
-					TcpName:    key,
-					Port:       int(tcpPort.Port),
-					TargetPort: targetPort,
-					PortName:   tcpPort.Name,
-					PodName:    group.Name,
-					Replicas:   int(*group.Replicas),
-					GroupName:  group.Name,
-				}
-				tcpMap[key] = append(tcpMap[key], tcpConfig)
+		}
+		// App Servers declared on the group are routed whether or not tcpPorts is enabled.
+		tcpPorts = appendMissingTcpPorts(tcpPorts, declaredTcpPorts)
+		for _, tcpPort := range tcpPorts {
+			targetPort := int(tcpPort.TargetPort)
+			if tcpPort.TargetPort == 0 {
+				targetPort = int(tcpPort.Port)
+			}
+			var key string
+			if int(tcpPort.Port) == targetPort {
+				key = fmt.Sprintf("%d", tcpPort.Port)
+			} else {
+				key = fmt.Sprintf("%d-%d", tcpPort.Port, targetPort)
+			}
+			tcpConfig := TCPConfig{
+				TcpName:     key,
+				Port:        int(tcpPort.Port),
+				TargetPort:  targetPort,
+				PortName:    tcpPort.Name,
+				PodName:     group.Name,
+				Replicas:    int(*group.Replicas),
+				GroupName:   group.Name,
+				Drained:     group.Maintenance,
+				HealthCheck: enabledHealthCheck(effectiveConfig.HealthCheck),
+				Balance:     tcpPort.Balance,
+				Timeout:     tcpPort.Timeout,
 			}
+			tcpMap[key] = append(tcpMap[key], tcpConfig)
 		}
 
 		// process http ports with appServers
@@ -138,6 +177,7 @@ func generateHAProxyConfig(ctx context.Context, cr *marklogicv1.MarklogicCluster
 		if len(appServers) == 0 {
 			appServers = defaultAppServer
 		}
+		appServers = appendMissingAppServers(appServers, declaredRoutes)
 		for _, appServer := range appServers {
 			targetPort := int(appServer.TargetPort)
 			if appServer.TargetPort == 0 {
@@ -161,23 +201,37 @@ func generateHAProxyConfig(ctx context.Context, cr *marklogicv1.MarklogicCluster
 				frontendName := "marklogic-" + key + "-frontend"
 				if _, exists := frontendMap[key]; !exists {
 					frontend := FrontEndConfig{
-						FrontendName: frontendName,
-						IsPathBased:  groupPathBased,
-						Port:         int(appServer.Port),
-						TargetPort:   targetPort,
-						BackendName:  backendName,
+						FrontendName:  frontendName,
+						AppServerName: appServer.Name,
+						IsPathBased:   groupPathBased,
+						Port:          int(appServer.Port),
+						TargetPort:    targetPort,
+						BackendName:   backendName,
+					}
+					if appServer.Timeout != nil {
+						frontend.ClientTimeout = appServer.Timeout.Client
+					}
+					if appServer.ExtraConfig != nil {
+						frontend.ExtraConfig = appServer.ExtraConfig.Frontend
 					}
 					frontendMap[key] = frontend
 				}
 			}
 			backend := BackendConfig{
-				BackendName: backendName,
-				GroupName:   group.Name,
-				Port:        int(appServer.Port),
-				TargetPort:  targetPort,
-				Path:        appServer.Path,
-				Replicas:    int(*group.Replicas),
-				IsPathBased: groupPathBased,
+				BackendName:     backendName,
+				AppServerName:   appServer.Name,
+				GroupName:       group.Name,
+				Port:            int(appServer.Port),
+				TargetPort:      targetPort,
+				Path:            appServer.Path,
+				Replicas:        int(*group.Replicas),
+				IsPathBased:     groupPathBased,
+				Drained:         group.Maintenance,
+				HealthCheck:     enabledHealthCheck(effectiveConfig.HealthCheck),
+				Balance:         appServer.Balance,
+				SessionAffinity: appServer.SessionAffinity,
+				Timeout:         appServer.Timeout,
+				ExtraConfig:     appServer.ExtraConfig,
 			}
 			backendMap[key] = append(backendMap[key], backend)
 		}
@@ -210,8 +264,19 @@ frontend marklogic-pathbased-frontend
 			SslCert:    getSSLConfig(cr.Spec.HAProxy.Tls),
 		}
 		result = parseTemplateToString(frontEndDef, data)
-		for _, backends := range config.BackendConfigMap {
-			for _, babackend := range backends {
+		// The App Servers share the frontend; their snippets go before the use_backend rules.
+		added := map[string]bool{}
+		for _, key := range slices.Sorted(maps.Keys(config.BackendConfigMap)) {
+			for _, backend := range config.BackendConfigMap[key] {
+				if !backend.IsPathBased || backend.ExtraConfig == nil || added[backend.ExtraConfig.Frontend] {
+					continue
+				}
+				added[backend.ExtraConfig.Frontend] = true
+				result += configSnippet(backend.ExtraConfig.Frontend)
+			}
+		}
+		for _, key := range slices.Sorted(maps.Keys(config.BackendConfigMap)) {
+			for _, babackend := range config.BackendConfigMap[key] {
 				if !babackend.IsPathBased {
 					continue
 				}
@@ -232,15 +297,17 @@ frontend marklogic-pathbased-frontend
 frontend {{ .FrontendName }}
   mode http
   bind :{{ .PortNumber }} {{ .SslCert }}
-  log-format "%ci:%cp [%tr] %ft %b/%s %TR/%Tw/%Tc/%Tr/%Ta %ST %B %CC %CS %tsc %ac/%fc/%bc/%sc/%rc %sq/%bq %hr %hs %{+Q}r"
+  log-format "%ci:%cp [%tr] %ft %b/%s %TR/%Tw/%Tc/%Tr/%Ta %ST %B %CC %CS %tsc %ac/%fc/%bc/%sc/%rc %sq/%bq %hr %hs %{+Q}r"{{ .Options }}
   default_backend {{ .BackendName }}`
-	for _, frontend := range frontEndConfigs {
+	for _, key := range slices.Sorted(maps.Keys(frontEndConfigs)) {
+		frontend := frontEndConfigs[key]
 		data = &HAProxyTemplate{
 			FrontendName:     frontend.FrontendName,
 			BackendName:      frontend.BackendName,
 			PortNumber:       frontend.Port,
 			TargetPortNumber: frontend.TargetPort,
 			SslCert:          getSSLConfig(cr.Spec.HAProxy.Tls),
+			Options:          clientTimeoutOption(frontend.ClientTimeout) + configSnippet(frontend.ExtraConfig),
 		}
 		result += parseTemplateToString(frontEndDef, data) + "\n"
 	}
@@ -252,29 +319,35 @@ func generateBackendConfig(cr *marklogicv1.MarklogicCluster, config *HAProxyConf
 	backendConfigs := config.BackendConfigMap
 	var result string
 
-	backendTemplate := `
+	backendDef := `
 backend {{ .BackendName }}
   mode http
-  balance leastconn
-  option forwardfor
-  cookie haproxy insert indirect httponly nocache maxidle 30m maxlife 4h
-  stick-table type string len 32 size 10k expire 4h
-  stick store-response res.cook(HostId)
-  stick store-response res.cook(SessionID)
-  stick match req.cook(HostId)
-  stick match req.cook(SessionID)
+  balance {{ .Balance }}
+  option forwardfor{{ .Options }}
   default-server check`
-	for _, backends := range backendConfigs {
+	for _, key := range slices.Sorted(maps.Keys(backendConfigs)) {
+		backends := backendConfigs[key]
+		// Groups routing the same port share the backend; the first group's settings apply.
 		data := &HAProxyTemplate{
 			BackendName: backends[0].BackendName,
 			PortNumber:  backends[0].Port,
 			Path:        backends[0].Path,
+			Balance:     balanceAlgorithm(backends[0].Balance),
+			Options:     sessionAffinityOptions(backends[0].SessionAffinity) + timeoutOptions(backends[0].Timeout),
 		}
+		backendTemplate := backendDef
 		if backends[0].IsPathBased {
 			backendTemplate += `
   http-request replace-path {{.Path}}(/)?(.*) /\2`
 		}
+		httpCheck := backendsHealthChecked(backends)
+		if httpCheck {
+			backendTemplate += healthCheckBackendOptions
+		}
 		result += parseTemplateToString(backendTemplate, data)
+		if backends[0].ExtraConfig != nil {
+			result += configSnippet(backends[0].ExtraConfig.Backend)
+		}
 		for _, backend := range backends {
 			name := backend.GroupName
 			groupReplicas := backend.Replicas
@@ -290,6 +363,9 @@ backend {{ .BackendName }}
 					ClusterName:      cr.Spec.ClusterDomain,
 					sslEnabledServer: cr.Spec.Tls != nil && cr.Spec.Tls.EnableOnDefaultAppServers,
 				}
+				if httpCheck {
+					data.HealthCheck = healthCheckServerOptions(backend.HealthCheck)
+				}
 				result += getBackendServerConfigs(data)
 			}
 		}
@@ -305,6 +381,13 @@ func getBackendServerConfigs(data *HAProxyTemplate) string {
 	if data.sslEnabledServer {
 		backend += " ssl verify none"
 	}
+	if data.HealthCheck != "" {
+		backend += data.HealthCheck
+		if data.sslEnabledServer {
+			// The HealthCheck App Server does not use the TLS settings of the App Servers.
+			backend += " no-check-ssl"
+		}
+	}
 
 	return parseTemplateToString(backend, data)
 }
@@ -327,7 +410,12 @@ func getFrontendForPathbased(data *HAProxyTemplate) string {
 
 func getBackendForTCP(data *HAProxyTemplate) string {
 	backend := `
-server ml-{{.PodName}}-{{.PortNumber}}-{{.Index}} {{.PodName}}-{{.Index}}.{{.ServiceName}}.{{.NSName}}.svc.{{.ClusterName}}:{{.PortNumber}} check resolvers dns init-addr none`
+server ml-{{.PodName}}-{{.PortNumber}}-{{.Index}} {{.PodName}}-{{.Index}}.{{.ServiceName}}.{{.NSName}}.svc.{{.ClusterName}}:{{.PortNumber}}`
+	if data.HealthCheck == "" {
+		backend += " check"
+	}
+	backend += " resolvers dns init-addr none"
+	backend += data.HealthCheck
 	return parseTemplateToString(backend, data)
 }
 
@@ -364,16 +452,26 @@ func generateTcpConfig(cr *marklogicv1.MarklogicCluster, config *HAProxyConfig)
 	if len(tcpConfigs) == 0 {
 		return result
 	}
-	for _, tcpConfigSlice := range tcpConfigs {
+	for _, key := range slices.Sorted(maps.Keys(tcpConfigs)) {
+		tcpConfigSlice := tcpConfigs[key]
 		t := `
 listen marklogic-TCP-{{.TcpName}}
   bind :{{ .PortNumber }} {{ .SslCert }}
   mode tcp
-  balance leastconn`
+  balance {{ .Balance }}{{ .Options }}`
 		data := &HAProxyTemplate{
 			PortNumber: tcpConfigSlice[0].Port,
 			TcpName:    tcpConfigSlice[0].TcpName,
 			SslCert:    getSSLConfig(cr.Spec.HAProxy.Tls),
+			Balance:    balanceAlgorithm(tcpConfigSlice[0].Balance),
+			Options:    timeoutOptions(tcpConfigSlice[0].Timeout),
+		}
+		if tcpConfigSlice[0].Timeout != nil {
+			data.Options = clientTimeoutOption(tcpConfigSlice[0].Timeout.Client) + data.Options
+		}
+		httpCheck := tcpHealthChecked(tcpConfigSlice)
+		if httpCheck {
+			t += healthCheckBackendOptions
 		}
 		result += parseTemplateToString(t, data)
 		name := tcpConfigSlice[0].GroupName
@@ -387,6 +485,9 @@ listen marklogic-TCP-{{.TcpName}}
 					NSName:      cr.ObjectMeta.Namespace,
 					ClusterName: cr.Spec.ClusterDomain,
 				}
+				if httpCheck {
+					data.HealthCheck = healthCheckServerOptions(tcpConfig.HealthCheck)
+				}
 				result += getBackendForTCP(data)
 			}
 		}
@@ -397,11 +498,24 @@ listen marklogic-TCP-{{.TcpName}}
 func getSSLConfig(tls *marklogicv1.TlsForHAProxy) string {
 	if tls == nil || !tls.Enabled {
 		return ""
+	} else if tls.IssuerRef != nil {
+		return "ssl crt /usr/local/etc/ssl/" + haproxyIssuedCertFile
 	} else {
 		return "ssl crt /usr/local/etc/ssl/" + tls.CertFileName
 	}
 }
 
+// configSnippet indents the lines of a user-provided snippet into the current section.
+func configSnippet(snippet string) string {
+	result := ""
+	for _, line := range strings.Split(snippet, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			result += "\n  " + line
+		}
+	}
+	return result
+}
+
 // parses the given template with the given data
 func parseTemplateToString(templateStr string, data interface{}) string {
 	t := template.Must(template.New("haproxyConfig").Parse(templateStr))
@@ -419,6 +533,7 @@ func createEffectiveHAProxyConfig(clusterConfig *marklogicv1.HAProxy, groupConfi
 		AppServers:       clusterConfig.AppServers,
 		PathBasedRouting: clusterConfig.PathBasedRouting,
 		TcpPorts:         clusterConfig.TcpPorts,
+		HealthCheck:      clusterConfig.HealthCheck,
 	}
 
 	if groupConfig != nil {
@@ -432,7 +547,46 @@ func createEffectiveHAProxyConfig(clusterConfig *marklogicv1.HAProxy, groupConfi
 		if groupConfig.TcpPorts != nil {
 			effective.TcpPorts = groupConfig.TcpPorts
 		}
+		if groupConfig.HealthCheck != nil {
+			effective.HealthCheck = groupConfig.HealthCheck
+		}
 	}
 
 	return effective
 }
+
+// appendMissingAppServers adds the declared routes whose port is not routed yet, without
+// modifying the slice from the spec.
+func appendMissingAppServers(appServers []marklogicv1.AppServers, declared []marklogicv1.AppServers) []marklogicv1.AppServers {
+	merged := append([]marklogicv1.AppServers{}, appServers...)
+	for _, candidate := range declared {
+		routed := false
+		for _, appServer := range appServers {
+			if appServer.Port == candidate.Port || appServer.TargetPort == candidate.Port {
+				routed = true
+				break
+			}
+		}
+		if !routed {
+			merged = append(merged, candidate)
+		}
+	}
+	return merged
+}
+
+func appendMissingTcpPorts(tcpPorts []marklogicv1.TcpPort, declared []marklogicv1.TcpPort) []marklogicv1.TcpPort {
+	merged := append([]marklogicv1.TcpPort{}, tcpPorts...)
+	for _, candidate := range declared {
+		routed := false
+		for _, tcpPort := range tcpPorts {
+			if tcpPort.Port == candidate.Port || tcpPort.TargetPort == candidate.Port {
+				routed = true
+				break
+			}
+		}
+		if !routed {
+			merged = append(merged, candidate)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,259 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+)
+
+const (
+	haproxyPreflightName = "marklogic-haproxy-preflight"
+	// haproxyPreflightHashAnnotation records the configuration a preflight Job checks.
+	haproxyPreflightHashAnnotation = "marklogic.progress.com/haproxy-config-hash"
+	requeueForHAProxyPreflight     = 5
+
+	haproxyConfigReasonValid      = "ConfigurationValid"
+	haproxyConfigReasonValidating = "Validating"
+	haproxyConfigReasonInvalid    = "InvalidConfiguration"
+)
+
+func haproxySnippetsConfigured(cr *marklogicv1.MarklogicCluster) bool {
+	haproxy := cr.Spec.HAProxy
+	if strings.TrimSpace(haproxy.GlobalConfig) != "" || strings.TrimSpace(haproxy.DefaultsConfig) != "" {
+		return true
+	}
+	appServers := append([]marklogicv1.AppServers{}, haproxy.AppServers...)
+	for _, group := range cr.Spec.MarkLogicGroups {
+		if group != nil && group.HAProxy != nil {
+			appServers = append(appServers, group.HAProxy.AppServers...)
+		}
+	}
+	for _, appServer := range appServers {
+		if appServer.ExtraConfig != nil && (strings.TrimSpace(appServer.ExtraConfig.Frontend) != "" || strings.TrimSpace(appServer.ExtraConfig.Backend) != "") {
+			return true
+		}
+	}
+	return false
+}
+
+func getHAProxyPreflightLabels(name string) map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/name":       "marklogic",
+		"app.kubernetes.io/instance":   name,
+		"app.kubernetes.io/managed-by": "marklogic-operator",
+		"app.kubernetes.io/component":  "haproxy-preflight",
+	}
+}
+
+// deployedHAProxyConfigHash returns the configuration hash the HAProxy pods run with, or
+// an empty string before the Deployment exists.
+func (cc *ClusterContext) deployedHAProxyConfigHash() (string, error) {
+	deployment := &appsv1.Deployment{}
+	err := cc.Client.Get(cc.Ctx, types.NamespacedName{Name: "marklogic-haproxy", Namespace: cc.MarklogicCluster.Namespace}, deployment)
+	if apierrors.IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return deployment.Spec.Template.Annotations["configmap-hash"], nil
+}
+
+// preflightHAProxyConfig checks a configuration with configuration snippets before it is
+// rolled out. A Job runs haproxy -c on it with the pod template of the HAProxy Deployment,
+// so that the image and the certificates are the ones the pods use. The result is
+// Continue once the check passed; an invalid configuration stops the reconcile until the
+// spec changes.
+func (cc *ClusterContext) preflightHAProxyConfig(data map[string]string, hash string, deploymentDef *appsv1.Deployment) result.ReconcileResult {
+	logger := cc.ReqLogger
+	cr := cc.MarklogicCluster
+	nsName := types.NamespacedName{Name: haproxyPreflightName, Namespace: cr.Namespace}
+
+	job := &batchv1.Job{}
+	err := cc.Client.Get(cc.Ctx, nsName, job)
+	switch {
+	case err == nil && job.Annotations[haproxyPreflightHashAnnotation] != hash:
+		logger.Info("HAProxy configuration changed, replacing the preflight Job")
+		if err := cc.Client.Delete(cc.Ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !apierrors.IsNotFound(err) {
+			logger.Error(err, "Failed to delete the HAProxy preflight Job")
+			return result.Error(err)
+		}
+		return result.RequeueSoon(requeueForHAProxyPreflight)
+	case err == nil && job.Status.Succeeded > 0:
+		return result.Continue()
+	case err == nil && job.Status.Failed > 0:
+		message := cc.haproxyPreflightOutput(job)
+		logger.Info("HAProxy configuration is invalid, keeping the running configuration", "output", message)
+		cc.Recorder.Event(cr, corev1.EventTypeWarning, "HAProxyConfigInvalid", message)
+		if err := cc.setHAProxyConfigCondition(metav1.ConditionFalse, haproxyConfigReasonInvalid, message); err != nil {
+			return result.Error(err)
+		}
+		return result.Done()
+	case err == nil:
+		return result.RequeueSoon(requeueForHAProxyPreflight)
+	case !apierrors.IsNotFound(err):
+		logger.Error(err, "Failed to get the HAProxy preflight Job")
+		return result.Error(err)
+	}
+
+	logger.Info("Checking the HAProxy configuration before rolling it out")
+	annotations := map[string]string{haproxyPreflightHashAnnotation: hash}
+	labels := getHAProxyPreflightLabels(cr.Name)
+	if err := cc.applyHAProxyPreflightConfigMap(data, labels, annotations); err != nil {
+		return result.Error(err)
+	}
+	if err := cc.Client.Create(cc.Ctx, generateHAProxyPreflightJob(cr, deploymentDef, labels, annotations)); err != nil && !apierrors.IsAlreadyExists(err) {
+		logger.Error(err, "Failed to create the HAProxy preflight Job")
+		return result.Error(err)
+	}
+	if err := cc.setHAProxyConfigCondition(metav1.ConditionUnknown, haproxyConfigReasonValidating, "haproxy -c is checking the configuration"); err != nil {
+		return result.Error(err)
+	}
+	return result.RequeueSoon(requeueForHAProxyPreflight)
+}
+
+func (cc *ClusterContext) applyHAProxyPreflightConfigMap(data map[string]string, labels, annotations map[string]string) error {
+	cr := cc.MarklogicCluster
+	configMap := &corev1.ConfigMap{}
+	err := cc.Client.Get(cc.Ctx, types.NamespacedName{Name: haproxyPreflightName, Namespace: cr.Namespace}, configMap)
+	if apierrors.IsNotFound(err) {
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: haproxyPreflightName, Namespace: cr.Namespace, Labels: labels, Annotations: annotations},
+			Data:       data,
+		}
+		AddOwnerRefToObject(configMap, marklogicClusterAsOwner(cr))
+		err = cc.Client.Create(cc.Ctx, configMap)
+	} else if err == nil {
+		configMap.Annotations = annotations
+		configMap.Data = data
+		err = cc.Client.Update(cc.Ctx, configMap)
+	}
+	if err != nil {
+		cc.ReqLogger.Error(err, "Failed to apply the HAProxy preflight ConfigMap")
+	}
+	return err
+}
+
+// generateHAProxyPreflightJob runs haproxy -c in the HAProxy pod template, with the
+// configuration mounted from the preflight ConfigMap. The pod does not carry the HAProxy
+// selector labels, so that the Service does not route to it.
+func generateHAProxyPreflightJob(cr *marklogicv1.MarklogicCluster, deploymentDef *appsv1.Deployment, labels, annotations map[string]string) *batchv1.Job {
+	podSpec := deploymentDef.Spec.Template.Spec.DeepCopy()
+	podSpec.RestartPolicy = corev1.RestartPolicyNever
+	// The check runs once; it need not be spread like the HAProxy pods.
+	podSpec.TopologySpreadConstraints = nil
+	for i := range podSpec.Volumes {
+		if podSpec.Volumes[i].ConfigMap != nil && podSpec.Volumes[i].Name == "haproxy-config" {
+			podSpec.Volumes[i].ConfigMap.Name = haproxyPreflightName
+		}
+	}
+	container := &podSpec.Containers[0]
+	container.Command = []string{"haproxy", "-c", "-f", "/usr/local/etc/haproxy/haproxy.cfg"}
+	container.Ports = nil
+	container.LivenessProbe = nil
+	container.ReadinessProbe = nil
+	container.StartupProbe = nil
+	// The output of haproxy -c becomes the termination message the condition reports.
+	container.TerminationMessagePolicy = corev1.TerminationMessageFallbackToLogsOnError
+
+	backoffLimit := int32(0)
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: haproxyPreflightName, Namespace: cr.Namespace, Labels: labels, Annotations: annotations},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec:       *podSpec,
+			},
+		},
+	}
+	AddOwnerRefToObject(job, marklogicClusterAsOwner(cr))
+	return job
+}
+
+// haproxyPreflightOutput returns what haproxy -c reported for a failed preflight Job.
+func (cc *ClusterContext) haproxyPreflightOutput(job *batchv1.Job) string {
+	pods := &corev1.PodList{}
+	if err := cc.Client.List(cc.Ctx, pods, client.InNamespace(job.Namespace), client.MatchingLabels{batchv1.JobNameLabel: job.Name}); err != nil {
+		cc.ReqLogger.Error(err, "Failed to list the HAProxy preflight pods")
+	}
+	for _, pod := range pods.Items {
+		if !metav1.IsControlledBy(&pod, job) {
+			continue
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.State.Terminated != nil && strings.TrimSpace(status.State.Terminated.Message) != "" {
+				return "haproxy -c rejected the configuration: " + strings.TrimSpace(status.State.Terminated.Message)
+			}
+		}
+	}
+	return fmt.Sprintf("haproxy -c rejected the configuration, see the logs of Job %s", job.Name)
+}
+
+// finishHAProxyPreflight runs once the configuration is rolled out. It reports the
+// configuration as valid and removes the preflight Job, or drops the condition when no
+// snippets are configured.
+func (cc *ClusterContext) finishHAProxyPreflight() result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	if !haproxySnippetsConfigured(cr) && apimeta.FindStatusCondition(cr.Status.Conditions, string(marklogicv1.ClusterHAProxyConfigValid)) == nil {
+		return result.Continue()
+	}
+	for _, obj := range []client.Object{&batchv1.Job{}, &corev1.ConfigMap{}} {
+		err := cc.Client.Get(cc.Ctx, types.NamespacedName{Name: haproxyPreflightName, Namespace: cr.Namespace}, obj)
+		if err == nil {
+			err = cc.Client.Delete(cc.Ctx, obj, client.PropagationPolicy(metav1.DeletePropagationBackground))
+		}
+		if err != nil && !apierrors.IsNotFound(err) {
+			cc.ReqLogger.Error(err, "Failed to delete the HAProxy preflight resources")
+			return result.Error(err)
+		}
+	}
+	var err error
+	if haproxySnippetsConfigured(cr) {
+		err = cc.setHAProxyConfigCondition(metav1.ConditionTrue, haproxyConfigReasonValid, "haproxy -c accepted the configuration")
+	} else {
+		err = cc.patchClusterStatus(func(status *marklogicv1.MarklogicClusterStatus) {
+			apimeta.RemoveStatusCondition(&status.Conditions, string(marklogicv1.ClusterHAProxyConfigValid))
+		})
+	}
+	if err != nil {
+		return result.Error(err)
+	}
+	return result.Continue()
+}
+
+func (cc *ClusterContext) setHAProxyConfigCondition(conditionStatus metav1.ConditionStatus, reason, message string) error {
+	return cc.patchClusterStatus(func(status *marklogicv1.MarklogicClusterStatus) {
+		setClusterCondition(status, cc.MarklogicCluster.Generation, marklogicv1.ClusterHAProxyConfigValid, conditionStatus, reason, message)
+	})
+}
+
+func (cc *ClusterContext) patchClusterStatus(update func(status *marklogicv1.MarklogicClusterStatus)) error {
+	cr := cc.MarklogicCluster
+	patchClient := client.MergeFrom(cr.DeepCopy())
+	status := cr.Status.DeepCopy()
+	update(status)
+	if reflect.DeepEqual(*status, cr.Status) {
+		return nil
+	}
+	cr.Status = *status
+	if err := cc.Client.Status().Patch(cc.Ctx, cr, patchClient); err != nil {
+		cc.ReqLogger.Error(err, "Failed to update MarkLogicCluster status")
+		return err
+	}
+	return nil
+}
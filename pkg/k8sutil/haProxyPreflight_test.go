@@ -0,0 +1,171 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestConfigSnippetsAreRenderedIntoTheirSections(t *testing.T) {
+	cr := newBalanceTestCluster([]marklogicv1.AppServers{
+		{Name: "app-service", Port: 8000, ExtraConfig: &marklogicv1.HAProxyExtraConfig{
+			Frontend: "http-request deny if { src 10.0.0.0/8 }\n\n  http-request set-header X-Forwarded-Proto https",
+			Backend:  "http-response set-header X-Served-By marklogic",
+		}},
+		{Name: "admin", Port: 8001},
+		{Name: "manage", Port: 8002},
+	}, nil)
+	cr.Spec.HAProxy.GlobalConfig = "maxconn 4096\ntune.bufsize 32768"
+	cr.Spec.HAProxy.DefaultsConfig = "option http-server-close"
+
+	cfg := generateHAProxyConfigMapData(context.Background(), cr)["haproxy.cfg"]
+	for _, expected := range []string{
+		"  maxconn 1024\n  maxconn 4096\n  tune.bufsize 32768\n",
+		"  timeout server 0s\n  option http-server-close\n\nresolvers dns",
+		"%{+Q}r\"\n  http-request deny if { src 10.0.0.0/8 }\n  http-request set-header X-Forwarded-Proto https\n  default_backend marklogic-8000-backend",
+		"  default-server check\n  http-response set-header X-Served-By marklogic\n  server dnode-8000-0 ",
+	} {
+		if !strings.Contains(cfg, expected) {
+			t.Fatalf("expected %q in\n%s", expected, cfg)
+		}
+	}
+	if strings.Count(cfg, "X-Served-By") != 1 || strings.Count(cfg, "deny") != 1 {
+		t.Fatalf("expected the snippets in the app-service sections only, got\n%s", cfg)
+	}
+	for i := 0; i < 5; i++ {
+		if again := generateHAProxyConfigMapData(context.Background(), cr)["haproxy.cfg"]; again != cfg {
+			t.Fatal("expected the configuration to render the same way every time")
+		}
+	}
+}
+
+func TestReconcileHAProxyChecksSnippetsBeforeRollout(t *testing.T) {
+	cc := newAppServerTestContext(t, &marklogicv1.MarklogicGroups{Name: "dnode", IsBootstrap: true, Replicas: int32Ptr(1)})
+	if err := appsv1.AddToScheme(cc.Scheme); err != nil {
+		t.Fatalf("failed to add apps scheme: %v", err)
+	}
+	if err := batchv1.AddToScheme(cc.Scheme); err != nil {
+		t.Fatalf("failed to add batch scheme: %v", err)
+	}
+	cc.Request = &reconcile.Request{NamespacedName: types.NamespacedName{Name: "ml", Namespace: "default"}}
+	cr := cc.MarklogicCluster
+	// The owner references of the generated objects need a UID to be patched.
+	cr.UID = "ml-uid"
+	setGlobalConfig := func(config string) {
+		cr.Spec.HAProxy.GlobalConfig = config
+		if err := cc.Client.Update(cc.Ctx, cr); err != nil {
+			t.Fatalf("failed to update cluster: %v", err)
+		}
+	}
+	setGlobalConfig("maxconn 100000 bogus")
+	preflight := types.NamespacedName{Name: haproxyPreflightName, Namespace: "default"}
+	condition := func() *metav1.Condition {
+		return apimeta.FindStatusCondition(cr.Status.Conditions, string(marklogicv1.ClusterHAProxyConfigValid))
+	}
+
+	res, err := cc.ReconcileHAProxy().Output()
+	if err != nil || res.RequeueAfter == 0 {
+		t.Fatalf("expected a requeue while the configuration is checked, got %+v %v", res, err)
+	}
+	job := &batchv1.Job{}
+	if err := cc.Client.Get(cc.Ctx, preflight, job); err != nil {
+		t.Fatalf("expected a preflight Job: %v", err)
+	}
+	container := job.Spec.Template.Spec.Containers[0]
+	if strings.Join(container.Command, " ") != "haproxy -c -f /usr/local/etc/haproxy/haproxy.cfg" || job.Spec.Template.Spec.Volumes[0].ConfigMap.Name != haproxyPreflightName {
+		t.Fatalf("expected haproxy -c on the preflight ConfigMap, got %+v", job.Spec.Template.Spec)
+	}
+	if job.Spec.Template.Labels["app.kubernetes.io/component"] == "haproxy" {
+		t.Fatal("expected the preflight pod to stay out of the HAProxy Service")
+	}
+	configMap := &corev1.ConfigMap{}
+	if err := cc.Client.Get(cc.Ctx, preflight, configMap); err != nil || !strings.Contains(configMap.Data["haproxy.cfg"], "maxconn 100000 bogus") {
+		t.Fatalf("expected the candidate configuration in the preflight ConfigMap: %v", err)
+	}
+	if err := cc.Client.Get(cc.Ctx, types.NamespacedName{Name: "marklogic-haproxy", Namespace: "default"}, &corev1.ConfigMap{}); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected no HAProxy ConfigMap before the check passed, got %v", err)
+	}
+	if c := condition(); c == nil || c.Status != metav1.ConditionUnknown {
+		t.Fatalf("expected the configuration to be validating, got %+v", c)
+	}
+
+	// haproxy -c rejects the snippet.
+	job.Status.Failed = 1
+	if err := cc.Client.Status().Update(cc.Ctx, job); err != nil {
+		t.Fatalf("failed to update Job status: %v", err)
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "marklogic-haproxy-preflight-abcde", Namespace: "default",
+			Labels:          map[string]string{batchv1.JobNameLabel: haproxyPreflightName},
+			OwnerReferences: []metav1.OwnerReference{{APIVersion: "batch/v1", Kind: "Job", Name: job.Name, UID: job.UID, Controller: boolPtr(true)}},
+		},
+		Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+			Name:  "haproxy",
+			State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Message: "[ALERT] config : parsing [haproxy.cfg:5] : 'maxconn' cannot handle unexpected argument 'bogus'.\n"}},
+		}}},
+	}
+	if err := cc.Client.Create(cc.Ctx, pod); err != nil {
+		t.Fatalf("failed to create pod: %v", err)
+	}
+	res, err = cc.ReconcileHAProxy().Output()
+	if err != nil || res.RequeueAfter != 0 || res.Requeue {
+		t.Fatalf("expected the reconcile to stop, got %+v %v", res, err)
+	}
+	if c := condition(); c == nil || c.Status != metav1.ConditionFalse || c.Reason != haproxyConfigReasonInvalid || !strings.Contains(c.Message, "unexpected argument 'bogus'") {
+		t.Fatalf("expected the haproxy -c output in the condition, got %+v", c)
+	}
+
+	// The snippet is fixed and the new configuration passes.
+	setGlobalConfig("maxconn 100000")
+	if res := cc.ReconcileHAProxy(); !res.Completed() {
+		t.Fatal("expected the outdated preflight Job to be replaced first")
+	}
+	if err := cc.Client.Get(cc.Ctx, preflight, &batchv1.Job{}); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected the outdated preflight Job to be deleted, got %v", err)
+	}
+	if res := cc.ReconcileHAProxy(); !res.Completed() {
+		t.Fatal("expected a requeue while the new configuration is checked")
+	}
+	if err := cc.Client.Get(cc.Ctx, preflight, job); err != nil {
+		t.Fatalf("expected a new preflight Job: %v", err)
+	}
+	job.Status.Succeeded = 1
+	if err := cc.Client.Status().Update(cc.Ctx, job); err != nil {
+		t.Fatalf("failed to update Job status: %v", err)
+	}
+	if res := cc.ReconcileHAProxy(); res.Completed() {
+		t.Fatalf("expected the reconcile to continue, got %+v", res)
+	}
+	haproxyConfig := &corev1.ConfigMap{}
+	if err := cc.Client.Get(cc.Ctx, types.NamespacedName{Name: "marklogic-haproxy", Namespace: "default"}, haproxyConfig); err != nil || !strings.Contains(haproxyConfig.Data["haproxy.cfg"], "maxconn 100000\n") {
+		t.Fatalf("expected the checked configuration to be rolled out: %v", err)
+	}
+	if err := cc.Client.Get(cc.Ctx, preflight, &batchv1.Job{}); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected the preflight Job to be removed, got %v", err)
+	}
+	if c := condition(); c == nil || c.Status != metav1.ConditionTrue {
+		t.Fatalf("expected the configuration to be valid, got %+v", c)
+	}
+
+	// Without snippets nothing is checked and the condition goes away.
+	setGlobalConfig("")
+	if res := cc.ReconcileHAProxy(); res.Completed() {
+		t.Fatalf("expected the reconcile to continue, got %+v", res)
+	}
+	if c := condition(); c != nil {
+		t.Fatalf("expected no condition without snippets, got %+v", c)
+	}
+}
@@ -0,0 +1,339 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/haproxy"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+)
+
+// requeueForHAProxyRuntime polls servers that could not be added or deleted yet, such as a
+// server whose pod has no IP or a removed server that still has connections.
+const requeueForHAProxyRuntime = 10
+
+// NewHAProxyRuntimeClient connects to the runtime API of an HAProxy pod. Tests replace it
+// with a stub.
+var NewHAProxyRuntimeClient = func(address string) haproxy.RuntimeClient {
+	return haproxy.NewRuntimeClient(address)
+}
+
+// ForwardHAProxyRuntimeAPI opens a port-forward through the Kubernetes API server to the
+// runtime API of an HAProxy pod, which only listens on the loopback interface of the pod,
+// and returns the local address to connect to. Tests replace it with a stub.
+var ForwardHAProxyRuntimeAPI = forwardPodPort
+
+// forwardPodPort forwards a local port of the operator to port of the pod until stop is
+// called. The API server authorizes the request against the pods/portforward permission
+// of the operator.
+func forwardPodPort(ctx context.Context, pod *corev1.Pod, port int32) (address string, stop func(), err error) {
+	config, err := GenerateK8sConfig()
+	if err != nil {
+		return "", nil, err
+	}
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return "", nil, err
+	}
+	transport, upgrader, err := spdy.RoundTripperFor(config)
+	if err != nil {
+		return "", nil, err
+	}
+	url := clientset.CoreV1().RESTClient().Post().Resource("pods").Namespace(pod.Namespace).Name(pod.Name).SubResource("portforward").URL()
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, url)
+	stopCh := make(chan struct{})
+	readyCh := make(chan struct{})
+	forwarder, err := portforward.NewOnAddresses(dialer, []string{"127.0.0.1"}, []string{fmt.Sprintf("0:%d", port)}, stopCh, readyCh, io.Discard, io.Discard)
+	if err != nil {
+		return "", nil, err
+	}
+	errCh := make(chan error, 1)
+	go func() { errCh <- forwarder.ForwardPorts() }()
+	select {
+	case <-readyCh:
+	case err := <-errCh:
+		return "", nil, fmt.Errorf("port-forward to pod %s: %w", pod.Name, err)
+	case <-ctx.Done():
+		close(stopCh)
+		return "", nil, ctx.Err()
+	}
+	ports, err := forwarder.GetPorts()
+	if err != nil {
+		close(stopCh)
+		return "", nil, err
+	}
+	return net.JoinHostPort("127.0.0.1", strconv.Itoa(int(ports[0].Local))), func() { close(stopCh) }, nil
+}
+
+func haproxyRuntimeAPIEnabled(cr *marklogicv1.MarklogicCluster) bool {
+	return cr.Spec.HAProxy.RuntimeAPI != nil && cr.Spec.HAProxy.RuntimeAPI.Enabled
+}
+
+func haproxyRuntimeAPIPort(cr *marklogicv1.MarklogicCluster) int32 {
+	return positiveOr(cr.Spec.HAProxy.RuntimeAPI.Port, 9999)
+}
+
+// haproxyRolloutHash is the hash that rolls the HAProxy Deployment when it changes. With
+// the runtime API the membership of the backends is left out: every group is rendered with
+// a single server, and servers are added and removed at runtime.
+func haproxyRolloutHash(ctx context.Context, cr *marklogicv1.MarklogicCluster, data map[string]string) string {
+	if !haproxyRuntimeAPIEnabled(cr) {
+		return calculateHash(data)
+	}
+	structural := cr.DeepCopy()
+	for _, group := range structural.Spec.MarkLogicGroups {
+		if group == nil {
+			continue
+		}
+		one := int32(1)
+		group.Replicas = &one
+	}
+	return calculateHash(generateHAProxyConfigMapData(ctx, structural))
+}
+
+// haproxyServer is a server line of the generated configuration.
+type haproxyServer struct {
+	Backend string
+	Name    string
+	PodName string
+	Port    int
+	// Options are the keywords of the server line, used when the server is added at runtime.
+	Options     []string
+	Weight      int
+	Maintenance bool
+}
+
+// haproxyServers lists the servers generateBackendConfig and generateTcpConfig render.
+func haproxyServers(cr *marklogicv1.MarklogicCluster, config *HAProxyConfig) []haproxyServer {
+	var servers []haproxyServer
+	sslEnabledServer := cr.Spec.Tls != nil && cr.Spec.Tls.EnableOnDefaultAppServers
+	weight := func(drained bool) int {
+		if drained {
+			return 0
+		}
+		return 1
+	}
+	for _, backends := range config.BackendConfigMap {
+		httpCheck := backendsHealthChecked(backends)
+		for _, backend := range backends {
+			for i := 0; i < backend.Replicas; i++ {
+				name := fmt.Sprintf("%s-%d-%d", backend.GroupName, backend.TargetPort, i)
+				podName := fmt.Sprintf("%s-%d", backend.GroupName, i)
+				options := []string{"check"}
+				if httpCheck {
+					options = strings.Fields(healthCheckServerOptions(backend.HealthCheck))
+				}
+				options = append(options, "cookie", name)
+				if sslEnabledServer {
+					options = append(options, "ssl", "verify", "none")
+					if httpCheck {
+						options = append(options, "no-check-ssl")
+					}
+				}
+				servers = append(servers, haproxyServer{
+					Backend:     backend.BackendName,
+					Name:        name,
+					PodName:     podName,
+					Port:        backend.TargetPort,
+					Options:     options,
+					Weight:      weight(backend.Drained),
+					Maintenance: config.Maintenance[podName],
+				})
+			}
+		}
+	}
+	for _, tcpConfigs := range config.TCPConfigMap {
+		httpCheck := tcpHealthChecked(tcpConfigs)
+		for _, tcpConfig := range tcpConfigs {
+			for i := 0; i < tcpConfig.Replicas; i++ {
+				options := []string{"check"}
+				if httpCheck {
+					options = strings.Fields(healthCheckServerOptions(tcpConfig.HealthCheck))
+				}
+				podName := fmt.Sprintf("%s-%d", tcpConfig.PodName, i)
+				servers = append(servers, haproxyServer{
+					Backend:     "marklogic-TCP-" + tcpConfig.TcpName,
+					Name:        fmt.Sprintf("ml-%s-%d-%d", tcpConfig.PodName, tcpConfig.TargetPort, i),
+					PodName:     podName,
+					Port:        tcpConfig.TargetPort,
+					Options:     options,
+					Weight:      weight(tcpConfig.Drained),
+					Maintenance: config.Maintenance[podName],
+				})
+			}
+		}
+	}
+	return servers
+}
+
+// haproxyRuntimePods returns the running HAProxy pods.
+func (cc *ClusterContext) haproxyRuntimePods() ([]*corev1.Pod, error) {
+	cr := cc.MarklogicCluster
+	pods := &corev1.PodList{}
+	if err := cc.Client.List(cc.Ctx, pods, client.InNamespace(cr.Namespace), client.MatchingLabels(getHAProxySelectorLabels(cr.Name))); err != nil {
+		return nil, err
+	}
+	var running []*corev1.Pod
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.DeletionTimestamp != nil || pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		running = append(running, pod)
+	}
+	return running, nil
+}
+
+// ReconcileHAProxyRuntime brings the servers of every running HAProxy process in line with
+// the generated configuration, so that membership changes need no rollout. Servers are
+// added with the IP of their pod and kept in line when the pod is recreated; servers from
+// the configuration file resolve their FQDN themselves. A removed server is put into
+// maintenance, which lets its sessions finish, and deleted once HAProxy accepts the deletion.
+func (cc *ClusterContext) ReconcileHAProxyRuntime() result.ReconcileResult {
+	logger := cc.ReqLogger
+	cr := cc.MarklogicCluster
+	if cr.Spec.HAProxy == nil || !cr.Spec.HAProxy.Enabled || !haproxyRuntimeAPIEnabled(cr) {
+		return result.Continue()
+	}
+	config := generateHAProxyConfig(cc.Ctx, cr)
+	config.Maintenance = cc.haproxyMaintenancePods()
+	desired := haproxyServers(cr, config)
+
+	pods, err := cc.haproxyRuntimePods()
+	if err != nil {
+		logger.Error(err, "Failed to list HAProxy pods")
+		return result.Error(err)
+	}
+	podIPs := map[string]string{}
+	podIP := func(name string) string {
+		if ip, ok := podIPs[name]; ok {
+			return ip
+		}
+		pod := &corev1.Pod{}
+		if err := cc.Client.Get(cc.Ctx, types.NamespacedName{Name: name, Namespace: cr.Namespace}, pod); err != nil && !apierrors.IsNotFound(err) {
+			logger.Error(err, "Failed to get pod for HAProxy server", "pod", name)
+		}
+		podIPs[name] = pod.Status.PodIP
+		return pod.Status.PodIP
+	}
+
+	pending := false
+	for _, pod := range pods {
+		address, stop, err := ForwardHAProxyRuntimeAPI(cc.Ctx, pod, haproxyRuntimeAPIPort(cr))
+		if err != nil {
+			logger.Error(err, "Failed to reach the HAProxy runtime API", "pod", pod.Name)
+			return result.Error(err)
+		}
+		podPending, err := cc.syncHAProxyProcess(NewHAProxyRuntimeClient(address), desired, podIP)
+		stop()
+		if err != nil {
+			logger.Error(err, "HAProxy runtime API update failed", "pod", pod.Name)
+			cc.Recorder.Event(cr, "Warning", "HAProxyRuntimeFailed", err.Error())
+			return result.Error(err)
+		}
+		pending = pending || podPending
+	}
+	if pending {
+		return result.RequeueSoon(requeueForHAProxyRuntime)
+	}
+	return result.Continue()
+}
+
+func (cc *ClusterContext) syncHAProxyProcess(runtime haproxy.RuntimeClient, desired []haproxyServer, podIP func(string) string) (bool, error) {
+	logger := cc.ReqLogger
+	ctx := cc.Ctx
+	states, err := runtime.ShowServersState(ctx)
+	if err != nil {
+		return false, err
+	}
+	current := map[string]haproxy.ServerState{}
+	for _, state := range states {
+		current[state.Backend+"/"+state.Name] = state
+	}
+
+	pending := false
+	wanted := map[string]bool{}
+	for _, server := range desired {
+		key := server.Backend + "/" + server.Name
+		wanted[key] = true
+		ip := podIP(server.PodName)
+		state, exists := current[key]
+		if !exists {
+			if ip == "" {
+				pending = true
+				continue
+			}
+			logger.Info("Adding HAProxy server", "server", key)
+			options := append(append([]string{}, server.Options...), "weight", strconv.Itoa(server.Weight))
+			if err := runtime.AddServer(ctx, server.Backend, server.Name, net.JoinHostPort(ip, strconv.Itoa(server.Port)), options); err != nil {
+				return false, err
+			}
+			if err := runtime.EnableHealth(ctx, server.Backend, server.Name); err != nil {
+				return false, err
+			}
+			// Servers added at runtime start in maintenance.
+			if !server.Maintenance {
+				if err := runtime.SetServerState(ctx, server.Backend, server.Name, "ready"); err != nil {
+					return false, err
+				}
+			}
+			continue
+		}
+		if state.FQDN == "" && ip != "" && state.Address != ip {
+			logger.Info("Updating HAProxy server address", "server", key, "address", ip)
+			if err := runtime.SetServerAddress(ctx, server.Backend, server.Name, ip, server.Port); err != nil {
+				return false, err
+			}
+		}
+		if state.Weight != server.Weight {
+			if err := runtime.SetServerWeight(ctx, server.Backend, server.Name, server.Weight); err != nil {
+				return false, err
+			}
+		}
+		if state.Maintenance != server.Maintenance {
+			newState := "ready"
+			if server.Maintenance {
+				newState = "maint"
+			}
+			logger.Info("Changing HAProxy server state", "server", key, "state", newState)
+			if err := runtime.SetServerState(ctx, server.Backend, server.Name, newState); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	for _, state := range states {
+		key := state.Backend + "/" + state.Name
+		if wanted[key] || !strings.HasPrefix(state.Backend, "marklogic-") {
+			continue
+		}
+		if !state.Maintenance {
+			logger.Info("Draining removed HAProxy server", "server", key)
+			if err := runtime.SetServerState(ctx, state.Backend, state.Name, "maint"); err != nil {
+				return false, err
+			}
+		}
+		if err := runtime.DeleteServer(ctx, state.Backend, state.Name); err != nil {
+			// Typically the server still has connections; retry once they are closed.
+			logger.Info("HAProxy server cannot be deleted yet", "server", key, "reason", err.Error())
+			pending = true
+		}
+	}
+	return pending, nil
+}
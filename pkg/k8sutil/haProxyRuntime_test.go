@@ -0,0 +1,210 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/haproxy"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// stubHAProxyRuntime keeps the servers of one HAProxy process and records the commands.
+type stubHAProxyRuntime struct {
+	servers  []haproxy.ServerState
+	commands []string
+	// busy servers refuse to be deleted.
+	busy map[string]bool
+	// forwards counts the port-forwards to the process that are open.
+	forwards int
+}
+
+func (s *stubHAProxyRuntime) find(backend, server string) *haproxy.ServerState {
+	for i := range s.servers {
+		if s.servers[i].Backend == backend && s.servers[i].Name == server {
+			return &s.servers[i]
+		}
+	}
+	return nil
+}
+
+func (s *stubHAProxyRuntime) ShowServersState(ctx context.Context) ([]haproxy.ServerState, error) {
+	return append([]haproxy.ServerState{}, s.servers...), nil
+}
+
+func (s *stubHAProxyRuntime) AddServer(ctx context.Context, backend, server, address string, options []string) error {
+	s.commands = append(s.commands, fmt.Sprintf("add %s/%s %s %s", backend, server, address, strings.Join(options, " ")))
+	ip, _, _ := strings.Cut(address, ":")
+	s.servers = append(s.servers, haproxy.ServerState{Backend: backend, Name: server, Address: ip, Weight: 1, Maintenance: true})
+	return nil
+}
+
+func (s *stubHAProxyRuntime) DeleteServer(ctx context.Context, backend, server string) error {
+	s.commands = append(s.commands, fmt.Sprintf("del %s/%s", backend, server))
+	if s.busy[server] {
+		return errors.New("Server still has connections attached to it, cannot remove it.")
+	}
+	for i := range s.servers {
+		if s.servers[i].Backend == backend && s.servers[i].Name == server {
+			s.servers = append(s.servers[:i], s.servers[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (s *stubHAProxyRuntime) SetServerState(ctx context.Context, backend, server, state string) error {
+	s.commands = append(s.commands, fmt.Sprintf("state %s/%s %s", backend, server, state))
+	s.find(backend, server).Maintenance = state == "maint"
+	return nil
+}
+
+func (s *stubHAProxyRuntime) SetServerWeight(ctx context.Context, backend, server string, weight int) error {
+	s.commands = append(s.commands, fmt.Sprintf("weight %s/%s %d", backend, server, weight))
+	s.find(backend, server).Weight = weight
+	return nil
+}
+
+func (s *stubHAProxyRuntime) SetServerAddress(ctx context.Context, backend, server, ip string, port int) error {
+	s.commands = append(s.commands, fmt.Sprintf("addr %s/%s %s", backend, server, ip))
+	s.find(backend, server).Address = ip
+	return nil
+}
+
+func (s *stubHAProxyRuntime) EnableHealth(ctx context.Context, backend, server string) error {
+	s.commands = append(s.commands, fmt.Sprintf("health %s/%s", backend, server))
+	return nil
+}
+
+func newHAProxyRuntimeTestContext(t *testing.T, runtime *stubHAProxyRuntime) *ClusterContext {
+	t.Helper()
+	cc := newAppServerTestContext(t, &marklogicv1.MarklogicGroups{Name: "dnode", IsBootstrap: true, Replicas: int32Ptr(2)})
+	cc.MarklogicCluster.Spec.HAProxy.RuntimeAPI = &marklogicv1.HAProxyRuntimeAPI{Enabled: true, Port: 9999}
+	pods := []*corev1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "haproxy-a", Namespace: "default", Labels: getHAProxySelectorLabels("ml")},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "10.1.0.1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "dnode-0", Namespace: "default"}, Status: corev1.PodStatus{PodIP: "10.0.0.10"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "dnode-1", Namespace: "default"}, Status: corev1.PodStatus{PodIP: "10.0.0.11"}},
+	}
+	for _, pod := range pods {
+		if err := cc.Client.Create(cc.Ctx, pod); err != nil {
+			t.Fatalf("failed to create pod: %v", err)
+		}
+	}
+	originalForward := ForwardHAProxyRuntimeAPI
+	ForwardHAProxyRuntimeAPI = func(ctx context.Context, pod *corev1.Pod, port int32) (string, func(), error) {
+		if pod.Name != "haproxy-a" || port != 9999 {
+			t.Errorf("unexpected port-forward to %s:%d", pod.Name, port)
+		}
+		runtime.forwards++
+		return "127.0.0.1:40000", func() { runtime.forwards-- }, nil
+	}
+	original := NewHAProxyRuntimeClient
+	NewHAProxyRuntimeClient = func(address string) haproxy.RuntimeClient {
+		if address != "127.0.0.1:40000" {
+			t.Errorf("unexpected runtime API address %s", address)
+		}
+		return runtime
+	}
+	t.Cleanup(func() {
+		ForwardHAProxyRuntimeAPI = originalForward
+		NewHAProxyRuntimeClient = original
+	})
+	return cc
+}
+
+func TestHAProxyRolloutHashIgnoresMembership(t *testing.T) {
+	cc := newHAProxyRuntimeTestContext(t, &stubHAProxyRuntime{})
+	cr := cc.MarklogicCluster
+	hash := func() string {
+		return haproxyRolloutHash(context.Background(), cr, generateHAProxyConfigMapData(context.Background(), cr))
+	}
+
+	before := hash()
+	cr.Spec.MarkLogicGroups[0].Replicas = int32Ptr(5)
+	cr.Spec.MarkLogicGroups[0].Maintenance = true
+	if after := hash(); after != before {
+		t.Fatal("expected replica and drain changes to leave the rollout hash alone")
+	}
+	cr.Spec.HAProxy.AppServers = append(cr.Spec.HAProxy.AppServers, marklogicv1.AppServers{Name: "manage", Port: 8002})
+	if after := hash(); after == before {
+		t.Fatal("expected a new App Server to roll HAProxy")
+	}
+
+	cr.Spec.HAProxy.RuntimeAPI.Enabled = false
+	data := generateHAProxyConfigMapData(context.Background(), cr)
+	if haproxyRolloutHash(context.Background(), cr, data) != calculateHash(data) || strings.Contains(data["haproxy.cfg"], "stats socket") {
+		t.Fatal("expected the full configuration to be hashed without the runtime API")
+	}
+}
+
+func TestReconcileHAProxyRuntimeAddsDrainsAndRemovesServers(t *testing.T) {
+	runtime := &stubHAProxyRuntime{
+		servers: []haproxy.ServerState{
+			// From the configuration file, resolved through DNS.
+			{Backend: "marklogic-8000-backend", Name: "dnode-8000-0", Address: "10.0.0.10", Weight: 1, FQDN: "dnode-0.dnode.default.svc.cluster.local"},
+			// Left over from a scale-down, still serving a session.
+			{Backend: "marklogic-8000-backend", Name: "dnode-8000-2", Address: "10.0.0.12", Weight: 1},
+			{Backend: "stats", Name: "other", Weight: 1},
+		},
+		busy: map[string]bool{"dnode-8000-2": true},
+	}
+	cc := newHAProxyRuntimeTestContext(t, runtime)
+
+	res, err := cc.ReconcileHAProxyRuntime().Output()
+	if err != nil || res.RequeueAfter == 0 {
+		t.Fatalf("expected a requeue while the removed server has connections, got %+v %v", res, err)
+	}
+	expected := []string{
+		"add marklogic-8000-backend/dnode-8000-1 10.0.0.11:8000 check cookie dnode-8000-1 weight 1",
+		"health marklogic-8000-backend/dnode-8000-1",
+		"state marklogic-8000-backend/dnode-8000-1 ready",
+		"state marklogic-8000-backend/dnode-8000-2 maint",
+		"del marklogic-8000-backend/dnode-8000-2",
+	}
+	if strings.Join(runtime.commands, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("unexpected commands:\n%s", strings.Join(runtime.commands, "\n"))
+	}
+
+	// The group is drained and the connections are gone.
+	runtime.commands = nil
+	runtime.busy = nil
+	cc.MarklogicCluster.Spec.MarkLogicGroups[0].Maintenance = true
+	if res := cc.ReconcileHAProxyRuntime(); res.Completed() {
+		t.Fatalf("expected the reconcile to continue, got %+v", res)
+	}
+	expected = []string{
+		"weight marklogic-8000-backend/dnode-8000-0 0",
+		"weight marklogic-8000-backend/dnode-8000-1 0",
+		"del marklogic-8000-backend/dnode-8000-2",
+	}
+	if strings.Join(runtime.commands, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("unexpected commands:\n%s", strings.Join(runtime.commands, "\n"))
+	}
+	if runtime.find("stats", "other") == nil {
+		t.Fatal("expected servers outside the generated backends to be left alone")
+	}
+}
+
+func TestHAProxyRuntimeAPIListensOnLoopbackOnly(t *testing.T) {
+	runtime := &stubHAProxyRuntime{}
+	cc := newHAProxyRuntimeTestContext(t, runtime)
+	config := generateHAProxyConfigMapData(context.Background(), cc.MarklogicCluster)["haproxy.cfg"]
+	if !strings.Contains(config, "stats socket ipv4@127.0.0.1:9999 level admin\n") {
+		t.Fatalf("expected the runtime API on the loopback interface, got:\n%s", config)
+	}
+
+	cc.ReconcileHAProxyRuntime()
+	if len(runtime.commands) == 0 {
+		t.Fatal("expected the servers to be added through the port-forward")
+	}
+	if runtime.forwards != 0 {
+		t.Fatalf("expected every port-forward to be closed, %d left open", runtime.forwards)
+	}
+}
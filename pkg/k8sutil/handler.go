@@ -18,9 +18,15 @@ func (oc *OperatorContext) ReconsileMarklogicGroupHandler() (reconcile.Result, e
 		return reconcile.Result{Requeue: true}, nil
 	}
 
+	if result := oc.ReconcilePause(); result.Completed() {
+		return result.Output()
+	}
 	if result := oc.ReconcileServices(); result.Completed() {
 		return result.Output()
 	}
+	if result := oc.ReconcilePodDisruptionBudget(); result.Completed() {
+		return result.Output()
+	}
 	err := setOperatorInternalStatus(oc, "Created")
 	if err != nil {
 		oc.ReqLogger.Error(err, "Failed to set operator internal status")
@@ -36,25 +42,56 @@ func (oc *OperatorContext) ReconsileMarklogicGroupHandler() (reconcile.Result, e
 		}
 	}
 
+	if result := oc.ReconcileCertificates(); result.Completed() {
+		return result.Output()
+	}
+
 	if result := oc.ReconcileVolumeResizeValidation(); result.Completed() {
 		return result.Output()
 	}
 
+	if result := oc.ReconcileAutoscaling(); result.Completed() {
+		return result.Output()
+	}
+
 	result, err := oc.ReconcileStatefulset()
 	if err != nil {
 		return result, err
 	}
 
+	if joinResult := oc.ReconcileOperatorJoin(); joinResult.Completed() {
+		return joinResult.Output()
+	}
+
+	if rotationResult := oc.ReconcileCertificateRotation(); rotationResult.Completed() {
+		return rotationResult.Output()
+	}
+
+	if metricsResult := oc.ReconcileServerMetrics(); metricsResult.Completed() {
+		return metricsResult.Output()
+	}
+
 	if oc.MarklogicGroup.Spec.IsDynamic {
 		if dynamicResult := oc.ReconcileDynamicGroupConfig(); dynamicResult.Completed() {
-			return dynamicResult.Output()
+			dynamicRes, dynamicErr := dynamicResult.Output()
+			dynamicRes, dynamicErr = requeueForAutoscaling(oc.MarklogicGroup, dynamicRes, dynamicErr)
+			return requeueForServerMetrics(oc.MarklogicGroup, dynamicRes, dynamicErr)
 		}
+	} else if scaleDownResult := oc.ReconcileScaleDown(); scaleDownResult.Completed() {
+		return scaleDownResult.Output()
+	} else if failoverResult := oc.ReconcileFailover(); failoverResult.Completed() {
+		return failoverResult.Output()
 	}
 
-	return result, err
+	result, err = requeueForAutoscaling(oc.MarklogicGroup, result, err)
+	result, err = requeueForServerMetrics(oc.MarklogicGroup, result, err)
+	return requeueForFailover(oc.MarklogicGroup, result, err)
 }
 
 func (cc *ClusterContext) ReconsileMarklogicClusterHandler() (reconcile.Result, error) {
+	if result := cc.ReconcilePause(); result.Completed() {
+		return result.Output()
+	}
 	if result := cc.ReconcileServiceAccount(); result.Completed() {
 		return result.Output()
 	}
@@ -62,12 +99,20 @@ func (cc *ClusterContext) ReconsileMarklogicClusterHandler() (reconcile.Result,
 		return result.Output()
 	}
 	result, err := cc.ReconsileMarklogicCluster()
+	if err == nil {
+		if statusResult := cc.ReconcileClusterStatus(); statusResult.Completed() {
+			return statusResult.Output()
+		}
+	}
 	if cc.MarklogicCluster.Spec.NetworkPolicy.Enabled {
 		if result := cc.ReconcileNetworkPolicy(); result.Completed() {
 			return result.Output()
 		}
 	}
 	if cc.MarklogicCluster.Spec.HAProxy != nil && cc.MarklogicCluster.Spec.HAProxy.Enabled {
+		if result := cc.ReconcileHAProxyCertificate(); result.Completed() {
+			return result.Output()
+		}
 		if result := cc.ReconcileHAProxy(); result.Completed() {
 			return result.Output()
 		}
@@ -76,6 +121,54 @@ func (cc *ClusterContext) ReconsileMarklogicClusterHandler() (reconcile.Result,
 				return result.Output()
 			}
 		}
+		if result := cc.ReconcileGatewayRoutes(); result.Completed() {
+			return result.Output()
+		}
+	}
+	if result := cc.ReconcileHAProxyPodDisruptionBudget(); result.Completed() {
+		return result.Output()
+	}
+	if result := cc.ReconcileHAProxyAutoscaling(); result.Completed() {
+		return result.Output()
+	}
+	if err == nil {
+		if result := cc.ReconcileAdminPasswordRotation(); result.Completed() {
+			return result.Output()
+		}
+		if result := cc.ReconcileAppServers(); result.Completed() {
+			return result.Output()
+		}
+		if result := cc.ReconcileHAProxyRuntime(); result.Completed() {
+			return result.Output()
+		}
+	}
+	return requeueForPasswordRotation(cc.MarklogicCluster, result, err)
+}
+
+func (bc *BackupContext) ReconcileMarklogicBackupHandler() (reconcile.Result, error) {
+	if result := bc.ReconcileBackup(); result.Completed() {
+		return result.Output()
+	}
+	return reconcile.Result{}, nil
+}
+
+func (rc *RestoreContext) ReconcileMarklogicRestoreHandler() (reconcile.Result, error) {
+	if result := rc.ReconcileRestore(); result.Completed() {
+		return result.Output()
+	}
+	return reconcile.Result{}, nil
+}
+
+func (dc *DatabaseContext) ReconcileMarklogicDatabaseHandler() (reconcile.Result, error) {
+	if result := dc.ReconcileDatabase(); result.Completed() {
+		return result.Output()
+	}
+	return reconcile.Result{}, nil
+}
+
+func (rc *ReplicationContext) ReconcileMarklogicReplicationHandler() (reconcile.Result, error) {
+	if result := rc.ReconcileReplication(); result.Completed() {
+		return result.Output()
 	}
-	return result, err
+	return reconcile.Result{}, nil
 }
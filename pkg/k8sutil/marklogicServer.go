@@ -49,13 +49,21 @@ type MarkLogicGroupParameters struct {
 	IsBootstrap                    bool
 	IsDynamic                      bool
 	Dynamic                        *marklogicv1.DynamicGroupConfig
+	Autoscaling                    *marklogicv1.GroupAutoscaling
+	JoinMode                       marklogicv1.JoinMode
+	Maintenance                    bool
+	Failover                       *marklogicv1.ForestFailover
+	PodDisruptionBudget            *marklogicv1.PodDisruptionBudget
 	LogCollection                  *marklogicv1.LogCollection
+	Metrics                        *marklogicv1.ServerMetrics
 	PathBasedRouting               bool
 	Tls                            *marklogicv1.Tls
 	AdditionalVolumes              *[]corev1.Volume
 	AdditionalVolumeMounts         *[]corev1.VolumeMount
 	SecretName                     string
+	AdminCredentialsCSI            *corev1.CSIVolumeSource
 	AdditionalVolumeClaimTemplates *[]corev1.PersistentVolumeClaim
+	AppServers                     []marklogicv1.AppServer
 }
 
 type MarkLogicClusterParameters struct {
@@ -80,6 +88,7 @@ type MarkLogicClusterParameters struct {
 	LivenessProbe                  marklogicv1.ContainerProbe
 	ReadinessProbe                 marklogicv1.ContainerProbe
 	LogCollection                  *marklogicv1.LogCollection
+	Metrics                        *marklogicv1.ServerMetrics
 	PodSecurityContext             *corev1.PodSecurityContext
 	ContainerSecurityContext       *corev1.SecurityContext
 	PathBasedRouting               bool
@@ -147,6 +156,11 @@ func (cc *ClusterContext) GenerateMarkLogicGroupDef(cr *marklogicv1.MarklogicClu
 			EnableConverters:               params.EnableConverters,
 			IsDynamic:                      params.IsDynamic,
 			Dynamic:                        params.Dynamic,
+			Autoscaling:                    params.Autoscaling,
+			JoinMode:                       params.JoinMode,
+			Maintenance:                    params.Maintenance,
+			Failover:                       params.Failover,
+			PodDisruptionBudget:            params.PodDisruptionBudget,
 			PriorityClassName:              params.PriorityClassName,
 			ClusterDomain:                  params.ClusterDomain,
 			UpdateStrategy:                 params.UpdateStrategy,
@@ -157,6 +171,7 @@ func (cc *ClusterContext) GenerateMarkLogicGroupDef(cr *marklogicv1.MarklogicClu
 			LivenessProbe:                  params.LivenessProbe,
 			ReadinessProbe:                 params.ReadinessProbe,
 			LogCollection:                  params.LogCollection,
+			Metrics:                        params.Metrics,
 			TopologySpreadConstraints:      params.TopologySpreadConstraints,
 			PodSecurityContext:             params.PodSecurityContext,
 			ContainerSecurityContext:       params.ContainerSecurityContext,
@@ -165,7 +180,9 @@ func (cc *ClusterContext) GenerateMarkLogicGroupDef(cr *marklogicv1.MarklogicClu
 			AdditionalVolumes:              params.AdditionalVolumes,
 			AdditionalVolumeMounts:         params.AdditionalVolumeMounts,
 			SecretName:                     params.SecretName,
+			AdminCredentialsCSI:            params.AdminCredentialsCSI,
 			AdditionalVolumeClaimTemplates: params.AdditionalVolumeClaimTemplates,
+			AppServers:                     params.AppServers,
 		},
 	}
 	AddOwnerRefToObject(MarkLogicGroupDef, ownerDef)
@@ -180,14 +197,27 @@ func (cc *ClusterContext) ReconsileMarklogicCluster() (reconcile.Result, error)
 	logger.Info("===== Total Count ==== ", "Count:", total)
 	cr := cc.MarklogicCluster
 
+	clusterParams := generateMarkLogicClusterParams(cr)
+	desiredImages := make(map[string]string, total)
+	for i := 0; i < total; i++ {
+		desiredImages[cr.Spec.MarkLogicGroups[i].Name] = generateMarkLogicGroupParams(cr, i, clusterParams).Image
+	}
+	upgradeHolds, upgradeInProgress, upgradeResult := cc.reconcileMarkLogicUpgrade(desiredImages)
+	if upgradeResult.Completed() {
+		return upgradeResult.Output()
+	}
+
 	for i := 0; i < total; i++ {
 		logger.Info("ReconcileCluster", "Count", i)
 		currentMlg := &marklogicv1.MarklogicGroup{}
 		namespace := cr.Namespace
 		name := cr.Spec.MarkLogicGroups[i].Name
 		namespacedName := types.NamespacedName{Name: name, Namespace: namespace}
-		clusterParams := generateMarkLogicClusterParams(cr)
 		params := generateMarkLogicGroupParams(cr, i, clusterParams)
+		if heldImage, held := upgradeHolds[name]; held {
+			// Queued behind the group that is currently upgrading.
+			params.Image = heldImage
+		}
 		markLogicGroupDef := cc.GenerateMarkLogicGroupDef(operatorCR, i, params)
 		err := cc.Client.Get(cc.Ctx, namespacedName, currentMlg)
 		if err != nil {
@@ -196,6 +226,10 @@ func (cc *ClusterContext) ReconsileMarklogicCluster() (reconcile.Result, error)
 				if err := patch.DefaultAnnotator.SetLastAppliedAnnotation(markLogicGroupDef); err != nil {
 					logger.Error(err, "Failed to set last applied annotation")
 				}
+				if policy := autoscalingPolicy(markLogicGroupDef); policy != nil && markLogicGroupDef.Spec.Replicas != nil {
+					replicas := clampReplicas(*markLogicGroupDef.Spec.Replicas, policy)
+					markLogicGroupDef.Spec.Replicas = &replicas
+				}
 				err = cc.Client.Create(ctx, markLogicGroupDef)
 				if err != nil {
 					logger.Error(err, "Failed to create markLogicCluster")
@@ -231,6 +265,10 @@ func (cc *ClusterContext) ReconsileMarklogicCluster() (reconcile.Result, error)
 				if err := patch.DefaultAnnotator.SetLastAppliedAnnotation(markLogicGroupDef); err != nil {
 					logger.Error(err, "Failed to set last applied annotation")
 				}
+				// The annotation keeps the spec replicas so that a later spec change is still detected.
+				if replicas := autoscaledReplicas(currentMlg, markLogicGroupDef); replicas != nil {
+					markLogicGroupDef.Spec.Replicas = replicas
+				}
 				err := cc.Client.Update(cc.Ctx, markLogicGroupDef)
 				if err != nil {
 					logger.Error(err, "Error updating MarklogicGroup")
@@ -242,6 +280,9 @@ func (cc *ClusterContext) ReconsileMarklogicCluster() (reconcile.Result, error)
 		}
 
 	}
+	if upgradeInProgress {
+		return result.RequeueSoon(upgradeRequeueSeconds).Output()
+	}
 	return result.Done().Output()
 }
 
@@ -278,6 +319,7 @@ func generateMarkLogicClusterParams(cr *marklogicv1.MarklogicCluster) *MarkLogic
 		LivenessProbe:                  marklogicv1.ContainerProbe{Enabled: true, InitialDelaySeconds: 30, TimeoutSeconds: 5, PeriodSeconds: 30, SuccessThreshold: 1, FailureThreshold: 3},
 		ReadinessProbe:                 marklogicv1.ContainerProbe{Enabled: true, InitialDelaySeconds: 10, TimeoutSeconds: 5, PeriodSeconds: 30, SuccessThreshold: 1, FailureThreshold: 3},
 		LogCollection:                  cr.Spec.LogCollection,
+		Metrics:                        cr.Spec.Metrics,
 		Auth:                           cr.Spec.Auth,
 		PodSecurityContext:             cr.Spec.PodSecurityContext,
 		ContainerSecurityContext:       cr.Spec.ContainerSecurityContext,
@@ -308,6 +350,7 @@ func generateMarkLogicGroupParams(cr *marklogicv1.MarklogicCluster, index int, c
 		Annotations:                    cr.Spec.MarkLogicGroups[index].Annotations,
 		GroupConfig:                    cr.Spec.MarkLogicGroups[index].GroupConfig,
 		Service:                        cr.Spec.MarkLogicGroups[index].Service,
+		AppServers:                     cr.Spec.MarkLogicGroups[index].AppServers,
 		Image:                          clusterParams.Image,
 		ImagePullPolicy:                clusterParams.ImagePullPolicy,
 		ImagePullSecrets:               clusterParams.ImagePullSecrets,
@@ -333,7 +376,13 @@ func generateMarkLogicGroupParams(cr *marklogicv1.MarklogicCluster, index int, c
 		IsBootstrap:                    cr.Spec.MarkLogicGroups[index].IsBootstrap,
 		IsDynamic:                      cr.Spec.MarkLogicGroups[index].IsDynamic,
 		Dynamic:                        cr.Spec.MarkLogicGroups[index].Dynamic,
+		Autoscaling:                    cr.Spec.MarkLogicGroups[index].Autoscaling,
+		JoinMode:                       cr.Spec.MarkLogicGroups[index].JoinMode,
+		Maintenance:                    cr.Spec.MarkLogicGroups[index].Maintenance,
+		Failover:                       cr.Spec.MarkLogicGroups[index].Failover,
+		PodDisruptionBudget:            cr.Spec.MarkLogicGroups[index].PodDisruptionBudget,
 		LogCollection:                  clusterParams.LogCollection,
+		Metrics:                        clusterParams.Metrics,
 		PathBasedRouting:               clusterParams.PathBasedRouting,
 		Tls:                            clusterParams.Tls,
 		AdditionalVolumeMounts:         clusterParams.AdditionalVolumeMounts,
@@ -353,10 +402,9 @@ func generateMarkLogicGroupParams(cr *marklogicv1.MarklogicCluster, index int, c
 		markLogicGroupParameters.AdditionalVolumeClaimTemplates = cr.Spec.MarkLogicGroups[index].AdditionalVolumeClaimTemplates
 	}
 
-	if cr.Spec.Auth != nil && cr.Spec.Auth.SecretName != nil && *cr.Spec.Auth.SecretName != "" {
-		markLogicGroupParameters.SecretName = *cr.Spec.Auth.SecretName
-	} else {
-		markLogicGroupParameters.SecretName = fmt.Sprintf("%s-admin", cr.ObjectMeta.Name)
+	markLogicGroupParameters.SecretName = clusterAdminSecretName(cr)
+	if cr.Spec.Auth != nil {
+		markLogicGroupParameters.AdminCredentialsCSI = cr.Spec.Auth.CSI
 	}
 	if cr.Spec.MarkLogicGroups[index].HAProxy != nil && cr.Spec.MarkLogicGroups[index].HAProxy.PathBasedRouting != nil {
 		markLogicGroupParameters.PathBasedRouting = *cr.Spec.MarkLogicGroups[index].HAProxy.PathBasedRouting
@@ -394,6 +442,9 @@ func generateMarkLogicGroupParams(cr *marklogicv1.MarklogicCluster, index int, c
 	if cr.Spec.MarkLogicGroups[index].LogCollection != nil {
 		markLogicGroupParameters.LogCollection = cr.Spec.MarkLogicGroups[index].LogCollection
 	}
+	if cr.Spec.MarkLogicGroups[index].Metrics != nil {
+		markLogicGroupParameters.Metrics = cr.Spec.MarkLogicGroups[index].Metrics
+	}
 	if cr.Spec.MarkLogicGroups[index].Tls != nil {
 		markLogicGroupParameters.Tls = cr.Spec.MarkLogicGroups[index].Tls
 	}
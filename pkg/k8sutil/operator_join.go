@@ -0,0 +1,449 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	operatorJoinRequeueSeconds = 10
+
+	joinHostStatePending     = "pending"
+	joinHostStateInitialized = "initialized"
+	joinHostStateSecuring    = "securing"
+	joinHostStateJoining     = "joining"
+	joinHostStateJoined      = "joined"
+
+	appServicesServerName = "App-Services"
+)
+
+// operatorJoinClient is the part of the Management API that initializes the hosts of a group
+// and joins them to the cluster.
+type operatorJoinClient interface {
+	mlmanage.HostClient
+	mlmanage.GroupClient
+	mlmanage.AdminClient
+	mlmanage.AppServerClient
+}
+
+// OperatorJoinRestartTimeout is how long a host may take to restart after initialization or
+// a cluster configuration change before the step is repeated.
+var OperatorJoinRestartTimeout = 2 * time.Minute
+
+// ReconcileOperatorJoin does for a group with joinMode operator what cluster-config.sh does
+// inside the pods: it initializes the bootstrap host and its Security database, creates and
+// configures the MarkLogic group, and joins the other hosts one at a time through the Admin
+// API. Every pass advances one step and requeues while a host restarts, so the progress of
+// each host is visible in status.join.
+func (oc *OperatorContext) ReconcileOperatorJoin() result.ReconcileResult {
+	cr := oc.MarklogicGroup
+	patchClient := client.MergeFrom(cr.DeepCopy())
+	original := cr.Status.DeepCopy()
+	if cr.Spec.JoinMode != marklogicv1.JoinModeOperator || cr.Spec.IsDynamic || cr.DeletionTimestamp != nil {
+		if cr.Status.Join == nil {
+			return result.Continue()
+		}
+		cr.Status.Join = nil
+		apimeta.RemoveStatusCondition(&cr.Status.Conditions, string(marklogicv1.GroupHostsJoined))
+		return oc.patchJoinStatus(patchClient, original, result.Continue())
+	}
+
+	status := &marklogicv1.JoinStatus{Phase: marklogicv1.JoinPhasePending}
+	if cr.Status.Join != nil {
+		status = cr.Status.Join.DeepCopy()
+	}
+	previousPhase := status.Phase
+	pods, err := oc.listGroupPods(false)
+	if err != nil {
+		oc.ReqLogger.Error(err, "Failed to list the pods of the group")
+		return result.Error(err)
+	}
+	status.Hosts = reconcileJoinHosts(cr, pods, status.Hosts)
+
+	mc, err := oc.groupManagementClient()
+	if err == nil {
+		err = oc.advanceOperatorJoin(mc, status, pods)
+	}
+	status.Message = ""
+	if err != nil {
+		oc.ReqLogger.Error(err, "Failed to advance the operator join", "phase", status.Phase)
+		status.Message = err.Error()
+	}
+
+	now := metav1.Now()
+	conditionStatus := metav1.ConditionFalse
+	if status.Phase == marklogicv1.JoinPhaseJoined {
+		conditionStatus = metav1.ConditionTrue
+		if previousPhase != marklogicv1.JoinPhaseJoined {
+			oc.Recorder.Eventf(cr, corev1.EventTypeNormal, "HostsJoined", "All %d hosts of group %s joined the cluster", len(pods), resolvedMarkLogicGroupName(cr))
+		}
+	}
+	apimeta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
+		Type:               string(marklogicv1.GroupHostsJoined),
+		Status:             conditionStatus,
+		ObservedGeneration: cr.Generation,
+		Reason:             string(status.Phase),
+		Message:            status.Message,
+		LastTransitionTime: now,
+	})
+	cr.Status.Join = status
+
+	requeue := result.RequeueSoon(operatorJoinRequeueSeconds)
+	if status.Phase == marklogicv1.JoinPhaseJoined {
+		requeue = result.Continue()
+	}
+	return oc.patchJoinStatus(patchClient, original, requeue)
+}
+
+// advanceOperatorJoin moves the group one step further and records the phase it reached.
+// A host waiting for a restart returns without error; errors are recorded on the host and
+// the step is retried on the next pass.
+func (oc *OperatorContext) advanceOperatorJoin(mc operatorJoinClient, status *marklogicv1.JoinStatus, pods []corev1.Pod) error {
+	cr := oc.MarklogicGroup
+	groupName := resolvedMarkLogicGroupName(cr)
+	isBootstrapGroup := strings.TrimSpace(cr.Spec.BootstrapHost) == ""
+
+	if isBootstrapGroup {
+		status.Phase = marklogicv1.JoinPhaseInitializingBootstrap
+		podName := cr.Spec.Name + "-0"
+		pod := findDynamicPodByName(pods, podName)
+		if !isPodRunning(pod) {
+			setJoinHostStatus(status, podName, dynamicPodFQDN(cr, podName), joinHostStatePending, "waiting for the pod to start")
+			return nil
+		}
+		done, err := oc.initializeBootstrapHost(mc, status, podName)
+		if err != nil || !done {
+			return err
+		}
+	}
+
+	if status.ConfiguredGeneration != cr.Generation {
+		status.Phase = marklogicv1.JoinPhaseConfiguringGroup
+		if err := oc.configureJoinGroup(mc, isBootstrapGroup, groupName); err != nil {
+			return fmt.Errorf("failed to configure group %s: %w", groupName, err)
+		}
+		status.ConfiguredGeneration = cr.Generation
+	}
+
+	// Hosts above the desired replicas are left to the scale-down, which takes them out of
+	// the cluster.
+	status.Phase = marklogicv1.JoinPhaseJoiningHosts
+	desired := int(desiredDynamicReplicas(cr))
+	for ordinal := 0; ordinal < desired; ordinal++ {
+		if isBootstrapGroup && ordinal == 0 {
+			continue
+		}
+		podName := fmt.Sprintf("%s-%d", cr.Spec.Name, ordinal)
+		hostFQDN := dynamicPodFQDN(cr, podName)
+		if !isPodRunning(findDynamicPodByName(pods, podName)) {
+			setJoinHostStatus(status, podName, hostFQDN, joinHostStatePending, "waiting for the pod to start")
+			return nil
+		}
+		done, err := oc.joinHost(mc, status, podName, groupName)
+		if err != nil || !done {
+			return err
+		}
+	}
+	status.Phase = marklogicv1.JoinPhaseJoined
+	return nil
+}
+
+// initializeBootstrapHost initializes MarkLogic on the first host of the bootstrap group and
+// installs the Security database with the admin credentials. It reports done once the host
+// asks for credentials.
+func (oc *OperatorContext) initializeBootstrapHost(mc mlmanage.AdminClient, status *marklogicv1.JoinStatus, podName string) (bool, error) {
+	cr := oc.MarklogicGroup
+	hostFQDN := dynamicPodFQDN(cr, podName)
+	secured, err := mc.HostSecurityInitialized(oc.Ctx, hostFQDN)
+	if err != nil {
+		return false, oc.joinHostFailed(status, podName, hostFQDN, "host is not responding", err)
+	}
+	current := findJoinHostStatus(status, podName)
+	if secured {
+		oc.joinHostSucceeded(status, podName, hostFQDN, current, "bootstrap host initialized")
+		return true, nil
+	}
+
+	switch {
+	case current.State == joinHostStateSecuring && !restartTimedOut(current):
+		return false, nil
+	case current.State == joinHostStateInitialized || current.State == joinHostStateSecuring:
+		username, password, err := readAdminCredentials(oc.Ctx, oc.Client, cr.Namespace, groupAdminSecretName(cr))
+		if err != nil {
+			return false, oc.joinHostFailed(status, podName, hostFQDN, "failed to read the admin credentials", err)
+		}
+		walletPassword, err := oc.readWalletPassword()
+		if err != nil {
+			return false, oc.joinHostFailed(status, podName, hostFQDN, "failed to read the wallet password", err)
+		}
+		oc.ReqLogger.Info("Initializing the Security database of the bootstrap host", "host", hostFQDN)
+		err = mc.InitializeSecurity(oc.Ctx, hostFQDN, mlmanage.SecurityOptions{Username: username, Password: password, WalletPassword: walletPassword})
+		if err != nil {
+			return false, oc.joinHostFailed(status, podName, hostFQDN, "failed to initialize security", err)
+		}
+		setJoinHostStatus(status, podName, hostFQDN, joinHostStateSecuring, "restarting after security initialization")
+		return false, nil
+	}
+	return false, oc.initHost(mc, status, podName, hostFQDN)
+}
+
+// joinHost initializes a host and installs the cluster configuration the bootstrap host
+// generates for it. It reports done once the host asks for credentials, which it only does
+// as a member of the cluster.
+func (oc *OperatorContext) joinHost(mc mlmanage.AdminClient, status *marklogicv1.JoinStatus, podName, groupName string) (bool, error) {
+	hostFQDN := dynamicPodFQDN(oc.MarklogicGroup, podName)
+	secured, err := mc.HostSecurityInitialized(oc.Ctx, hostFQDN)
+	if err != nil {
+		return false, oc.joinHostFailed(status, podName, hostFQDN, "host is not responding", err)
+	}
+	current := findJoinHostStatus(status, podName)
+	if secured {
+		oc.joinHostSucceeded(status, podName, hostFQDN, current, "joined group "+groupName)
+		return true, nil
+	}
+
+	switch {
+	case current.State == joinHostStateJoining && !restartTimedOut(current):
+		return false, nil
+	case current.State == joinHostStateInitialized || current.State == joinHostStateJoining:
+		oc.ReqLogger.Info("Joining host to the cluster", "host", hostFQDN, "group", groupName)
+		serverConfig, err := mc.GetServerConfig(oc.Ctx, hostFQDN)
+		if err != nil {
+			return false, oc.joinHostFailed(status, podName, hostFQDN, "failed to read the server configuration", err)
+		}
+		clusterConfig, err := mc.GetClusterConfig(oc.Ctx, groupName, serverConfig)
+		if err != nil {
+			return false, oc.joinHostFailed(status, podName, hostFQDN, "failed to get the cluster configuration from the bootstrap host", err)
+		}
+		if err := mc.ApplyClusterConfig(oc.Ctx, hostFQDN, clusterConfig); err != nil {
+			return false, oc.joinHostFailed(status, podName, hostFQDN, "failed to apply the cluster configuration", err)
+		}
+		setJoinHostStatus(status, podName, hostFQDN, joinHostStateJoining, "restarting as a member of group "+groupName)
+		return false, nil
+	}
+	return false, oc.initHost(mc, status, podName, hostFQDN)
+}
+
+func (oc *OperatorContext) initHost(mc mlmanage.AdminClient, status *marklogicv1.JoinStatus, podName, hostFQDN string) error {
+	license, err := oc.readHostLicense()
+	if err != nil {
+		return oc.joinHostFailed(status, podName, hostFQDN, "failed to read the license", err)
+	}
+	oc.ReqLogger.Info("Initializing MarkLogic on host", "host", hostFQDN)
+	if err := mc.InitHost(oc.Ctx, hostFQDN, license); err != nil {
+		return oc.joinHostFailed(status, podName, hostFQDN, "failed to initialize the host", err)
+	}
+	setJoinHostStatus(status, podName, hostFQDN, joinHostStateInitialized, "host initialized")
+	return nil
+}
+
+// configureJoinGroup names the group of the bootstrap host, or creates the group with its
+// App-Services server on the bootstrap host, and applies the group settings of the spec.
+func (oc *OperatorContext) configureJoinGroup(mc operatorJoinClient, isBootstrapGroup bool, groupName string) error {
+	cr := oc.MarklogicGroup
+	properties := map[string]any{"xdqp-ssl-enabled": cr.Spec.GroupConfig == nil || cr.Spec.GroupConfig.EnableXdqpSsl}
+	if isBootstrapGroup {
+		currentGroup, err := mc.GetHostGroupName(oc.Ctx, dynamicPodFQDN(cr, cr.Spec.Name+"-0"))
+		if err != nil {
+			return err
+		}
+		if currentGroup != groupName {
+			properties["group-name"] = groupName
+		}
+		if err := mc.UpdateGroupProperties(oc.Ctx, currentGroup, properties); err != nil {
+			return err
+		}
+	} else {
+		group, err := mc.GetGroup(oc.Ctx, groupName)
+		if err != nil {
+			return err
+		}
+		if !group.Exists {
+			oc.ReqLogger.Info("Creating MarkLogic group", "group", groupName)
+			if err := mc.CreateGroup(oc.Ctx, groupName); err != nil {
+				return err
+			}
+			oc.Recorder.Eventf(cr, corev1.EventTypeNormal, "GroupCreated", "Created MarkLogic group %s", groupName)
+		}
+		if err := mc.UpdateGroupProperties(oc.Ctx, groupName, properties); err != nil {
+			return err
+		}
+		appServices, err := mc.GetAppServer(oc.Ctx, groupName, appServicesServerName)
+		if err != nil {
+			return err
+		}
+		if !appServices.Exists {
+			if err := mc.CreateAppServer(oc.Ctx, groupName, map[string]any{
+				"server-name":      appServicesServerName,
+				"server-type":      "http",
+				"root":             "/",
+				"port":             8000,
+				"modules-database": "Modules",
+				"content-database": "Documents",
+				"error-handler":    "/MarkLogic/rest-api/8000-error-handler.xqy",
+				"url-rewriter":     "/MarkLogic/rest-api/8000-rewriter.xml",
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	if cr.Spec.PathBasedRouting {
+		// HAProxy cannot forward digest authentication under a path prefix.
+		for _, server := range []string{"Admin", appServicesServerName, "Manage"} {
+			if err := mc.UpdateAppServerProperties(oc.Ctx, groupName, server, map[string]any{"authentication": "basic"}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (oc *OperatorContext) joinHostSucceeded(status *marklogicv1.JoinStatus, podName, hostFQDN string, previous marklogicv1.HostJoinStatus, message string) {
+	if previous.State != joinHostStateJoined {
+		oc.Recorder.Eventf(oc.MarklogicGroup, corev1.EventTypeNormal, "HostJoined", "Host %s: %s", hostFQDN, message)
+	}
+	setJoinHostStatus(status, podName, hostFQDN, joinHostStateJoined, "")
+}
+
+// joinHostFailed keeps the state of the host, so the failed step is retried, and records
+// the error on it.
+func (oc *OperatorContext) joinHostFailed(status *marklogicv1.JoinStatus, podName, hostFQDN, message string, err error) error {
+	err = fmt.Errorf("%s %s: %w", hostFQDN, message, err)
+	current := findJoinHostStatus(status, podName)
+	if current.Message != err.Error() {
+		oc.Recorder.Event(oc.MarklogicGroup, corev1.EventTypeWarning, "HostJoinFailed", err.Error())
+	}
+	state := current.State
+	if state == "" {
+		state = joinHostStatePending
+	}
+	host := setJoinHostStatus(status, podName, hostFQDN, state, err.Error())
+	host.Attempts = current.Attempts + 1
+	return err
+}
+
+func (oc *OperatorContext) readHostLicense() (mlmanage.HostLicense, error) {
+	license := oc.MarklogicGroup.Spec.License
+	if license == nil {
+		return mlmanage.HostLicense{}, nil
+	}
+	if license.SecretRef == nil {
+		return mlmanage.HostLicense{LicenseKey: license.Key, Licensee: license.Licensee}, nil
+	}
+	secret := &corev1.Secret{}
+	if err := oc.Client.Get(oc.Ctx, types.NamespacedName{Name: license.SecretRef.Name, Namespace: oc.MarklogicGroup.Namespace}, secret); err != nil {
+		return mlmanage.HostLicense{}, err
+	}
+	keyKey, licenseeKey := license.SecretRef.LicenseKeyKey, license.SecretRef.LicenseeKey
+	if keyKey == "" {
+		keyKey = "license-key"
+	}
+	if licenseeKey == "" {
+		licenseeKey = "licensee"
+	}
+	return mlmanage.HostLicense{LicenseKey: string(secret.Data[keyKey]), Licensee: string(secret.Data[licenseeKey])}, nil
+}
+
+func (oc *OperatorContext) readWalletPassword() (string, error) {
+	secret := &corev1.Secret{}
+	err := oc.Client.Get(oc.Ctx, types.NamespacedName{Name: groupAdminSecretName(oc.MarklogicGroup), Namespace: oc.MarklogicGroup.Namespace}, secret)
+	if apierrors.IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(secret.Data["wallet-password"]), nil
+}
+
+func (oc *OperatorContext) patchJoinStatus(patchClient client.Patch, original *marklogicv1.MarklogicGroupStatus, res result.ReconcileResult) result.ReconcileResult {
+	if reflect.DeepEqual(*original, oc.MarklogicGroup.Status) {
+		return res
+	}
+	if err := oc.Client.Status().Patch(oc.Ctx, oc.MarklogicGroup, patchClient); err != nil {
+		oc.ReqLogger.Error(err, "Failed to update MarkLogicGroup join status")
+		return result.Error(err)
+	}
+	return res
+}
+
+// groupAdminSecretName falls back to the secret name the StatefulSet mounts when the group
+// does not name one.
+func groupAdminSecretName(cr *marklogicv1.MarklogicGroup) string {
+	if cr.Spec.SecretName != "" {
+		return cr.Spec.SecretName
+	}
+	return cr.Name + "-admin"
+}
+
+// reconcileJoinHosts lists every pod of the group, keeping the recorded progress of hosts
+// that still have a pod or are below the desired replicas.
+func reconcileJoinHosts(cr *marklogicv1.MarklogicGroup, pods []corev1.Pod, previous []marklogicv1.HostJoinStatus) []marklogicv1.HostJoinStatus {
+	desired := int(desiredDynamicReplicas(cr))
+	hosts := []marklogicv1.HostJoinStatus{}
+	for _, host := range previous {
+		if findDynamicPodByName(pods, host.PodName) != nil || podOrdinal(host.PodName) < desired {
+			hosts = append(hosts, host)
+		}
+	}
+	status := &marklogicv1.JoinStatus{Hosts: hosts}
+	for i := range pods {
+		if findJoinHostStatus(status, pods[i].Name).PodName == "" {
+			setJoinHostStatus(status, pods[i].Name, dynamicPodFQDN(cr, pods[i].Name), joinHostStatePending, "")
+		}
+	}
+	return status.Hosts
+}
+
+func findJoinHostStatus(status *marklogicv1.JoinStatus, podName string) marklogicv1.HostJoinStatus {
+	for _, host := range status.Hosts {
+		if host.PodName == podName {
+			return host
+		}
+	}
+	return marklogicv1.HostJoinStatus{}
+}
+
+// setJoinHostStatus records the state of a host and resets its failed attempts.
+// LastUpdated moves with the state only, so it tells how long a host has been restarting.
+func setJoinHostStatus(status *marklogicv1.JoinStatus, podName, hostFQDN, state, message string) *marklogicv1.HostJoinStatus {
+	now := metav1.Now()
+	for i := range status.Hosts {
+		host := &status.Hosts[i]
+		if host.PodName != podName {
+			continue
+		}
+		if host.State != state {
+			host.LastUpdated = &now
+		}
+		host.Hostname = hostFQDN
+		host.State = state
+		host.Message = message
+		host.Attempts = 0
+		return host
+	}
+	status.Hosts = append(status.Hosts, marklogicv1.HostJoinStatus{PodName: podName, Hostname: hostFQDN, State: state, Message: message, LastUpdated: &now})
+	return &status.Hosts[len(status.Hosts)-1]
+}
+
+func restartTimedOut(host marklogicv1.HostJoinStatus) bool {
+	return host.LastUpdated == nil || time.Since(host.LastUpdated.Time) > OperatorJoinRestartTimeout
+}
+
+func isPodRunning(pod *corev1.Pod) bool {
+	return pod != nil && pod.DeletionTimestamp == nil && pod.Status.Phase == corev1.PodRunning
+}
@@ -0,0 +1,184 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newOperatorJoinTestContext(t *testing.T, name, bootstrapHost string, replicas int32) *OperatorContext {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := marklogicv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add marklogic scheme: %v", err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add core scheme: %v", err)
+	}
+	group := &marklogicv1.MarklogicGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Generation: 1},
+		Spec: marklogicv1.MarklogicGroupSpec{
+			Name:          name,
+			Replicas:      int32Ptr(replicas),
+			ClusterDomain: "cluster.local",
+			GroupConfig:   &marklogicv1.GroupConfig{Name: name, EnableXdqpSsl: true},
+			BootstrapHost: bootstrapHost,
+			SecretName:    "ml-admin",
+			JoinMode:      marklogicv1.JoinModeOperator,
+			License:       &marklogicv1.License{Key: "KEY", Licensee: "ACME"},
+		},
+	}
+	objects := []client.Object{
+		group,
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "ml-admin", Namespace: "default"},
+			Data:       map[string][]byte{"username": []byte("admin"), "password": []byte("admin"), "wallet-password": []byte("wallet")},
+		},
+	}
+	for i := int32(0); i < replicas; i++ {
+		objects = append(objects, &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("%s-%d", name, i), Namespace: "default", Labels: getSelectorLabelsByComponent(name, false)},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		})
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithStatusSubresource(&marklogicv1.MarklogicGroup{}).
+		WithObjects(objects...).
+		Build()
+	return &OperatorContext{
+		Ctx:            context.Background(),
+		Client:         fakeClient,
+		Scheme:         scheme,
+		MarklogicGroup: group,
+		Recorder:       record.NewFakeRecorder(20),
+	}
+}
+
+// stubAdminClient stands in for the Admin API of the hosts. securedHosts are the hosts that
+// ask for credentials; adminCalls records the calls as "<call> <host>".
+type stubAdminClient struct {
+	securedHosts map[string]bool
+	adminCalls   []string
+}
+
+func (s *stubAdminClient) HostSecurityInitialized(ctx context.Context, hostFQDN string) (bool, error) {
+	return s.securedHosts[hostFQDN], nil
+}
+
+func (s *stubAdminClient) InitHost(ctx context.Context, hostFQDN string, license mlmanage.HostLicense) error {
+	s.adminCalls = append(s.adminCalls, "init "+hostFQDN)
+	return nil
+}
+
+func (s *stubAdminClient) InitializeSecurity(ctx context.Context, hostFQDN string, opts mlmanage.SecurityOptions) error {
+	s.adminCalls = append(s.adminCalls, "instance-admin "+hostFQDN)
+	return nil
+}
+
+func (s *stubAdminClient) GetServerConfig(ctx context.Context, hostFQDN string) ([]byte, error) {
+	return []byte("<host>" + hostFQDN + "</host>"), nil
+}
+
+func (s *stubAdminClient) GetClusterConfig(ctx context.Context, groupName string, serverConfig []byte) ([]byte, error) {
+	return []byte(groupName + ":" + string(serverConfig)), nil
+}
+
+func (s *stubAdminClient) ApplyClusterConfig(ctx context.Context, hostFQDN string, clusterConfig []byte) error {
+	s.adminCalls = append(s.adminCalls, "cluster-config "+hostFQDN)
+	return nil
+}
+
+func TestReconcileOperatorJoinInitializesBootstrapAndJoinsHosts(t *testing.T) {
+	oc := newOperatorJoinTestContext(t, "dnode", "", 2)
+	stub := &stubAdminClient{securedHosts: map[string]bool{}}
+	groups := &stubDynamicManagementClient{}
+	useStubClusterManagementClient(t, managementStub{HostClient: groups, GroupClient: groups, AdminClient: stub})
+	group := oc.MarklogicGroup
+	bootstrap := "dnode-0.dnode.default.svc.cluster.local"
+	joiner := "dnode-1.dnode.default.svc.cluster.local"
+
+	// init, instance-admin, then a pass waiting for the restart.
+	for i := 0; i < 3; i++ {
+		if res := oc.ReconcileOperatorJoin(); !res.Completed() {
+			t.Fatalf("pass %d: expected a requeue while the bootstrap host initializes", i)
+		}
+	}
+	if want := []string{"init " + bootstrap, "instance-admin " + bootstrap}; !reflect.DeepEqual(stub.adminCalls, want) {
+		t.Fatalf("expected calls %v, got %v", want, stub.adminCalls)
+	}
+	if host := findJoinHostStatus(group.Status.Join, "dnode-0"); host.State != joinHostStateSecuring {
+		t.Fatalf("expected the bootstrap host to be restarting, got %+v", host)
+	}
+	if group.Status.Join.Phase != marklogicv1.JoinPhaseInitializingBootstrap {
+		t.Fatalf("unexpected phase %s", group.Status.Join.Phase)
+	}
+
+	// The bootstrap host came back with security; the group is renamed and dnode-1 joins.
+	stub.securedHosts[bootstrap] = true
+	oc.ReconcileOperatorJoin()
+	oc.ReconcileOperatorJoin()
+	if want := map[string]any{"group-name": "dnode", "xdqp-ssl-enabled": true}; !reflect.DeepEqual(groups.groupProperties["Default"], want) {
+		t.Fatalf("expected the Default group to be renamed, got %v", groups.groupProperties)
+	}
+	if want := []string{"init " + joiner, "cluster-config " + joiner}; !reflect.DeepEqual(stub.adminCalls[2:], want) {
+		t.Fatalf("expected calls %v, got %v", want, stub.adminCalls[2:])
+	}
+	if group.Status.Join.Phase != marklogicv1.JoinPhaseJoiningHosts || apimeta.IsStatusConditionTrue(group.Status.Conditions, string(marklogicv1.GroupHostsJoined)) {
+		t.Fatalf("expected the join to be in progress, got %+v", group.Status.Join)
+	}
+
+	stub.securedHosts[joiner] = true
+	if res := oc.ReconcileOperatorJoin(); res.Completed() {
+		t.Fatal("expected the reconcile to continue once every host joined")
+	}
+	if group.Status.Join.Phase != marklogicv1.JoinPhaseJoined || !apimeta.IsStatusConditionTrue(group.Status.Conditions, string(marklogicv1.GroupHostsJoined)) {
+		t.Fatalf("expected every host to be joined, got %+v", group.Status.Join)
+	}
+	for _, host := range group.Status.Join.Hosts {
+		if host.State != joinHostStateJoined {
+			t.Fatalf("expected %s to be joined, got %+v", host.PodName, host)
+		}
+	}
+	if len(stub.adminCalls) != 4 {
+		t.Fatalf("expected no further Admin API calls, got %v", stub.adminCalls)
+	}
+}
+
+func TestReconcileOperatorJoinCreatesNonBootstrapGroup(t *testing.T) {
+	oc := newOperatorJoinTestContext(t, "enode", "dnode-0.dnode.default.svc.cluster.local", 1)
+	oc.MarklogicGroup.Spec.PathBasedRouting = true
+	// MarkLogic creates the Admin and Manage servers with the group, but not App-Services.
+	stub := &stubAdminClient{securedHosts: map[string]bool{}}
+	groups := &stubDynamicManagementClient{}
+	apps := &stubAppServerClient{appServers: map[string]map[string]any{"enode/Admin": {}, "enode/Manage": {}}}
+	useStubClusterManagementClient(t, managementStub{HostClient: groups, GroupClient: groups, AdminClient: stub, AppServerClient: apps})
+
+	oc.ReconcileOperatorJoin()
+	if !groups.groups["enode"] {
+		t.Fatal("expected the enode group to be created")
+	}
+	appServices := apps.appServers["enode/App-Services"]
+	if appServices["server-type"] != "http" || appServices["port"] != 8000 || appServices["authentication"] != "basic" {
+		t.Fatalf("unexpected App-Services server %v", appServices)
+	}
+	if want := []string{"init enode-0.enode.default.svc.cluster.local"}; !reflect.DeepEqual(stub.adminCalls, want) {
+		t.Fatalf("expected the first host of the group to be initialized, got %v", stub.adminCalls)
+	}
+	if oc.MarklogicGroup.Status.Join.ConfiguredGeneration != 1 {
+		t.Fatalf("expected the group configuration to be recorded, got %+v", oc.MarklogicGroup.Status.Join)
+	}
+}
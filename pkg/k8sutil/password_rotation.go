@@ -0,0 +1,245 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func passwordRotationEnabled(cr *marklogicv1.MarklogicCluster) bool {
+	return cr.Spec.Auth != nil && cr.Spec.Auth.PasswordRotation != nil && cr.Spec.Auth.PasswordRotation.Enabled
+}
+
+// appliedAdminSecretName is the operator-owned copy of the admin credentials MarkLogic
+// currently accepts. The admin secret itself holds the desired credentials.
+func appliedAdminSecretName(adminSecretName string) string {
+	return adminSecretName + "-applied"
+}
+
+// readAdminCredentials returns the admin credentials MarkLogic accepts right now. While a
+// password change is pending the admin secret is already ahead of MarkLogic, so the
+// applied copy kept by password rotation wins over the admin secret.
+func readAdminCredentials(ctx context.Context, c client.Client, namespace, adminSecretName string) (string, string, error) {
+	username, password, err := readCredentialSecret(ctx, c, namespace, appliedAdminSecretName(adminSecretName))
+	if err == nil {
+		return username, password, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return "", "", err
+	}
+	return readCredentialSecret(ctx, c, namespace, adminSecretName)
+}
+
+// MarklogicClustersForAdminSecret lists the clusters rotating their admin password from the
+// secret. The cluster controller uses it to react to a changed password.
+func MarklogicClustersForAdminSecret(clusters []marklogicv1.MarklogicCluster, secret client.Object) []types.NamespacedName {
+	requests := []types.NamespacedName{}
+	for i := range clusters {
+		cluster := &clusters[i]
+		if cluster.Namespace == secret.GetNamespace() && passwordRotationEnabled(cluster) && clusterAdminSecretName(cluster) == secret.GetName() {
+			requests = append(requests, types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace})
+		}
+	}
+	return requests
+}
+
+// requeueForPasswordRotation schedules the reconcile that generates the next admin password.
+func requeueForPasswordRotation(cr *marklogicv1.MarklogicCluster, res reconcile.Result, err error) (reconcile.Result, error) {
+	if err != nil || cr.Status.PasswordRotation == nil || cr.Status.PasswordRotation.NextRotationTime == nil {
+		return res, err
+	}
+	wait := time.Until(cr.Status.PasswordRotation.NextRotationTime.Time)
+	if wait < time.Second {
+		wait = time.Second
+	}
+	if res.RequeueAfter == 0 || wait < res.RequeueAfter {
+		res.RequeueAfter = wait
+	}
+	return res, err
+}
+
+// ReconcileAdminPasswordRotation changes the MarkLogic admin password when the password in
+// the admin secret differs from the applied copy, and writes a new password into the admin
+// secret once rotationPeriod has passed. The secret mounted in the pods follows the admin
+// secret, and the operator connects with the applied copy, so both keep working while
+// MarkLogic switches over. Readiness probes use the unauthenticated health check port.
+func (cc *ClusterContext) ReconcileAdminPasswordRotation() result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	logger := cc.ReqLogger
+	secretName := clusterAdminSecretName(cr)
+	appliedName := appliedAdminSecretName(secretName)
+	original := cr.Status.DeepCopy()
+	patchClient := client.MergeFrom(cr.DeepCopy())
+
+	if !passwordRotationEnabled(cr) {
+		if err := cc.deleteAppliedAdminSecret(appliedName); err != nil {
+			return result.Error(err)
+		}
+		cr.Status.PasswordRotation = nil
+		return cc.patchPasswordRotationStatus(patchClient, original)
+	}
+
+	admin := &corev1.Secret{}
+	if err := cc.Client.Get(cc.Ctx, types.NamespacedName{Name: secretName, Namespace: cr.Namespace}, admin); err != nil {
+		logger.Error(err, "Failed to get MarkLogic admin Secret")
+		return result.Error(err)
+	}
+	now := metav1.Now()
+	status := &marklogicv1.PasswordRotationStatus{}
+	if cr.Status.PasswordRotation != nil {
+		status = cr.Status.PasswordRotation.DeepCopy()
+	}
+	if status.LastRotationTime == nil {
+		created := admin.CreationTimestamp
+		if created.IsZero() {
+			created = now
+		}
+		status.LastRotationTime = &created
+	}
+	cr.Status.PasswordRotation = status
+
+	applied := &corev1.Secret{}
+	err := cc.Client.Get(cc.Ctx, types.NamespacedName{Name: appliedName, Namespace: cr.Namespace}, applied)
+	if apierrors.IsNotFound(err) {
+		// MarkLogic was initialized from the admin secret, so it is the applied state.
+		logger.Info("Recording the applied MarkLogic admin credentials", "secret", appliedName)
+		objectMeta := generateObjectMeta(appliedName, cr.Namespace, cc.GetClusterLabels(cr.Name), cc.GetClusterAnnotations())
+		appliedDef := generateSecretDef(objectMeta, marklogicClusterAsOwner(cr), map[string][]byte{
+			"username": admin.Data["username"],
+			"password": admin.Data["password"],
+		})
+		if err := cc.Client.Create(cc.Ctx, appliedDef); err != nil {
+			logger.Error(err, "Failed to create the applied admin credentials Secret")
+			return result.Error(err)
+		}
+		setNextPasswordRotation(cr, status)
+		return cc.patchPasswordRotationStatus(patchClient, original)
+	} else if err != nil {
+		logger.Error(err, "Failed to get the applied admin credentials Secret")
+		return result.Error(err)
+	}
+
+	rotation := cr.Spec.Auth.PasswordRotation
+	inSync := string(admin.Data["password"]) == string(applied.Data["password"])
+	if inSync && rotation.RotationPeriod != nil && rotation.RotationPeriod.Duration > 0 &&
+		!now.Before(&metav1.Time{Time: status.LastRotationTime.Add(rotation.RotationPeriod.Duration)}) {
+		logger.Info("MarkLogic admin password is due for rotation, generating a new one")
+		admin.Data["password"] = []byte(generateRandomAlphaNumeric(16))
+		// The update carries the resourceVersion read above, so a concurrent edit of the
+		// secret fails the update instead of being overwritten.
+		if err := cc.Client.Update(cc.Ctx, admin); err != nil {
+			logger.Error(err, "Failed to write the new admin password to the Secret")
+			return result.Error(err)
+		}
+	}
+
+	if string(admin.Data["password"]) != string(applied.Data["password"]) {
+		if string(admin.Data["username"]) != string(applied.Data["username"]) {
+			status.Message = fmt.Sprintf("Secret %s changes the admin username, only the password can be rotated", secretName)
+			cc.Recorder.Event(cr, "Warning", "AdminPasswordRotationFailed", status.Message)
+			return cc.patchPasswordRotationStatus(patchClient, original)
+		}
+		username := string(applied.Data["username"])
+		if err := cc.changeAdminPassword(username, string(applied.Data["password"]), string(admin.Data["password"])); err != nil {
+			logger.Error(err, "Failed to change the MarkLogic admin password")
+			status.Message = fmt.Sprintf("Failed to change the admin password: %v", err)
+			cc.Recorder.Event(cr, "Warning", "AdminPasswordRotationFailed", status.Message)
+			if res := cc.patchPasswordRotationStatus(patchClient, original); res.Completed() {
+				return res
+			}
+			return result.Error(err)
+		}
+		applied.Data["password"] = admin.Data["password"]
+		if err := cc.Client.Update(cc.Ctx, applied); err != nil {
+			logger.Error(err, "Failed to record the applied admin password")
+			return result.Error(err)
+		}
+		logger.Info("MarkLogic admin password changed", "user", username)
+		cc.Recorder.Eventf(cr, "Normal", "AdminPasswordRotated", "Changed the MarkLogic password of %s from Secret %s", username, secretName)
+		status.LastRotationTime = &now
+		status.Message = ""
+	}
+	setNextPasswordRotation(cr, status)
+	return cc.patchPasswordRotationStatus(patchClient, original)
+}
+
+func setNextPasswordRotation(cr *marklogicv1.MarklogicCluster, status *marklogicv1.PasswordRotationStatus) {
+	status.NextRotationTime = nil
+	if period := cr.Spec.Auth.PasswordRotation.RotationPeriod; period != nil && period.Duration > 0 {
+		next := metav1.NewTime(status.LastRotationTime.Add(period.Duration))
+		status.NextRotationTime = &next
+	}
+}
+
+// changeAdminPassword sets the new password with the applied credentials. If those are
+// rejected, an earlier attempt may have changed the password without recording it, which
+// the new credentials confirm.
+func (cc *ClusterContext) changeAdminPassword(username, currentPassword, newPassword string) error {
+	cr := cc.MarklogicCluster
+	host := bootstrapHostFQDN(cr)
+	if host == "" {
+		return fmt.Errorf("marklogiccluster %s/%s has no bootstrap group", cr.Namespace, cr.Name)
+	}
+	useTLS := clusterManagementUsesTLS(cr)
+	options := mlmanage.ClientOptions{
+		Host:               host,
+		Username:           username,
+		Password:           currentPassword,
+		UseTLS:             useTLS,
+		InsecureSkipVerify: useTLS,
+	}
+	err := NewClusterManagementClient(options).SetUserPassword(cc.Ctx, username, newPassword)
+	if err == nil {
+		return nil
+	}
+	options.Password = newPassword
+	if retryErr := NewClusterManagementClient(options).SetUserPassword(cc.Ctx, username, newPassword); retryErr == nil {
+		return nil
+	}
+	return err
+}
+
+func (cc *ClusterContext) deleteAppliedAdminSecret(name string) error {
+	secret := &corev1.Secret{}
+	err := cc.Client.Get(cc.Ctx, types.NamespacedName{Name: name, Namespace: cc.MarklogicCluster.Namespace}, secret)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		cc.ReqLogger.Error(err, "Failed to get the applied admin credentials Secret")
+		return err
+	}
+	if !metav1.IsControlledBy(secret, cc.MarklogicCluster) {
+		return nil
+	}
+	cc.ReqLogger.Info("Password rotation is disabled, removing the applied admin credentials", "secret", name)
+	if err := cc.Client.Delete(cc.Ctx, secret); err != nil && !apierrors.IsNotFound(err) {
+		cc.ReqLogger.Error(err, "Failed to delete the applied admin credentials Secret")
+		return err
+	}
+	return nil
+}
+
+func (cc *ClusterContext) patchPasswordRotationStatus(patchClient client.Patch, original *marklogicv1.MarklogicClusterStatus) result.ReconcileResult {
+	if reflect.DeepEqual(*original, cc.MarklogicCluster.Status) {
+		return result.Continue()
+	}
+	if err := cc.Client.Status().Patch(cc.Ctx, cc.MarklogicCluster, patchClient); err != nil {
+		cc.ReqLogger.Error(err, "Failed to update MarkLogicCluster password rotation status")
+		return result.Error(err)
+	}
+	return result.Continue()
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// stubSecurityClient observes admin password changes and records the certificates pushed
+// per certificate template.
+type stubSecurityClient struct {
+	setPasswordFn        func(username, password string) error
+	insertedCertificates map[string][]mlmanage.HostCertificate
+}
+
+func (s *stubSecurityClient) SetUserPassword(ctx context.Context, username, password string) error {
+	if s.setPasswordFn != nil {
+		return s.setPasswordFn(username, password)
+	}
+	return nil
+}
+
+func (s *stubSecurityClient) InsertHostCertificates(ctx context.Context, template string, certificates []mlmanage.HostCertificate) error {
+	if s.insertedCertificates == nil {
+		s.insertedCertificates = map[string][]mlmanage.HostCertificate{}
+	}
+	s.insertedCertificates[template] = append(s.insertedCertificates[template], certificates...)
+	return nil
+}
+
+func TestReconcileAdminPasswordRotation(t *testing.T) {
+	changed := []string{}
+	stub := &stubSecurityClient{setPasswordFn: func(username, password string) error {
+		changed = append(changed, username+":"+password)
+		return nil
+	}}
+	useStubClusterManagementClient(t, managementStub{SecurityClient: stub})
+	cc := newAppServerTestContext(t, &marklogicv1.MarklogicGroups{Name: "dnode", IsBootstrap: true})
+	cc.MarklogicCluster.Spec.Auth = &marklogicv1.AdminAuth{PasswordRotation: &marklogicv1.PasswordRotation{
+		Enabled:        true,
+		RotationPeriod: &metav1.Duration{Duration: 24 * time.Hour},
+	}}
+	if err := cc.Client.Update(cc.Ctx, cc.MarklogicCluster); err != nil {
+		t.Fatalf("failed to update cluster: %v", err)
+	}
+	readSecret := func(name string) *corev1.Secret {
+		t.Helper()
+		secret := &corev1.Secret{}
+		if err := cc.Client.Get(cc.Ctx, types.NamespacedName{Name: name, Namespace: "default"}, secret); err != nil {
+			t.Fatalf("failed to get secret %s: %v", name, err)
+		}
+		return secret
+	}
+
+	if res := cc.ReconcileAdminPasswordRotation(); res.Completed() {
+		t.Fatalf("expected reconcile to continue, got %+v", res)
+	}
+	if applied := readSecret("ml-admin-applied"); string(applied.Data["password"]) != "admin" {
+		t.Fatalf("expected the initial credentials to be recorded as applied, got %q", applied.Data["password"])
+	}
+	status := cc.MarklogicCluster.Status.PasswordRotation
+	if len(changed) != 0 || status == nil || status.NextRotationTime == nil {
+		t.Fatalf("expected no password change and a scheduled rotation, got %v %+v", changed, status)
+	}
+
+	admin := readSecret("ml-admin")
+	admin.Data["password"] = []byte("changed")
+	if err := cc.Client.Update(cc.Ctx, admin); err != nil {
+		t.Fatalf("failed to update admin secret: %v", err)
+	}
+	if res := cc.ReconcileAdminPasswordRotation(); res.Completed() {
+		t.Fatalf("expected reconcile to continue, got %+v", res)
+	}
+	if len(changed) != 1 || changed[0] != "admin:changed" {
+		t.Fatalf("expected the password from the secret to be set in MarkLogic, got %v", changed)
+	}
+	if applied := readSecret("ml-admin-applied"); string(applied.Data["password"]) != "changed" {
+		t.Fatalf("expected the applied copy to follow, got %q", applied.Data["password"])
+	}
+	if username, password, _ := readAdminCredentials(cc.Ctx, cc.Client, "default", "ml-admin"); username != "admin" || password != "changed" {
+		t.Fatalf("expected the operator to connect with the applied credentials, got %s/%s", username, password)
+	}
+
+	due := metav1.NewTime(time.Now().Add(-25 * time.Hour))
+	cc.MarklogicCluster.Status.PasswordRotation.LastRotationTime = &due
+	if err := cc.Client.Status().Update(cc.Ctx, cc.MarklogicCluster); err != nil {
+		t.Fatalf("failed to update cluster status: %v", err)
+	}
+	if res := cc.ReconcileAdminPasswordRotation(); res.Completed() {
+		t.Fatalf("expected reconcile to continue, got %+v", res)
+	}
+	generated := string(readSecret("ml-admin").Data["password"])
+	if generated == "changed" || len(changed) != 2 || changed[1] != "admin:"+generated {
+		t.Fatalf("expected a generated password to be written and applied, got %q %v", generated, changed)
+	}
+	if !cc.MarklogicCluster.Status.PasswordRotation.LastRotationTime.After(due.Time) {
+		t.Fatalf("expected the rotation time to advance, got %+v", cc.MarklogicCluster.Status.PasswordRotation)
+	}
+
+	cc.MarklogicCluster.Spec.Auth.PasswordRotation.Enabled = false
+	if res := cc.ReconcileAdminPasswordRotation(); res.Completed() {
+		t.Fatalf("expected reconcile to continue, got %+v", res)
+	}
+	if _, _, err := readCredentialSecret(cc.Ctx, cc.Client, "default", "ml-admin-applied"); err == nil {
+		t.Fatalf("expected the applied copy to be removed when rotation is disabled")
+	}
+}
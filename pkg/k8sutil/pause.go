@@ -0,0 +1,209 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/metrics"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	pauseAnnotationKey = "marklogic.progress.com/paused"
+
+	// Groups do not watch their cluster, so a group paused through the cluster looks again
+	// on its own.
+	pausedRequeueSeconds = 30
+
+	pauseReasonAnnotation    = "PausedByAnnotation"
+	pauseReasonClusterPaused = "ClusterPaused"
+	pauseReasonMaintenance   = "Maintenance"
+	pauseReasonResumed       = "Resumed"
+)
+
+// isPaused reports whether the marklogic.progress.com/paused annotation is set to true.
+func isPaused(obj metav1.Object) bool {
+	if obj == nil {
+		return false
+	}
+	return strings.EqualFold(obj.GetAnnotations()[pauseAnnotationKey], "true")
+}
+
+// ReconcilePause stops the group reconcile before any resource is touched while the group
+// is in maintenance or paused, either directly or through the cluster that owns it. The
+// replica counts in the status still follow the StatefulSet.
+func (oc *OperatorContext) ReconcilePause() result.ReconcileResult {
+	cr := oc.MarklogicGroup
+	reason, message, err := oc.groupPauseReason()
+	if err != nil {
+		oc.ReqLogger.Error(err, "Failed to check whether the MarkLogicGroup is paused")
+		return result.Error(err)
+	}
+
+	patchClient := client.MergeFrom(cr.DeepCopy())
+	original := cr.Status.DeepCopy()
+	previous := apimeta.FindStatusCondition(cr.Status.Conditions, string(marklogicv1.GroupPaused))
+	if reason == "" {
+		if previous == nil || previous.Status != metav1.ConditionTrue {
+			return result.Continue()
+		}
+		apimeta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
+			Type:               string(marklogicv1.GroupPaused),
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: cr.Generation,
+			Reason:             pauseReasonResumed,
+			Message:            "reconciliation resumed",
+		})
+		oc.Recorder.Event(cr, corev1.EventTypeNormal, "Resumed", "Reconciliation of the MarkLogic group resumed")
+		return oc.patchPauseStatus(patchClient, original, result.Continue())
+	}
+
+	oc.ReqLogger.Info("MarkLogicGroup is paused, skipping reconcile", "reason", reason)
+	if previous == nil || previous.Status != metav1.ConditionTrue || previous.Reason != reason {
+		oc.Recorder.Event(cr, corev1.EventTypeNormal, "Paused", message)
+	}
+	apimeta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
+		Type:               string(marklogicv1.GroupPaused),
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: cr.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+	if err := oc.refreshReplicaStatus(); err != nil {
+		return result.Error(err)
+	}
+	return oc.patchPauseStatus(patchClient, original, result.RequeueSoon(pausedRequeueSeconds))
+}
+
+// groupPauseReason returns the condition reason and message of a paused group, or an empty
+// reason when the group is reconciled normally.
+func (oc *OperatorContext) groupPauseReason() (string, string, error) {
+	cr := oc.MarklogicGroup
+	if cr.Spec.Maintenance {
+		return pauseReasonMaintenance, "MarkLogic group is in maintenance; its hosts are drained from HAProxy", nil
+	}
+	if isPaused(cr) {
+		return pauseReasonAnnotation, fmt.Sprintf("Reconciliation is paused by annotation %s", pauseAnnotationKey), nil
+	}
+	cluster, err := oc.owningCluster()
+	if err != nil && !apierrors.IsNotFound(err) {
+		return "", "", err
+	}
+	if cluster != nil && isPaused(cluster) {
+		return pauseReasonClusterPaused, fmt.Sprintf("Reconciliation is paused by annotation %s on MarklogicCluster %s", pauseAnnotationKey, cluster.Name), nil
+	}
+	return "", "", nil
+}
+
+// refreshReplicaStatus copies the replica counts of the StatefulSet into the group status
+// without changing the StatefulSet.
+func (oc *OperatorContext) refreshReplicaStatus() error {
+	cr := oc.MarklogicGroup
+	sts, err := oc.GetStatefulSet(cr.Namespace, cr.Spec.Name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	metrics.SetGroupReplicas(cr.Namespace, cr.Name, sts.Status.Replicas, sts.Status.ReadyReplicas)
+	cr.Status.Replicas = sts.Status.Replicas
+	cr.Status.ReadyReplicas = sts.Status.ReadyReplicas
+	return nil
+}
+
+func (oc *OperatorContext) patchPauseStatus(patchClient client.Patch, original *marklogicv1.MarklogicGroupStatus, res result.ReconcileResult) result.ReconcileResult {
+	if reflect.DeepEqual(*original, oc.MarklogicGroup.Status) {
+		return res
+	}
+	if err := oc.Client.Status().Patch(oc.Ctx, oc.MarklogicGroup, patchClient); err != nil {
+		oc.ReqLogger.Error(err, "Failed to update MarkLogicGroup pause status")
+		return result.Error(err)
+	}
+	return res
+}
+
+// ReconcilePause stops the cluster reconcile while the cluster carries the
+// marklogic.progress.com/paused annotation. The status rollup of the groups and their
+// maintenance setting are still updated; groups see the annotation through their owner
+// and pause themselves.
+func (cc *ClusterContext) ReconcilePause() result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	paused := isPaused(cr)
+	previous := apimeta.FindStatusCondition(cr.Status.Conditions, string(marklogicv1.ClusterPaused))
+	wasPaused := previous != nil && previous.Status == metav1.ConditionTrue
+	if !paused && !wasPaused {
+		return result.Continue()
+	}
+	if paused {
+		cc.ReqLogger.Info("MarkLogicCluster is paused, skipping reconcile")
+		if err := cc.syncGroupMaintenance(); err != nil {
+			cc.ReqLogger.Error(err, "Failed to pass maintenance on to the MarkLogicGroups")
+			return result.Error(err)
+		}
+		if statusResult := cc.ReconcileClusterStatus(); statusResult.Completed() {
+			return statusResult
+		}
+	}
+
+	patchClient := client.MergeFrom(cr.DeepCopy())
+	original := cr.Status.DeepCopy()
+	res := result.Done()
+	if paused {
+		message := fmt.Sprintf("Reconciliation is paused by annotation %s", pauseAnnotationKey)
+		if !wasPaused {
+			cc.Recorder.Event(cr, corev1.EventTypeNormal, "Paused", message)
+		}
+		setClusterCondition(&cr.Status, cr.Generation, marklogicv1.ClusterPaused, metav1.ConditionTrue, pauseReasonAnnotation, message)
+	} else {
+		cc.Recorder.Event(cr, corev1.EventTypeNormal, "Resumed", "Reconciliation of the MarkLogic cluster resumed")
+		setClusterCondition(&cr.Status, cr.Generation, marklogicv1.ClusterPaused, metav1.ConditionFalse, pauseReasonResumed, "reconciliation resumed")
+		res = result.Continue()
+	}
+	if reflect.DeepEqual(*original, cr.Status) {
+		return res
+	}
+	if err := cc.Client.Status().Patch(cc.Ctx, cr, patchClient); err != nil {
+		cc.ReqLogger.Error(err, "Failed to update MarkLogicCluster pause status")
+		return result.Error(err)
+	}
+	return res
+}
+
+// syncGroupMaintenance passes the maintenance setting of spec.markLogicGroups on to the
+// groups while the cluster is paused, since the reconcile that updates the group specs
+// does not run.
+func (cc *ClusterContext) syncGroupMaintenance() error {
+	cr := cc.MarklogicCluster
+	for _, groupSpec := range cr.Spec.MarkLogicGroups {
+		if groupSpec == nil {
+			continue
+		}
+		group := &marklogicv1.MarklogicGroup{}
+		if err := cc.Client.Get(cc.Ctx, types.NamespacedName{Name: groupSpec.Name, Namespace: cr.Namespace}, group); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		if group.Spec.Maintenance == groupSpec.Maintenance {
+			continue
+		}
+		patchClient := client.MergeFrom(group.DeepCopy())
+		group.Spec.Maintenance = groupSpec.Maintenance
+		if err := cc.Client.Patch(cc.Ctx, group, patchClient); err != nil {
+			return err
+		}
+	}
+	return nil
+}
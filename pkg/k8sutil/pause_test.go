@@ -0,0 +1,232 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconsileMarklogicGroupHandlerSkipsGroupOfPausedCluster(t *testing.T) {
+	oc, sts := newDecommissionTestContext(t, 3, 3)
+	sts.Status.Replicas = 3
+	sts.Status.ReadyReplicas = 2
+	if err := oc.Client.Status().Update(oc.Ctx, sts); err != nil {
+		t.Fatalf("failed to update statefulset status: %v", err)
+	}
+	cluster := &marklogicv1.MarklogicCluster{}
+	if err := oc.Client.Get(oc.Ctx, types.NamespacedName{Name: "ml", Namespace: "default"}, cluster); err != nil {
+		t.Fatalf("failed to get cluster: %v", err)
+	}
+	cluster.Annotations = map[string]string{pauseAnnotationKey: "true"}
+	if err := oc.Client.Update(oc.Ctx, cluster); err != nil {
+		t.Fatalf("failed to pause cluster: %v", err)
+	}
+
+	res, err := oc.ReconsileMarklogicGroupHandler()
+	if err != nil {
+		t.Fatalf("ReconsileMarklogicGroupHandler returned error: %v", err)
+	}
+	if res.RequeueAfter != pausedRequeueSeconds*time.Second {
+		t.Fatalf("expected a paused group to requeue after %ds, got %+v", pausedRequeueSeconds, res)
+	}
+	if err := oc.Client.Get(oc.Ctx, types.NamespacedName{Name: "dnode", Namespace: "default"}, &corev1.Service{}); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected no service to be created for a paused group, got err=%v", err)
+	}
+	group := oc.MarklogicGroup
+	condition := apimeta.FindStatusCondition(group.Status.Conditions, string(marklogicv1.GroupPaused))
+	if condition == nil || condition.Status != metav1.ConditionTrue || condition.Reason != pauseReasonClusterPaused {
+		t.Fatalf("expected the group to be paused by its cluster, got %+v", condition)
+	}
+	if group.Status.Replicas != 3 || group.Status.ReadyReplicas != 2 {
+		t.Fatalf("expected the replica status to follow the statefulset, got %d/%d", group.Status.ReadyReplicas, group.Status.Replicas)
+	}
+	if event := <-oc.Recorder.(*record.FakeRecorder).Events; !strings.HasPrefix(event, "Normal Paused") {
+		t.Fatalf("expected a Paused event, got %q", event)
+	}
+
+	cluster.Annotations = nil
+	if err := oc.Client.Update(oc.Ctx, cluster); err != nil {
+		t.Fatalf("failed to resume cluster: %v", err)
+	}
+	if res := oc.ReconcilePause(); res.Completed() {
+		t.Fatal("expected a resumed group to continue the reconcile")
+	}
+	if apimeta.IsStatusConditionTrue(group.Status.Conditions, string(marklogicv1.GroupPaused)) {
+		t.Fatal("expected the Paused condition to be cleared")
+	}
+	if event := <-oc.Recorder.(*record.FakeRecorder).Events; !strings.HasPrefix(event, "Normal Resumed") {
+		t.Fatalf("expected a Resumed event, got %q", event)
+	}
+}
+
+func TestReconcilePausePrefersMaintenance(t *testing.T) {
+	oc, _ := newDecommissionTestContext(t, 3, 3)
+	oc.MarklogicGroup.Annotations = map[string]string{pauseAnnotationKey: "True"}
+	oc.MarklogicGroup.Spec.Maintenance = true
+
+	if res := oc.ReconcilePause(); !res.Completed() {
+		t.Fatal("expected a group in maintenance to stop the reconcile")
+	}
+	condition := apimeta.FindStatusCondition(oc.MarklogicGroup.Status.Conditions, string(marklogicv1.GroupPaused))
+	if condition == nil || condition.Reason != pauseReasonMaintenance {
+		t.Fatalf("expected the Maintenance reason, got %+v", condition)
+	}
+}
+
+func TestReconsileMarklogicClusterHandlerOnlyUpdatesStatusWhenPaused(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	if err := marklogicv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add marklogic scheme: %v", err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add core scheme: %v", err)
+	}
+	cluster := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "ml",
+			Namespace:   "default",
+			Annotations: map[string]string{pauseAnnotationKey: "true"},
+		},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "dnode", IsBootstrap: true, Replicas: int32Ptr(1)}},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithStatusSubresource(&marklogicv1.MarklogicCluster{}).
+		WithObjects(cluster).
+		Build()
+	cc := &ClusterContext{
+		Ctx:              context.Background(),
+		Client:           fakeClient,
+		Scheme:           scheme,
+		MarklogicCluster: cluster,
+		Recorder:         record.NewFakeRecorder(10),
+	}
+
+	if _, err := cc.ReconsileMarklogicClusterHandler(); err != nil {
+		t.Fatalf("ReconsileMarklogicClusterHandler returned error: %v", err)
+	}
+	if err := fakeClient.Get(context.Background(), types.NamespacedName{Name: "dnode", Namespace: "default"}, &marklogicv1.MarklogicGroup{}); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected no group to be created for a paused cluster, got err=%v", err)
+	}
+	if err := fakeClient.Get(context.Background(), types.NamespacedName{Name: "ml-admin", Namespace: "default"}, &corev1.Secret{}); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected no admin secret to be created for a paused cluster, got err=%v", err)
+	}
+	if !apimeta.IsStatusConditionTrue(cluster.Status.Conditions, string(marklogicv1.ClusterPaused)) {
+		t.Fatalf("expected the Paused condition, got %+v", cluster.Status.Conditions)
+	}
+	if cluster.Status.Hosts != "0/1" || len(cluster.Status.Groups) != 1 {
+		t.Fatalf("expected the group rollup to be updated, got %+v", cluster.Status)
+	}
+}
+
+func TestPausedClusterPassesMaintenanceOnToGroups(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	if err := marklogicv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add marklogic scheme: %v", err)
+	}
+	cluster := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "ml",
+			Namespace:   "default",
+			Annotations: map[string]string{pauseAnnotationKey: "true"},
+		},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{
+				{Name: "dnode", IsBootstrap: true, Replicas: int32Ptr(1)},
+				{Name: "enode", Replicas: int32Ptr(1), Maintenance: true},
+			},
+		},
+	}
+	enode := &marklogicv1.MarklogicGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "enode", Namespace: "default"},
+		Spec:       marklogicv1.MarklogicGroupSpec{Replicas: int32Ptr(1)},
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithStatusSubresource(&marklogicv1.MarklogicCluster{}).
+		WithObjects(cluster, enode).
+		Build()
+	cc := &ClusterContext{
+		Ctx:              context.Background(),
+		Client:           fakeClient,
+		Scheme:           scheme,
+		MarklogicCluster: cluster,
+		Recorder:         record.NewFakeRecorder(10),
+	}
+
+	if res := cc.ReconcilePause(); !res.Completed() {
+		t.Fatal("expected the paused cluster to stop the reconcile")
+	}
+	group := &marklogicv1.MarklogicGroup{}
+	if err := fakeClient.Get(context.Background(), types.NamespacedName{Name: "enode", Namespace: "default"}, group); err != nil {
+		t.Fatalf("failed to get group: %v", err)
+	}
+	if !group.Spec.Maintenance {
+		t.Fatal("expected maintenance to reach the group while the cluster is paused")
+	}
+}
+
+func TestHAProxyDrainsGroupInMaintenance(t *testing.T) {
+	t.Parallel()
+
+	pathBased := false
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			ClusterDomain: "cluster.local",
+			HAProxy: &marklogicv1.HAProxy{
+				Enabled:          true,
+				PathBasedRouting: &pathBased,
+				AppServers:       []marklogicv1.AppServers{{Name: "app-service", Port: 8000}},
+				TcpPorts: &marklogicv1.Tcpports{
+					Enabled: true,
+					Ports:   []marklogicv1.TcpPort{{Name: "odbc", Port: 5432}},
+				},
+			},
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{
+				{Name: "dnode", IsBootstrap: true, Replicas: int32Ptr(1)},
+				{Name: "enode", Replicas: int32Ptr(1), Maintenance: true},
+			},
+		},
+	}
+
+	config := generateHAProxyConfig(context.Background(), cr)
+	for _, server := range haproxyServers(cr, config) {
+		if drained := server.Weight == 0; (server.PodName == "enode-0") != drained {
+			t.Fatalf("expected only enode servers to be drained, got %+v", server)
+		}
+	}
+	// The drain is applied at runtime; the configuration, and so the rollout hash, stay the same.
+	backends := generateBackendConfig(cr, config)
+	tcp := generateTcpConfig(cr, config)
+	if strings.Contains(backends+tcp, "weight 0") {
+		t.Fatalf("expected the drain to stay out of the configuration:\n%s%s", backends, tcp)
+	}
+	if !strings.Contains(backends, "enode-8000-0") || !strings.Contains(tcp, "enode-5432-0") {
+		t.Fatalf("expected the enode servers to stay in the configuration:\n%s%s", backends, tcp)
+	}
+	before := haproxyRolloutHash(context.Background(), cr, generateHAProxyConfigMapData(context.Background(), cr))
+	cr.Spec.MarkLogicGroups[1].Maintenance = false
+	if after := haproxyRolloutHash(context.Background(), cr, generateHAProxyConfigMapData(context.Background(), cr)); after != before {
+		t.Fatal("expected ending maintenance not to roll HAProxy")
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"context"
+
+	"github.com/cisco-open/k8s-objectmatcher/patch"
+	"github.com/go-logr/logr"
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const haproxyPodDisruptionBudgetName = "marklogic-haproxy"
+
+func podDisruptionBudgetEnabled(pdb *marklogicv1.PodDisruptionBudget) bool {
+	return pdb != nil && pdb.Enabled
+}
+
+// defaultMaxUnavailable keeps forests of data groups available by draining one host at a
+// time. Dynamic e-nodes hold no forests, so half of them may go at once.
+func defaultMaxUnavailable(isDynamic bool) intstr.IntOrString {
+	if isDynamic {
+		return intstr.FromString("50%")
+	}
+	return intstr.FromInt32(1)
+}
+
+func generatePodDisruptionBudget(meta metav1.ObjectMeta, ownerDef metav1.OwnerReference, selector map[string]string,
+	spec *marklogicv1.PodDisruptionBudget, defaultMax intstr.IntOrString) *policyv1.PodDisruptionBudget {
+	pdb := &policyv1.PodDisruptionBudget{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "PodDisruptionBudget",
+			APIVersion: "policy/v1",
+		},
+		ObjectMeta: meta,
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			Selector:       LabelSelectors(selector),
+			MinAvailable:   spec.MinAvailable,
+			MaxUnavailable: spec.MaxUnavailable,
+		},
+	}
+	if pdb.Spec.MinAvailable == nil && pdb.Spec.MaxUnavailable == nil {
+		pdb.Spec.MaxUnavailable = &defaultMax
+	}
+	pdb.SetOwnerReferences(append(pdb.GetOwnerReferences(), ownerDef))
+	return pdb
+}
+
+func (oc *OperatorContext) generatePodDisruptionBudget(cr *marklogicv1.MarklogicGroup) *policyv1.PodDisruptionBudget {
+	meta := generateObjectMeta(cr.Spec.Name, cr.Namespace, oc.GetOperatorLabels(cr.Spec.Name), oc.GetOperatorAnnotations())
+	return generatePodDisruptionBudget(meta, marklogicServerAsOwner(cr), getSelectorLabelsByComponent(cr.Spec.Name, cr.Spec.IsDynamic),
+		cr.Spec.PodDisruptionBudget, defaultMaxUnavailable(cr.Spec.IsDynamic))
+}
+
+// haproxyPodDisruptionBudget returns the budget of the HAProxy Deployment, which is enabled
+// unless the spec disables it.
+func haproxyPodDisruptionBudget(cr *marklogicv1.MarklogicCluster) *marklogicv1.PodDisruptionBudget {
+	if cr.Spec.HAProxy.PodDisruptionBudget == nil {
+		return &marklogicv1.PodDisruptionBudget{Enabled: true}
+	}
+	return cr.Spec.HAProxy.PodDisruptionBudget
+}
+
+func (cc *ClusterContext) generateHAProxyPodDisruptionBudget() *policyv1.PodDisruptionBudget {
+	cr := cc.MarklogicCluster
+	meta := generateObjectMeta(haproxyPodDisruptionBudgetName, cr.Namespace, cc.GetHAProxyLabels(cr.Name), cc.GetClusterAnnotations())
+	return generatePodDisruptionBudget(meta, marklogicClusterAsOwner(cr), getHAProxySelectorLabels(cr.Name),
+		haproxyPodDisruptionBudget(cr), defaultMaxUnavailable(false))
+}
+
+// ReconcilePodDisruptionBudget keeps the PodDisruptionBudget of the group StatefulSet in
+// line with spec.podDisruptionBudget, and removes it when the budget is disabled.
+func (oc *OperatorContext) ReconcilePodDisruptionBudget() result.ReconcileResult {
+	cr := oc.MarklogicGroup
+	name := types.NamespacedName{Name: cr.Spec.Name, Namespace: cr.Namespace}
+	if !podDisruptionBudgetEnabled(cr.Spec.PodDisruptionBudget) {
+		if err := deletePodDisruptionBudget(oc.Ctx, oc.Client, oc.ReqLogger, name, cr.UID); err != nil {
+			return result.Error(err)
+		}
+		return result.Continue()
+	}
+	if err := applyPodDisruptionBudget(oc.Ctx, oc.Client, oc.ReqLogger, oc.generatePodDisruptionBudget(cr)); err != nil {
+		return result.Error(err)
+	}
+	return result.Continue()
+}
+
+// ReconcileHAProxyPodDisruptionBudget does the same for the HAProxy Deployment.
+func (cc *ClusterContext) ReconcileHAProxyPodDisruptionBudget() result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	name := types.NamespacedName{Name: haproxyPodDisruptionBudgetName, Namespace: cr.Namespace}
+	if cr.Spec.HAProxy == nil || !cr.Spec.HAProxy.Enabled || !podDisruptionBudgetEnabled(haproxyPodDisruptionBudget(cr)) {
+		if err := deletePodDisruptionBudget(cc.Ctx, cc.Client, cc.ReqLogger, name, cr.UID); err != nil {
+			return result.Error(err)
+		}
+		return result.Continue()
+	}
+	if err := applyPodDisruptionBudget(cc.Ctx, cc.Client, cc.ReqLogger, cc.generateHAProxyPodDisruptionBudget()); err != nil {
+		return result.Error(err)
+	}
+	return result.Continue()
+}
+
+func applyPodDisruptionBudget(ctx context.Context, c client.Client, logger logr.Logger, desired *policyv1.PodDisruptionBudget) error {
+	current := &policyv1.PodDisruptionBudget{}
+	err := c.Get(ctx, types.NamespacedName{Name: desired.Name, Namespace: desired.Namespace}, current)
+	if apierrors.IsNotFound(err) {
+		logger.Info("PodDisruptionBudget not found, creating a new one", "name", desired.Name)
+		if err := patch.DefaultAnnotator.SetLastAppliedAnnotation(desired); err != nil {
+			logger.Error(err, "Failed to set last applied annotation for PodDisruptionBudget")
+		}
+		if err := c.Create(ctx, desired); err != nil {
+			logger.Error(err, "PodDisruptionBudget creation has failed")
+			return err
+		}
+		return nil
+	}
+	if err != nil {
+		logger.Error(err, "Failed to get PodDisruptionBudget")
+		return err
+	}
+	patchDiff, err := patch.DefaultPatchMaker.Calculate(current, desired,
+		patch.IgnoreStatusFields(),
+		patch.IgnoreField("kind"))
+	if err != nil {
+		logger.Error(err, "Error calculating patch")
+		return err
+	}
+	if patchDiff.IsEmpty() {
+		return nil
+	}
+	logger.Info("PodDisruptionBudget is different from the spec, updating it", "name", desired.Name)
+	current.Spec = desired.Spec
+	current.ObjectMeta.Labels = desired.ObjectMeta.Labels
+	current.ObjectMeta.Annotations = desired.ObjectMeta.Annotations
+	if err := patch.DefaultAnnotator.SetLastAppliedAnnotation(current); err != nil {
+		logger.Error(err, "Failed to set last applied annotation for PodDisruptionBudget")
+	}
+	if err := c.Update(ctx, current); err != nil {
+		logger.Error(err, "Error updating PodDisruptionBudget")
+		return err
+	}
+	return nil
+}
+
+// deletePodDisruptionBudget removes a budget created by the owner; budgets created by users
+// under the same name are left alone.
+func deletePodDisruptionBudget(ctx context.Context, c client.Client, logger logr.Logger, name types.NamespacedName, ownerUID types.UID) error {
+	current := &policyv1.PodDisruptionBudget{}
+	if err := c.Get(ctx, name, current); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		logger.Error(err, "Failed to get PodDisruptionBudget")
+		return err
+	}
+	if controller := metav1.GetControllerOf(current); controller == nil || controller.UID != ownerUID {
+		return nil
+	}
+	logger.Info("PodDisruptionBudget is disabled, removing it", "name", name.Name)
+	if err := c.Delete(ctx, current); err != nil && !apierrors.IsNotFound(err) {
+		logger.Error(err, "Failed to delete PodDisruptionBudget")
+		return err
+	}
+	return nil
+}
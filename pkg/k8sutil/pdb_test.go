@@ -0,0 +1,112 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"context"
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcilePodDisruptionBudgetDefaultsAndRemoval(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := marklogicv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add marklogic scheme: %v", err)
+	}
+	if err := policyv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add policy scheme: %v", err)
+	}
+	dnode := &marklogicv1.MarklogicGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "dnode", Namespace: "ml", UID: "dnode-uid"},
+		Spec: marklogicv1.MarklogicGroupSpec{
+			Name:                "dnode",
+			PodDisruptionBudget: &marklogicv1.PodDisruptionBudget{Enabled: true},
+		},
+	}
+	enode := &marklogicv1.MarklogicGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "enode", Namespace: "ml", UID: "enode-uid"},
+		Spec: marklogicv1.MarklogicGroupSpec{
+			Name:                "enode",
+			IsDynamic:           true,
+			PodDisruptionBudget: &marklogicv1.PodDisruptionBudget{Enabled: true},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(dnode, enode).Build()
+	getPDB := func(name string) (*policyv1.PodDisruptionBudget, error) {
+		pdb := &policyv1.PodDisruptionBudget{}
+		err := fakeClient.Get(context.Background(), types.NamespacedName{Name: name, Namespace: "ml"}, pdb)
+		return pdb, err
+	}
+	for _, group := range []*marklogicv1.MarklogicGroup{dnode, enode} {
+		oc := &OperatorContext{Ctx: context.Background(), Client: fakeClient, Scheme: scheme, MarklogicGroup: group, Recorder: record.NewFakeRecorder(10)}
+		if res := oc.ReconcilePodDisruptionBudget(); res.Completed() {
+			t.Fatalf("expected reconcile to continue, got %+v", res)
+		}
+	}
+	pdb, err := getPDB("dnode")
+	if err != nil || pdb.Spec.MaxUnavailable == nil || *pdb.Spec.MaxUnavailable != intstr.FromInt32(1) {
+		t.Fatalf("expected data groups to allow one unavailable host, got %+v (%v)", pdb.Spec, err)
+	}
+	if pdb.Spec.Selector.MatchLabels["app.kubernetes.io/component"] != "database" {
+		t.Fatalf("expected the StatefulSet pod selector, got %v", pdb.Spec.Selector)
+	}
+	if pdb, err := getPDB("enode"); err != nil || *pdb.Spec.MaxUnavailable != intstr.FromString("50%") {
+		t.Fatalf("expected dynamic groups to allow half of the hosts, got %+v (%v)", pdb.Spec, err)
+	}
+
+	oc := &OperatorContext{Ctx: context.Background(), Client: fakeClient, Scheme: scheme, MarklogicGroup: dnode, Recorder: record.NewFakeRecorder(10)}
+	minAvailable := intstr.FromInt32(2)
+	dnode.Spec.PodDisruptionBudget.MinAvailable = &minAvailable
+	if res := oc.ReconcilePodDisruptionBudget(); res.Completed() {
+		t.Fatalf("expected reconcile to continue, got %+v", res)
+	}
+	if pdb, _ := getPDB("dnode"); pdb.Spec.MaxUnavailable != nil || *pdb.Spec.MinAvailable != minAvailable {
+		t.Fatalf("expected minAvailable to replace the default, got %+v", pdb.Spec)
+	}
+
+	dnode.Spec.PodDisruptionBudget.Enabled = false
+	if res := oc.ReconcilePodDisruptionBudget(); res.Completed() {
+		t.Fatalf("expected reconcile to continue, got %+v", res)
+	}
+	if _, err := getPDB("dnode"); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected the PodDisruptionBudget to be removed, got %v", err)
+	}
+}
+
+func TestReconcileHAProxyPodDisruptionBudgetIsOnByDefault(t *testing.T) {
+	cc := newAppServerTestContext(t, &marklogicv1.MarklogicGroups{Name: "dnode", IsBootstrap: true, Replicas: int32Ptr(1)})
+	if err := policyv1.AddToScheme(cc.Scheme); err != nil {
+		t.Fatalf("failed to add policy scheme: %v", err)
+	}
+	cr := cc.MarklogicCluster
+	cr.UID = "ml-uid"
+	name := types.NamespacedName{Name: haproxyPodDisruptionBudgetName, Namespace: "default"}
+
+	if res := cc.ReconcileHAProxyPodDisruptionBudget(); res.Completed() {
+		t.Fatalf("expected reconcile to continue, got %+v", res)
+	}
+	pdb := &policyv1.PodDisruptionBudget{}
+	if err := cc.Client.Get(cc.Ctx, name, pdb); err != nil {
+		t.Fatalf("expected the HAProxy PodDisruptionBudget without a spec: %v", err)
+	}
+	if pdb.Spec.MaxUnavailable == nil || *pdb.Spec.MaxUnavailable != intstr.FromInt32(1) {
+		t.Fatalf("expected maxUnavailable 1, got %+v", pdb.Spec)
+	}
+
+	cr.Spec.HAProxy.PodDisruptionBudget = &marklogicv1.PodDisruptionBudget{Enabled: false}
+	if res := cc.ReconcileHAProxyPodDisruptionBudget(); res.Completed() {
+		t.Fatalf("expected reconcile to continue, got %+v", res)
+	}
+	if err := cc.Client.Get(cc.Ctx, name, pdb); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected the budget to be removed when disabled, got %v", err)
+	}
+}
@@ -0,0 +1,412 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	replicationCleanupFinalizer = "marklogic.progress.com/replication-cleanup"
+
+	// Lag is only observed by polling.
+	replicationResyncSeconds = 60
+	replicationRetrySeconds  = 30
+
+	replicationReasonEndpointUnavailable = "EndpointUnavailable"
+	replicationReasonCoupled             = "Coupled"
+	replicationReasonNotCoupled          = "NotCoupled"
+	replicationReasonDatabaseNotFound    = "DatabaseNotFound"
+	replicationReasonSuspended           = "Suspended"
+	replicationReasonLagLimitExceeded    = "LagLimitExceeded"
+	replicationReasonReplicating         = "Replicating"
+
+	replicationStateDatabaseNotFound = "DatabaseNotFound"
+	replicationStateConfigured       = "Configured"
+)
+
+// ReconcileReplication couples the source and target clusters of a MarklogicReplication,
+// configures the listed databases to replicate from the source to the target and reports
+// the replication state and lag.
+func (rc *ReplicationContext) ReconcileReplication() result.ReconcileResult {
+	cr := rc.MarklogicReplication
+	if cr.DeletionTimestamp != nil {
+		return rc.finalizeReplication()
+	}
+	if !controllerutil.ContainsFinalizer(cr, replicationCleanupFinalizer) {
+		patch := client.MergeFrom(cr.DeepCopy())
+		controllerutil.AddFinalizer(cr, replicationCleanupFinalizer)
+		if err := rc.Client.Patch(rc.Ctx, cr, patch); err != nil {
+			return result.Error(err)
+		}
+	}
+
+	patchClient := client.MergeFrom(cr.DeepCopy())
+	status := cr.Status.DeepCopy()
+	status.ObservedGeneration = cr.Generation
+	requeueSecs := rc.syncReplication(status)
+	if !reflect.DeepEqual(*status, cr.Status) {
+		cr.Status = *status
+		if err := rc.Client.Status().Patch(rc.Ctx, cr, patchClient); err != nil {
+			rc.ReqLogger.Error(err, "Failed to update MarklogicReplication status")
+			return result.Error(err)
+		}
+	}
+	return result.RequeueSoon(requeueSecs)
+}
+
+// syncReplication brings both clusters in line with the spec and returns how long to wait
+// before the next check. Management API failures are reported in the conditions.
+func (rc *ReplicationContext) syncReplication(status *marklogicv1.MarklogicReplicationStatus) int {
+	cr := rc.MarklogicReplication
+	source, target, err := rc.replicationClients()
+	if err != nil {
+		setReplicationCondition(status, cr.Generation, marklogicv1.ReplicationReady, metav1.ConditionFalse, replicationReasonEndpointUnavailable, err.Error())
+		return replicationRetrySeconds
+	}
+	sourceName, targetName, err := rc.coupleClusters(source, target)
+	if err != nil {
+		setReplicationCondition(status, cr.Generation, marklogicv1.ReplicationCoupled, metav1.ConditionFalse, replicationReasonNotCoupled, err.Error())
+		setReplicationCondition(status, cr.Generation, marklogicv1.ReplicationReady, metav1.ConditionFalse, replicationReasonNotCoupled, err.Error())
+		return replicationRetrySeconds
+	}
+	status.SourceClusterName = sourceName
+	status.TargetClusterName = targetName
+	setReplicationCondition(status, cr.Generation, marklogicv1.ReplicationCoupled, metav1.ConditionTrue, replicationReasonCoupled,
+		fmt.Sprintf("clusters %s and %s are coupled", sourceName, targetName))
+
+	databases := make([]marklogicv1.DatabaseReplicationState, 0, len(cr.Spec.Databases))
+	var missing, lagging []string
+	for _, database := range cr.Spec.Databases {
+		state, err := rc.syncDatabaseReplication(source, target, sourceName, targetName, database)
+		if err != nil {
+			setReplicationCondition(status, cr.Generation, marklogicv1.ReplicationReady, metav1.ConditionFalse, replicationReasonEndpointUnavailable, err.Error())
+			return replicationRetrySeconds
+		}
+		switch {
+		case state.State == replicationStateDatabaseNotFound:
+			missing = append(missing, state.Message)
+		case state.LagSeconds > int64(cr.Spec.LagLimit):
+			lagging = append(lagging, fmt.Sprintf("%s is %ds behind", state.Name, state.LagSeconds))
+		}
+		databases = append(databases, state)
+	}
+	status.Databases = databases
+
+	switch {
+	case len(missing) > 0:
+		setReplicationCondition(status, cr.Generation, marklogicv1.ReplicationReady, metav1.ConditionFalse, replicationReasonDatabaseNotFound, strings.Join(missing, "; "))
+		return replicationRetrySeconds
+	case cr.Spec.Suspended:
+		setReplicationCondition(status, cr.Generation, marklogicv1.ReplicationReady, metav1.ConditionFalse, replicationReasonSuspended, "replication is suspended")
+	case len(lagging) > 0:
+		message := fmt.Sprintf("lag limit of %ds exceeded: %s", cr.Spec.LagLimit, strings.Join(lagging, "; "))
+		if previous := apimeta.FindStatusCondition(status.Conditions, string(marklogicv1.ReplicationReady)); previous == nil || previous.Reason != replicationReasonLagLimitExceeded {
+			rc.Recorder.Event(cr, corev1.EventTypeWarning, replicationReasonLagLimitExceeded, message)
+		}
+		setReplicationCondition(status, cr.Generation, marklogicv1.ReplicationReady, metav1.ConditionFalse, replicationReasonLagLimitExceeded, message)
+	default:
+		setReplicationCondition(status, cr.Generation, marklogicv1.ReplicationReady, metav1.ConditionTrue, replicationReasonReplicating,
+			fmt.Sprintf("%d database(s) replicate from %s to %s", len(databases), sourceName, targetName))
+	}
+	return replicationResyncSeconds
+}
+
+// replicationClient is the part of the Management API that couples clusters and replicates
+// their databases.
+type replicationClient interface {
+	mlmanage.DatabaseClient
+	mlmanage.ReplicationClient
+}
+
+func (rc *ReplicationContext) replicationClients() (mlmanage.Client, mlmanage.Client, error) {
+	source, err := rc.endpointClient(rc.MarklogicReplication.Spec.Source)
+	if err != nil {
+		return nil, nil, fmt.Errorf("source: %w", err)
+	}
+	target, err := rc.endpointClient(rc.MarklogicReplication.Spec.Target)
+	if err != nil {
+		return nil, nil, fmt.Errorf("target: %w", err)
+	}
+	return source, target, nil
+}
+
+// endpointClient connects to the bootstrap host of a MarklogicCluster or to the
+// Management API of an external cluster.
+func (rc *ReplicationContext) endpointClient(endpoint marklogicv1.ReplicationEndpoint) (mlmanage.Client, error) {
+	cr := rc.MarklogicReplication
+	if ref := endpoint.ClusterRef; ref != nil {
+		cluster, err := rc.referencedCluster(ref)
+		if err != nil {
+			return nil, err
+		}
+		return managementClientForCluster(rc.Ctx, rc.Client, cluster)
+	}
+	if external := endpoint.External; external != nil {
+		username, password, err := readCredentialSecret(rc.Ctx, rc.Client, cr.Namespace, external.SecretName)
+		if err != nil {
+			return nil, err
+		}
+		return NewClusterManagementClient(mlmanage.ClientOptions{
+			Host:     external.Host,
+			Username: username,
+			Password: password,
+			UseTLS:   external.UseTLS,
+		}), nil
+	}
+	return nil, fmt.Errorf("neither clusterRef nor external is set")
+}
+
+func (rc *ReplicationContext) referencedCluster(ref *marklogicv1.ReplicationClusterRef) (*marklogicv1.MarklogicCluster, error) {
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = rc.MarklogicReplication.Namespace
+	}
+	cluster := &marklogicv1.MarklogicCluster{}
+	if err := rc.Client.Get(rc.Ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, cluster); err != nil {
+		return nil, err
+	}
+	return cluster, nil
+}
+
+// coupleClusters makes each cluster a foreign cluster of the other and returns their
+// MarkLogic names.
+func (rc *ReplicationContext) coupleClusters(source, target mlmanage.ReplicationClient) (string, string, error) {
+	sourceProperties, err := source.GetClusterProperties(rc.Ctx)
+	if err != nil {
+		return "", "", fmt.Errorf("source: %w", err)
+	}
+	targetProperties, err := target.GetClusterProperties(rc.Ctx)
+	if err != nil {
+		return "", "", fmt.Errorf("target: %w", err)
+	}
+	sourceName := fmt.Sprint(sourceProperties["cluster-name"])
+	targetName := fmt.Sprint(targetProperties["cluster-name"])
+	if sourceName == targetName {
+		return "", "", fmt.Errorf("source and target are both MarkLogic cluster %s", sourceName)
+	}
+
+	coupled := false
+	for _, side := range []struct {
+		local             mlmanage.ReplicationClient
+		foreignName       string
+		foreignProperties map[string]any
+	}{
+		{local: source, foreignName: targetName, foreignProperties: targetProperties},
+		{local: target, foreignName: sourceName, foreignProperties: sourceProperties},
+	} {
+		foreign, err := side.local.ListForeignClusters(rc.Ctx)
+		if err != nil {
+			return "", "", err
+		}
+		if slices.Contains(foreign, side.foreignName) {
+			continue
+		}
+		if err := side.local.CoupleForeignCluster(rc.Ctx, side.foreignProperties); err != nil {
+			return "", "", fmt.Errorf("coupling %s: %w", side.foreignName, err)
+		}
+		coupled = true
+	}
+	if coupled {
+		rc.Recorder.Eventf(rc.MarklogicReplication, corev1.EventTypeNormal, "ClustersCoupled", "Coupled MarkLogic clusters %s and %s", sourceName, targetName)
+	}
+	return sourceName, targetName, nil
+}
+
+// syncDatabaseReplication configures the source database with the target as foreign
+// replica and the target database with the source as foreign master. Replication to other
+// clusters configured on the same database is kept.
+func (rc *ReplicationContext) syncDatabaseReplication(source, target replicationClient, sourceName, targetName string, database marklogicv1.ReplicatedDatabase) (marklogicv1.DatabaseReplicationState, error) {
+	cr := rc.MarklogicReplication
+	targetDatabase := replicationTargetDatabase(database)
+	state := marklogicv1.DatabaseReplicationState{Name: database.Name, TargetName: targetDatabase}
+
+	sourceInfo, err := source.GetDatabase(rc.Ctx, database.Name)
+	if err != nil {
+		return state, err
+	}
+	targetInfo, err := target.GetDatabase(rc.Ctx, targetDatabase)
+	if err != nil {
+		return state, err
+	}
+	if !sourceInfo.Exists || !targetInfo.Exists {
+		state.State = replicationStateDatabaseNotFound
+		if !sourceInfo.Exists {
+			state.Message = fmt.Sprintf("database %s does not exist on %s", database.Name, sourceName)
+		} else {
+			state.Message = fmt.Sprintf("database %s does not exist on %s", targetDatabase, targetName)
+		}
+		return state, nil
+	}
+
+	replica := mlmanage.ForeignDatabase{
+		ClusterName:  targetName,
+		DatabaseName: targetDatabase,
+		LagLimit:     cr.Spec.LagLimit,
+		Enabled:      !cr.Spec.Suspended,
+	}
+	if replicas, changed := withForeignReplica(sourceInfo.ForeignReplicas, replica); changed {
+		if err := source.UpdateDatabaseProperties(rc.Ctx, database.Name, mlmanage.DatabaseReplicationProperties(replicas, sourceInfo.ForeignMaster)); err != nil {
+			return state, err
+		}
+		rc.Recorder.Eventf(cr, corev1.EventTypeNormal, "ReplicaConfigured", "Database %s on %s replicates to %s on %s", database.Name, sourceName, targetDatabase, targetName)
+	}
+	master := mlmanage.ForeignDatabase{ClusterName: sourceName, DatabaseName: database.Name}
+	if targetInfo.ForeignMaster == nil || *targetInfo.ForeignMaster != master {
+		if err := target.UpdateDatabaseProperties(rc.Ctx, targetDatabase, mlmanage.DatabaseReplicationProperties(targetInfo.ForeignReplicas, &master)); err != nil {
+			return state, err
+		}
+	}
+
+	statuses, err := source.GetDatabaseReplicationStatus(rc.Ctx, database.Name)
+	if err != nil {
+		return state, err
+	}
+	state.State = replicationStateConfigured
+	for _, replicaStatus := range statuses {
+		if replicaStatus.ClusterName == targetName && replicaStatus.DatabaseName == targetDatabase {
+			if replicaStatus.State != "" {
+				state.State = replicaStatus.State
+			}
+			state.LagSeconds = replicaStatus.LagSeconds
+		}
+	}
+	return state, nil
+}
+
+// withForeignReplica returns the foreign replicas with the entry for the replica's cluster
+// and database replaced by the replica.
+func withForeignReplica(replicas []mlmanage.ForeignDatabase, replica mlmanage.ForeignDatabase) ([]mlmanage.ForeignDatabase, bool) {
+	updated := make([]mlmanage.ForeignDatabase, 0, len(replicas)+1)
+	found, changed := false, false
+	for _, current := range replicas {
+		if current.ClusterName == replica.ClusterName && current.DatabaseName == replica.DatabaseName {
+			found = true
+			changed = current != replica
+			current = replica
+		}
+		updated = append(updated, current)
+	}
+	if !found {
+		updated = append(updated, replica)
+		changed = true
+	}
+	return updated, changed
+}
+
+func withoutForeignReplica(replicas []mlmanage.ForeignDatabase, clusterName, databaseName string) ([]mlmanage.ForeignDatabase, bool) {
+	updated := make([]mlmanage.ForeignDatabase, 0, len(replicas))
+	for _, current := range replicas {
+		if current.ClusterName != clusterName || current.DatabaseName != databaseName {
+			updated = append(updated, current)
+		}
+	}
+	return updated, len(updated) != len(replicas)
+}
+
+func replicationTargetDatabase(database marklogicv1.ReplicatedDatabase) string {
+	if database.TargetName != "" {
+		return database.TargetName
+	}
+	return database.Name
+}
+
+// finalizeReplication removes the database replication configuration before releasing the
+// finalizer. The clusters stay coupled, since other replications may rely on that. A
+// referenced MarklogicCluster that is gone or being deleted has nothing left to clean up.
+func (rc *ReplicationContext) finalizeReplication() result.ReconcileResult {
+	cr := rc.MarklogicReplication
+	if !controllerutil.ContainsFinalizer(cr, replicationCleanupFinalizer) {
+		return result.Done()
+	}
+	if cr.Status.SourceClusterName != "" && cr.Status.TargetClusterName != "" {
+		source, err := rc.cleanupClient(cr.Spec.Source)
+		if err != nil {
+			return result.Error(err)
+		}
+		target, err := rc.cleanupClient(cr.Spec.Target)
+		if err != nil {
+			return result.Error(err)
+		}
+		for _, database := range cr.Spec.Databases {
+			if err := rc.removeDatabaseReplication(source, target, database); err != nil {
+				rc.ReqLogger.Error(err, "Failed to remove database replication", "database", database.Name)
+				return result.RequeueSoon(replicationRetrySeconds)
+			}
+		}
+	}
+	patch := client.MergeFrom(cr.DeepCopy())
+	controllerutil.RemoveFinalizer(cr, replicationCleanupFinalizer)
+	if err := rc.Client.Patch(rc.Ctx, cr, patch); err != nil {
+		return result.Error(err)
+	}
+	return result.Done()
+}
+
+// cleanupClient is endpointClient for the finalizer; it returns nil for a referenced
+// cluster that no longer needs cleaning up.
+func (rc *ReplicationContext) cleanupClient(endpoint marklogicv1.ReplicationEndpoint) (mlmanage.Client, error) {
+	if ref := endpoint.ClusterRef; ref != nil {
+		cluster, err := rc.referencedCluster(ref)
+		if apierrors.IsNotFound(err) || (err == nil && cluster.DeletionTimestamp != nil) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return rc.endpointClient(endpoint)
+}
+
+func (rc *ReplicationContext) removeDatabaseReplication(source, target replicationClient, database marklogicv1.ReplicatedDatabase) error {
+	cr := rc.MarklogicReplication
+	targetDatabase := replicationTargetDatabase(database)
+	if source != nil {
+		info, err := source.GetDatabase(rc.Ctx, database.Name)
+		if err != nil {
+			return err
+		}
+		if replicas, changed := withoutForeignReplica(info.ForeignReplicas, cr.Status.TargetClusterName, targetDatabase); info.Exists && changed {
+			if err := source.UpdateDatabaseProperties(rc.Ctx, database.Name, mlmanage.DatabaseReplicationProperties(replicas, info.ForeignMaster)); err != nil {
+				return err
+			}
+		}
+	}
+	if target != nil {
+		info, err := target.GetDatabase(rc.Ctx, targetDatabase)
+		if err != nil {
+			return err
+		}
+		master := mlmanage.ForeignDatabase{ClusterName: cr.Status.SourceClusterName, DatabaseName: database.Name}
+		if info.Exists && info.ForeignMaster != nil && *info.ForeignMaster == master {
+			if err := target.UpdateDatabaseProperties(rc.Ctx, targetDatabase, mlmanage.DatabaseReplicationProperties(info.ForeignReplicas, nil)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func setReplicationCondition(status *marklogicv1.MarklogicReplicationStatus, generation int64, conditionType marklogicv1.MarkLogicConditionType, conditionStatus metav1.ConditionStatus, reason, message string) {
+	apimeta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               string(conditionType),
+		Status:             conditionStatus,
+		ObservedGeneration: generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
@@ -0,0 +1,215 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// stubDatabaseReplication reads back the database-replication property built by
+// mlmanage.DatabaseReplicationProperties.
+func stubDatabaseReplication(replication map[string]any) ([]mlmanage.ForeignDatabase, *mlmanage.ForeignDatabase) {
+	var replicas []mlmanage.ForeignDatabase
+	entries, _ := replication["foreign-replicas"].([]map[string]any)
+	for _, entry := range entries {
+		replicas = append(replicas, mlmanage.ForeignDatabase{
+			ClusterName:  fmt.Sprint(entry["foreign-cluster-name"]),
+			DatabaseName: fmt.Sprint(entry["foreign-database-name"]),
+			LagLimit:     entry["lag-limit"].(int32),
+			Enabled:      entry["replication-enabled"].(bool),
+		})
+	}
+	var master *mlmanage.ForeignDatabase
+	if entry, ok := replication["foreign-master"].(map[string]any); ok {
+		master = &mlmanage.ForeignDatabase{
+			ClusterName:  fmt.Sprint(entry["foreign-cluster-name"]),
+			DatabaseName: fmt.Sprint(entry["foreign-database-name"]),
+		}
+	}
+	return replicas, master
+}
+
+// stubReplicationCluster is one side of a replication: its databases and the clusters it is
+// coupled with. coupledClusters records the cluster properties posted to couple a foreign
+// cluster.
+type stubReplicationCluster struct {
+	stubDatabaseClient
+	clusterProperties map[string]any
+	foreignClusters   []string
+	coupledClusters   []map[string]any
+	replicaStatus     map[string][]mlmanage.ForeignReplicaStatus
+}
+
+func (s *stubReplicationCluster) GetClusterProperties(ctx context.Context) (map[string]any, error) {
+	return s.clusterProperties, nil
+}
+
+func (s *stubReplicationCluster) ListForeignClusters(ctx context.Context) ([]string, error) {
+	return s.foreignClusters, nil
+}
+
+func (s *stubReplicationCluster) CoupleForeignCluster(ctx context.Context, properties map[string]any) error {
+	s.coupledClusters = append(s.coupledClusters, properties)
+	s.foreignClusters = append(s.foreignClusters, fmt.Sprint(properties["cluster-name"]))
+	return nil
+}
+
+func (s *stubReplicationCluster) GetDatabaseReplicationStatus(ctx context.Context, database string) ([]mlmanage.ForeignReplicaStatus, error) {
+	return s.replicaStatus[database], nil
+}
+
+func newReplicationTestContext(t *testing.T, source, target *stubReplicationCluster) *ReplicationContext {
+	t.Helper()
+	replication := &marklogicv1.MarklogicReplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "orders-dr", Namespace: "default", Generation: 1},
+		Spec: marklogicv1.MarklogicReplicationSpec{
+			Source:    marklogicv1.ReplicationEndpoint{ClusterRef: &marklogicv1.ReplicationClusterRef{Name: "ml"}},
+			Target:    marklogicv1.ReplicationEndpoint{External: &marklogicv1.ExternalCluster{Host: "dr.example.com", SecretName: "dr-admin"}},
+			Databases: []marklogicv1.ReplicatedDatabase{{Name: "orders", TargetName: "orders-dr"}},
+			LagLimit:  15,
+		},
+	}
+	c := newBackupTestClient(t, replication, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "dr-admin", Namespace: "default"},
+		Data:       map[string][]byte{"username": []byte("dr"), "password": []byte("secret")},
+	})
+	originalFactory := NewClusterManagementClient
+	NewClusterManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+		if opts.Host == "dr.example.com" {
+			if opts.Username != "dr" || opts.InsecureSkipVerify {
+				t.Errorf("unexpected options for the external cluster: %+v", opts)
+			}
+			return managementStub{DatabaseClient: target, ReplicationClient: target}
+		}
+		return managementStub{DatabaseClient: source, ReplicationClient: source}
+	}
+	t.Cleanup(func() { NewClusterManagementClient = originalFactory })
+	return &ReplicationContext{
+		Ctx:                  context.Background(),
+		Client:               c,
+		MarklogicReplication: replication,
+		Recorder:             record.NewFakeRecorder(20),
+	}
+}
+
+func TestReconcileReplicationCouplesClustersAndConfiguresDatabases(t *testing.T) {
+	source := &stubReplicationCluster{
+		stubDatabaseClient: stubDatabaseClient{databases: map[string]mlmanage.DatabaseInfo{"orders": {
+			Exists:          true,
+			ForeignReplicas: []mlmanage.ForeignDatabase{{ClusterName: "archive", DatabaseName: "orders", LagLimit: 60, Enabled: true}},
+		}}},
+		clusterProperties: map[string]any{"cluster-name": "primary"},
+		replicaStatus: map[string][]mlmanage.ForeignReplicaStatus{"orders": {
+			{ClusterName: "archive", DatabaseName: "orders", State: "connected", LagSeconds: 90},
+			{ClusterName: "dr", DatabaseName: "orders-dr", State: "connected", LagSeconds: 2},
+		}},
+	}
+	target := &stubReplicationCluster{
+		stubDatabaseClient: stubDatabaseClient{databases: map[string]mlmanage.DatabaseInfo{"orders-dr": {Exists: true}}},
+		clusterProperties:  map[string]any{"cluster-name": "dr"},
+		foreignClusters:    []string{"primary"},
+	}
+	rc := newReplicationTestContext(t, source, target)
+
+	if res, _ := rc.ReconcileReplication().Output(); res.RequeueAfter == 0 {
+		t.Fatal("expected the replication to be polled again")
+	}
+	if !controllerutil.ContainsFinalizer(rc.MarklogicReplication, replicationCleanupFinalizer) {
+		t.Fatal("expected the cleanup finalizer to be added")
+	}
+	if len(source.coupledClusters) != 1 || source.coupledClusters[0]["cluster-name"] != "dr" || len(target.coupledClusters) != 0 {
+		t.Fatalf("expected only the source to be coupled to dr, got source=%v target=%v", source.coupledClusters, target.coupledClusters)
+	}
+	replicas := source.databases["orders"].ForeignReplicas
+	if len(replicas) != 2 || replicas[0].ClusterName != "archive" || replicas[1] != (mlmanage.ForeignDatabase{ClusterName: "dr", DatabaseName: "orders-dr", LagLimit: 15, Enabled: true}) {
+		t.Fatalf("expected dr to be added next to the existing replica, got %+v", replicas)
+	}
+	if master := target.databases["orders-dr"].ForeignMaster; master == nil || *master != (mlmanage.ForeignDatabase{ClusterName: "primary", DatabaseName: "orders"}) {
+		t.Fatalf("expected the target to replicate from primary, got %+v", master)
+	}
+
+	status := rc.MarklogicReplication.Status
+	if status.SourceClusterName != "primary" || status.TargetClusterName != "dr" {
+		t.Fatalf("unexpected cluster names %q and %q", status.SourceClusterName, status.TargetClusterName)
+	}
+	if len(status.Databases) != 1 || status.Databases[0].State != "connected" || status.Databases[0].LagSeconds != 2 {
+		t.Fatalf("expected the lag of the dr replica only, got %+v", status.Databases)
+	}
+	if !apimeta.IsStatusConditionTrue(status.Conditions, string(marklogicv1.ReplicationCoupled)) ||
+		!apimeta.IsStatusConditionTrue(status.Conditions, string(marklogicv1.ReplicationReady)) {
+		t.Fatalf("expected Coupled and Ready, got %+v", status.Conditions)
+	}
+
+	source.replicaStatus["orders"][1].LagSeconds = 40
+	rc.ReconcileReplication()
+	ready := apimeta.FindStatusCondition(rc.MarklogicReplication.Status.Conditions, string(marklogicv1.ReplicationReady))
+	if ready == nil || ready.Status != metav1.ConditionFalse || ready.Reason != replicationReasonLagLimitExceeded {
+		t.Fatalf("expected the lag limit to be exceeded, got %+v", ready)
+	}
+	var lagging int
+	for len(rc.Recorder.(*record.FakeRecorder).Events) > 0 {
+		if event := <-rc.Recorder.(*record.FakeRecorder).Events; strings.HasPrefix(event, "Warning "+replicationReasonLagLimitExceeded) {
+			lagging++
+		}
+	}
+	rc.ReconcileReplication()
+	if lagging != 1 || len(rc.Recorder.(*record.FakeRecorder).Events) != 0 {
+		t.Fatalf("expected a single lag warning, got %d", lagging)
+	}
+}
+
+func TestReconcileReplicationReportsMissingDatabaseAndCleansUp(t *testing.T) {
+	source := &stubReplicationCluster{
+		stubDatabaseClient: stubDatabaseClient{databases: map[string]mlmanage.DatabaseInfo{"orders": {Exists: true}}},
+		clusterProperties:  map[string]any{"cluster-name": "primary"},
+		foreignClusters:    []string{"dr"},
+	}
+	target := &stubReplicationCluster{
+		stubDatabaseClient: stubDatabaseClient{databases: map[string]mlmanage.DatabaseInfo{}},
+		clusterProperties:  map[string]any{"cluster-name": "dr"},
+		foreignClusters:    []string{"primary"},
+	}
+	rc := newReplicationTestContext(t, source, target)
+
+	rc.ReconcileReplication()
+	status := rc.MarklogicReplication.Status
+	ready := apimeta.FindStatusCondition(status.Conditions, string(marklogicv1.ReplicationReady))
+	if ready == nil || ready.Reason != replicationReasonDatabaseNotFound || !strings.Contains(ready.Message, "orders-dr") {
+		t.Fatalf("expected the missing target database to be reported, got %+v", ready)
+	}
+	if len(status.Databases) != 1 || status.Databases[0].State != replicationStateDatabaseNotFound {
+		t.Fatalf("unexpected database status %+v", status.Databases)
+	}
+	if len(source.databases["orders"].ForeignReplicas) != 0 {
+		t.Fatal("expected the source not to be configured before the target database exists")
+	}
+
+	target.databases["orders-dr"] = mlmanage.DatabaseInfo{Exists: true}
+	rc.ReconcileReplication()
+	if len(source.databases["orders"].ForeignReplicas) != 1 || target.databases["orders-dr"].ForeignMaster == nil {
+		t.Fatal("expected replication to be configured once the database exists")
+	}
+
+	now := metav1.Now()
+	rc.MarklogicReplication.DeletionTimestamp = &now
+	if res := rc.ReconcileReplication(); !res.Completed() {
+		t.Fatal("expected the finalizer to complete the reconcile")
+	}
+	if len(source.databases["orders"].ForeignReplicas) != 0 || target.databases["orders-dr"].ForeignMaster != nil {
+		t.Fatalf("expected replication to be removed, got source=%+v target=%+v", source.databases["orders"], target.databases["orders-dr"])
+	}
+	if controllerutil.ContainsFinalizer(rc.MarklogicReplication, replicationCleanupFinalizer) {
+		t.Fatal("expected the finalizer to be removed")
+	}
+}
@@ -0,0 +1,219 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	restoreReasonBackupNotFound   = "BackupNotFound"
+	restoreReasonBackupInProgress = "BackupInProgress"
+)
+
+// ReconcileRestore runs the restore described by a MarklogicRestore once and follows its
+// Management API jobs until every database is restored or has failed.
+func (rc *RestoreContext) ReconcileRestore() result.ReconcileResult {
+	return rc.reconcileRestore(time.Now())
+}
+
+func (rc *RestoreContext) reconcileRestore(now time.Time) result.ReconcileResult {
+	cr := rc.MarklogicRestore
+	if cr.Status.Phase == marklogicv1.RestorePhaseCompleted || cr.Status.Phase == marklogicv1.RestorePhaseFailed {
+		return result.Done()
+	}
+	patchClient := client.MergeFrom(cr.DeepCopy())
+	status := cr.Status.DeepCopy()
+
+	requeueSecs, err := rc.advanceRestore(status, now)
+	if err != nil {
+		return result.Error(err)
+	}
+	if !reflect.DeepEqual(*status, cr.Status) {
+		cr.Status = *status
+		if err := rc.Client.Status().Patch(rc.Ctx, cr, patchClient); err != nil {
+			rc.ReqLogger.Error(err, "Failed to update MarklogicRestore status")
+			return result.Error(err)
+		}
+	}
+	if requeueSecs > 0 {
+		return result.RequeueSoon(requeueSecs)
+	}
+	return result.Done()
+}
+
+func (rc *RestoreContext) advanceRestore(status *marklogicv1.MarklogicRestoreStatus, now time.Time) (int, error) {
+	cr := rc.MarklogicRestore
+	if status.Phase == "" {
+		status.Phase = marklogicv1.RestorePhasePending
+	}
+
+	cluster := &marklogicv1.MarklogicCluster{}
+	if err := rc.Client.Get(rc.Ctx, types.NamespacedName{Name: cr.Spec.ClusterRef.Name, Namespace: cr.Namespace}, cluster); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return 0, err
+		}
+		setBackupCondition(&status.Conditions, cr.Generation, metav1.ConditionFalse, backupReasonClusterNotFound,
+			fmt.Sprintf("MarklogicCluster %s not found", cr.Spec.ClusterRef.Name))
+		return backupRetrySeconds, nil
+	}
+
+	if len(status.Databases) == 0 {
+		databases, reason, message, err := rc.restoreTargets()
+		if err != nil {
+			return 0, err
+		}
+		if reason != "" {
+			setBackupCondition(&status.Conditions, cr.Generation, metav1.ConditionFalse, reason, message)
+			return backupRetrySeconds, nil
+		}
+		started := metav1.NewTime(now)
+		status.StartTime = &started
+		status.Databases = databases
+		status.Phase = marklogicv1.RestorePhaseRunning
+		rc.Recorder.Eventf(cr, corev1.EventTypeNormal, "RestoreStarted", "Restoring %s", restoreDatabaseNames(databases))
+	}
+
+	done, err := rc.pollRestore(cluster, status.Databases)
+	if err != nil {
+		setBackupCondition(&status.Conditions, cr.Generation, metav1.ConditionFalse, backupReasonClusterUnavailable, err.Error())
+		return backupRetrySeconds, nil
+	}
+	if !done {
+		setBackupCondition(&status.Conditions, cr.Generation, metav1.ConditionTrue, backupReasonReady, "restore in progress")
+		return backupPollSeconds, nil
+	}
+
+	completed := metav1.NewTime(now)
+	status.CompletionTime = &completed
+	var failed []string
+	for _, db := range status.Databases {
+		if db.State != marklogicv1.DatabaseJobStateCompleted {
+			failed = append(failed, fmt.Sprintf("%s: %s", db.Name, db.Message))
+		}
+	}
+	if len(failed) > 0 {
+		status.Phase = marklogicv1.RestorePhaseFailed
+		status.Message = strings.Join(failed, "; ")
+		rc.Recorder.Eventf(cr, corev1.EventTypeWarning, "RestoreFailed", "Restore failed for %s", status.Message)
+	} else {
+		status.Phase = marklogicv1.RestorePhaseCompleted
+		status.Message = fmt.Sprintf("restored %d database(s)", len(status.Databases))
+		rc.Recorder.Eventf(cr, corev1.EventTypeNormal, "RestoreCompleted", "Restored %s", restoreDatabaseNames(status.Databases))
+	}
+	setBackupCondition(&status.Conditions, cr.Generation, metav1.ConditionTrue, backupReasonReady, status.Message)
+	return 0, nil
+}
+
+// restoreTargets resolves the databases to restore and their backup directories. A
+// non-empty reason means the restore has to wait.
+func (rc *RestoreContext) restoreTargets() ([]marklogicv1.DatabaseJobStatus, string, string, error) {
+	cr := rc.MarklogicRestore
+	databases := cr.Spec.Databases
+	directory := func(database string) string { return path.Join(cr.Spec.Path, database) }
+
+	if cr.Spec.BackupRef != nil {
+		backup := &marklogicv1.MarklogicBackup{}
+		if err := rc.Client.Get(rc.Ctx, types.NamespacedName{Name: cr.Spec.BackupRef.Name, Namespace: cr.Namespace}, backup); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, restoreReasonBackupNotFound, fmt.Sprintf("MarklogicBackup %s not found", cr.Spec.BackupRef.Name), nil
+			}
+			return nil, "", "", err
+		}
+		if backup.Status.CurrentRun != nil {
+			return nil, restoreReasonBackupInProgress, fmt.Sprintf("MarklogicBackup %s has a run in progress", backup.Name), nil
+		}
+		if len(databases) == 0 {
+			databases = backup.Spec.Databases
+		}
+		directory = func(database string) string {
+			return backupDirectory(backup.Spec.Storage.Path, backup.Name, database)
+		}
+	}
+
+	targets := make([]marklogicv1.DatabaseJobStatus, 0, len(databases))
+	for _, database := range databases {
+		targets = append(targets, marklogicv1.DatabaseJobStatus{
+			Name:      database,
+			Directory: directory(database),
+			State:     marklogicv1.DatabaseJobStatePending,
+		})
+	}
+	return targets, "", "", nil
+}
+
+// pollRestore starts pending restore jobs and refreshes running ones, reporting whether
+// every job has finished.
+func (rc *RestoreContext) pollRestore(cluster *marklogicv1.MarklogicCluster, databases []marklogicv1.DatabaseJobStatus) (bool, error) {
+	mc, err := managementClientForCluster(rc.Ctx, rc.Client, cluster)
+	if err != nil {
+		return false, err
+	}
+	spec := rc.MarklogicRestore.Spec
+	opts := mlmanage.RestoreOptions{IncludeReplicas: spec.IncludeReplicas == nil || *spec.IncludeReplicas}
+	if spec.RestoreToTime != nil {
+		restoreTo := spec.RestoreToTime.Time
+		opts.RestoreToTime = &restoreTo
+	}
+	done := true
+	for i := range databases {
+		db := &databases[i]
+		switch db.State {
+		case marklogicv1.DatabaseJobStatePending:
+			opts.BackupDir = db.Directory
+			job, err := mc.RestoreDatabase(rc.Ctx, db.Name, opts)
+			if err != nil {
+				db.State = marklogicv1.DatabaseJobStateFailed
+				db.Message = err.Error()
+				continue
+			}
+			db.JobID = job.ID
+			db.HostName = job.HostName
+			db.State = marklogicv1.DatabaseJobStateRunning
+			done = false
+		case marklogicv1.DatabaseJobStateRunning:
+			jobStatus, err := mc.GetRestoreStatus(rc.Ctx, db.Name, mlmanage.DatabaseJob{ID: db.JobID, HostName: db.HostName})
+			if err != nil {
+				db.Message = err.Error()
+				done = false
+				continue
+			}
+			switch jobStatus.State {
+			case mlmanage.JobStateCompleted:
+				db.State = marklogicv1.DatabaseJobStateCompleted
+				db.Message = ""
+			case mlmanage.JobStateFailed:
+				db.State = marklogicv1.DatabaseJobStateFailed
+				db.Message = jobStatus.Message
+				if db.Message == "" {
+					db.Message = "restore job failed"
+				}
+			default:
+				db.Message = jobStatus.Message
+				done = false
+			}
+		}
+	}
+	return done, nil
+}
+
+func restoreDatabaseNames(databases []marklogicv1.DatabaseJobStatus) string {
+	names := make([]string, 0, len(databases))
+	for _, db := range databases {
+		names = append(names, db.Name)
+	}
+	return strings.Join(names, ", ")
+}
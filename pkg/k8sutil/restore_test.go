@@ -0,0 +1,73 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestReconcileRestoreFromBackupRef(t *testing.T) {
+	backup := &marklogicv1.MarklogicBackup{
+		ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "default"},
+		Spec: marklogicv1.MarklogicBackupSpec{
+			ClusterRef: corev1.LocalObjectReference{Name: "ml"},
+			Databases:  []string{"Documents", "Security"},
+			Storage:    marklogicv1.BackupStorage{Path: "/backups"},
+		},
+	}
+	restoreTo := metav1.NewTime(time.Date(2026, 1, 9, 0, 0, 0, 0, time.UTC))
+	restore := &marklogicv1.MarklogicRestore{
+		ObjectMeta: metav1.ObjectMeta{Name: "restore", Namespace: "default"},
+		Spec: marklogicv1.MarklogicRestoreSpec{
+			ClusterRef:    corev1.LocalObjectReference{Name: "ml"},
+			BackupRef:     &corev1.LocalObjectReference{Name: "nightly"},
+			Databases:     []string{"Documents"},
+			RestoreToTime: &restoreTo,
+		},
+	}
+	fakeClient := newBackupTestClient(t, backup, restore)
+
+	state := mlmanage.JobStateInProgress
+	var gotOpts mlmanage.RestoreOptions
+	useStubClusterManagementClient(t, managementStub{BackupClient: &stubBackupClient{
+		restoreFn: func(database string, opts mlmanage.RestoreOptions) (mlmanage.DatabaseJob, error) {
+			if database != "Documents" {
+				t.Errorf("unexpected database %s", database)
+			}
+			gotOpts = opts
+			return mlmanage.DatabaseJob{ID: "7", HostName: "dnode-0"}, nil
+		},
+		restoreStatusFn: func(database string, job mlmanage.DatabaseJob) (mlmanage.JobStatus, error) {
+			return mlmanage.JobStatus{State: state}, nil
+		},
+	}})
+
+	rc := &RestoreContext{Ctx: context.Background(), Client: fakeClient, MarklogicRestore: restore, Recorder: record.NewFakeRecorder(10)}
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	rc.reconcileRestore(now)
+	if gotOpts.BackupDir != "/backups/nightly/Documents" || !gotOpts.IncludeReplicas {
+		t.Fatalf("unexpected restore options %+v", gotOpts)
+	}
+	if gotOpts.RestoreToTime == nil || !gotOpts.RestoreToTime.Equal(restoreTo.Time) {
+		t.Fatalf("expected restore-to-time to be passed through, got %v", gotOpts.RestoreToTime)
+	}
+	if restore.Status.Phase != marklogicv1.RestorePhaseRunning {
+		t.Fatalf("expected a running restore, got %s", restore.Status.Phase)
+	}
+
+	state = mlmanage.JobStateCompleted
+	if res := rc.reconcileRestore(now.Add(time.Minute)); !res.Completed() {
+		t.Fatal("expected the restore reconcile to finish")
+	}
+	if restore.Status.Phase != marklogicv1.RestorePhaseCompleted || restore.Status.CompletionTime == nil {
+		t.Fatalf("expected a completed restore, got %+v", restore.Status)
+	}
+}
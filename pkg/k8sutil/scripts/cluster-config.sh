@@ -16,6 +16,11 @@ if [[ "${MARKLOGIC_DYNAMIC_HOST}" == "true" ]]; then
     exit 0
 fi
 
+if [[ "${MARKLOGIC_JOIN_MODE}" == "operator" ]]; then
+    echo "$(date +"%Y-%m-%d %T.%3N") [cluster-config] Info: Join mode operator. The operator initializes and joins this host."
+    exit 0
+fi
+
 # HTTP_PROTOCOL could be http or https 
 HTTP_PROTOCOL="http"
 HTTPS_OPTION=""
@@ -101,6 +106,15 @@ if [[ -z "${MARKLOGIC_ADMIN_USERNAME}" ]] || [[ -z "${MARKLOGIC_ADMIN_PASSWORD}"
     error "MARKLOGIC_ADMIN_USERNAME and MARKLOGIC_ADMIN_PASSWORD must be set." exit
 fi
 
+# license and wallet password mounted as files take precedence over environment variables
+if [[ -f /run/secrets/ml-license/license-key ]] && [[ -f /run/secrets/ml-license/licensee ]]; then
+    LICENSE_KEY="$(< /run/secrets/ml-license/license-key)"
+    LICENSEE="$(< /run/secrets/ml-license/licensee)"
+fi
+if [[ -z "${MARKLOGIC_WALLET_PASSWORD}" ]] && [[ -s /run/secrets/ml-secrets/wallet-password ]]; then
+    MARKLOGIC_WALLET_PASSWORD="$(< /run/secrets/ml-secrets/wallet-password)"
+fi
+
 # generate JSON payload conditionally with license details.
 if [[ -z "${LICENSE_KEY}" ]] || [[ -z "${LICENSEE}" ]]; then
     LICENSE_PAYLOAD="{}"
@@ -22,7 +22,9 @@ if [[ "$certType" == "named" ]]; then
     for cert_path in $cert_paths; do
     cert_cn=$(openssl x509 -noout -subject -nameopt multiline -in $cert_path | grep 'commonName' | sed 's/.*= *//')
     log "Info: [copy-certs] FQDN for the certificate: $cert_cn"
-    if [[ "$host_FQDN" == "$cert_cn" ]]; then
+    # certificates issued by cert-manager cover every host of the group in their SANs
+    cert_sans=$(openssl x509 -noout -ext subjectAltName -in $cert_path 2>/dev/null | tr ',' '\n' | sed -n 's/^ *DNS://p')
+    if [[ "$host_FQDN" == "$cert_cn" ]] || grep -qxF "$host_FQDN" <<< "$cert_sans"; then
         log "Info: [copy-certs] found certificate for the server"
         foundMatchingCert="true"
         cp $cert_path /run/secrets/marklogic-certs/tls.crt
@@ -25,6 +25,10 @@ func (cc *ClusterContext) ReconcileSecret() result.ReconcileResult {
 		logger.Info("MarkLogic Secret is provided, skipping the creation")
 		return result.Continue()
 	}
+	if mlc.Spec.Auth != nil && mlc.Spec.Auth.CSI != nil {
+		logger.Info("MarkLogic admin credentials are mounted from the Secrets Store CSI driver, skipping the Secret creation")
+		return result.Continue()
+	}
 
 	logger.Info("Reconciling MarkLogic Secret")
 	labels := cc.GetClusterLabels(mlc.ObjectMeta.Name)
@@ -0,0 +1,81 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"sync"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/metrics"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// defaultServerMetricsInterval applies when metrics.intervalSeconds is not set.
+const defaultServerMetricsInterval = 30 * time.Second
+
+// serverMetricsRead records when the metrics of a group were last read, so that reconciles
+// triggered by other changes do not read the Management API more often than the interval.
+var serverMetricsRead sync.Map
+
+func serverMetricsEnabled(cr *marklogicv1.MarklogicGroup) bool {
+	return cr.Spec.Metrics != nil && cr.Spec.Metrics.Enabled
+}
+
+func serverMetricsInterval(cr *marklogicv1.MarklogicGroup) time.Duration {
+	if cr.Spec.Metrics.IntervalSeconds <= 0 {
+		return defaultServerMetricsInterval
+	}
+	return time.Duration(cr.Spec.Metrics.IntervalSeconds) * time.Second
+}
+
+// requeueForServerMetrics polls groups with metrics enabled; nothing else triggers a reconcile
+// when the metrics change.
+func requeueForServerMetrics(cr *marklogicv1.MarklogicGroup, res reconcile.Result, err error) (reconcile.Result, error) {
+	if err != nil || !serverMetricsEnabled(cr) {
+		return res, err
+	}
+	if interval := serverMetricsInterval(cr); res.RequeueAfter == 0 || interval < res.RequeueAfter {
+		res.RequeueAfter = interval
+	}
+	return res, err
+}
+
+// ReconcileServerMetrics reads the status summaries of the hosts, App Servers and forests of
+// the group through the Management API and publishes them on the operator's metrics
+// endpoint. Failures are logged and keep the last values; they never block the reconcile.
+func (oc *OperatorContext) ReconcileServerMetrics() result.ReconcileResult {
+	cr := oc.MarklogicGroup
+	logger := oc.ReqLogger
+	key := cr.Namespace + "/" + cr.Name
+	if !serverMetricsEnabled(cr) {
+		if _, read := serverMetricsRead.LoadAndDelete(key); read {
+			metrics.ForgetServerMetrics(cr.Namespace, cr.Name)
+		}
+		return result.Continue()
+	}
+	if last, read := serverMetricsRead.Load(key); read && time.Since(last.(time.Time)) < serverMetricsInterval(cr) {
+		return result.Continue()
+	}
+	serverMetricsRead.Store(key, time.Now())
+
+	mc, err := oc.groupManagementClient()
+	if err != nil {
+		logger.Info("Cannot read MarkLogic metrics", "error", err.Error())
+		return result.Continue()
+	}
+	serverMetrics, err := mc.GetServerMetrics(oc.Ctx, resolvedMarkLogicGroupName(cr))
+	if err != nil {
+		logger.Info("Cannot read all MarkLogic metrics", "error", err.Error())
+	}
+	if len(serverMetrics) == 0 {
+		return result.Continue()
+	}
+	values := make(map[string]float64, len(serverMetrics))
+	for _, metric := range serverMetrics {
+		values[metrics.ServerMetricName(metric.Resource, metric.Name)] = metric.Value
+	}
+	metrics.SetServerMetrics(cr.Namespace, cr.Name, values)
+	return result.Continue()
+}
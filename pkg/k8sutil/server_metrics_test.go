@@ -0,0 +1,71 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"testing"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func serverMetricValue(t *testing.T, name string) (float64, bool) {
+	t.Helper()
+	families, err := crmetrics.Registry.Gather()
+	if err != nil {
+		t.Fatalf("failed to gather metrics: %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "group" && label.GetValue() == "enode" {
+					return metric.GetGauge().GetValue(), true
+				}
+			}
+		}
+	}
+	return 0, false
+}
+
+func TestReconcileServerMetricsPublishesStatusSummaries(t *testing.T) {
+	stub := &stubMetricsClient{serverMetrics: map[string][]mlmanage.ServerMetric{
+		"E-Nodes": {
+			{Resource: "servers", Name: "request-rate", Value: 40},
+			{Resource: "forests", Name: "merge-count", Value: 2},
+		},
+	}}
+	useStubClusterManagementClient(t, managementStub{MetricsClient: stub})
+	oc := newAutoscalingTestContext(t, 1, nil)
+	oc.MarklogicGroup.Spec.Metrics = &marklogicv1.ServerMetrics{Enabled: true, IntervalSeconds: 60}
+	t.Cleanup(func() { serverMetricsRead.Delete("default/enode") })
+
+	if res := oc.ReconcileServerMetrics(); res.Completed() {
+		t.Fatalf("expected reconcile to continue, got %+v", res)
+	}
+	if value, ok := serverMetricValue(t, "marklogic_servers_request_rate"); !ok || value != 40 {
+		t.Fatalf("expected the request rate of the group, got %v %v", value, ok)
+	}
+
+	// Reconciles within the interval keep the last values.
+	stub.serverMetrics["E-Nodes"][0].Value = 80
+	oc.ReconcileServerMetrics()
+	if value, _ := serverMetricValue(t, "marklogic_servers_request_rate"); value != 40 {
+		t.Fatalf("expected the metrics not to be read again within the interval, got %v", value)
+	}
+	res, err := requeueForServerMetrics(oc.MarklogicGroup, reconcile.Result{RequeueAfter: 5 * time.Minute}, nil)
+	if err != nil || res.RequeueAfter != time.Minute {
+		t.Fatalf("expected the group to be polled every interval, got %+v %v", res, err)
+	}
+
+	oc.MarklogicGroup.Spec.Metrics.Enabled = false
+	oc.ReconcileServerMetrics()
+	if _, ok := serverMetricValue(t, "marklogic_forests_merge_count"); ok {
+		t.Fatal("expected the metrics to be dropped once disabled")
+	}
+}
@@ -29,7 +29,7 @@ func generateServiceParams(cr *marklogicv1.MarklogicGroup) serviceParameters {
 		StsName:     cr.Spec.Name,
 		IsDynamic:   cr.Spec.IsDynamic,
 		Type:        cr.Spec.Service.Type,
-		Ports:       cr.Spec.Service.AdditionalPorts,
+		Ports:       append(append([]corev1.ServicePort{}, cr.Spec.Service.AdditionalPorts...), appServerServicePorts(cr.Spec.AppServers, cr.Spec.Service.AdditionalPorts)...),
 		Annotations: cr.Spec.Service.Annotations,
 	}
 }
@@ -9,12 +9,14 @@ import (
 
 	"github.com/cisco-open/k8s-objectmatcher/patch"
 	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/metrics"
 	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -50,6 +52,7 @@ type containerParameters struct {
 	MountPaths             []corev1.VolumeMount
 	LicenseKey             string
 	Licensee               string
+	LicenseSource          *corev1.VolumeSource
 	BootstrapHost          string
 	LivenessProbe          marklogicv1.ContainerProbe
 	ReadinessProbe         marklogicv1.ContainerProbe
@@ -64,7 +67,9 @@ type containerParameters struct {
 	AdditionalVolumes      *[]corev1.Volume
 	AdditionalVolumeMounts *[]corev1.VolumeMount
 	SecretName             string
+	AdminCredentialsCSI    *corev1.CSIVolumeSource
 	IsDynamic              bool
+	JoinMode               marklogicv1.JoinMode
 }
 
 func (oc *OperatorContext) ReconcileStatefulset() (reconcile.Result, error) {
@@ -108,6 +113,14 @@ func (oc *OperatorContext) ReconcileStatefulset() (reconcile.Result, error) {
 			patch.IgnoreVolumeClaimTemplateTypeMetaAndStatus(),
 			patch.IgnoreField("kind"))
 	}
+	if replicas := decommissionReplicas(cr, currentSts); replicas != nil && *replicas != *statefulSetDef.Spec.Replicas {
+		// Non-dynamic hosts leave the cluster through ReconcileScaleDown before their pod goes away.
+		statefulSetDef.Spec.Replicas = replicas
+		patchDiff, err = patch.DefaultPatchMaker.Calculate(currentSts, statefulSetDef,
+			patch.IgnoreStatusFields(),
+			patch.IgnoreVolumeClaimTemplateTypeMetaAndStatus(),
+			patch.IgnoreField("kind"))
+	}
 	logger.Info("Patch Diff:", "Diff", patchDiff.String())
 	logger.Info("statefulSetDef Spec:", "Spec", statefulSetDef.Spec.Replicas)
 	if err != nil {
@@ -138,8 +151,16 @@ func (oc *OperatorContext) ReconcileStatefulset() (reconcile.Result, error) {
 		logger.Info("Pods in statefulSet: ", "Pods", pods)
 	}
 
+	metrics.SetGroupReplicas(cr.Namespace, cr.Name, currentSts.Status.Replicas, currentSts.Status.ReadyReplicas)
 	patchClient := client.MergeFrom(oc.MarklogicGroup.DeepCopy())
 	updated := false
+	selector := labels.SelectorFromSet(getSelectorLabelsByComponent(cr.Spec.Name, cr.Spec.IsDynamic)).String()
+	if cr.Status.Replicas != currentSts.Status.Replicas || cr.Status.ReadyReplicas != currentSts.Status.ReadyReplicas || cr.Status.Selector != selector {
+		cr.Status.Replicas = currentSts.Status.Replicas
+		cr.Status.ReadyReplicas = currentSts.Status.ReadyReplicas
+		cr.Status.Selector = selector
+		updated = true
+	}
 	if currentSts.Status.ReadyReplicas == 0 || currentSts.Status.ReadyReplicas != currentSts.Status.Replicas {
 		logger.Info("MarkLogic statefulSet is not ready, setting condition and requeue")
 		condition := metav1.Condition{
@@ -148,7 +169,7 @@ func (oc *OperatorContext) ReconcileStatefulset() (reconcile.Result, error) {
 			Reason:  "MarkLogicGroupStatefulSetNotReady",
 			Message: "MarkLogicGroup statefulSet is not ready",
 		}
-		updated = oc.setCondition(&condition)
+		updated = oc.setCondition(&condition) || updated
 		if updated {
 			err := oc.Client.Status().Patch(oc.Ctx, oc.MarklogicGroup, patchClient)
 			if err != nil {
@@ -164,7 +185,7 @@ func (oc *OperatorContext) ReconcileStatefulset() (reconcile.Result, error) {
 			Reason:  "MarkLogicGroupStatefulSetReady",
 			Message: "MarkLogicGroup statefulSet is ready",
 		}
-		updated = oc.setCondition(&condition)
+		updated = oc.setCondition(&condition) || updated
 	}
 	if updated {
 		err := oc.Client.Status().Patch(oc.Ctx, oc.MarklogicGroup, patchClient)
@@ -295,7 +316,7 @@ func generateStatefulSetsDef(stsMeta metav1.ObjectMeta, params statefulSetParame
 				MountPath: "/tmp/helm-scripts/",
 			},
 		}
-		if containerParams.Tls.CertSecretNames != nil {
+		if containerParams.Tls.CertSecretNames != nil || certManagerIssued(containerParams.Tls) {
 			copyCertsVM = append(copyCertsVM, corev1.VolumeMount{
 				Name:      "ca-cert-secret",
 				MountPath: "/tmp/ca-cert-secret/",
@@ -444,6 +465,7 @@ func generateContainerParams(cr *marklogicv1.MarklogicGroup) containerParameters
 		AdditionalVolumeMounts: cr.Spec.AdditionalVolumeMounts,
 		Persistence:            cr.Spec.Persistence,
 		IsDynamic:              cr.Spec.IsDynamic,
+		JoinMode:               cr.Spec.JoinMode,
 	}
 
 	// Set SecretName with fallback to default if not specified
@@ -453,9 +475,13 @@ func generateContainerParams(cr *marklogicv1.MarklogicGroup) containerParameters
 		containerParams.SecretName = cr.ObjectMeta.Name + "-admin"
 	}
 
+	containerParams.AdminCredentialsCSI = cr.Spec.AdminCredentialsCSI
 	if cr.Spec.License != nil {
-		containerParams.LicenseKey = cr.Spec.License.Key
-		containerParams.Licensee = cr.Spec.License.Licensee
+		containerParams.LicenseSource = licenseVolumeSource(cr.Spec.License)
+		if containerParams.LicenseSource == nil {
+			containerParams.LicenseKey = cr.Spec.License.Key
+			containerParams.Licensee = cr.Spec.License.Licensee
+		}
 	}
 	if cr.Spec.HugePages.Enabled {
 		containerParams.HugePages = cr.Spec.HugePages
@@ -494,6 +520,58 @@ func getLifeCycleWithoutPostStart() *corev1.Lifecycle {
 	}
 }
 
+// adminCredentialsVolumeSource mounts the username, password and wallet-password files from
+// the admin secret, or from the Secrets Store CSI driver when the group uses it.
+func adminCredentialsVolumeSource(containerParams containerParameters) corev1.VolumeSource {
+	if containerParams.AdminCredentialsCSI != nil {
+		return corev1.VolumeSource{CSI: readOnlyCSIVolumeSource(containerParams.AdminCredentialsCSI)}
+	}
+	return corev1.VolumeSource{
+		Secret: &corev1.SecretVolumeSource{
+			SecretName: containerParams.SecretName,
+		},
+	}
+}
+
+// licenseVolumeSource mounts the license-key and licensee files read by cluster-config.sh,
+// so the license stays out of the pod environment. It returns nil for an inline license.
+func licenseVolumeSource(license *marklogicv1.License) *corev1.VolumeSource {
+	if license.CSI != nil {
+		return &corev1.VolumeSource{CSI: readOnlyCSIVolumeSource(license.CSI)}
+	}
+	if license.SecretRef == nil {
+		return nil
+	}
+	keyKey := license.SecretRef.LicenseKeyKey
+	if keyKey == "" {
+		keyKey = "license-key"
+	}
+	licenseeKey := license.SecretRef.LicenseeKey
+	if licenseeKey == "" {
+		licenseeKey = "licensee"
+	}
+	return &corev1.VolumeSource{
+		Secret: &corev1.SecretVolumeSource{
+			SecretName: license.SecretRef.Name,
+			Items: []corev1.KeyToPath{
+				{Key: keyKey, Path: "license-key"},
+				{Key: licenseeKey, Path: "licensee"},
+			},
+		},
+	}
+}
+
+// readOnlyCSIVolumeSource returns the CSI source with readOnly set, which the Secrets Store
+// CSI driver requires.
+func readOnlyCSIVolumeSource(source *corev1.CSIVolumeSource) *corev1.CSIVolumeSource {
+	csi := source.DeepCopy()
+	if csi.ReadOnly == nil {
+		readOnly := true
+		csi.ReadOnly = &readOnly
+	}
+	return csi
+}
+
 func generateVolumes(stsName string, containerParams containerParameters) []corev1.Volume {
 	volumes := []corev1.Volume{}
 	volumes = append(volumes, corev1.Volume{
@@ -507,13 +585,15 @@ func generateVolumes(stsName string, containerParams containerParameters) []core
 			},
 		},
 	}, corev1.Volume{
-		Name: "mladmin-secrets",
-		VolumeSource: corev1.VolumeSource{
-			Secret: &corev1.SecretVolumeSource{
-				SecretName: containerParams.SecretName,
-			},
-		},
+		Name:         "mladmin-secrets",
+		VolumeSource: adminCredentialsVolumeSource(containerParams),
 	})
+	if containerParams.LicenseSource != nil {
+		volumes = append(volumes, corev1.Volume{
+			Name:         "license-secrets",
+			VolumeSource: *containerParams.LicenseSource,
+		})
+	}
 	if containerParams.HugePages != nil && containerParams.HugePages.Enabled {
 		volumes = append(volumes, corev1.Volume{
 			Name: "huge-pages",
@@ -585,11 +665,42 @@ func generateVolumes(stsName string, containerParams containerParameters) []core
 				},
 			})
 		}
+		if certManagerIssued(containerParams.Tls) {
+			// A single cert-manager secret covers every pod of the group, laid out the way
+			// copy-certs.sh expects for certSecretNames and caSecretName.
+			volumes = append(volumes, generateIssuedCertVolumes(groupCertificateName(containerParams.Name))...)
+		}
 	}
 
 	return volumes
 }
 
+func generateIssuedCertVolumes(secretName string) []corev1.Volume {
+	return []corev1.Volume{
+		{
+			Name: "ca-cert-secret",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: secretName,
+					Items:      []corev1.KeyToPath{{Key: "ca.crt", Path: "cacert.pem"}},
+				},
+			},
+		},
+		{
+			Name: "server-cert-secrets",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: secretName,
+					Items: []corev1.KeyToPath{
+						{Key: "tls.crt", Path: "tls_0.crt"},
+						{Key: "tls.key", Path: "tls_0.key"},
+					},
+				},
+			},
+		},
+	}
+}
+
 func generatePVCTemplate(persistence *marklogicv1.Persistence) corev1.PersistentVolumeClaim {
 	pvcTemplate := corev1.PersistentVolumeClaim{}
 	pvcTemplate.CreationTimestamp = metav1.Time{}
@@ -686,6 +797,13 @@ func getEnvironmentVariables(containerParams containerParameters) []corev1.EnvVa
 		})
 	}
 
+	if containerParams.JoinMode == marklogicv1.JoinModeOperator {
+		envVars = append(envVars, corev1.EnvVar{
+			Name:  "MARKLOGIC_JOIN_MODE",
+			Value: string(marklogicv1.JoinModeOperator),
+		})
+	}
+
 	if containerParams.Tls != nil && containerParams.Tls.EnableOnDefaultAppServers {
 		envVars = append(envVars, corev1.EnvVar{
 			Name:  "MARKLOGIC_JOIN_TLS_ENABLED",
@@ -738,6 +856,13 @@ func getVolumeMount(containerParams containerParameters) []corev1.VolumeMount {
 			ReadOnly:  true,
 		},
 	)
+	if containerParams.LicenseSource != nil {
+		VolumeMounts = append(VolumeMounts, corev1.VolumeMount{
+			Name:      "license-secrets",
+			MountPath: "/run/secrets/ml-license",
+			ReadOnly:  true,
+		})
+	}
 	if containerParams.HugePages != nil && containerParams.HugePages.Enabled {
 		VolumeMounts = append(VolumeMounts,
 			corev1.VolumeMount{
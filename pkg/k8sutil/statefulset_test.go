@@ -0,0 +1,62 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCredentialsAreMountedAsFiles(t *testing.T) {
+	group := &marklogicv1.MarklogicGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "dnode", Namespace: "ml"},
+		Spec: marklogicv1.MarklogicGroupSpec{
+			Name:          "dnode",
+			SecretName:    "ml-admin",
+			HugePages:     &marklogicv1.HugePages{},
+			LogCollection: &marklogicv1.LogCollection{},
+			AdminCredentialsCSI: &corev1.CSIVolumeSource{
+				Driver:           "secrets-store.csi.k8s.io",
+				VolumeAttributes: map[string]string{"secretProviderClass": "ml-admin"},
+			},
+			License: &marklogicv1.License{SecretRef: &marklogicv1.LicenseSecretRef{Name: "ml-license", LicenseKeyKey: "key"}},
+		},
+	}
+	params := generateContainerParams(group)
+	for _, env := range getEnvironmentVariables(params) {
+		if env.Name == "LICENSE_KEY" || env.Name == "LICENSEE" {
+			t.Fatalf("expected the license to stay out of the environment, got %s", env.Name)
+		}
+	}
+	volumes := map[string]corev1.Volume{}
+	for _, v := range generateVolumes("dnode", params) {
+		volumes[v.Name] = v
+	}
+	admin := volumes["mladmin-secrets"]
+	if admin.CSI == nil || admin.Secret != nil || admin.CSI.ReadOnly == nil || !*admin.CSI.ReadOnly {
+		t.Fatalf("expected the admin credentials from a read-only CSI volume, got %+v", admin.VolumeSource)
+	}
+	license := volumes["license-secrets"]
+	if license.Secret == nil || license.Secret.SecretName != "ml-license" ||
+		license.Secret.Items[0] != (corev1.KeyToPath{Key: "key", Path: "license-key"}) ||
+		license.Secret.Items[1] != (corev1.KeyToPath{Key: "licensee", Path: "licensee"}) {
+		t.Fatalf("expected the license secret keys mapped to files, got %+v", license.VolumeSource)
+	}
+	mounted := false
+	for _, mount := range getVolumeMount(params) {
+		if mount.Name == "license-secrets" && mount.MountPath == "/run/secrets/ml-license" && mount.ReadOnly {
+			mounted = true
+		}
+	}
+	if !mounted {
+		t.Fatalf("expected the license to be mounted at /run/secrets/ml-license")
+	}
+
+	group.Spec.License = &marklogicv1.License{Key: "inline", Licensee: "Example"}
+	if params := generateContainerParams(group); params.LicenseSource != nil || params.LicenseKey != "inline" {
+		t.Fatalf("expected an inline license to keep using the environment, got %+v", params.LicenseSource)
+	}
+}
@@ -0,0 +1,353 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	upgradeRequeueSeconds = 10
+
+	upgradeReasonInProgress = "UpgradeInProgress"
+	upgradeReasonCompleted  = "UpgradeCompleted"
+)
+
+// reconcileMarkLogicUpgrade drives an image change through the groups one at a time,
+// bootstrap group first. MarkLogic upgrades its configuration and Security database
+// when the bootstrap host first starts the new binaries, so every other group waits
+// until all hosts of the previous group report the new version via ListHostsStatus.
+//
+// desiredImages maps each group in the spec to the image it should end up on. The
+// returned map holds the image a group must keep for this pass because it is queued
+// behind the group currently upgrading. inProgress is true until the upgrade completes.
+func (cc *ClusterContext) reconcileMarkLogicUpgrade(desiredImages map[string]string) (holds map[string]string, inProgress bool, res result.ReconcileResult) {
+	cr := cc.MarklogicCluster
+	holds = map[string]string{}
+
+	current := map[string]*marklogicv1.MarklogicGroup{}
+	for _, spec := range cr.Spec.MarkLogicGroups {
+		if spec == nil {
+			continue
+		}
+		group := &marklogicv1.MarklogicGroup{}
+		if err := cc.Client.Get(cc.Ctx, types.NamespacedName{Name: spec.Name, Namespace: cr.Namespace}, group); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, false, result.Error(err)
+		}
+		current[spec.Name] = group
+	}
+
+	patchClient := client.MergeFrom(cr.DeepCopy())
+	original := cr.Status.DeepCopy()
+	upgrade := cr.Status.Upgrade
+
+	if upgrade == nil || upgrade.Phase != marklogicv1.UpgradePhaseInProgress || !upgradePlanMatches(upgrade, desiredImages, current) {
+		plan := newUpgradePlan(cr, desiredImages, current)
+		if plan == nil {
+			if upgrade != nil && upgrade.Phase == marklogicv1.UpgradePhaseInProgress {
+				// The image change was reverted before any group needed to move.
+				completeUpgrade(cr, "image change no longer requires an upgrade")
+				return holds, false, cc.patchUpgradeStatus(patchClient, original)
+			}
+			return holds, false, result.Continue()
+		}
+		cr.Status.Upgrade = plan
+		upgrade = plan
+		cc.Recorder.Eventf(cr, "Normal", "UpgradeStarted", "Starting MarkLogic upgrade of %d group(s): %s", len(plan.Groups), upgradeGroupNames(plan))
+	}
+
+	var active *marklogicv1.UpgradeGroupStatus
+	for i := range upgrade.Groups {
+		entry := &upgrade.Groups[i]
+		if _, inSpec := desiredImages[entry.Name]; !inSpec || entry.State == marklogicv1.UpgradeGroupStateCompleted {
+			continue
+		}
+		if active == nil {
+			active = entry
+			continue
+		}
+		if group, exists := current[entry.Name]; exists {
+			holds[entry.Name] = group.Spec.Image
+		}
+	}
+
+	if active == nil {
+		completeUpgrade(cr, "all groups report the upgraded MarkLogic version")
+		cc.Recorder.Event(cr, "Normal", "UpgradeCompleted", "MarkLogic upgrade completed for all groups")
+		return holds, false, cc.patchUpgradeStatus(patchClient, original)
+	}
+
+	upgrade.CurrentGroup = active.Name
+	switch active.State {
+	case "", marklogicv1.UpgradeGroupStatePending:
+		setUpgradeGroupState(upgrade, active, marklogicv1.UpgradeGroupStateRolling, fmt.Sprintf("rolling pods to %s", active.ToImage))
+		cc.Recorder.Eventf(cr, "Normal", "UpgradeGroupStarted", "Upgrading group %s to %s", active.Name, active.ToImage)
+	case marklogicv1.UpgradeGroupStateRolling:
+		done, message, err := cc.advanceUpgradeRollout(active)
+		if err != nil {
+			return nil, true, result.Error(err)
+		}
+		if done {
+			setUpgradeGroupState(upgrade, active, marklogicv1.UpgradeGroupStateVerifying, "all pods run the new image; verifying host versions")
+		} else {
+			active.Message = message
+		}
+	case marklogicv1.UpgradeGroupStateVerifying:
+		done, message := cc.verifyUpgradedHosts(active)
+		if done {
+			setUpgradeGroupState(upgrade, active, marklogicv1.UpgradeGroupStateCompleted, message)
+			cc.Recorder.Eventf(cr, "Normal", "UpgradeGroupCompleted", "Group %s upgraded: %s", active.Name, message)
+		} else {
+			active.Message = message
+		}
+	}
+	upgrade.Message = fmt.Sprintf("group %s: %s", active.Name, active.Message)
+	setClusterCondition(&cr.Status, cr.Generation, marklogicv1.ClusterUpdating, metav1.ConditionTrue, upgradeReasonInProgress, upgrade.Message)
+
+	return holds, true, cc.patchUpgradeStatus(patchClient, original)
+}
+
+// newUpgradePlan lists the existing groups whose image differs from the desired image,
+// bootstrap group first and the rest in spec order. It returns nil when nothing changes.
+func newUpgradePlan(cr *marklogicv1.MarklogicCluster, desiredImages map[string]string, current map[string]*marklogicv1.MarklogicGroup) *marklogicv1.UpgradeStatus {
+	ordered := make([]*marklogicv1.MarklogicGroups, 0, len(cr.Spec.MarkLogicGroups))
+	for _, spec := range cr.Spec.MarkLogicGroups {
+		if spec != nil && spec.IsBootstrap {
+			ordered = append(ordered, spec)
+		}
+	}
+	for _, spec := range cr.Spec.MarkLogicGroups {
+		if spec != nil && !spec.IsBootstrap {
+			ordered = append(ordered, spec)
+		}
+	}
+
+	plan := &marklogicv1.UpgradeStatus{Phase: marklogicv1.UpgradePhaseInProgress}
+	for _, spec := range ordered {
+		group, exists := current[spec.Name]
+		if !exists || group.Spec.Image == desiredImages[spec.Name] {
+			continue
+		}
+		plan.Groups = append(plan.Groups, marklogicv1.UpgradeGroupStatus{
+			Name:          spec.Name,
+			FromImage:     group.Spec.Image,
+			ToImage:       desiredImages[spec.Name],
+			TargetVersion: upgradeTargetVersion(desiredImages[spec.Name]),
+			State:         marklogicv1.UpgradeGroupStatePending,
+		})
+	}
+	if len(plan.Groups) == 0 {
+		return nil
+	}
+	now := metav1.Now()
+	plan.StartedTime = &now
+	plan.LastTransitionTime = &now
+	return plan
+}
+
+// upgradePlanMatches reports whether the recorded plan still leads to the desired images.
+// A new image set mid-upgrade, or a group drifting outside the plan, starts a new plan.
+func upgradePlanMatches(upgrade *marklogicv1.UpgradeStatus, desiredImages map[string]string, current map[string]*marklogicv1.MarklogicGroup) bool {
+	planned := map[string]bool{}
+	for _, entry := range upgrade.Groups {
+		desired, inSpec := desiredImages[entry.Name]
+		if !inSpec {
+			continue
+		}
+		if entry.ToImage != desired {
+			return false
+		}
+		planned[entry.Name] = true
+	}
+	for name, group := range current {
+		if !planned[name] && group.Spec.Image != desiredImages[name] {
+			return false
+		}
+	}
+	return true
+}
+
+// advanceUpgradeRollout waits for the group's StatefulSet to run the new image on every
+// pod. OnDelete StatefulSets do not roll on their own, so one outdated pod is deleted at
+// a time, highest ordinal first, and only while every other pod is ready.
+func (cc *ClusterContext) advanceUpgradeRollout(entry *marklogicv1.UpgradeGroupStatus) (bool, string, error) {
+	cr := cc.MarklogicCluster
+	sts := &appsv1.StatefulSet{}
+	if err := cc.Client.Get(cc.Ctx, types.NamespacedName{Name: entry.Name, Namespace: cr.Namespace}, sts); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, "waiting for the StatefulSet to exist", nil
+		}
+		return false, "", err
+	}
+	if !statefulSetTemplateHasImage(sts, entry.ToImage) {
+		return false, "waiting for the StatefulSet template to pick up the new image", nil
+	}
+	if sts.Status.ObservedGeneration < sts.Generation || sts.Status.UpdateRevision == "" {
+		return false, "waiting for the StatefulSet controller to observe the new template", nil
+	}
+
+	replicas := int32(1)
+	if sts.Spec.Replicas != nil {
+		replicas = *sts.Spec.Replicas
+	}
+	podList := &corev1.PodList{}
+	selector := map[string]string{}
+	if sts.Spec.Selector != nil {
+		selector = sts.Spec.Selector.MatchLabels
+	}
+	if err := cc.Client.List(cc.Ctx, podList, client.InNamespace(cr.Namespace), client.MatchingLabels(selector)); err != nil {
+		return false, "", err
+	}
+
+	var outdated []corev1.Pod
+	ready := int32(0)
+	for _, pod := range podList.Items {
+		if pod.Labels[appsv1.ControllerRevisionHashLabelKey] != sts.Status.UpdateRevision {
+			outdated = append(outdated, pod)
+		}
+		if pod.DeletionTimestamp == nil && isPodLocallyReady(&pod) {
+			ready++
+		}
+	}
+	if len(outdated) == 0 && ready >= replicas {
+		return true, "", nil
+	}
+
+	if sts.Spec.UpdateStrategy.Type == appsv1.OnDeleteStatefulSetStrategyType && len(outdated) > 0 && ready >= replicas {
+		sort.SliceStable(outdated, func(i, j int) bool {
+			return podOrdinal(outdated[i].Name) > podOrdinal(outdated[j].Name)
+		})
+		pod := &outdated[0]
+		if err := cc.Client.Delete(cc.Ctx, pod); err != nil && !apierrors.IsNotFound(err) {
+			return false, "", err
+		}
+		cc.Recorder.Eventf(cr, "Normal", "UpgradeRestartingPod", "Restarting pod %s to run %s", pod.Name, entry.ToImage)
+		return false, fmt.Sprintf("restarting pod %s (%d pod(s) left on the old image)", pod.Name, len(outdated)), nil
+	}
+	return false, fmt.Sprintf("%d/%d pods updated, %d ready", replicas-int32(len(outdated)), replicas, ready), nil
+}
+
+// verifyUpgradedHosts confirms every MarkLogic host of the group is online and reports
+// the target version. Management API failures are treated as "not yet".
+func (cc *ClusterContext) verifyUpgradedHosts(entry *marklogicv1.UpgradeGroupStatus) (bool, string) {
+	mgmt, err := cc.newClusterManagementClient()
+	if err != nil {
+		return false, fmt.Sprintf("waiting for Management API credentials: %v", err)
+	}
+	hosts, err := mgmt.ListHostsStatus(cc.Ctx)
+	if err != nil {
+		return false, fmt.Sprintf("waiting for Management API host status: %v", err)
+	}
+	matched := 0
+	for _, host := range hosts {
+		if !isGroupPodName(hostnameToPodName(host.Name), entry.Name) {
+			continue
+		}
+		matched++
+		if !host.Online {
+			return false, fmt.Sprintf("waiting for host %s to come online", host.Name)
+		}
+		if entry.TargetVersion != "" && !markLogicVersionMatches(host.Version, entry.TargetVersion) {
+			return false, fmt.Sprintf("host %s reports MarkLogic %q, waiting for %s", host.Name, host.Version, entry.TargetVersion)
+		}
+	}
+	if matched == 0 {
+		return false, "waiting for the group's hosts to appear in the cluster host status"
+	}
+	if entry.TargetVersion == "" {
+		return true, fmt.Sprintf("%d host(s) online", matched)
+	}
+	return true, fmt.Sprintf("%d host(s) report MarkLogic %s", matched, entry.TargetVersion)
+}
+
+func (cc *ClusterContext) patchUpgradeStatus(patchClient client.Patch, original *marklogicv1.MarklogicClusterStatus) result.ReconcileResult {
+	if reflect.DeepEqual(*original, cc.MarklogicCluster.Status) {
+		return result.Continue()
+	}
+	if err := cc.Client.Status().Patch(cc.Ctx, cc.MarklogicCluster, patchClient); err != nil {
+		cc.ReqLogger.Error(err, "Failed to update MarkLogicCluster upgrade status")
+		return result.Error(err)
+	}
+	return result.Continue()
+}
+
+func setUpgradeGroupState(upgrade *marklogicv1.UpgradeStatus, entry *marklogicv1.UpgradeGroupStatus, state marklogicv1.UpgradeGroupState, message string) {
+	now := metav1.Now()
+	entry.State = state
+	entry.Message = message
+	upgrade.LastTransitionTime = &now
+}
+
+func completeUpgrade(cr *marklogicv1.MarklogicCluster, message string) {
+	now := metav1.Now()
+	upgrade := cr.Status.Upgrade
+	upgrade.Phase = marklogicv1.UpgradePhaseCompleted
+	upgrade.CurrentGroup = ""
+	upgrade.Message = message
+	upgrade.CompletionTime = &now
+	upgrade.LastTransitionTime = &now
+	if apimeta.FindStatusCondition(cr.Status.Conditions, string(marklogicv1.ClusterUpdating)) != nil {
+		setClusterCondition(&cr.Status, cr.Generation, marklogicv1.ClusterUpdating, metav1.ConditionFalse, upgradeReasonCompleted, message)
+	}
+}
+
+func upgradeGroupNames(upgrade *marklogicv1.UpgradeStatus) string {
+	names := make([]string, 0, len(upgrade.Groups))
+	for _, entry := range upgrade.Groups {
+		names = append(names, entry.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+func statefulSetTemplateHasImage(sts *appsv1.StatefulSet, image string) bool {
+	for _, container := range sts.Spec.Template.Spec.Containers {
+		if container.Image == image {
+			return true
+		}
+	}
+	return false
+}
+
+// upgradeTargetVersion only returns versions parsed from the tag; tags such as
+// "latest" cannot be checked against the version a host reports.
+func upgradeTargetVersion(image string) string {
+	version := markLogicVersionFromImage(image)
+	if version == "" || version[0] < '0' || version[0] > '9' {
+		return ""
+	}
+	return version
+}
+
+// markLogicVersionMatches compares a host version such as "12.0.3" or "10.0-9.5" with
+// a version parsed from an image tag, which may be less specific.
+func markLogicVersionMatches(hostVersion, target string) bool {
+	host := strings.ReplaceAll(strings.TrimSpace(hostVersion), "-", ".")
+	target = strings.ReplaceAll(strings.TrimSpace(target), "-", ".")
+	return host == target || strings.HasPrefix(host, target+".")
+}
+
+func isGroupPodName(podName, groupName string) bool {
+	suffix, found := strings.CutPrefix(podName, groupName+"-")
+	if !found || suffix == "" {
+		return false
+	}
+	_, err := strconv.Atoi(suffix)
+	return err == nil
+}
@@ -0,0 +1,232 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"context"
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	upgradeTestOldImage = "progressofficial/marklogic-db:11.3.1-ubi9"
+	upgradeTestNewImage = "progressofficial/marklogic-db:12.0.3-ubi9"
+)
+
+func newUpgradeTestContext(t *testing.T, objects ...client.Object) (*ClusterContext, client.Client) {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := marklogicv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add marklogic scheme: %v", err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add core scheme: %v", err)
+	}
+	if err := appsv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add apps scheme: %v", err)
+	}
+
+	cluster := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			ClusterDomain: "cluster.local",
+			Image:         upgradeTestNewImage,
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{
+				{Name: "enode", Replicas: int32Ptr(1)},
+				{Name: "dnode", IsBootstrap: true, Replicas: int32Ptr(1)},
+			},
+		},
+	}
+	objects = append(objects, cluster,
+		&marklogicv1.MarklogicGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: "dnode", Namespace: "default"},
+			Spec:       marklogicv1.MarklogicGroupSpec{Name: "dnode", Image: upgradeTestOldImage},
+		},
+		&marklogicv1.MarklogicGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: "enode", Namespace: "default"},
+			Spec:       marklogicv1.MarklogicGroupSpec{Name: "enode", Image: upgradeTestOldImage},
+		},
+	)
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithStatusSubresource(&marklogicv1.MarklogicCluster{}).
+		WithObjects(objects...).
+		Build()
+	return &ClusterContext{
+		Ctx:              context.Background(),
+		Client:           fakeClient,
+		Scheme:           scheme,
+		MarklogicCluster: cluster,
+		Recorder:         record.NewFakeRecorder(20),
+	}, fakeClient
+}
+
+func upgradeTestDesiredImages() map[string]string {
+	return map[string]string{"dnode": upgradeTestNewImage, "enode": upgradeTestNewImage}
+}
+
+func TestReconcileMarkLogicUpgradeStartsWithBootstrapGroup(t *testing.T) {
+	t.Parallel()
+
+	cc, fakeClient := newUpgradeTestContext(t)
+
+	holds, inProgress, res := cc.reconcileMarkLogicUpgrade(upgradeTestDesiredImages())
+	if res.Completed() {
+		_, err := res.Output()
+		t.Fatalf("expected upgrade reconcile to continue, got err=%v", err)
+	}
+	if !inProgress {
+		t.Fatal("expected upgrade to be in progress")
+	}
+	if holds["enode"] != upgradeTestOldImage {
+		t.Fatalf("expected enode to be held on the old image, got %q", holds["enode"])
+	}
+	if _, held := holds["dnode"]; held {
+		t.Fatal("expected bootstrap group to be released first")
+	}
+
+	current := &marklogicv1.MarklogicCluster{}
+	if err := fakeClient.Get(context.Background(), types.NamespacedName{Name: "ml", Namespace: "default"}, current); err != nil {
+		t.Fatalf("failed to fetch MarklogicCluster: %v", err)
+	}
+	upgrade := current.Status.Upgrade
+	if upgrade == nil || upgrade.Phase != marklogicv1.UpgradePhaseInProgress {
+		t.Fatalf("expected persisted in-progress upgrade, got %+v", upgrade)
+	}
+	if len(upgrade.Groups) != 2 || upgrade.Groups[0].Name != "dnode" || upgrade.Groups[1].Name != "enode" {
+		t.Fatalf("expected bootstrap-first upgrade order, got %+v", upgrade.Groups)
+	}
+	if upgrade.Groups[0].State != marklogicv1.UpgradeGroupStateRolling || upgrade.Groups[0].TargetVersion != "12.0.3" {
+		t.Fatalf("unexpected bootstrap upgrade entry: %+v", upgrade.Groups[0])
+	}
+	if upgrade.Groups[1].State != marklogicv1.UpgradeGroupStatePending {
+		t.Fatalf("expected enode to be pending, got %s", upgrade.Groups[1].State)
+	}
+}
+
+func TestReconcileMarkLogicUpgradeResumesAndRestartsOnDeletePods(t *testing.T) {
+	t.Parallel()
+
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "dnode", Namespace: "default", Generation: 2},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas:       int32Ptr(2),
+			Selector:       &metav1.LabelSelector{MatchLabels: map[string]string{"app.kubernetes.io/instance": "dnode"}},
+			UpdateStrategy: appsv1.StatefulSetUpdateStrategy{Type: appsv1.OnDeleteStatefulSetStrategyType},
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "marklogic-server", Image: upgradeTestNewImage}}},
+			},
+		},
+		Status: appsv1.StatefulSetStatus{ObservedGeneration: 2, UpdateRevision: "dnode-new"},
+	}
+	readyPod := func(name, revision string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels: map[string]string{
+					"app.kubernetes.io/instance":          "dnode",
+					appsv1.ControllerRevisionHashLabelKey: revision,
+				},
+			},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+			},
+		}
+	}
+	cc, fakeClient := newUpgradeTestContext(t, sts, readyPod("dnode-0", "dnode-old"), readyPod("dnode-1", "dnode-old"))
+	cc.MarklogicCluster.Status.Upgrade = &marklogicv1.UpgradeStatus{
+		Phase: marklogicv1.UpgradePhaseInProgress,
+		Groups: []marklogicv1.UpgradeGroupStatus{
+			{Name: "dnode", FromImage: upgradeTestOldImage, ToImage: upgradeTestNewImage, TargetVersion: "12.0.3", State: marklogicv1.UpgradeGroupStateRolling},
+			{Name: "enode", FromImage: upgradeTestOldImage, ToImage: upgradeTestNewImage, TargetVersion: "12.0.3", State: marklogicv1.UpgradeGroupStatePending},
+		},
+	}
+	// The interrupted upgrade already moved dnode to the new image.
+	dnode := &marklogicv1.MarklogicGroup{}
+	if err := fakeClient.Get(context.Background(), types.NamespacedName{Name: "dnode", Namespace: "default"}, dnode); err != nil {
+		t.Fatalf("failed to fetch dnode: %v", err)
+	}
+	dnode.Spec.Image = upgradeTestNewImage
+	if err := fakeClient.Update(context.Background(), dnode); err != nil {
+		t.Fatalf("failed to update dnode: %v", err)
+	}
+
+	holds, inProgress, res := cc.reconcileMarkLogicUpgrade(upgradeTestDesiredImages())
+	if res.Completed() {
+		_, err := res.Output()
+		t.Fatalf("expected upgrade reconcile to continue, got err=%v", err)
+	}
+	if !inProgress || holds["enode"] != upgradeTestOldImage {
+		t.Fatalf("expected enode to stay held while dnode rolls, holds=%v", holds)
+	}
+
+	err := fakeClient.Get(context.Background(), types.NamespacedName{Name: "dnode-1", Namespace: "default"}, &corev1.Pod{})
+	if !apierrors.IsNotFound(err) {
+		t.Fatalf("expected highest ordinal outdated pod to be deleted, got %v", err)
+	}
+	if err := fakeClient.Get(context.Background(), types.NamespacedName{Name: "dnode-0", Namespace: "default"}, &corev1.Pod{}); err != nil {
+		t.Fatalf("expected dnode-0 to be kept until dnode-1 is back, got %v", err)
+	}
+}
+
+func TestVerifyUpgradedHosts(t *testing.T) {
+	cc, _ := newUpgradeTestContext(t, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml-admin", Namespace: "default"},
+		Data:       map[string][]byte{"username": []byte("admin"), "password": []byte("admin")},
+	})
+
+	hosts := []mlmanage.HostStatus{
+		{Name: "dnode-0.dnode.default.svc.cluster.local", Online: true, Version: "12.0.3"},
+		{Name: "enode-0.enode.default.svc.cluster.local", Online: true, Version: "11.3.1"},
+	}
+	originalFactory := NewClusterManagementClient
+	NewClusterManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+		if opts.Host != "dnode-0.dnode.default.svc.cluster.local" {
+			t.Errorf("expected bootstrap host, got %q", opts.Host)
+		}
+		return managementStub{HostClient: &stubDynamicManagementClient{listHostsFn: func() ([]mlmanage.HostStatus, error) { return hosts, nil }}}
+	}
+	t.Cleanup(func() { NewClusterManagementClient = originalFactory })
+
+	done, message := cc.verifyUpgradedHosts(&marklogicv1.UpgradeGroupStatus{Name: "dnode", TargetVersion: "12.0.3"})
+	if !done {
+		t.Fatalf("expected dnode to be verified, got %q", message)
+	}
+	done, message = cc.verifyUpgradedHosts(&marklogicv1.UpgradeGroupStatus{Name: "enode", TargetVersion: "12.0.3"})
+	if done {
+		t.Fatalf("expected enode verification to wait, got %q", message)
+	}
+}
+
+func TestMarkLogicVersionMatches(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		host, target string
+		expected     bool
+	}{
+		{"12.0.3", "12.0.3", true},
+		{"12.0.3.1", "12.0.3", true},
+		{"10.0-9.5", "10.0", true},
+		{"12.0.30", "12.0.3", false},
+		{"11.3.1", "12.0.3", false},
+	}
+	for _, tc := range cases {
+		if got := markLogicVersionMatches(tc.host, tc.target); got != tc.expected {
+			t.Errorf("markLogicVersionMatches(%q, %q) = %t, expected %t", tc.host, tc.target, got, tc.expected)
+		}
+	}
+}
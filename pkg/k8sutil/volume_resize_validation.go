@@ -12,6 +12,7 @@ import (
 	"time"
 
 	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/metrics"
 	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -880,8 +881,10 @@ func (oc *OperatorContext) initializePVCStatuses(status *marklogicv1.VolumeResiz
 
 func (oc *OperatorContext) transitionResizePhase(status *marklogicv1.VolumeResizeStatus, phase marklogicv1.VolumeResizePhase, reason marklogicv1.VolumeResizeReason, message string) {
 	now := metav1.Now()
-	if status.Phase != phase {
+	changed := status.Phase != phase
+	if changed {
 		status.LastTransitionTime = &now
+		metrics.VolumeResizePhaseTransitions.WithLabelValues(oc.MarklogicGroup.Namespace, oc.MarklogicGroup.Name, string(phase)).Inc()
 	}
 	status.Phase = phase
 	status.Reason = reason
@@ -891,6 +894,9 @@ func (oc *OperatorContext) transitionResizePhase(status *marklogicv1.VolumeResiz
 	}
 	if phase == marklogicv1.VolumeResizePhaseFailed || phase == marklogicv1.VolumeResizePhaseCompleted {
 		status.CompletionTime = &now
+		if changed && status.FirstStartedTime != nil {
+			metrics.VolumeResizeDuration.WithLabelValues(oc.MarklogicGroup.Namespace, oc.MarklogicGroup.Name, string(phase)).Observe(now.Sub(status.FirstStartedTime.Time).Seconds())
+		}
 	}
 }
 
@@ -907,6 +913,9 @@ func (oc *OperatorContext) patchResizeStatus(status *marklogicv1.VolumeResizeSta
 	}
 
 	oc.MarklogicGroup.Status.VolumeResizeStatus = status
+	if status != nil {
+		metrics.VolumeResizeRetries.WithLabelValues(oc.MarklogicGroup.Namespace, oc.MarklogicGroup.Name).Set(float64(status.RetryCount))
+	}
 	return nil
 }
 
@@ -0,0 +1,210 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+// Package metrics holds the operator's Prometheus collectors. They are registered with the
+// controller-runtime registry and served by the manager's metrics endpoint next to the
+// default controller metrics.
+package metrics
+
+import (
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const namespace = "marklogic_operator"
+
+const (
+	DynamicHostOperationJoin   = "join"
+	DynamicHostOperationRemove = "remove"
+	DynamicHostOperationToken  = "token"
+
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+
+	// CodeTransportError is the code label of Management API calls that got no response.
+	CodeTransportError = "error"
+)
+
+var (
+	VolumeResizePhaseTransitions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "volume_resize",
+		Name:      "phase_transitions_total",
+		Help:      "Volume resize phase transitions by the phase entered.",
+	}, []string{"namespace", "group", "phase"})
+
+	VolumeResizeDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "volume_resize",
+		Name:      "duration_seconds",
+		Help:      "Time from the start of a volume resize to its completion or failure.",
+		Buckets:   prometheus.ExponentialBuckets(30, 2, 10),
+	}, []string{"namespace", "group", "phase"})
+
+	VolumeResizeRetries = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "volume_resize",
+		Name:      "retries",
+		Help:      "Retry count of the current volume resize operation.",
+	}, []string{"namespace", "group"})
+
+	DynamicHostOperations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "dynamic_host",
+		Name:      "operations_total",
+		Help:      "Dynamic host join, remove and token requests by result.",
+	}, []string{"namespace", "group", "operation", "result"})
+
+	ManagementRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "management_api",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of MarkLogic Management and Admin API requests by response code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "endpoint", "code"})
+
+	ManagementRequestErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "management_api",
+		Name:      "request_errors_total",
+		Help:      "MarkLogic Management and Admin API requests that failed or returned an unexpected status.",
+	}, []string{"method", "endpoint", "code"})
+
+	GroupReplicas = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "group",
+		Name:      "replicas",
+		Help:      "Replicas of the MarklogicGroup StatefulSet.",
+	}, []string{"namespace", "group"})
+
+	GroupReadyReplicas = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "group",
+		Name:      "ready_replicas",
+		Help:      "Ready replicas of the MarklogicGroup StatefulSet.",
+	}, []string{"namespace", "group"})
+)
+
+// serverMetrics holds the MarkLogic metrics last read per group. Their names follow the
+// status summaries of the Management API and are only known once read, so the collector
+// describes nothing and is registered unchecked; the values of a group are keyed by name,
+// which rules out duplicate series.
+var serverMetrics = &serverMetricsCollector{values: map[groupKey]map[string]float64{}}
+
+type groupKey struct{ namespace, group string }
+
+type serverMetricsCollector struct {
+	mu     sync.Mutex
+	values map[groupKey]map[string]float64
+}
+
+func (c *serverMetricsCollector) Describe(chan<- *prometheus.Desc) {}
+
+func (c *serverMetricsCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, values := range c.values {
+		for name, value := range values {
+			// A name the registry rejects would fail the whole scrape.
+			if !model.IsValidLegacyMetricName(name) {
+				continue
+			}
+			desc := prometheus.NewDesc(name, "MarkLogic status summary value read from the Management API.", []string{"namespace", "group"}, nil)
+			metric, err := prometheus.NewConstMetric(desc, prometheus.GaugeValue, value, key.namespace, key.group)
+			if err != nil {
+				continue
+			}
+			ch <- metric
+		}
+	}
+}
+
+func init() {
+	crmetrics.Registry.MustRegister(
+		serverMetrics,
+		VolumeResizePhaseTransitions,
+		VolumeResizeDuration,
+		VolumeResizeRetries,
+		DynamicHostOperations,
+		ManagementRequestDuration,
+		ManagementRequestErrors,
+		GroupReplicas,
+		GroupReadyReplicas,
+	)
+}
+
+// ObserveManagementRequest records one Management API round trip. code is the HTTP status,
+// or 0 when the request failed before a response arrived.
+func ObserveManagementRequest(method, endpoint string, code int, elapsed time.Duration) {
+	ManagementRequestDuration.WithLabelValues(method, endpoint, codeLabel(code)).Observe(elapsed.Seconds())
+}
+
+// CountManagementError records a request that returned an error to its caller.
+func CountManagementError(method, endpoint string, code int) {
+	ManagementRequestErrors.WithLabelValues(method, endpoint, codeLabel(code)).Inc()
+}
+
+func CountDynamicHostOperation(namespace, group, operation string, err error) {
+	result := ResultSuccess
+	if err != nil {
+		result = ResultFailure
+	}
+	DynamicHostOperations.WithLabelValues(namespace, group, operation, result).Inc()
+}
+
+func SetGroupReplicas(namespace, group string, replicas, readyReplicas int32) {
+	GroupReplicas.WithLabelValues(namespace, group).Set(float64(replicas))
+	GroupReadyReplicas.WithLabelValues(namespace, group).Set(float64(readyReplicas))
+}
+
+// invalidMetricNameChars are the characters Prometheus does not accept in series names.
+var invalidMetricNameChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// ServerMetricName returns the series name of a status summary value, for example
+// marklogic_servers_request_rate for the request-rate of the App Servers. Characters
+// Prometheus does not accept are replaced with underscores.
+func ServerMetricName(resource, name string) string {
+	return "marklogic_" + invalidMetricNameChars.ReplaceAllString(resource+"_"+name, "_")
+}
+
+// SetServerMetrics replaces the MarkLogic metrics of a group, keyed by ServerMetricName.
+func SetServerMetrics(namespace, group string, values map[string]float64) {
+	serverMetrics.mu.Lock()
+	defer serverMetrics.mu.Unlock()
+	serverMetrics.values[groupKey{namespace, group}] = values
+}
+
+// ForgetServerMetrics drops the MarkLogic metrics of a group.
+func ForgetServerMetrics(namespace, group string) {
+	serverMetrics.mu.Lock()
+	defer serverMetrics.mu.Unlock()
+	delete(serverMetrics.values, groupKey{namespace, group})
+}
+
+// ForgetGroup drops the per-group series of a deleted MarklogicGroup.
+func ForgetGroup(namespace, group string) {
+	ForgetServerMetrics(namespace, group)
+	labels := prometheus.Labels{"namespace": namespace, "group": group}
+	for _, vec := range []*prometheus.MetricVec{
+		VolumeResizePhaseTransitions.MetricVec,
+		VolumeResizeDuration.MetricVec,
+		VolumeResizeRetries.MetricVec,
+		DynamicHostOperations.MetricVec,
+		GroupReplicas.MetricVec,
+		GroupReadyReplicas.MetricVec,
+	} {
+		vec.DeletePartialMatch(labels)
+	}
+}
+
+func codeLabel(code int) string {
+	if code == 0 {
+		return CodeTransportError
+	}
+	return strconv.Itoa(code)
+}
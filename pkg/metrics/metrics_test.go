@@ -0,0 +1,77 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package metrics
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestDynamicHostOperationsAndForgetGroup(t *testing.T) {
+	CountDynamicHostOperation("ml", "enode", DynamicHostOperationJoin, nil)
+	CountDynamicHostOperation("ml", "enode", DynamicHostOperationJoin, errors.New("token expired"))
+	CountDynamicHostOperation("ml", "enode", DynamicHostOperationJoin, errors.New("token expired"))
+	SetGroupReplicas("ml", "enode", 3, 2)
+
+	if got := testutil.ToFloat64(DynamicHostOperations.WithLabelValues("ml", "enode", DynamicHostOperationJoin, ResultFailure)); got != 2 {
+		t.Fatalf("expected 2 failed joins, got %v", got)
+	}
+	if got := testutil.ToFloat64(GroupReadyReplicas.WithLabelValues("ml", "enode")); got != 2 {
+		t.Fatalf("expected 2 ready replicas, got %v", got)
+	}
+
+	ForgetGroup("ml", "enode")
+	if got := testutil.CollectAndCount(DynamicHostOperations); got != 0 {
+		t.Fatalf("expected the group series to be dropped, got %d", got)
+	}
+	if got := testutil.CollectAndCount(GroupReadyReplicas); got != 0 {
+		t.Fatalf("expected the group series to be dropped, got %d", got)
+	}
+}
+
+func TestManagementRequestCodeLabel(t *testing.T) {
+	CountManagementError("GET", "/manage/v2/hosts", 0)
+	CountManagementError("GET", "/manage/v2/hosts", 503)
+
+	if got := testutil.ToFloat64(ManagementRequestErrors.WithLabelValues("GET", "/manage/v2/hosts", CodeTransportError)); got != 1 {
+		t.Fatalf("expected one transport error, got %v", got)
+	}
+	if got := testutil.ToFloat64(ManagementRequestErrors.WithLabelValues("GET", "/manage/v2/hosts", "503")); got != 1 {
+		t.Fatalf("expected one 503, got %v", got)
+	}
+}
+
+func TestServerMetricsFollowTheLastRead(t *testing.T) {
+	name := ServerMetricName("servers", "expanded-tree-cache-hit-rate")
+	if name != "marklogic_servers_expanded_tree_cache_hit_rate" {
+		t.Fatalf("unexpected series name %s", name)
+	}
+	SetServerMetrics("ml", "enode", map[string]float64{name: 3.5, ServerMetricName("hosts", "total-cpu-stat-user"): 12})
+	if got := testutil.CollectAndCount(serverMetrics); got != 2 {
+		t.Fatalf("expected two series, got %d", got)
+	}
+	SetServerMetrics("ml", "enode", map[string]float64{name: 4})
+	if got := testutil.CollectAndCount(serverMetrics, name); got != 1 {
+		t.Fatalf("expected the cache hit rate, got %d series", got)
+	}
+
+	ForgetGroup("ml", "enode")
+	if got := testutil.CollectAndCount(serverMetrics); got != 0 {
+		t.Fatalf("expected the group series to be dropped, got %d", got)
+	}
+}
+
+func TestServerMetricNamesAreAlwaysValid(t *testing.T) {
+	name := ServerMetricName("forests", "journal-write-rate (MB/sec)")
+	if name != "marklogic_forests_journal_write_rate__MB_sec_" {
+		t.Fatalf("unexpected series name %s", name)
+	}
+	// Values set under a name Prometheus rejects are skipped rather than failing the scrape.
+	SetServerMetrics("ml", "dnode", map[string]float64{name: 1, "marklogic_bad name": 2})
+	t.Cleanup(func() { ForgetServerMetrics("ml", "dnode") })
+	if got := testutil.CollectAndCount(serverMetrics); got != 1 {
+		t.Fatalf("expected only the valid series, got %d", got)
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package mlmanage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/metrics"
+)
+
+// HostLicense is the license installed when a host is initialized. An empty license keeps
+// the host unlicensed.
+type HostLicense struct {
+	LicenseKey string
+	Licensee   string
+}
+
+// SecurityOptions are the credentials the bootstrap host installs into its Security
+// database.
+type SecurityOptions struct {
+	Username       string
+	Password       string
+	Realm          string
+	WalletPassword string
+}
+
+// HostSecurityInitialized asks the Admin API of a host whether it has a Security database.
+// The timestamp endpoint answers anonymous requests until security is installed, which for
+// a host other than the bootstrap host happens when it joins the cluster.
+func (c *managementClient) HostSecurityInitialized(ctx context.Context, hostFQDN string) (initialized bool, err error) {
+	req, err := newRequest(ctx, http.MethodGet, c.adminURL(hostFQDN, "/admin/v1/timestamp"), nil, nil)
+	if err != nil {
+		return false, err
+	}
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		metrics.ObserveManagementRequest(http.MethodGet, "/admin/v1/timestamp", 0, time.Since(start))
+		metrics.CountManagementError(http.MethodGet, "/admin/v1/timestamp", 0)
+		return false, err
+	}
+	metrics.ObserveManagementRequest(http.MethodGet, "/admin/v1/timestamp", resp.StatusCode, time.Since(start))
+	defer func() {
+		err = errors.Join(err, resp.Body.Close())
+	}()
+	if _, err := io.Copy(io.Discard, resp.Body); err != nil {
+		return false, err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return false, nil
+	case http.StatusUnauthorized:
+		return true, nil
+	}
+	metrics.CountManagementError(http.MethodGet, "/admin/v1/timestamp", resp.StatusCode)
+	return false, fmt.Errorf("host timestamp GET /admin/v1/timestamp returned status %d", resp.StatusCode)
+}
+
+// InitHost initializes MarkLogic on a freshly installed host. MarkLogic answers 202 when the
+// host restarts to complete the initialization.
+func (c *managementClient) InitHost(ctx context.Context, hostFQDN string, license HostLicense) error {
+	payload := map[string]string{}
+	if license.LicenseKey != "" && license.Licensee != "" {
+		payload["license-key"] = license.LicenseKey
+		payload["licensee"] = license.Licensee
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	_, err = c.doAdmin(ctx, http.MethodPost, c.adminURL(hostFQDN, "/admin/v1/init"),
+		map[string]string{"Content-Type": "application/json"}, body,
+		http.StatusOK, http.StatusAccepted, http.StatusNoContent)
+	return err
+}
+
+// InitializeSecurity installs the Security database on the bootstrap host and creates the
+// admin user. The host restarts afterwards.
+func (c *managementClient) InitializeSecurity(ctx context.Context, hostFQDN string, opts SecurityOptions) error {
+	form := url.Values{}
+	form.Set("admin-username", opts.Username)
+	form.Set("admin-password", opts.Password)
+	realm := opts.Realm
+	if realm == "" {
+		realm = "public"
+	}
+	form.Set("realm", realm)
+	if opts.WalletPassword != "" {
+		form.Set("wallet-password", opts.WalletPassword)
+	}
+	_, err := c.doAdmin(ctx, http.MethodPost, c.adminURL(hostFQDN, "/admin/v1/instance-admin"),
+		map[string]string{"Content-Type": "application/x-www-form-urlencoded; charset=utf-8"},
+		[]byte(form.Encode()), http.StatusAccepted)
+	return err
+}
+
+// GetServerConfig returns the server configuration of a host that has not joined a cluster.
+func (c *managementClient) GetServerConfig(ctx context.Context, hostFQDN string) ([]byte, error) {
+	return c.doAdmin(ctx, http.MethodGet, c.adminURL(hostFQDN, "/admin/v1/server-config"),
+		map[string]string{"Accept": "application/xml"}, nil, http.StatusOK)
+}
+
+// GetClusterConfig asks the bootstrap host for the cluster configuration a joining host
+// installs to become a member of the group.
+func (c *managementClient) GetClusterConfig(ctx context.Context, groupName string, serverConfig []byte) ([]byte, error) {
+	form := url.Values{}
+	form.Set("group", groupName)
+	form.Set("server-config", string(serverConfig))
+	return c.doAdmin(ctx, http.MethodPost, c.adminURL(c.baseURL, "/admin/v1/cluster-config"),
+		map[string]string{"Content-Type": "application/x-www-form-urlencoded"},
+		[]byte(form.Encode()), http.StatusOK)
+}
+
+// ApplyClusterConfig installs the cluster configuration on the joining host, which restarts
+// as a member of the cluster.
+func (c *managementClient) ApplyClusterConfig(ctx context.Context, hostFQDN string, clusterConfig []byte) error {
+	_, err := c.doAdmin(ctx, http.MethodPost, c.adminURL(hostFQDN, "/admin/v1/cluster-config"),
+		map[string]string{"Content-Type": "application/zip"}, clusterConfig, http.StatusAccepted)
+	return err
+}
+
+// UpdateGroupProperties changes properties of a group, including its name.
+func (c *managementClient) UpdateGroupProperties(ctx context.Context, groupName string, properties map[string]any) error {
+	if len(properties) == 0 {
+		return nil
+	}
+	_, _, err := c.doJSON(ctx, http.MethodPut, "/manage/v2/groups/"+url.PathEscape(groupName)+"/properties", nil, properties,
+		http.StatusAccepted, http.StatusNoContent)
+	return err
+}
+
+// adminURL addresses the Admin API on port 8001 of a host, using the scheme of the client.
+func (c *managementClient) adminURL(host, path string) string {
+	scheme := "http"
+	if strings.HasPrefix(c.baseURL, "https://") {
+		scheme = "https"
+	}
+	host = strings.TrimPrefix(strings.TrimPrefix(host, "https://"), "http://")
+	if parsedHost, _, err := net.SplitHostPort(host); err == nil {
+		host = parsedHost
+	}
+	return fmt.Sprintf("%s://%s:8001%s", scheme, host, path)
+}
+
+func (c *managementClient) doAdmin(ctx context.Context, method, endpoint string, headers map[string]string, body []byte, expectedStatus ...int) (data []byte, err error) {
+	resp, err := c.doRequestWithAuth(ctx, method, endpoint, headers, body)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = errors.Join(err, resp.Body.Close())
+	}()
+
+	data, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	for _, code := range expectedStatus {
+		if resp.StatusCode == code {
+			return data, nil
+		}
+	}
+	path := endpointTemplate(endpoint)
+	metrics.CountManagementError(method, path, resp.StatusCode)
+	return data, fmt.Errorf("admin api %s %s returned status %d: %s", method, path, resp.StatusCode, string(data))
+}
@@ -0,0 +1,146 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package mlmanage
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// adminTransport sends the requests for port 8001 of any host to the test server.
+type adminTransport struct {
+	target *url.URL
+	hosts  []string
+}
+
+func (a *adminTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	a.hosts = append(a.hosts, req.URL.Host)
+	out := req.Clone(req.Context())
+	out.URL.Scheme = a.target.Scheme
+	out.URL.Host = a.target.Host
+	return http.DefaultTransport.RoundTrip(out)
+}
+
+func TestOperatorJoinAdminRequests(t *testing.T) {
+	t.Parallel()
+
+	secured := false
+	var clusterConfigForm url.Values
+	var applied []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("read body: %v", err)
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/admin/v1/timestamp":
+			if secured {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte("2026-01-01T00:00:00Z"))
+		case r.Method == http.MethodPost && r.URL.Path == "/admin/v1/init":
+			if string(body) != `{"license-key":"KEY","licensee":"ACME"}` {
+				t.Errorf("unexpected init payload %s", body)
+			}
+			w.WriteHeader(http.StatusAccepted)
+		case r.Method == http.MethodPost && r.URL.Path == "/admin/v1/instance-admin":
+			form, _ := url.ParseQuery(string(body))
+			if form.Get("admin-username") != "admin" || form.Get("realm") != "public" || form.Get("wallet-password") != "" {
+				t.Errorf("unexpected instance-admin form %v", form)
+			}
+			secured = true
+			w.WriteHeader(http.StatusAccepted)
+		case r.Method == http.MethodGet && r.URL.Path == "/admin/v1/server-config":
+			_, _ = w.Write([]byte("<host/>"))
+		case r.Method == http.MethodPost && r.URL.Path == "/admin/v1/cluster-config":
+			if r.Header.Get("Content-Type") == "application/zip" {
+				applied = body
+				w.WriteHeader(http.StatusAccepted)
+				return
+			}
+			clusterConfigForm, _ = url.ParseQuery(string(body))
+			_, _ = w.Write([]byte("ZIP"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	target, _ := url.Parse(server.URL)
+	transport := &adminTransport{target: target}
+	client := NewClient(ClientOptions{
+		Host:       "ml-0.ml.default.svc.cluster.local",
+		Username:   "admin",
+		Password:   "admin",
+		HTTPClient: &http.Client{Transport: transport},
+	})
+	ctx := context.Background()
+	host := "ml-1.ml.default.svc.cluster.local"
+
+	initialized, err := client.HostSecurityInitialized(ctx, host)
+	if err != nil || initialized {
+		t.Fatalf("expected an uninitialized host, got %t, %v", initialized, err)
+	}
+	if err := client.InitHost(ctx, host, HostLicense{LicenseKey: "KEY", Licensee: "ACME"}); err != nil {
+		t.Fatalf("InitHost returned error: %v", err)
+	}
+	if err := client.InitializeSecurity(ctx, host, SecurityOptions{Username: "admin", Password: "secret"}); err != nil {
+		t.Fatalf("InitializeSecurity returned error: %v", err)
+	}
+	initialized, err = client.HostSecurityInitialized(ctx, host)
+	if err != nil || !initialized {
+		t.Fatalf("expected security to be initialized, got %t, %v", initialized, err)
+	}
+
+	serverConfig, err := client.GetServerConfig(ctx, host)
+	if err != nil {
+		t.Fatalf("GetServerConfig returned error: %v", err)
+	}
+	clusterConfig, err := client.GetClusterConfig(ctx, "enode", serverConfig)
+	if err != nil {
+		t.Fatalf("GetClusterConfig returned error: %v", err)
+	}
+	if clusterConfigForm.Get("group") != "enode" || clusterConfigForm.Get("server-config") != "<host/>" {
+		t.Fatalf("unexpected cluster-config form %v", clusterConfigForm)
+	}
+	if err := client.ApplyClusterConfig(ctx, host, clusterConfig); err != nil {
+		t.Fatalf("ApplyClusterConfig returned error: %v", err)
+	}
+	if string(applied) != "ZIP" {
+		t.Fatalf("expected the cluster configuration to be applied, got %q", applied)
+	}
+
+	// Only the cluster configuration comes from the bootstrap host; every other call goes to
+	// the Admin API of the joining host.
+	for i, got := range transport.hosts {
+		want := host + ":8001"
+		if i == 5 {
+			want = "ml-0.ml.default.svc.cluster.local:8001"
+		}
+		if got != want {
+			t.Fatalf("request %d went to %s, expected %s", i, got, want)
+		}
+	}
+}
+
+func TestInitHostReportsAdminAPIError(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("bad license"))
+	}))
+	defer server.Close()
+
+	target, _ := url.Parse(server.URL)
+	client := NewClient(ClientOptions{Host: "ml-0", HTTPClient: &http.Client{Transport: &adminTransport{target: target}}})
+	err := client.InitHost(context.Background(), "ml-0", HostLicense{})
+	if err == nil || err.Error() != "admin api POST /admin/v1/init returned status 400: bad license" {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
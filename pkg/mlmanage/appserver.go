@@ -0,0 +1,59 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package mlmanage
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+)
+
+// AppServerInfo is an App Server as reported by its properties endpoint.
+type AppServerInfo struct {
+	Exists     bool
+	Properties map[string]any
+}
+
+func (c *managementClient) GetAppServer(ctx context.Context, groupName, serverName string) (AppServerInfo, error) {
+	query := url.Values{}
+	query.Set("group-id", groupName)
+	query.Set("format", "json")
+	data, statusCode, err := c.doJSON(ctx, http.MethodGet, serverPath(serverName)+"/properties", query, nil, http.StatusOK, http.StatusNotFound)
+	if err != nil {
+		return AppServerInfo{}, err
+	}
+	if statusCode == http.StatusNotFound {
+		return AppServerInfo{Exists: false}, nil
+	}
+	var properties map[string]any
+	if err := json.Unmarshal(data, &properties); err != nil {
+		return AppServerInfo{}, err
+	}
+	return AppServerInfo{Exists: true, Properties: properties}, nil
+}
+
+// CreateAppServer creates an App Server in the group. properties must hold at least
+// server-name, server-type and port.
+func (c *managementClient) CreateAppServer(ctx context.Context, groupName string, properties map[string]any) error {
+	query := url.Values{}
+	query.Set("group-id", groupName)
+	_, _, err := c.doJSON(ctx, http.MethodPost, "/manage/v2/servers", query, properties, http.StatusCreated)
+	return err
+}
+
+// UpdateAppServerProperties changes properties of an App Server. MarkLogic answers 202
+// when the change restarts the server.
+func (c *managementClient) UpdateAppServerProperties(ctx context.Context, groupName, serverName string, properties map[string]any) error {
+	if len(properties) == 0 {
+		return nil
+	}
+	query := url.Values{}
+	query.Set("group-id", groupName)
+	_, _, err := c.doJSON(ctx, http.MethodPut, serverPath(serverName)+"/properties", query, properties, http.StatusNoContent, http.StatusAccepted, http.StatusOK)
+	return err
+}
+
+func serverPath(serverName string) string {
+	return "/manage/v2/servers/" + url.PathEscape(serverName)
+}
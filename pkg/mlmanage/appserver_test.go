@@ -0,0 +1,53 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package mlmanage
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAppServerRequestsAreScopedToGroup(t *testing.T) {
+	t.Parallel()
+
+	var created map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("group-id"); got != "enode" {
+			t.Errorf("expected group-id enode, got %q", got)
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/manage/v2/servers/orders/properties":
+			_, _ = w.Write([]byte(`{"server-name":"orders","server-type":"http","port":8010}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/manage/v2/servers":
+			if err := json.NewDecoder(r.Body).Decode(&created); err != nil {
+				t.Errorf("decode body: %v", err)
+			}
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &managementClient{baseURL: server.URL, httpClient: server.Client()}
+	info, err := client.GetAppServer(context.Background(), "enode", "orders")
+	if err != nil {
+		t.Fatalf("GetAppServer returned error: %v", err)
+	}
+	if !info.Exists || info.Properties["server-type"] != "http" {
+		t.Fatalf("unexpected App Server info %+v", info)
+	}
+	missing, err := client.GetAppServer(context.Background(), "enode", "reporting")
+	if err != nil || missing.Exists {
+		t.Fatalf("expected a missing App Server to be reported as not existing, got %+v, %v", missing, err)
+	}
+	if err := client.CreateAppServer(context.Background(), "enode", map[string]any{"server-name": "reporting", "server-type": "odbc", "port": 5432}); err != nil {
+		t.Fatalf("CreateAppServer returned error: %v", err)
+	}
+	if created["server-name"] != "reporting" {
+		t.Fatalf("unexpected create payload %v", created)
+	}
+}
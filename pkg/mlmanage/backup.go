@@ -0,0 +1,221 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package mlmanage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Job states reported by the backup-status and restore-status operations, normalized
+// across MarkLogic versions.
+const (
+	JobStateInProgress = "in-progress"
+	JobStateCompleted  = "completed"
+	JobStateFailed     = "failed"
+)
+
+type BackupOptions struct {
+	BackupDir       string
+	IncludeReplicas bool
+}
+
+type RestoreOptions struct {
+	BackupDir       string
+	IncludeReplicas bool
+	// RestoreToTime restores to a point in time when journal archiving was enabled.
+	RestoreToTime *time.Time
+}
+
+// DatabaseJob identifies a backup or restore job. MarkLogic only answers status
+// requests for a job on the host that started it.
+type DatabaseJob struct {
+	ID       string
+	HostName string
+}
+
+type JobStatus struct {
+	State   string
+	Message string
+}
+
+func (c *managementClient) BackupDatabase(ctx context.Context, database string, opts BackupOptions) (DatabaseJob, error) {
+	if strings.TrimSpace(opts.BackupDir) == "" {
+		return DatabaseJob{}, fmt.Errorf("backup directory is required to back up database %s", database)
+	}
+	payload := map[string]any{
+		"operation":        "backup-database",
+		"backup-dir":       opts.BackupDir,
+		"include-replicas": opts.IncludeReplicas,
+	}
+	return c.startDatabaseJob(ctx, database, payload)
+}
+
+func (c *managementClient) GetBackupStatus(ctx context.Context, database string, job DatabaseJob) (JobStatus, error) {
+	return c.databaseJobStatus(ctx, database, "backup-status", job)
+}
+
+// PurgeBackups removes all but the newest keep backups of the database in backupDir.
+func (c *managementClient) PurgeBackups(ctx context.Context, database, backupDir string, keep int) error {
+	if keep < 1 {
+		return fmt.Errorf("refusing to purge every backup of database %s", database)
+	}
+	payload := map[string]any{
+		"operation":        "backup-purge",
+		"backup-dir":       backupDir,
+		"keep-num-backups": keep,
+	}
+	_, _, err := c.doJSON(ctx, http.MethodPost, databasePath(database), nil, payload, http.StatusOK, http.StatusAccepted, http.StatusNoContent)
+	return err
+}
+
+func (c *managementClient) RestoreDatabase(ctx context.Context, database string, opts RestoreOptions) (DatabaseJob, error) {
+	if strings.TrimSpace(opts.BackupDir) == "" {
+		return DatabaseJob{}, fmt.Errorf("backup directory is required to restore database %s", database)
+	}
+	payload := map[string]any{
+		"operation":        "restore-database",
+		"backup-dir":       opts.BackupDir,
+		"include-replicas": opts.IncludeReplicas,
+	}
+	if opts.RestoreToTime != nil {
+		payload["restore-to-time"] = opts.RestoreToTime.UTC().Format(time.RFC3339)
+	}
+	return c.startDatabaseJob(ctx, database, payload)
+}
+
+func (c *managementClient) GetRestoreStatus(ctx context.Context, database string, job DatabaseJob) (JobStatus, error) {
+	return c.databaseJobStatus(ctx, database, "restore-status", job)
+}
+
+// GetDatabaseSize returns the on-disk size of the database's forests in bytes.
+func (c *managementClient) GetDatabaseSize(ctx context.Context, database string) (int64, error) {
+	query := url.Values{}
+	query.Set("view", "status")
+	query.Set("format", "json")
+	data, _, err := c.doJSON(ctx, http.MethodGet, databasePath(database), query, nil, http.StatusOK)
+	if err != nil {
+		return 0, err
+	}
+	var payload any
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return 0, err
+	}
+	size, ok := extractSizeBytes(payload, "on-disk-size", "data-size")
+	if !ok {
+		return 0, fmt.Errorf("database %s status did not report an on-disk size", database)
+	}
+	return size, nil
+}
+
+func (c *managementClient) startDatabaseJob(ctx context.Context, database string, payload map[string]any) (DatabaseJob, error) {
+	query := url.Values{}
+	query.Set("format", "json")
+	data, _, err := c.doJSON(ctx, http.MethodPost, databasePath(database), query, payload, http.StatusOK, http.StatusAccepted)
+	if err != nil {
+		return DatabaseJob{}, err
+	}
+	// Job ids are 64-bit integers; keep them exact instead of decoding to float64.
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var body any
+	if err := decoder.Decode(&body); err != nil {
+		return DatabaseJob{}, err
+	}
+	job := DatabaseJob{
+		ID:       findFirstStringByKeys(body, "job-id"),
+		HostName: findFirstStringByKeys(body, "host-name"),
+	}
+	if job.ID == "" {
+		return DatabaseJob{}, fmt.Errorf("%s for database %s did not return a job id", payload["operation"], database)
+	}
+	return job, nil
+}
+
+func (c *managementClient) databaseJobStatus(ctx context.Context, database, operation string, job DatabaseJob) (JobStatus, error) {
+	payload := map[string]any{
+		"operation": operation,
+		"job-id":    job.ID,
+	}
+	if job.HostName != "" {
+		payload["host-name"] = job.HostName
+	}
+	query := url.Values{}
+	query.Set("format", "json")
+	data, _, err := c.doJSON(ctx, http.MethodPost, databasePath(database), query, payload, http.StatusOK)
+	if err != nil {
+		return JobStatus{}, err
+	}
+	var body any
+	if err := json.Unmarshal(data, &body); err != nil {
+		return JobStatus{}, err
+	}
+	return JobStatus{
+		State:   normalizeJobState(findFirstStringByKeys(body, "status")),
+		Message: findFirstStringByKeys(body, "error", "message"),
+	}, nil
+}
+
+func databasePath(database string) string {
+	return "/manage/v2/databases/" + url.PathEscape(database)
+}
+
+// normalizeJobState folds the job states MarkLogic reports ("queued", "in-progress",
+// "completed", "failed", "cancelled", ...) into the three the operator acts on.
+func normalizeJobState(state string) string {
+	switch strings.ToLower(strings.TrimSpace(state)) {
+	case "completed", "complete":
+		return JobStateCompleted
+	case "", "queued", "in-progress", "in progress", "pending", "started":
+		return JobStateInProgress
+	default:
+		return JobStateFailed
+	}
+}
+
+// extractSizeBytes finds the first of keys holding a {"units": ..., "value": ...}
+// quantity and converts it to bytes. MarkLogic reports sizes in MB.
+func extractSizeBytes(payload any, keys ...string) (int64, bool) {
+	for _, key := range keys {
+		var size int64
+		found := false
+		walkAny(payload, func(m map[string]any) {
+			if found {
+				return
+			}
+			raw, ok := m[key]
+			if !ok {
+				return
+			}
+			value, ok := quantityValueAsInt(raw)
+			if !ok {
+				return
+			}
+			units := ""
+			if quantity, ok := raw.(map[string]any); ok {
+				units = strings.ToUpper(toString(quantity["units"]))
+			}
+			switch units {
+			case "B", "BYTES":
+				size = int64(value)
+			case "KB":
+				size = int64(value) << 10
+			case "GB":
+				size = int64(value) << 30
+			default:
+				size = int64(value) << 20
+			}
+			found = true
+		})
+		if found {
+			return size, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package mlmanage
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBackupDatabaseStartsJobAndKeepsJobIDExact(t *testing.T) {
+	t.Parallel()
+
+	var gotPath string
+	var gotBody map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		if err := json.NewDecoder(r.Body).Decode(&gotBody); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"job-id": 17813236788574286536, "host-name": "dnode-0.dnode.default.svc.cluster.local"}`))
+	}))
+	defer server.Close()
+
+	client := &managementClient{baseURL: server.URL, httpClient: server.Client()}
+	job, err := client.BackupDatabase(context.Background(), "Documents", BackupOptions{BackupDir: "/backups/nightly/Documents", IncludeReplicas: true})
+	if err != nil {
+		t.Fatalf("BackupDatabase returned error: %v", err)
+	}
+	if gotPath != "/manage/v2/databases/Documents" {
+		t.Fatalf("unexpected path %s", gotPath)
+	}
+	if gotBody["operation"] != "backup-database" || gotBody["backup-dir"] != "/backups/nightly/Documents" || gotBody["include-replicas"] != true {
+		t.Fatalf("unexpected backup payload %v", gotBody)
+	}
+	if job.ID != "17813236788574286536" {
+		t.Fatalf("expected exact job id, got %q", job.ID)
+	}
+	if job.HostName != "dnode-0.dnode.default.svc.cluster.local" {
+		t.Fatalf("unexpected job host %q", job.HostName)
+	}
+}
+
+func TestGetBackupStatusNormalizesState(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		`{"job-id":"1","status":"in-progress","forest":[{"forest-name":"f1","status":"completed"}]}`: JobStateInProgress,
+		`{"job-id":"1","status":"completed"}`:                                                        JobStateCompleted,
+		`{"job-id":"1","status":"cancelled"}`:                                                        JobStateFailed,
+	}
+	for response, expected := range cases {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var body map[string]any
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("decode body: %v", err)
+			}
+			if body["operation"] != "backup-status" || body["job-id"] != "1" || body["host-name"] != "h1" {
+				t.Errorf("unexpected status payload %v", body)
+			}
+			_, _ = w.Write([]byte(response))
+		}))
+		client := &managementClient{baseURL: server.URL, httpClient: server.Client()}
+		status, err := client.GetBackupStatus(context.Background(), "Documents", DatabaseJob{ID: "1", HostName: "h1"})
+		server.Close()
+		if err != nil {
+			t.Fatalf("GetBackupStatus returned error: %v", err)
+		}
+		if status.State != expected {
+			t.Errorf("response %s: expected state %s, got %s", response, expected, status.State)
+		}
+	}
+}
+
+func TestPurgeBackupsRejectsKeepingNothing(t *testing.T) {
+	t.Parallel()
+
+	client := &managementClient{baseURL: "http://127.0.0.1:1", httpClient: http.DefaultClient}
+	if err := client.PurgeBackups(context.Background(), "Documents", "/backups", 0); err == nil {
+		t.Fatal("expected purge with keep=0 to be rejected")
+	}
+}
+
+func TestExtractSizeBytesConvertsUnits(t *testing.T) {
+	t.Parallel()
+
+	var payload any
+	body := `{"database-status":{"status-properties":{"on-disk-size":{"units":"MB","value":12}}}}`
+	if err := json.Unmarshal([]byte(body), &payload); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	size, ok := extractSizeBytes(payload, "on-disk-size", "data-size")
+	if !ok || size != 12<<20 {
+		t.Fatalf("expected 12MB in bytes, got %d (found=%t)", size, ok)
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package mlmanage
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// DefaultCertificateTemplate is the certificate template cluster-config.sh assigns to the
+// default App Servers.
+const DefaultCertificateTemplate = "defaultTemplate"
+
+// HostCertificate is a PEM encoded host certificate and its private key.
+type HostCertificate struct {
+	Cert       string
+	PrivateKey string
+}
+
+// InsertHostCertificates installs named host certificates in a certificate template.
+// MarkLogic replaces the certificate of the matching hosts and the App Servers using the
+// template pick it up without a restart.
+func (c *managementClient) InsertHostCertificates(ctx context.Context, template string, certificates []HostCertificate) error {
+	if len(certificates) == 0 {
+		return nil
+	}
+	entries := make([]map[string]any, 0, len(certificates))
+	for _, certificate := range certificates {
+		entries = append(entries, map[string]any{
+			"certificate": map[string]any{
+				"cert": certificate.Cert,
+				"pkey": certificate.PrivateKey,
+			},
+		})
+	}
+	payload := map[string]any{
+		"operation":    "insert-host-certificates",
+		"certificates": entries,
+	}
+	_, _, err := c.doJSON(ctx, http.MethodPost, "/manage/v2/certificate-templates/"+url.PathEscape(template), nil, payload,
+		http.StatusOK, http.StatusCreated, http.StatusNoContent)
+	return err
+}
@@ -0,0 +1,42 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package mlmanage
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestInsertHostCertificatesPostsToTemplate(t *testing.T) {
+	t.Parallel()
+
+	var payload map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/manage/v2/certificate-templates/defaultTemplate" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := &managementClient{baseURL: server.URL, httpClient: server.Client()}
+	err := client.InsertHostCertificates(context.Background(), DefaultCertificateTemplate, []HostCertificate{{Cert: "CERT", PrivateKey: "KEY"}})
+	if err != nil {
+		t.Fatalf("InsertHostCertificates returned error: %v", err)
+	}
+	if payload["operation"] != "insert-host-certificates" {
+		t.Fatalf("unexpected operation in %v", payload)
+	}
+	certificates := payload["certificates"].([]any)
+	certificate := certificates[0].(map[string]any)["certificate"].(map[string]any)
+	if certificate["cert"] != "CERT" || certificate["pkey"] != "KEY" {
+		t.Fatalf("unexpected certificate payload %v", certificate)
+	}
+}
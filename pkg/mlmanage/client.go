@@ -19,23 +19,120 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/metrics"
 )
 
+// Client is the Management API of a cluster. The features of the operator each depend on
+// the part they use.
 type Client interface {
+	HostClient
+	GroupClient
+	AdminClient
+	SecurityClient
+	MetricsClient
+	DatabaseClient
+	ForestClient
+	BackupClient
+	AppServerClient
+	ReplicationClient
+}
+
+// HostClient reads the hosts of the cluster and moves them in and out of it.
+type HostClient interface {
 	ListHostsStatus(ctx context.Context) ([]HostStatus, error)
 	GetHostGroupName(ctx context.Context, hostName string) (string, error)
-	GetGroup(ctx context.Context, groupName string) (GroupInfo, error)
-	CreateGroup(ctx context.Context, groupName string) error
-	EnableDynamicHosts(ctx context.Context, groupName string) error
-	EnableAdminAPITokenAuthentication(ctx context.Context, groupName string) error
-	EnsureManageAdminUser(ctx context.Context, username, password string) error
 	ResolveClusterName(ctx context.Context) (string, error)
 	RequestDynamicHostToken(ctx context.Context, clusterName, groupName, hostFQDN, duration string) (string, error)
 	JoinDynamicHost(ctx context.Context, hostFQDN, token string) error
 	ListGroupHosts(ctx context.Context, groupName string) ([]GroupHost, error)
 	RemoveDynamicHost(ctx context.Context, clusterName, hostID string) error
+	LeaveCluster(ctx context.Context, hostFQDN string) error
+}
+
+// GroupClient creates and configures groups.
+type GroupClient interface {
+	GetGroup(ctx context.Context, groupName string) (GroupInfo, error)
+	CreateGroup(ctx context.Context, groupName string) error
+	EnableDynamicHosts(ctx context.Context, groupName string) error
+	EnableAdminAPITokenAuthentication(ctx context.Context, groupName string) error
+	EnsureManageAdminUser(ctx context.Context, username, password string) error
+	UpdateGroupProperties(ctx context.Context, groupName string, properties map[string]any) error
+}
+
+// DynamicHostClient is what the dynamic host lifecycle uses.
+type DynamicHostClient interface {
+	HostClient
+	GroupClient
+}
+
+// AdminClient initializes hosts through the Admin API.
+type AdminClient interface {
+	HostSecurityInitialized(ctx context.Context, hostFQDN string) (bool, error)
+	InitHost(ctx context.Context, hostFQDN string, license HostLicense) error
+	InitializeSecurity(ctx context.Context, hostFQDN string, opts SecurityOptions) error
+	GetServerConfig(ctx context.Context, hostFQDN string) ([]byte, error)
+	GetClusterConfig(ctx context.Context, groupName string, serverConfig []byte) ([]byte, error)
+	ApplyClusterConfig(ctx context.Context, hostFQDN string, clusterConfig []byte) error
+}
+
+// SecurityClient rotates the admin password and the host certificates.
+type SecurityClient interface {
+	SetUserPassword(ctx context.Context, username, password string) error
+	InsertHostCertificates(ctx context.Context, template string, certificates []HostCertificate) error
+}
+
+// MetricsClient reads the status summaries of a group.
+type MetricsClient interface {
+	GetGroupRequestRate(ctx context.Context, groupName string) (float64, error)
+	GetServerMetrics(ctx context.Context, groupName string) ([]ServerMetric, error)
+}
+
+// DatabaseClient manages databases.
+type DatabaseClient interface {
+	GetDatabase(ctx context.Context, database string) (DatabaseInfo, error)
+	CreateDatabase(ctx context.Context, database string, properties map[string]any) error
+	UpdateDatabaseProperties(ctx context.Context, database string, properties map[string]any) error
+	DeleteDatabase(ctx context.Context, database string) error
+}
+
+// ForestClient manages forests, their replicas and the host they live on.
+type ForestClient interface {
+	GetForest(ctx context.Context, forest string) (ForestInfo, error)
+	GetForestState(ctx context.Context, forest string) (string, error)
+	CreateForest(ctx context.Context, opts ForestOptions) error
+	SetForestReplicas(ctx context.Context, forest string, replicas []ForestReplica) error
+	DeleteForest(ctx context.Context, forest string) error
+	ListHostForests(ctx context.Context, hostName string) ([]string, error)
+	MigrateForest(ctx context.Context, forest, targetHost string) error
+}
+
+// BackupClient runs database backups and restores.
+type BackupClient interface {
+	BackupDatabase(ctx context.Context, database string, opts BackupOptions) (DatabaseJob, error)
+	GetBackupStatus(ctx context.Context, database string, job DatabaseJob) (JobStatus, error)
+	PurgeBackups(ctx context.Context, database, backupDir string, keep int) error
+	RestoreDatabase(ctx context.Context, database string, opts RestoreOptions) (DatabaseJob, error)
+	GetRestoreStatus(ctx context.Context, database string, job DatabaseJob) (JobStatus, error)
+	GetDatabaseSize(ctx context.Context, database string) (int64, error)
+}
+
+// AppServerClient manages the App Servers of a group.
+type AppServerClient interface {
+	GetAppServer(ctx context.Context, groupName, serverName string) (AppServerInfo, error)
+	CreateAppServer(ctx context.Context, groupName string, properties map[string]any) error
+	UpdateAppServerProperties(ctx context.Context, groupName, serverName string, properties map[string]any) error
+}
+
+// ReplicationClient couples clusters and reads database replication.
+type ReplicationClient interface {
+	GetClusterProperties(ctx context.Context) (map[string]any, error)
+	ListForeignClusters(ctx context.Context) ([]string, error)
+	CoupleForeignCluster(ctx context.Context, properties map[string]any) error
+	GetDatabaseReplicationStatus(ctx context.Context, database string) ([]ForeignReplicaStatus, error)
 }
 
 type ClientOptions struct {
@@ -183,6 +280,30 @@ func (c *managementClient) GetGroup(ctx context.Context, groupName string) (Grou
 	return GroupInfo{Exists: true, ForestCount: countForests(payload)}, nil
 }
 
+// GetGroupRequestRate returns the requests per second served by the App Servers of a group,
+// as summarized by the server status list.
+func (c *managementClient) GetGroupRequestRate(ctx context.Context, groupName string) (float64, error) {
+	query := url.Values{}
+	query.Set("view", "status")
+	query.Set("group-id", groupName)
+	query.Set("format", "json")
+	data, _, err := c.doJSON(ctx, http.MethodGet, "/manage/v2/servers", query, nil, http.StatusOK)
+	if err != nil {
+		return 0, err
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return 0, err
+	}
+	list, _ := payload["server-status-list"].(map[string]any)
+	summary, _ := list["status-list-summary"].(map[string]any)
+	rate, ok := quantityValueAsFloat(summary["request-rate"])
+	if !ok {
+		return 0, fmt.Errorf("server status of group %s does not report a request rate", groupName)
+	}
+	return rate, nil
+}
+
 func (c *managementClient) GetHostGroupName(ctx context.Context, hostName string) (string, error) {
 	query := url.Values{}
 	query.Set("format", "json")
@@ -254,6 +375,13 @@ func (c *managementClient) EnsureManageAdminUser(ctx context.Context, username,
 	return err
 }
 
+// SetUserPassword changes the password of an existing MarkLogic user.
+func (c *managementClient) SetUserPassword(ctx context.Context, username, password string) error {
+	payload := map[string]any{"password": password}
+	_, _, err := c.doJSON(ctx, http.MethodPut, "/manage/v2/users/"+url.PathEscape(username)+"/properties", nil, payload, http.StatusAccepted, http.StatusNoContent)
+	return err
+}
+
 func (c *managementClient) ResolveClusterName(ctx context.Context) (string, error) {
 	if clusterName, err := c.resolveClusterNameFromClusterList(ctx); err == nil && strings.TrimSpace(clusterName) != "" {
 		return clusterName, nil
@@ -390,10 +518,14 @@ func (c *managementClient) JoinDynamicHost(ctx context.Context, hostFQDN, token
 	req.Header.Set("Content-Type", "application/xml")
 	req.Header.Set("Accept", "application/xml")
 
+	start := time.Now()
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
+		metrics.ObserveManagementRequest(http.MethodPost, "/admin/v1/init", 0, time.Since(start))
+		metrics.CountManagementError(http.MethodPost, "/admin/v1/init", 0)
 		return err
 	}
+	metrics.ObserveManagementRequest(http.MethodPost, "/admin/v1/init", resp.StatusCode, time.Since(start))
 	defer func() {
 		err = errors.Join(err, resp.Body.Close())
 	}()
@@ -406,9 +538,41 @@ func (c *managementClient) JoinDynamicHost(ctx context.Context, hostFQDN, token
 		return nil
 	}
 
+	metrics.CountManagementError(http.MethodPost, "/admin/v1/init", resp.StatusCode)
 	return fmt.Errorf("dynamic host init POST /admin/v1/init returned status %d: %s", resp.StatusCode, string(respBody))
 }
 
+// LeaveCluster asks a host to leave the cluster through the Admin API on the host itself.
+// MarkLogic refuses while forests are still placed on the host.
+func (c *managementClient) LeaveCluster(ctx context.Context, hostFQDN string) (err error) {
+	scheme := "http"
+	if strings.HasPrefix(c.baseURL, "https://") {
+		scheme = "https"
+	}
+	host := hostFQDN
+	if parsedHost, _, err := net.SplitHostPort(hostFQDN); err == nil {
+		host = parsedHost
+	}
+	leaveURL := fmt.Sprintf("%s://%s:8001/admin/v1/leave", scheme, host)
+	resp, err := c.doRequestWithAuth(ctx, http.MethodPost, leaveURL, map[string]string{"Accept": "application/json"}, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, resp.Body.Close())
+	}()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusAccepted || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	metrics.CountManagementError(http.MethodPost, "/admin/v1/leave", resp.StatusCode)
+	return fmt.Errorf("host leave POST /admin/v1/leave returned status %d: %s", resp.StatusCode, string(respBody))
+}
+
 func (c *managementClient) ListGroupHosts(ctx context.Context, groupName string) ([]GroupHost, error) {
 	query := url.Values{}
 	query.Set("group-id", groupName)
@@ -510,6 +674,7 @@ func (c *managementClient) doJSON(ctx context.Context, method, path string, quer
 			return data, resp.StatusCode, nil
 		}
 	}
+	metrics.CountManagementError(method, endpointTemplate(path), resp.StatusCode)
 	return data, resp.StatusCode, fmt.Errorf("management api %s %s returned status %d: %s", method, path, resp.StatusCode, string(data))
 }
 
@@ -548,16 +713,29 @@ func (c *managementClient) doXML(ctx context.Context, method, path string, query
 			return data, resp.StatusCode, nil
 		}
 	}
+	metrics.CountManagementError(method, endpointTemplate(path), resp.StatusCode)
 	return data, resp.StatusCode, fmt.Errorf("management api %s %s returned status %d: %s", method, path, resp.StatusCode, string(data))
 }
 
-func (c *managementClient) doRequestWithAuth(ctx context.Context, method, endpoint string, headers map[string]string, body []byte) (*http.Response, error) {
+func (c *managementClient) doRequestWithAuth(ctx context.Context, method, endpoint string, headers map[string]string, body []byte) (resp *http.Response, err error) {
+	start := time.Now()
+	defer func() {
+		code := 0
+		if resp != nil {
+			code = resp.StatusCode
+		}
+		metrics.ObserveManagementRequest(method, endpointTemplate(endpoint), code, time.Since(start))
+		if err != nil {
+			metrics.CountManagementError(method, endpointTemplate(endpoint), 0)
+		}
+	}()
+
 	req, err := newRequest(ctx, method, endpoint, headers, body)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err = c.httpClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -581,6 +759,21 @@ func (c *managementClient) doRequestWithAuth(ctx context.Context, method, endpoi
 	return c.httpClient.Do(digestReq)
 }
 
+// endpointTemplate reduces a request URL to its path with the resource name replaced, so
+// metrics stay bounded: /manage/v2/databases/orders/properties becomes
+// /manage/v2/databases/{name}/properties.
+func endpointTemplate(endpoint string) string {
+	path := endpoint
+	if parsed, err := url.Parse(endpoint); err == nil {
+		path = parsed.Path
+	}
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segments) >= 4 && segments[0] == "manage" {
+		segments[3] = "{name}"
+	}
+	return "/" + strings.Join(segments, "/")
+}
+
 func newRequest(ctx context.Context, method, endpoint string, headers map[string]string, body []byte) (*http.Request, error) {
 	var bodyReader io.Reader
 	if len(body) > 0 {
@@ -845,6 +1038,20 @@ func quantityValueAsInt(value any) (int, bool) {
 	return 0, false
 }
 
+func quantityValueAsFloat(value any) (float64, bool) {
+	if valueMap, ok := value.(map[string]any); ok {
+		value = valueMap["value"]
+	}
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case string:
+		parsed, err := strconv.ParseFloat(v, 64)
+		return parsed, err == nil
+	}
+	return 0, false
+}
+
 func countForests(payload any) int {
 	forestNodes := 0
 	walkAny(payload, func(m map[string]any) {
@@ -898,6 +1105,8 @@ func toString(value any) string {
 		return v
 	case float64:
 		return fmt.Sprintf("%g", v)
+	case json.Number:
+		return v.String()
 	case bool:
 		if v {
 			return "true"
@@ -631,3 +631,19 @@ func TestListHostsStatusParsesHostDefaultListEnvelope(t *testing.T) {
 		t.Fatalf("expected host name node-0, got %s", hosts[0].Name)
 	}
 }
+
+func TestEndpointTemplateDropsResourceNames(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"http://dnode-0:8002/manage/v2/databases/orders/properties?format=json": "/manage/v2/databases/{name}/properties",
+		"http://dnode-0:8002/manage/v2/hosts?view=status":                       "/manage/v2/hosts",
+		"/manage/v2/clusters/ml/dynamic-host-token":                             "/manage/v2/clusters/{name}/dynamic-host-token",
+		"https://dnode-2:8001/admin/v1/leave":                                   "/admin/v1/leave",
+	}
+	for endpoint, expected := range cases {
+		if got := endpointTemplate(endpoint); got != expected {
+			t.Errorf("endpointTemplate(%q) = %q, expected %q", endpoint, got, expected)
+		}
+	}
+}
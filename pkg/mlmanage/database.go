@@ -0,0 +1,249 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package mlmanage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DatabaseInfo is a database as reported by its properties endpoint.
+type DatabaseInfo struct {
+	Exists bool
+	// Forests are the forests attached to the database.
+	Forests []string
+	// Properties is the raw properties document, used to compare index settings.
+	Properties map[string]any
+	// ForeignReplicas and ForeignMaster are the database replication configuration.
+	ForeignReplicas []ForeignDatabase
+	ForeignMaster   *ForeignDatabase
+}
+
+type ForestInfo struct {
+	Exists bool
+	Host   string
+	// Database is the database the forest is attached to, empty for replicas and
+	// detached forests.
+	Database string
+	Replicas []ForestReplica
+}
+
+type ForestReplica struct {
+	Name string
+	Host string
+}
+
+type ForestOptions struct {
+	Name string
+	Host string
+	// Database attaches the new forest to a database. Replica forests are created unattached.
+	Database      string
+	DataDirectory string
+}
+
+func (c *managementClient) GetDatabase(ctx context.Context, database string) (DatabaseInfo, error) {
+	query := url.Values{}
+	query.Set("format", "json")
+	data, statusCode, err := c.doJSON(ctx, http.MethodGet, databasePath(database)+"/properties", query, nil, http.StatusOK, http.StatusNotFound)
+	if err != nil {
+		return DatabaseInfo{}, err
+	}
+	if statusCode == http.StatusNotFound {
+		return DatabaseInfo{Exists: false}, nil
+	}
+	var properties map[string]any
+	if err := json.Unmarshal(data, &properties); err != nil {
+		return DatabaseInfo{}, err
+	}
+	info := DatabaseInfo{Exists: true, Forests: stringList(properties["forest"]), Properties: properties}
+	info.ForeignReplicas, info.ForeignMaster = databaseReplication(properties)
+	return info, nil
+}
+
+// CreateDatabase creates a database with the given properties and no forests.
+func (c *managementClient) CreateDatabase(ctx context.Context, database string, properties map[string]any) error {
+	payload := map[string]any{}
+	for key, value := range properties {
+		payload[key] = value
+	}
+	payload["database-name"] = database
+	_, _, err := c.doJSON(ctx, http.MethodPost, "/manage/v2/databases", nil, payload, http.StatusCreated)
+	return err
+}
+
+func (c *managementClient) UpdateDatabaseProperties(ctx context.Context, database string, properties map[string]any) error {
+	if len(properties) == 0 {
+		return nil
+	}
+	_, _, err := c.doJSON(ctx, http.MethodPut, databasePath(database)+"/properties", nil, properties, http.StatusNoContent, http.StatusAccepted, http.StatusOK)
+	return err
+}
+
+// DeleteDatabase deletes a database together with the configuration and data of its
+// attached forests. Deleting a database that does not exist succeeds.
+func (c *managementClient) DeleteDatabase(ctx context.Context, database string) error {
+	query := url.Values{}
+	query.Set("forest-delete", "data")
+	_, _, err := c.doJSON(ctx, http.MethodDelete, databasePath(database), query, nil, http.StatusNoContent, http.StatusAccepted, http.StatusOK, http.StatusNotFound)
+	return err
+}
+
+func (c *managementClient) GetForest(ctx context.Context, forest string) (ForestInfo, error) {
+	query := url.Values{}
+	query.Set("format", "json")
+	data, statusCode, err := c.doJSON(ctx, http.MethodGet, forestPath(forest)+"/properties", query, nil, http.StatusOK, http.StatusNotFound)
+	if err != nil {
+		return ForestInfo{}, err
+	}
+	if statusCode == http.StatusNotFound {
+		return ForestInfo{Exists: false}, nil
+	}
+	var properties map[string]any
+	if err := json.Unmarshal(data, &properties); err != nil {
+		return ForestInfo{}, err
+	}
+	info := ForestInfo{
+		Exists:   true,
+		Host:     toString(properties["host"]),
+		Database: toString(properties["database"]),
+	}
+	walkAny(properties["forest-replica"], func(m map[string]any) {
+		if name := firstString(m, "replica-name"); name != "" {
+			info.Replicas = append(info.Replicas, ForestReplica{Name: name, Host: firstString(m, "host")})
+		}
+	})
+	return info, nil
+}
+
+// GetForestState returns the state MarkLogic reports for a forest, such as "open",
+// "sync replicating" or "unmounted". A forest that does not exist has no state.
+func (c *managementClient) GetForestState(ctx context.Context, forest string) (string, error) {
+	query := url.Values{}
+	query.Set("view", "status")
+	query.Set("format", "json")
+	data, statusCode, err := c.doJSON(ctx, http.MethodGet, forestPath(forest), query, nil, http.StatusOK, http.StatusNotFound)
+	if err != nil || statusCode == http.StatusNotFound {
+		return "", err
+	}
+	var payload any
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return "", err
+	}
+	state := ""
+	walkAny(payload, func(m map[string]any) {
+		if state != "" {
+			return
+		}
+		switch value := m["state"].(type) {
+		case string:
+			state = value
+		case map[string]any:
+			state = firstString(value, "value")
+		}
+	})
+	return state, nil
+}
+
+func (c *managementClient) CreateForest(ctx context.Context, opts ForestOptions) error {
+	if strings.TrimSpace(opts.Name) == "" || strings.TrimSpace(opts.Host) == "" {
+		return fmt.Errorf("forest name and host are required to create a forest")
+	}
+	payload := map[string]any{
+		"forest-name": opts.Name,
+		"host":        opts.Host,
+	}
+	if opts.Database != "" {
+		payload["database"] = opts.Database
+	}
+	if opts.DataDirectory != "" {
+		payload["data-directory"] = opts.DataDirectory
+	}
+	_, _, err := c.doJSON(ctx, http.MethodPost, "/manage/v2/forests", nil, payload, http.StatusCreated)
+	return err
+}
+
+// SetForestReplicas replaces the replica configuration of a forest. The replica forests
+// must already exist.
+func (c *managementClient) SetForestReplicas(ctx context.Context, forest string, replicas []ForestReplica) error {
+	entries := make([]map[string]any, 0, len(replicas))
+	for _, replica := range replicas {
+		entries = append(entries, map[string]any{"replica-name": replica.Name, "host": replica.Host})
+	}
+	payload := map[string]any{"forest-replica": entries}
+	_, _, err := c.doJSON(ctx, http.MethodPut, forestPath(forest)+"/properties", nil, payload, http.StatusNoContent, http.StatusAccepted, http.StatusOK)
+	return err
+}
+
+// DeleteForest deletes a forest's configuration and data. Deleting a forest that does not
+// exist succeeds.
+func (c *managementClient) DeleteForest(ctx context.Context, forest string) error {
+	query := url.Values{}
+	query.Set("level", "full")
+	_, _, err := c.doJSON(ctx, http.MethodDelete, forestPath(forest), query, nil, http.StatusNoContent, http.StatusAccepted, http.StatusOK, http.StatusNotFound)
+	return err
+}
+
+// ListHostForests returns the names of the forests placed on a host, replicas included.
+func (c *managementClient) ListHostForests(ctx context.Context, hostName string) ([]string, error) {
+	query := url.Values{}
+	query.Set("host-id", hostName)
+	query.Set("format", "json")
+	data, _, err := c.doJSON(ctx, http.MethodGet, "/manage/v2/forests", query, nil, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, err
+	}
+	var forests []string
+	for _, item := range extractListItems(payload, "forest-default-list", "list-items", "list-item") {
+		if name := firstString(item, "nameref", "forest-name"); name != "" {
+			forests = append(forests, name)
+		}
+	}
+	return forests, nil
+}
+
+// MigrateForest moves a forest and its data to another host. MarkLogic copies the data in
+// the background; the forest reports the new host once the move is complete.
+func (c *managementClient) MigrateForest(ctx context.Context, forest, targetHost string) error {
+	if strings.TrimSpace(forest) == "" || strings.TrimSpace(targetHost) == "" {
+		return fmt.Errorf("forest name and target host are required to migrate a forest")
+	}
+	payload := map[string]any{
+		"operation": "forest-migrate",
+		"forest":    []string{forest},
+		"host":      targetHost,
+	}
+	_, _, err := c.doJSON(ctx, http.MethodPut, "/manage/v2/forests", nil, payload, http.StatusAccepted, http.StatusNoContent, http.StatusOK)
+	return err
+}
+
+func forestPath(forest string) string {
+	return "/manage/v2/forests/" + url.PathEscape(forest)
+}
+
+// stringList reads a property that MarkLogic reports either as a single string or as a
+// list of strings.
+func stringList(value any) []string {
+	switch v := value.(type) {
+	case string:
+		if v != "" {
+			return []string{v}
+		}
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s := toString(item); s != "" {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package mlmanage
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestGetDatabaseReadsForestsAndReportsMissingDatabase(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/manage/v2/databases/orders/properties":
+			_, _ = w.Write([]byte(`{"database-name":"orders","forest":["orders-dnode-0-1","orders-dnode-1-1"],"triple-index":true}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &managementClient{baseURL: server.URL, httpClient: server.Client()}
+	info, err := client.GetDatabase(context.Background(), "orders")
+	if err != nil {
+		t.Fatalf("GetDatabase returned error: %v", err)
+	}
+	if !info.Exists || !reflect.DeepEqual(info.Forests, []string{"orders-dnode-0-1", "orders-dnode-1-1"}) {
+		t.Fatalf("unexpected database info %+v", info)
+	}
+	if info.Properties["triple-index"] != true {
+		t.Fatalf("expected raw properties to be kept, got %v", info.Properties)
+	}
+
+	missing, err := client.GetDatabase(context.Background(), "missing")
+	if err != nil {
+		t.Fatalf("GetDatabase returned error for a missing database: %v", err)
+	}
+	if missing.Exists {
+		t.Fatal("expected a missing database to be reported as not existing")
+	}
+}
+
+func TestForestReplicasRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	var gotBody map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/manage/v2/forests/orders-dnode-0-1/properties" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.Method == http.MethodPut {
+			if err := json.NewDecoder(r.Body).Decode(&gotBody); err != nil {
+				t.Errorf("decode body: %v", err)
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		_, _ = w.Write([]byte(`{"forest-name":"orders-dnode-0-1","host":"dnode-0","database":"orders",` +
+			`"forest-replica":[{"replica-name":"orders-dnode-0-1-replica-1","host":"dnode-1"}]}`))
+	}))
+	defer server.Close()
+
+	client := &managementClient{baseURL: server.URL, httpClient: server.Client()}
+	info, err := client.GetForest(context.Background(), "orders-dnode-0-1")
+	if err != nil {
+		t.Fatalf("GetForest returned error: %v", err)
+	}
+	expected := []ForestReplica{{Name: "orders-dnode-0-1-replica-1", Host: "dnode-1"}}
+	if info.Host != "dnode-0" || info.Database != "orders" || !reflect.DeepEqual(info.Replicas, expected) {
+		t.Fatalf("unexpected forest info %+v", info)
+	}
+
+	if err := client.SetForestReplicas(context.Background(), "orders-dnode-0-1", expected); err != nil {
+		t.Fatalf("SetForestReplicas returned error: %v", err)
+	}
+	replicas, ok := gotBody["forest-replica"].([]any)
+	if !ok || len(replicas) != 1 {
+		t.Fatalf("unexpected replica payload %v", gotBody)
+	}
+	if entry := replicas[0].(map[string]any); entry["replica-name"] != "orders-dnode-0-1-replica-1" || entry["host"] != "dnode-1" {
+		t.Fatalf("unexpected replica entry %v", entry)
+	}
+}
+
+func TestListHostForestsAndMigrateForest(t *testing.T) {
+	t.Parallel()
+
+	var migrate map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/manage/v2/forests":
+			if got := r.URL.Query().Get("host-id"); got != "dnode-2" {
+				t.Errorf("expected host-id dnode-2, got %q", got)
+			}
+			_, _ = w.Write([]byte(`{"forest-default-list":{"list-items":{"list-item":[{"nameref":"orders-dnode-2-1"},{"nameref":"orders-dnode-2-2"}]}}}`))
+		case r.Method == http.MethodPut && r.URL.Path == "/manage/v2/forests":
+			if err := json.NewDecoder(r.Body).Decode(&migrate); err != nil {
+				t.Errorf("decode body: %v", err)
+			}
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &managementClient{baseURL: server.URL, httpClient: server.Client()}
+	forests, err := client.ListHostForests(context.Background(), "dnode-2")
+	if err != nil {
+		t.Fatalf("ListHostForests returned error: %v", err)
+	}
+	if !reflect.DeepEqual(forests, []string{"orders-dnode-2-1", "orders-dnode-2-2"}) {
+		t.Fatalf("unexpected forests %v", forests)
+	}
+	if err := client.MigrateForest(context.Background(), "orders-dnode-2-1", "dnode-0"); err != nil {
+		t.Fatalf("MigrateForest returned error: %v", err)
+	}
+	if migrate["operation"] != "forest-migrate" || migrate["host"] != "dnode-0" || !reflect.DeepEqual(migrate["forest"], []any{"orders-dnode-2-1"}) {
+		t.Fatalf("unexpected migrate payload %v", migrate)
+	}
+}
+
+func TestGetForestStateReadsStatusView(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/manage/v2/forests/orders-dnode-0-1" && r.URL.Query().Get("view") == "status" {
+			_, _ = w.Write([]byte(`{"forest-status":{"id":"1","name":"orders-dnode-0-1","status-properties":{"state":{"units":"enum","value":"sync replicating"}}}}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := &managementClient{baseURL: server.URL, httpClient: server.Client()}
+	state, err := client.GetForestState(context.Background(), "orders-dnode-0-1")
+	if err != nil {
+		t.Fatalf("GetForestState returned error: %v", err)
+	}
+	if state != "sync replicating" {
+		t.Fatalf("expected sync replicating, got %q", state)
+	}
+	if state, err := client.GetForestState(context.Background(), "missing"); err != nil || state != "" {
+		t.Fatalf("expected no state for a missing forest, got %q, %v", state, err)
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package mlmanage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+)
+
+// ServerMetric is one value of the status summary of a resource, such as the
+// total-cpu-stat-user of the hosts or the expanded-tree-cache-hit-rate of the App Servers.
+type ServerMetric struct {
+	// Resource is the Management API resource the value summarizes: hosts, servers or forests.
+	Resource string
+	// Name is the property name in the status summary.
+	Name  string
+	Value float64
+}
+
+// serverMetricResources are the status views the metrics are read from, with the envelope of
+// each. Together they cover host CPU and memory, request rates and cache hit rates, and
+// forest sizes and merges.
+var serverMetricResources = []struct{ resource, list string }{
+	{"hosts", "host-status-list"},
+	{"servers", "server-status-list"},
+	{"forests", "forest-status-list"},
+}
+
+// GetServerMetrics returns the numeric properties of the status summaries of the hosts, App
+// Servers and forests of a group. A resource whose status cannot be read is reported in the
+// error next to the metrics of the other resources.
+func (c *managementClient) GetServerMetrics(ctx context.Context, groupName string) ([]ServerMetric, error) {
+	var result []ServerMetric
+	var errs []error
+	for _, view := range serverMetricResources {
+		resource := view.resource
+		query := url.Values{}
+		query.Set("view", "status")
+		query.Set("group-id", groupName)
+		query.Set("format", "json")
+		data, _, err := c.doJSON(ctx, http.MethodGet, "/manage/v2/"+resource, query, nil, http.StatusOK)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s status: %w", resource, err))
+			continue
+		}
+		var payload map[string]any
+		if err := json.Unmarshal(data, &payload); err != nil {
+			errs = append(errs, fmt.Errorf("%s status: %w", resource, err))
+			continue
+		}
+		list, _ := payload[view.list].(map[string]any)
+		summary, ok := list["status-list-summary"].(map[string]any)
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s status of group %s has no summary", resource, groupName))
+			continue
+		}
+		result = append(result, summaryMetrics(resource, "", summary)...)
+	}
+	return result, errors.Join(errs...)
+}
+
+// summaryMetrics flattens a status summary. Quantities are either plain numbers or objects
+// with a value and units; nested property groups such as load-properties are descended into,
+// and their properties are named after the group, as in load-properties-total-load, since
+// the same property can appear in several groups.
+func summaryMetrics(resource, prefix string, summary map[string]any) []ServerMetric {
+	var result []ServerMetric
+	for _, key := range sortedKeys(summary) {
+		value := summary[key]
+		name := prefix + key
+		if number, ok := quantityValueAsFloat(value); ok {
+			result = append(result, ServerMetric{Resource: resource, Name: name, Value: number})
+			continue
+		}
+		if nested, ok := value.(map[string]any); ok {
+			if _, quantity := nested["value"]; !quantity {
+				result = append(result, summaryMetrics(resource, name+"-", nested)...)
+			}
+		}
+	}
+	return result
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
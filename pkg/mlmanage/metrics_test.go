@@ -0,0 +1,48 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package mlmanage
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGetServerMetricsFlattensStatusSummaries(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("group-id") != "E-Nodes" || r.URL.Query().Get("view") != "status" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		switch r.URL.Path {
+		case "/manage/v2/hosts":
+			_, _ = w.Write([]byte(`{"host-status-list":{"status-list-summary":{"total-hosts":{"units":"quantity","value":2},"total-cpu-stat-user":{"units":"percent","value":12.5},"load-properties":{"total-load":{"units":"sec/sec","value":0.25}},"rate-properties":{"total-load":{"units":"MB/sec","value":3}}}}}`))
+		case "/manage/v2/servers":
+			_, _ = w.Write([]byte(`{"server-status-list":{"status-list-summary":{"request-rate":{"units":"requests/sec","value":40},"expanded-tree-cache-hit-rate":{"units":"hits/sec","value":"3.5"},"name":{"value":"summary"}}}}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	client := &managementClient{baseURL: server.URL, httpClient: server.Client()}
+	got, err := client.GetServerMetrics(context.Background(), "E-Nodes")
+	if err == nil || !strings.Contains(err.Error(), "forests status") {
+		t.Fatalf("expected the forest status failure to be reported, got %v", err)
+	}
+	want := []ServerMetric{
+		{Resource: "hosts", Name: "load-properties-total-load", Value: 0.25},
+		{Resource: "hosts", Name: "rate-properties-total-load", Value: 3},
+		{Resource: "hosts", Name: "total-cpu-stat-user", Value: 12.5},
+		{Resource: "hosts", Name: "total-hosts", Value: 2},
+		{Resource: "servers", Name: "expanded-tree-cache-hit-rate", Value: 3.5},
+		{Resource: "servers", Name: "request-rate", Value: 40},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected metrics %+v", got)
+	}
+}
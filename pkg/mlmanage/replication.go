@@ -0,0 +1,185 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package mlmanage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ForeignDatabase is one side of a database replication: a foreign replica configured on
+// the master database, or the foreign master configured on a replica database.
+type ForeignDatabase struct {
+	ClusterName  string
+	DatabaseName string
+	// LagLimit and Enabled only apply to foreign replicas.
+	LagLimit int32
+	Enabled  bool
+}
+
+// ForeignReplicaStatus is the replication of a database to one foreign replica, as the
+// master reports it.
+type ForeignReplicaStatus struct {
+	ClusterName  string
+	DatabaseName string
+	State        string
+	LagSeconds   int64
+}
+
+// GetClusterProperties returns the properties of the local cluster. Coupling another
+// cluster to this one posts them to the other cluster unchanged.
+func (c *managementClient) GetClusterProperties(ctx context.Context) (map[string]any, error) {
+	query := url.Values{}
+	query.Set("format", "json")
+	data, _, err := c.doJSON(ctx, http.MethodGet, "/manage/v2/properties", query, nil, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	var properties map[string]any
+	if err := json.Unmarshal(data, &properties); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(toString(properties["cluster-name"])) == "" {
+		return nil, fmt.Errorf("cluster name was not present in cluster properties")
+	}
+	return properties, nil
+}
+
+// ListForeignClusters returns the names of the clusters coupled to this one.
+func (c *managementClient) ListForeignClusters(ctx context.Context) ([]string, error) {
+	query := url.Values{}
+	query.Set("format", "json")
+	data, _, err := c.doJSON(ctx, http.MethodGet, "/manage/v2/clusters", query, nil, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, item := range extractListItems(payload, "cluster-default-list", "list-items", "list-item") {
+		if !strings.EqualFold(firstString(item, "roleref"), "foreign") {
+			continue
+		}
+		if name := firstString(item, "nameref"); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// CoupleForeignCluster couples this cluster to the cluster whose properties are given, as
+// returned by GetClusterProperties on that cluster. Coupling is one-way; both clusters
+// need the other as a foreign cluster.
+func (c *managementClient) CoupleForeignCluster(ctx context.Context, properties map[string]any) error {
+	_, _, err := c.doJSON(ctx, http.MethodPost, "/manage/v2/clusters", nil, properties, http.StatusCreated, http.StatusNoContent)
+	return err
+}
+
+// GetDatabaseReplicationStatus reports the foreign replicas of a master database.
+func (c *managementClient) GetDatabaseReplicationStatus(ctx context.Context, database string) ([]ForeignReplicaStatus, error) {
+	query := url.Values{}
+	query.Set("view", "status")
+	query.Set("format", "json")
+	data, _, err := c.doJSON(ctx, http.MethodGet, databasePath(database), query, nil, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	var payload any
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, err
+	}
+	replicas := map[string]*ForeignReplicaStatus{}
+	var order []string
+	// Each forest reports its own replication; the database is as far behind as its
+	// slowest forest.
+	walkAny(payload, func(m map[string]any) {
+		clusterName := firstString(m, "foreign-cluster-name")
+		if clusterName == "" {
+			return
+		}
+		databaseName := firstString(m, "foreign-database-name")
+		key := clusterName + "/" + databaseName
+		replica, ok := replicas[key]
+		if !ok {
+			replica = &ForeignReplicaStatus{ClusterName: clusterName, DatabaseName: databaseName}
+			replicas[key] = replica
+			order = append(order, key)
+		}
+		if state := firstString(m, "replication-state", "state"); state != "" && replica.State == "" {
+			replica.State = state
+		}
+		for _, lagKey := range []string{"lag", "replication-lag"} {
+			if lag, ok := quantityValueAsInt(m[lagKey]); ok && int64(lag) > replica.LagSeconds {
+				replica.LagSeconds = int64(lag)
+			}
+		}
+	})
+	statuses := make([]ForeignReplicaStatus, 0, len(order))
+	for _, key := range order {
+		statuses = append(statuses, *replicas[key])
+	}
+	return statuses, nil
+}
+
+// DatabaseReplicationProperties is the database-replication property of a database with
+// the given foreign replicas and foreign master. Forests are connected by name.
+func DatabaseReplicationProperties(replicas []ForeignDatabase, master *ForeignDatabase) map[string]any {
+	replication := map[string]any{}
+	if len(replicas) > 0 {
+		entries := make([]map[string]any, 0, len(replicas))
+		for _, replica := range replicas {
+			entries = append(entries, map[string]any{
+				"foreign-cluster-name":    replica.ClusterName,
+				"foreign-database-name":   replica.DatabaseName,
+				"connect-forests-by-name": true,
+				"lag-limit":               replica.LagLimit,
+				"replication-enabled":     replica.Enabled,
+			})
+		}
+		replication["foreign-replicas"] = entries
+	}
+	if master != nil {
+		replication["foreign-master"] = map[string]any{
+			"foreign-cluster-name":    master.ClusterName,
+			"foreign-database-name":   master.DatabaseName,
+			"connect-forests-by-name": true,
+		}
+	}
+	return map[string]any{"database-replication": replication}
+}
+
+// databaseReplication reads the foreign replicas and foreign master from the properties
+// of a database.
+func databaseReplication(properties map[string]any) ([]ForeignDatabase, *ForeignDatabase) {
+	replication, ok := properties["database-replication"].(map[string]any)
+	if !ok {
+		return nil, nil
+	}
+	var replicas []ForeignDatabase
+	for _, key := range []string{"foreign-replicas", "foreign-replica"} {
+		walkAny(replication[key], func(m map[string]any) {
+			if name := firstString(m, "foreign-cluster-name"); name != "" {
+				lagLimit, _ := quantityValueAsInt(m["lag-limit"])
+				replicas = append(replicas, ForeignDatabase{
+					ClusterName:  name,
+					DatabaseName: firstString(m, "foreign-database-name"),
+					LagLimit:     int32(lagLimit),
+					Enabled:      firstString(m, "replication-enabled") != "false",
+				})
+			}
+		})
+	}
+	var master *ForeignDatabase
+	walkAny(replication["foreign-master"], func(m map[string]any) {
+		if name := firstString(m, "foreign-cluster-name"); name != "" && master == nil {
+			master = &ForeignDatabase{ClusterName: name, DatabaseName: firstString(m, "foreign-database-name")}
+		}
+	})
+	return replicas, master
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package mlmanage
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestCoupleForeignClusterRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	var coupled map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/manage/v2/properties":
+			_, _ = w.Write([]byte(`{"cluster-name":"primary","cluster-id":"123","xdqp-ssl-certificate":"PEM"}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/manage/v2/clusters":
+			_, _ = w.Write([]byte(`{"cluster-default-list":{"list-items":{"list-item":[` +
+				`{"nameref":"primary","roleref":"local"},{"nameref":"dr","roleref":"foreign"}]}}}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/manage/v2/clusters":
+			if err := json.NewDecoder(r.Body).Decode(&coupled); err != nil {
+				t.Errorf("decode body: %v", err)
+			}
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &managementClient{baseURL: server.URL, httpClient: server.Client()}
+	properties, err := client.GetClusterProperties(context.Background())
+	if err != nil {
+		t.Fatalf("GetClusterProperties returned error: %v", err)
+	}
+	foreign, err := client.ListForeignClusters(context.Background())
+	if err != nil {
+		t.Fatalf("ListForeignClusters returned error: %v", err)
+	}
+	if !reflect.DeepEqual(foreign, []string{"dr"}) {
+		t.Fatalf("expected only the foreign cluster to be listed, got %v", foreign)
+	}
+	if err := client.CoupleForeignCluster(context.Background(), properties); err != nil {
+		t.Fatalf("CoupleForeignCluster returned error: %v", err)
+	}
+	if !reflect.DeepEqual(coupled, properties) {
+		t.Fatalf("expected the cluster properties to be posted unchanged, got %v", coupled)
+	}
+}
+
+func TestDatabaseReplicationConfigurationAndStatus(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/manage/v2/databases/orders/properties":
+			_, _ = w.Write([]byte(`{"database-name":"orders","database-replication":{` +
+				`"foreign-replicas":[{"foreign-cluster-name":"dr","foreign-database-name":"orders-dr","lag-limit":30,"replication-enabled":false}],` +
+				`"foreign-master":{"foreign-cluster-name":"origin","foreign-database-name":"orders"}}}`))
+		case r.URL.Path == "/manage/v2/databases/orders" && r.URL.Query().Get("view") == "status":
+			_, _ = w.Write([]byte(`{"database-status":{"status-properties":{"forests":[` +
+				`{"foreign-cluster-name":"dr","foreign-database-name":"orders-dr","replication-state":"connected","lag":{"units":"sec","value":3}},` +
+				`{"foreign-cluster-name":"dr","foreign-database-name":"orders-dr","replication-state":"connected","lag":{"units":"sec","value":7}}]}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &managementClient{baseURL: server.URL, httpClient: server.Client()}
+	info, err := client.GetDatabase(context.Background(), "orders")
+	if err != nil {
+		t.Fatalf("GetDatabase returned error: %v", err)
+	}
+	expectedReplicas := []ForeignDatabase{{ClusterName: "dr", DatabaseName: "orders-dr", LagLimit: 30, Enabled: false}}
+	if !reflect.DeepEqual(info.ForeignReplicas, expectedReplicas) {
+		t.Fatalf("unexpected foreign replicas %+v", info.ForeignReplicas)
+	}
+	if info.ForeignMaster == nil || *info.ForeignMaster != (ForeignDatabase{ClusterName: "origin", DatabaseName: "orders"}) {
+		t.Fatalf("unexpected foreign master %+v", info.ForeignMaster)
+	}
+
+	statuses, err := client.GetDatabaseReplicationStatus(context.Background(), "orders")
+	if err != nil {
+		t.Fatalf("GetDatabaseReplicationStatus returned error: %v", err)
+	}
+	expectedStatus := []ForeignReplicaStatus{{ClusterName: "dr", DatabaseName: "orders-dr", State: "connected", LagSeconds: 7}}
+	if !reflect.DeepEqual(statuses, expectedStatus) {
+		t.Fatalf("expected the slowest forest to set the lag, got %+v", statuses)
+	}
+
+	properties := DatabaseReplicationProperties(expectedReplicas, nil)
+	replication := properties["database-replication"].(map[string]any)
+	if _, ok := replication["foreign-master"]; ok {
+		t.Fatalf("expected no foreign master, got %v", replication)
+	}
+	entry := replication["foreign-replicas"].([]map[string]any)[0]
+	if entry["foreign-cluster-name"] != "dr" || entry["connect-forests-by-name"] != true || entry["lag-limit"] != int32(30) {
+		t.Fatalf("unexpected foreign replica entry %v", entry)
+	}
+}